@@ -3,6 +3,7 @@ package app
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
 	"net/url"
 	"os"
@@ -11,9 +12,12 @@ import (
 	"sync/atomic"
 	"syscall"
 
+	"online-registration/internal/interview/infrastructure/db/tenantconn"
+
 	_ "github.com/denisenkom/go-mssqldb"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/stdlib"
+	"github.com/nats-io/nats.go"
 	"github.com/uptrace/bun"
 	"github.com/uptrace/bun/dialect/pgdialect"
 	bundebug "github.com/uptrace/bun/extra/bundebug"
@@ -41,9 +45,14 @@ type App struct {
 	onStop      appHooks
 	onAfterStop appHooks
 
+	schedule scheduledTasks
+
 	// lazy init
 	dbOnce sync.Once
 	db     *bun.DB
+
+	natsOnce sync.Once
+	nats     *nats.Conn
 }
 
 func New(ctx context.Context, cfg *Config) *App {
@@ -121,7 +130,8 @@ func (app *App) DB() *bun.DB {
 			panic(err)
 		}
 
-		sqldb := stdlib.OpenDB(*config)
+		// every query exposes its tenant to the row level security policies
+		sqldb := sql.OpenDB(tenantconn.NewConnector(stdlib.GetConnector(*config)))
 
 		err = sqldb.Ping()
 		if err != nil {
@@ -133,7 +143,9 @@ func (app *App) DB() *bun.DB {
 			db.AddQueryHook(bundebug.NewQueryHook(bundebug.WithVerbose(true)))
 		}
 
-		app.OnStop("db.Close", func(ctx context.Context, _ *App) error {
+		// closed after the stop hooks, which may drain work that still
+		// needs the connections
+		app.OnAfterStop("db.Close", func(ctx context.Context, _ *App) error {
 			return db.Close()
 		})
 
@@ -143,6 +155,38 @@ func (app *App) DB() *bun.DB {
 	return app.db
 }
 
+func (app *App) NATS() *nats.Conn {
+	app.natsOnce.Do(func() {
+		natsURL := &url.URL{
+			Scheme: "nats",
+			Host:   fmt.Sprintf("%s:%d", app.cfg.Nats.Host, app.cfg.Nats.Port),
+		}
+
+		opts := []nats.Option{
+			nats.Name("online-registration"),
+			nats.MaxReconnects(-1),
+			// keep serving the API while NATS is down, the outbox catches up later
+			nats.RetryOnFailedConnect(true),
+		}
+		if app.cfg.Nats.JwtCredentialFilePath != "" {
+			opts = append(opts, nats.UserCredentials(app.cfg.Nats.JwtCredentialFilePath))
+		}
+
+		nc, err := nats.Connect(natsURL.String(), opts...)
+		if err != nil {
+			panic(err)
+		}
+
+		app.OnAfterStop("nats.Drain", func(ctx context.Context, _ *App) error {
+			return nc.Drain()
+		})
+
+		app.nats = nc
+	})
+
+	return app.nats
+}
+
 //------------------------------------------------------------------------------
 
 func (app *App) WaitExitSignal() os.Signal {
@@ -3,9 +3,11 @@ package app
 
 import (
 	"context"
+	"fmt"
 	"log"
 	"os"
 	"strconv"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -30,12 +32,135 @@ type Config struct {
 			Host                string
 			Port                string
 			OnboardingGroupUuid string
+			Url                 string
+			ClientID            string
+			ClientSecret        string
+			TokenUrl            string
+			Timeout             time.Duration
+			MaxRetries          int
+			RetryBackoff        time.Duration
+			BreakerThreshold    int
+			BreakerCooldown     time.Duration
 		}
 	}
+	Auth struct {
+		Disabled    bool
+		Issuer      string
+		Audience    string
+		RolesClaim  string
+		TenantClaim string
+		PolicyFile  string
+	}
+	Tenant struct {
+		// Default is used for requests and commands that name no tenant.
+		Default string
+	}
 	Nats struct {
 		Host                  string
 		Port                  int
 		JwtCredentialFilePath string
+		Stream                string
+	}
+	Outbox struct {
+		PollInterval time.Duration
+	}
+	Events struct {
+		// CompleteSchedule is the cron expression ended events are marked
+		// completed on.
+		CompleteSchedule string
+		// TombstoneRetention is how long deleted events are kept before they
+		// are purged with their registrations.
+		TombstoneRetention time.Duration
+		// SearchLanguage is the text search configuration of the event
+		// search, english or russian.
+		SearchLanguage string
+	}
+	EventStream struct {
+		// Heartbeat is how often idle streams get a comment, so that proxies
+		// keep them open.
+		Heartbeat time.Duration
+		// Buffer is how many changes a client may fall behind before it is
+		// dropped.
+		Buffer int
+		// ReplayLimit is the most changes replayed to a resuming client.
+		ReplayLimit int
+	}
+	SeatAvailability struct {
+		// Heartbeat is how often connections watching seats are pinged.
+		Heartbeat time.Duration
+		// MaxConnections bounds the connections watching seats on each
+		// instance.
+		MaxConnections int
+	}
+	Webhooks struct {
+		PollInterval time.Duration
+		Timeout      time.Duration
+		MaxAttempts  int
+		DisableAfter int
+	}
+	Notifications struct {
+		// Sender is "smtp" to send the emails, "file" to write them to
+		// FileDir, or "log" to only log them.
+		Sender string
+		From   string
+		// Language is the language of the emails, en or ru.
+		Language string
+		// ReminderBefore is how long before the start of an event its
+		// registrants are reminded of it, never when zero.
+		ReminderBefore time.Duration
+		PollInterval   time.Duration
+		MaxAttempts    int
+		FileDir        string
+		SMTP           struct {
+			Host     string
+			Port     string
+			Username string
+			Password string
+			Timeout  time.Duration
+		}
+	}
+	Jobs struct {
+		// Concurrency is the number of jobs a worker runs at the same time.
+		Concurrency  int
+		PollInterval time.Duration
+		// Retention is how long finished jobs and published outbox messages
+		// are kept.
+		Retention time.Duration
+		// PurgeSchedule is the cron expression purge jobs are enqueued on.
+		PurgeSchedule string
+	}
+	Scheduler struct {
+		// PollInterval is how often the instances that aren't leading the
+		// scheduler try to take over.
+		PollInterval time.Duration
+		// TimeZone is the IANA time zone the schedules are read in.
+		TimeZone string
+	}
+	Worker struct {
+		CommandStream     string
+		CreateSubject     string
+		CancelSubject     string
+		ResultSubject     string
+		DeadLetterSubject string
+		MaxDeliver        int
+	}
+	Idempotency struct {
+		TTL time.Duration
+		// RequestTimeout cancels the requests holding a key, a stalled
+		// request keeps its key a minute longer.
+		RequestTimeout time.Duration
+	}
+	RateLimit struct {
+		Enabled bool
+		// Store is "postgres" to share the limits between instances, or
+		// "memory" to keep them per instance.
+		Store string
+		// IP, Read and Write are written as <requests>/<period>, e.g. 30/1m.
+		// IP limits every address before authentication, Read and Write
+		// every authenticated client.
+		IP    string
+		Read  string
+		Write string
 	}
 	MockGRPC string
 }
@@ -58,8 +183,38 @@ func LoadConfig(ctx context.Context) *Config {
 
 	debug, _ := strconv.ParseBool(getEnv("DEBUG", "false"))
 	batchSize, _ := strconv.Atoi(getEnv("DB_BATCH_SIZE", "100"))
+	authDisabled, _ := strconv.ParseBool(getEnv("AUTH_DISABLED", "false"))
+	rateLimitEnabled, _ := strconv.ParseBool(getEnv("RATE_LIMIT_ENABLED", "true"))
+	idempotencyTTL, _ := time.ParseDuration(getEnv("IDEMPOTENCY_TTL", "24h"))
+	idempotencyRequestTimeout, _ := time.ParseDuration(getEnv("IDEMPOTENCY_REQUEST_TIMEOUT", "30s"))
+	natsPort, _ := strconv.Atoi(getEnv("NATS_PORT", "4222"))
+	outboxPollInterval, _ := time.ParseDuration(getEnv("OUTBOX_POLL_INTERVAL", "1s"))
+	workerMaxDeliver, _ := strconv.Atoi(getEnv("WORKER_MAX_DELIVER", "5"))
+	eventsTombstoneRetention, _ := time.ParseDuration(getEnv("EVENTS_TOMBSTONE_RETENTION", "720h"))
+	eventStreamHeartbeat, _ := time.ParseDuration(getEnv("EVENT_STREAM_HEARTBEAT", "15s"))
+	eventStreamBuffer, _ := strconv.Atoi(getEnv("EVENT_STREAM_BUFFER", "64"))
+	eventStreamReplayLimit, _ := strconv.Atoi(getEnv("EVENT_STREAM_REPLAY_LIMIT", "1000"))
+	seatAvailabilityHeartbeat, _ := time.ParseDuration(getEnv("SEAT_AVAILABILITY_HEARTBEAT", "30s"))
+	seatAvailabilityMaxConnections, _ := strconv.Atoi(getEnv("SEAT_AVAILABILITY_MAX_CONNECTIONS", "1000"))
+	webhookPollInterval, _ := time.ParseDuration(getEnv("WEBHOOK_POLL_INTERVAL", "1s"))
+	webhookTimeout, _ := time.ParseDuration(getEnv("WEBHOOK_TIMEOUT", "10s"))
+	webhookMaxAttempts, _ := strconv.Atoi(getEnv("WEBHOOK_MAX_ATTEMPTS", "10"))
+	webhookDisableAfter, _ := strconv.Atoi(getEnv("WEBHOOK_DISABLE_AFTER", "20"))
+	notificationsReminderBefore, _ := time.ParseDuration(getEnv("NOTIFICATIONS_REMINDER_BEFORE", "24h"))
+	notificationsPollInterval, _ := time.ParseDuration(getEnv("NOTIFICATIONS_POLL_INTERVAL", "5s"))
+	notificationsMaxAttempts, _ := strconv.Atoi(getEnv("NOTIFICATIONS_MAX_ATTEMPTS", "8"))
+	smtpTimeout, _ := time.ParseDuration(getEnv("SMTP_TIMEOUT", "30s"))
+	jobsConcurrency, _ := strconv.Atoi(getEnv("JOBS_CONCURRENCY", "4"))
+	jobsPollInterval, _ := time.ParseDuration(getEnv("JOBS_POLL_INTERVAL", "1s"))
+	jobsRetention, _ := time.ParseDuration(getEnv("JOBS_RETENTION", "168h"))
+	schedulerPollInterval, _ := time.ParseDuration(getEnv("SCHEDULER_POLL_INTERVAL", "15s"))
+	avanpostTimeout, _ := time.ParseDuration(getEnv("AVANPOST_TIMEOUT", "5s"))
+	avanpostMaxRetries, _ := strconv.Atoi(getEnv("AVANPOST_MAX_RETRIES", "3"))
+	avanpostRetryBackoff, _ := time.ParseDuration(getEnv("AVANPOST_RETRY_BACKOFF", "200ms"))
+	avanpostBreakerThreshold, _ := strconv.Atoi(getEnv("AVANPOST_BREAKER_THRESHOLD", "5"))
+	avanpostBreakerCooldown, _ := time.ParseDuration(getEnv("AVANPOST_BREAKER_COOLDOWN", "30s"))
 
-	return &Config{
+	cfg := &Config{
 		Env:   getEnv("APP_ENV", "dev"),
 		Debug: debug,
 		Url:   getEnv("APP_URL", ""),
@@ -79,6 +234,82 @@ func LoadConfig(ctx context.Context) *Config {
 			BatchSize: batchSize,
 		},
 	}
+	cfg.ExternalServices.Avanpost.Host = getEnv("AVANPOST_HOST", "")
+	cfg.ExternalServices.Avanpost.Port = getEnv("AVANPOST_PORT", "443")
+	cfg.ExternalServices.Avanpost.OnboardingGroupUuid = getEnv("AVANPOST_ONBOARDING_GROUP_UUID", "")
+	cfg.ExternalServices.Avanpost.Url = getEnv("AVANPOST_URL", fmt.Sprintf(
+		"https://%s:%s", cfg.ExternalServices.Avanpost.Host, cfg.ExternalServices.Avanpost.Port,
+	))
+	cfg.ExternalServices.Avanpost.ClientID = getEnv("AVANPOST_CLIENT_ID", "")
+	cfg.ExternalServices.Avanpost.ClientSecret = getEnv("AVANPOST_CLIENT_SECRET", "")
+	cfg.ExternalServices.Avanpost.TokenUrl = getEnv(
+		"AVANPOST_TOKEN_URL", cfg.ExternalServices.Avanpost.Url+"/oauth2/token",
+	)
+	cfg.ExternalServices.Avanpost.Timeout = avanpostTimeout
+	cfg.ExternalServices.Avanpost.MaxRetries = avanpostMaxRetries
+	cfg.ExternalServices.Avanpost.RetryBackoff = avanpostRetryBackoff
+	cfg.ExternalServices.Avanpost.BreakerThreshold = avanpostBreakerThreshold
+	cfg.ExternalServices.Avanpost.BreakerCooldown = avanpostBreakerCooldown
+	cfg.Auth.Disabled = authDisabled
+	cfg.Auth.Issuer = getEnv("AUTH_ISSUER", fmt.Sprintf(
+		"https://%s:%s", cfg.ExternalServices.Avanpost.Host, cfg.ExternalServices.Avanpost.Port,
+	))
+	cfg.Auth.Audience = getEnv("AUTH_AUDIENCE", "")
+	cfg.Auth.RolesClaim = getEnv("AUTH_ROLES_CLAIM", "roles")
+	cfg.Auth.TenantClaim = getEnv("AUTH_TENANT_CLAIM", "tenant_id")
+	cfg.Auth.PolicyFile = getEnv("AUTH_POLICY_FILE", "")
+	cfg.Tenant.Default = getEnv("TENANT_DEFAULT", "default")
+	cfg.Nats.Host = getEnv("NATS_HOST", "localhost")
+	cfg.Nats.Port = natsPort
+	cfg.Nats.JwtCredentialFilePath = getEnv("NATS_JWT_CREDENTIAL_FILE_PATH", "")
+	cfg.Nats.Stream = getEnv("NATS_STREAM", "EVENTS")
+	cfg.Idempotency.TTL = idempotencyTTL
+	cfg.Idempotency.RequestTimeout = idempotencyRequestTimeout
+	cfg.RateLimit.Enabled = rateLimitEnabled
+	cfg.RateLimit.Store = getEnv("RATE_LIMIT_STORE", "postgres")
+	cfg.RateLimit.IP = getEnv("RATE_LIMIT_IP", "600/1m")
+	cfg.RateLimit.Read = getEnv("RATE_LIMIT_READ", "300/1m")
+	cfg.RateLimit.Write = getEnv("RATE_LIMIT_WRITE", "30/1m")
+	cfg.Outbox.PollInterval = outboxPollInterval
+	cfg.Events.CompleteSchedule = getEnv("EVENTS_COMPLETE_SCHEDULE", "* * * * *")
+	cfg.Events.TombstoneRetention = eventsTombstoneRetention
+	cfg.Events.SearchLanguage = getEnv("EVENTS_SEARCH_LANGUAGE", "english")
+	cfg.EventStream.Heartbeat = eventStreamHeartbeat
+	cfg.EventStream.Buffer = eventStreamBuffer
+	cfg.EventStream.ReplayLimit = eventStreamReplayLimit
+	cfg.SeatAvailability.Heartbeat = seatAvailabilityHeartbeat
+	cfg.SeatAvailability.MaxConnections = seatAvailabilityMaxConnections
+	cfg.Webhooks.PollInterval = webhookPollInterval
+	cfg.Webhooks.Timeout = webhookTimeout
+	cfg.Webhooks.MaxAttempts = webhookMaxAttempts
+	cfg.Webhooks.DisableAfter = webhookDisableAfter
+	cfg.Notifications.Sender = getEnv("NOTIFICATIONS_SENDER", "log")
+	cfg.Notifications.From = getEnv("NOTIFICATIONS_FROM", "Online Registration <no-reply@localhost>")
+	cfg.Notifications.Language = getEnv("NOTIFICATIONS_LANGUAGE", "en")
+	cfg.Notifications.ReminderBefore = notificationsReminderBefore
+	cfg.Notifications.PollInterval = notificationsPollInterval
+	cfg.Notifications.MaxAttempts = notificationsMaxAttempts
+	cfg.Notifications.FileDir = getEnv("NOTIFICATIONS_FILE_DIR", "var/mail")
+	cfg.Notifications.SMTP.Host = getEnv("SMTP_HOST", "localhost")
+	cfg.Notifications.SMTP.Port = getEnv("SMTP_PORT", "25")
+	cfg.Notifications.SMTP.Username = getEnv("SMTP_USERNAME", "")
+	cfg.Notifications.SMTP.Password = getEnv("SMTP_PASSWORD", "")
+	cfg.Notifications.SMTP.Timeout = smtpTimeout
+	cfg.Jobs.Concurrency = jobsConcurrency
+	cfg.Jobs.PollInterval = jobsPollInterval
+	cfg.Jobs.Retention = jobsRetention
+	cfg.Jobs.PurgeSchedule = getEnv("JOBS_PURGE_SCHEDULE", "0 3 * * *")
+	cfg.Scheduler.PollInterval = schedulerPollInterval
+	cfg.Scheduler.TimeZone = getEnv("SCHEDULER_TIME_ZONE", "UTC")
+	cfg.Worker.CommandStream = getEnv("WORKER_COMMAND_STREAM", "EVENT_COMMANDS")
+	cfg.Worker.CreateSubject = getEnv("WORKER_CREATE_SUBJECT", "commands.events.create")
+	cfg.Worker.CancelSubject = getEnv("WORKER_CANCEL_SUBJECT", "commands.events.cancel")
+	cfg.Worker.ResultSubject = getEnv("WORKER_RESULT_SUBJECT", "commands.events.results")
+	cfg.Worker.DeadLetterSubject = getEnv("WORKER_DEAD_LETTER_SUBJECT", "commands.events.dead_letter")
+	cfg.Worker.MaxDeliver = workerMaxDeliver
+	cfg.MockGRPC = getEnv("MOCK_GRPC", "")
+
+	return cfg
 }
 
 func getEnv(key, defaultValue string) string {
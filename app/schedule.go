@@ -0,0 +1,37 @@
+package app
+
+import "sync"
+
+// ScheduledTask is a task registered with App.Schedule.
+type ScheduledTask struct {
+	Name string
+	// Spec is the cron expression the task runs on.
+	Spec string
+	Fn   HookFunc
+}
+
+type scheduledTasks struct {
+	mu    sync.Mutex
+	tasks []ScheduledTask
+}
+
+// Schedule registers fn to run on the cron expression spec. The tasks are
+// run by the scheduler of the worker, once per tick across every instance.
+func (app *App) Schedule(name, spec string, fn HookFunc) {
+	app.schedule.mu.Lock()
+	defer app.schedule.mu.Unlock()
+
+	app.schedule.tasks = append(app.schedule.tasks, ScheduledTask{
+		Name: name,
+		Spec: spec,
+		Fn:   fn,
+	})
+}
+
+// ScheduledTasks returns the tasks registered with Schedule.
+func (app *App) ScheduledTasks() []ScheduledTask {
+	app.schedule.mu.Lock()
+	defer app.schedule.mu.Unlock()
+
+	return append([]ScheduledTask(nil), app.schedule.tasks...)
+}
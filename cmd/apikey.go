@@ -0,0 +1,190 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"online-registration/app"
+	"online-registration/internal/interview/domain/policy"
+	"online-registration/internal/interview/domain/usecase"
+	"online-registration/internal/interview/infrastructure/db/repository"
+
+	"github.com/google/uuid"
+	"github.com/urfave/cli/v2"
+)
+
+var apiKeyCommand = &cli.Command{
+	Name:  "apikey",
+	Usage: "manage API keys for service-to-service access",
+	Subcommands: []*cli.Command{
+		{
+			Name:  "create",
+			Usage: "issue a new API key",
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:     "name",
+					Usage:    "name of the service using the key",
+					Required: true,
+				},
+				&cli.StringSliceFlag{
+					Name:     "scope",
+					Usage:    "scope granted to the key, e.g. events:read, can be repeated",
+					Required: true,
+				},
+				&cli.StringFlag{
+					Name:  "tenant",
+					Usage: "tenant the key is bound to, keys without one act on the default tenant",
+				},
+				&cli.DurationFlag{
+					Name:  "ttl",
+					Usage: "lifetime of the key, the key doesn't expire when 0",
+				},
+			},
+			Action: func(c *cli.Context) error {
+				ctx, apiKeys, stop, err := startAPIKeyUseCase(c)
+				if err != nil {
+					return err
+				}
+				defer stop()
+
+				key, err := apiKeys.Create(
+					ctx, c.String("name"), c.StringSlice("scope"), c.String("tenant"), c.Duration("ttl"),
+				)
+				if err != nil {
+					return err
+				}
+				printIssuedAPIKey(key)
+				return nil
+			},
+		},
+		{
+			Name:  "list",
+			Usage: "list API keys",
+			Action: func(c *cli.Context) error {
+				ctx, apiKeys, stop, err := startAPIKeyUseCase(c)
+				if err != nil {
+					return err
+				}
+				defer stop()
+
+				keys, err := apiKeys.List(ctx)
+				if err != nil {
+					return err
+				}
+
+				w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+				fmt.Fprintln(w, "ID\tNAME\tPREFIX\tSCOPES\tTENANT\tCREATED\tLAST USED\tEXPIRES\tREVOKED")
+				for _, key := range keys {
+					fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+						key.ID, key.Name, usecase.APIKeyPrefix+key.Prefix, strings.Join(key.Scopes, ","),
+						formatTenant(key.Tenant),
+						formatTime(&key.CreatedAt), formatTime(key.LastUsedAt),
+						formatTime(key.ExpiresAt), formatTime(key.RevokedAt),
+					)
+				}
+				return w.Flush()
+			},
+		},
+		{
+			Name:      "revoke",
+			Usage:     "revoke an API key immediately",
+			ArgsUsage: "<id>",
+			Action: func(c *cli.Context) error {
+				id, err := uuid.Parse(c.Args().First())
+				if err != nil {
+					return fmt.Errorf("invalid API key id %q", c.Args().First())
+				}
+
+				ctx, apiKeys, stop, err := startAPIKeyUseCase(c)
+				if err != nil {
+					return err
+				}
+				defer stop()
+
+				if err := apiKeys.Revoke(ctx, id); err != nil {
+					return err
+				}
+				fmt.Printf("revoked API key %s\n", id)
+				return nil
+			},
+		},
+		{
+			Name:      "rotate",
+			Usage:     "issue a replacement for an API key, the old key expires after a grace period",
+			ArgsUsage: "<id>",
+			Flags: []cli.Flag{
+				&cli.DurationFlag{
+					Name:  "grace",
+					Value: 24 * time.Hour,
+					Usage: "how long the old key keeps working",
+				},
+			},
+			Action: func(c *cli.Context) error {
+				id, err := uuid.Parse(c.Args().First())
+				if err != nil {
+					return fmt.Errorf("invalid API key id %q", c.Args().First())
+				}
+
+				ctx, apiKeys, stop, err := startAPIKeyUseCase(c)
+				if err != nil {
+					return err
+				}
+				defer stop()
+
+				key, err := apiKeys.Rotate(ctx, id, c.Duration("grace"))
+				if err != nil {
+					return err
+				}
+				printIssuedAPIKey(key)
+				fmt.Printf("the old key expires in %s\n", c.Duration("grace"))
+				return nil
+			},
+		},
+	},
+}
+
+func startAPIKeyUseCase(c *cli.Context) (ctx context.Context, apiKeys *usecase.APIKeyUseCase, stop func(), err error) {
+	ctx, appInstance, err := app.StartCLI(c)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	accessPolicy, err := policy.Load(appInstance.Config().Auth.PolicyFile)
+	if err != nil {
+		appInstance.Stop()
+		return nil, nil, nil, err
+	}
+
+	apiKeys = usecase.NewAPIKeyUseCase(repository.NewDBAPIKeyRepository(appInstance.DB()), accessPolicy)
+	return ctx, apiKeys, appInstance.Stop, nil
+}
+
+func printIssuedAPIKey(key *usecase.IssuedAPIKey) {
+	fmt.Printf("id:      %s\n", key.ID)
+	fmt.Printf("name:    %s\n", key.Name)
+	fmt.Printf("scopes:  %s\n", strings.Join(key.Scopes, ","))
+	fmt.Printf("tenant:  %s\n", formatTenant(key.Tenant))
+	if key.ExpiresAt != nil {
+		fmt.Printf("expires: %s\n", formatTime(key.ExpiresAt))
+	}
+	fmt.Printf("key:     %s\n", key.Key)
+	fmt.Println("store the key now, it can't be shown again")
+}
+
+func formatTime(t *time.Time) string {
+	if t == nil {
+		return "-"
+	}
+	return t.Local().Format(time.RFC3339)
+}
+
+func formatTenant(tenant string) string {
+	if tenant == "" {
+		return "*"
+	}
+	return tenant
+}
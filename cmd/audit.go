@@ -0,0 +1,174 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"time"
+
+	"online-registration/app"
+	"online-registration/internal/interview/domain/entity"
+	"online-registration/internal/interview/domain/policy"
+	"online-registration/internal/interview/domain/tenant"
+	"online-registration/internal/interview/domain/usecase"
+	"online-registration/internal/interview/infrastructure/db/repository"
+
+	"github.com/urfave/cli/v2"
+)
+
+var auditCommand = &cli.Command{
+	Name:  "audit",
+	Usage: "inspect the audit log of event changes",
+	Subcommands: []*cli.Command{
+		{
+			Name:  "export",
+			Usage: "write audit entries, oldest first, as JSON lines or CSV",
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:  "tenant",
+					Usage: "only export entries of this tenant, all tenants are exported when empty",
+				},
+				&cli.TimestampFlag{
+					Name:   "since",
+					Usage:  "only export entries written at or after this time, e.g. 2024-01-02T15:04:05Z",
+					Layout: time.RFC3339,
+				},
+				&cli.TimestampFlag{
+					Name:   "until",
+					Usage:  "only export entries written before this time",
+					Layout: time.RFC3339,
+				},
+				&cli.StringFlag{
+					Name:  "format",
+					Value: "jsonl",
+					Usage: "jsonl or csv",
+				},
+				&cli.StringFlag{
+					Name:  "output",
+					Usage: "file to write to, stdout when empty",
+				},
+			},
+			Action: func(c *cli.Context) error {
+				format := c.String("format")
+				if format != "jsonl" && format != "csv" {
+					return fmt.Errorf("unknown format %q, use jsonl or csv", format)
+				}
+
+				ctx, appInstance, err := app.StartCLI(c)
+				if err != nil {
+					return err
+				}
+				defer appInstance.Stop()
+
+				if tenantID := c.String("tenant"); tenantID != "" {
+					if !tenant.Valid(tenantID) {
+						return fmt.Errorf("invalid tenant %q", tenantID)
+					}
+					ctx = tenant.ContextWithTenant(ctx, tenantID)
+				} else {
+					ctx = tenant.ContextWithAllTenants(ctx)
+				}
+
+				accessPolicy, err := policy.Load(appInstance.Config().Auth.PolicyFile)
+				if err != nil {
+					return err
+				}
+
+				eventRepository, err := newEventRepository(appInstance)
+				if err != nil {
+					return err
+				}
+
+				auditLog := usecase.NewAuditLogUseCase(
+					eventRepository,
+					repository.NewDBAuditLogRepository(appInstance.DB()),
+					accessPolicy,
+				)
+
+				out := io.Writer(os.Stdout)
+				if path := c.String("output"); path != "" {
+					file, err := os.Create(path)
+					if err != nil {
+						return err
+					}
+					defer file.Close()
+					out = file
+				}
+
+				filter := entity.AuditLogFilter{}
+				if since := c.Timestamp("since"); since != nil {
+					filter.Since = *since
+				}
+				if until := c.Timestamp("until"); until != nil {
+					filter.Until = *until
+				}
+
+				writer, err := newAuditWriter(out, format)
+				if err != nil {
+					return err
+				}
+				exported, err := auditLog.Export(ctx, filter, writer.write)
+				if err != nil {
+					return err
+				}
+				if err := writer.flush(); err != nil {
+					return err
+				}
+
+				fmt.Fprintf(os.Stderr, "exported %d audit entries\n", exported)
+				return nil
+			},
+		},
+	},
+}
+
+// auditWriter writes audit entries in an export format.
+type auditWriter struct {
+	json *json.Encoder
+	csv  *csv.Writer
+}
+
+func newAuditWriter(out io.Writer, format string) (*auditWriter, error) {
+	if format == "jsonl" {
+		return &auditWriter{json: json.NewEncoder(out)}, nil
+	}
+
+	w := &auditWriter{csv: csv.NewWriter(out)}
+	err := w.csv.Write([]string{
+		"id", "tenant_id", "event_id", "action", "actor", "request_id", "changes", "created_at",
+	})
+	return w, err
+}
+
+func (w *auditWriter) write(entry *entity.EventAuditEntry) error {
+	if w.json != nil {
+		return w.json.Encode(entry)
+	}
+
+	changes, err := json.Marshal(entry.Changes)
+	if err != nil {
+		return err
+	}
+
+	return w.csv.Write([]string{
+		strconv.FormatInt(entry.ID, 10),
+		entry.TenantID,
+		entry.EventID.String(),
+		entry.Action,
+		entry.Actor,
+		entry.RequestID,
+		string(changes),
+		entry.CreatedAt.UTC().Format(time.RFC3339Nano),
+	})
+}
+
+func (w *auditWriter) flush() error {
+	if w.csv == nil {
+		return nil
+	}
+	w.csv.Flush()
+	return w.csv.Error()
+}
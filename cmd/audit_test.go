@@ -0,0 +1,96 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"online-registration/internal/interview/domain/entity"
+
+	"github.com/google/uuid"
+)
+
+func auditExportEntries() []*entity.EventAuditEntry {
+	eventID := uuid.MustParse("7f0c3a52-5d1e-4b8a-9a57-3f4d2c1b0e9f")
+	at := time.Date(2026, 3, 2, 9, 0, 0, 0, time.FixedZone("CET", 3600))
+	return []*entity.EventAuditEntry{
+		{
+			ID: 1, TenantID: "acme", EventID: eventID, Action: entity.AuditActionCreated, Actor: "bob",
+			RequestID: "r1", Changes: map[string]entity.AuditChange{"title": {To: "Onboarding, day one"}}, CreatedAt: at,
+		},
+		{
+			ID: 2, TenantID: "acme", EventID: eventID, Action: entity.AuditActionDeleted, Actor: "root",
+			Changes: map[string]entity.AuditChange{"title": {From: "Onboarding, day one"}}, CreatedAt: at.Add(time.Hour),
+		},
+	}
+}
+
+func TestAuditWriterCSV(t *testing.T) {
+	var out bytes.Buffer
+	writer, err := newAuditWriter(&out, "csv")
+	if err != nil {
+		t.Fatalf("newAuditWriter: %v", err)
+	}
+	for _, entry := range auditExportEntries() {
+		if err := writer.write(entry); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+	}
+	if err := writer.flush(); err != nil {
+		t.Fatalf("flush: %v", err)
+	}
+
+	records, err := csv.NewReader(&out).ReadAll()
+	if err != nil {
+		t.Fatalf("read CSV: %v", err)
+	}
+	want := [][]string{
+		{"id", "tenant_id", "event_id", "action", "actor", "request_id", "changes", "created_at"},
+		{"1", "acme", "7f0c3a52-5d1e-4b8a-9a57-3f4d2c1b0e9f", "created", "bob", "r1",
+			`{"title":{"from":null,"to":"Onboarding, day one"}}`, "2026-03-02T08:00:00Z"},
+		{"2", "acme", "7f0c3a52-5d1e-4b8a-9a57-3f4d2c1b0e9f", "deleted", "root", "",
+			`{"title":{"from":"Onboarding, day one","to":null}}`, "2026-03-02T09:00:00Z"},
+	}
+	if len(records) != len(want) {
+		t.Fatalf("wrote %d records, want %d", len(records), len(want))
+	}
+	for i := range want {
+		if strings.Join(records[i], "|") != strings.Join(want[i], "|") {
+			t.Errorf("record %d = %q, want %q", i, records[i], want[i])
+		}
+	}
+}
+
+func TestAuditWriterJSONLines(t *testing.T) {
+	var out bytes.Buffer
+	writer, err := newAuditWriter(&out, "jsonl")
+	if err != nil {
+		t.Fatalf("newAuditWriter: %v", err)
+	}
+	entries := auditExportEntries()
+	for _, entry := range entries {
+		if err := writer.write(entry); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+	}
+	if err := writer.flush(); err != nil {
+		t.Fatalf("flush: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+	if len(lines) != len(entries) {
+		t.Fatalf("wrote %d lines, want %d", len(lines), len(entries))
+	}
+	for i, line := range lines {
+		var entry entity.EventAuditEntry
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("line %d: %v", i, err)
+		}
+		if entry.ID != entries[i].ID || entry.Action != entries[i].Action || !entry.CreatedAt.Equal(entries[i].CreatedAt) {
+			t.Errorf("line %d = %s, want entry %d", i, line, entries[i].ID)
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"context"
+	"net/http"
+
+	"online-registration/app"
+	"online-registration/internal/interview/domain/policy"
+	"online-registration/internal/interview/domain/usecase"
+	"online-registration/internal/interview/infrastructure/db/repository"
+)
+
+// startEventStream starts fanning event changes out to the streaming clients
+// of srv. The streams end when srv shuts down, which would otherwise wait
+// for them, and the listener stops before the app closes the database.
+func startEventStream(
+	ctx context.Context, a *app.App, accessPolicy *policy.Policy, srv *http.Server,
+) *usecase.StreamEventChangesUseCase {
+	eventStream := usecase.NewStreamEventChangesUseCase(
+		repository.NewDBEventChangeRepository(a.DB()),
+		accessPolicy,
+		usecase.EventStreamConfig{
+			Buffer:      a.Config().EventStream.Buffer,
+			ReplayLimit: a.Config().EventStream.ReplayLimit,
+		},
+	)
+
+	ctx, stop := context.WithCancel(ctx)
+	srv.RegisterOnShutdown(stop)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		eventStream.Run(ctx)
+	}()
+
+	a.OnStop("events.stream", func(context.Context, *app.App) error {
+		stop()
+		<-done
+		return nil
+	})
+
+	return eventStream
+}
@@ -0,0 +1,184 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"online-registration/app"
+	"online-registration/internal/interview/domain/entity"
+	"online-registration/internal/interview/domain/tenant"
+	"online-registration/internal/interview/domain/usecase"
+	"online-registration/internal/interview/infrastructure/db/repository"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+	"github.com/urfave/cli/v2"
+)
+
+// registerJobWorkers starts running background jobs once the app is started.
+// The workers stop claiming jobs when the app context is cancelled, and the
+// app waits for the running jobs to finish when it stops.
+func registerJobWorkers() {
+	app.OnStart("jobs.workers", func(ctx context.Context, a *app.App) error {
+		cfg := a.Config()
+		jobRepository := repository.NewDBJobRepository(a.DB())
+
+		eventRepository, err := newEventRepository(a)
+		if err != nil {
+			return err
+		}
+
+		purge := usecase.NewPurgeUseCase(
+			repository.NewDBIdempotencyKeyRepository(a.DB()),
+			repository.NewDBOutboxRepository(a.DB()),
+			jobRepository,
+			eventRepository,
+			repository.NewDBRateLimitRepository(a.DB()),
+			usecase.PurgeConfig{
+				Retention:          cfg.Jobs.Retention,
+				TombstoneRetention: cfg.Events.TombstoneRetention,
+				BatchSize:          cfg.DB.BatchSize,
+				RateLimitRefill:    rateLimitRefill(a),
+			},
+		)
+
+		workers := usecase.NewProcessJobsUseCase(
+			jobRepository,
+			usecase.ProcessJobsConfig{
+				Concurrency:  cfg.Jobs.Concurrency,
+				PollInterval: cfg.Jobs.PollInterval,
+			},
+			purge.Handler(),
+		)
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			workers.Run(ctx)
+		}()
+
+		a.OnStop("jobs.workers", func(ctx context.Context, _ *app.App) error {
+			<-done
+			return nil
+		})
+
+		return nil
+	})
+}
+
+var jobsCommand = &cli.Command{
+	Name:  "jobs",
+	Usage: "inspect the background job queue",
+	Subcommands: []*cli.Command{
+		{
+			Name:  "list",
+			Usage: "list jobs, the newest first",
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:  "kind",
+					Usage: "only list jobs of the kind",
+				},
+				&cli.StringSliceFlag{
+					Name:  "status",
+					Usage: "only list jobs in the status, can be repeated",
+				},
+				&cli.IntFlag{
+					Name:  "limit",
+					Value: 50,
+					Usage: "number of jobs listed",
+				},
+			},
+			Action: func(c *cli.Context) error {
+				ctx, manageJobs, stop, err := startManageJobsUseCase(c)
+				if err != nil {
+					return err
+				}
+				defer stop()
+
+				list, err := manageJobs.List(ctx, entity.JobFilter{
+					Kind:     c.String("kind"),
+					Statuses: c.StringSlice("status"),
+					Limit:    c.Int("limit"),
+				})
+				if err != nil {
+					return err
+				}
+
+				w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+				fmt.Fprintln(w, "ID\tKIND\tTENANT\tSTATUS\tATTEMPTS\tRUN AT\tFINISHED\tLAST ERROR")
+				for _, job := range list {
+					fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d/%d\t%s\t%s\t%s\n",
+						job.ID, job.Kind, formatTenant(job.TenantID), job.Status,
+						job.Attempts, job.MaxAttempts,
+						formatTime(&job.RunAt), formatTime(job.FinishedAt), job.LastError,
+					)
+				}
+				return w.Flush()
+			},
+		},
+		{
+			Name:      "retry",
+			Usage:     "run a failed or cancelled job again",
+			ArgsUsage: "<id>",
+			Action: func(c *cli.Context) error {
+				id, err := uuid.Parse(c.Args().First())
+				if err != nil {
+					return fmt.Errorf("invalid job id %q", c.Args().First())
+				}
+
+				ctx, manageJobs, stop, err := startManageJobsUseCase(c)
+				if err != nil {
+					return err
+				}
+				defer stop()
+
+				job, err := manageJobs.Retry(ctx, id)
+				if err != nil {
+					return err
+				}
+				log.Info().Str("job_id", job.ID.String()).Str("kind", job.Kind).Msg("Job scheduled again")
+				return nil
+			},
+		},
+		{
+			Name:      "cancel",
+			Usage:     "cancel a pending job",
+			ArgsUsage: "<id>",
+			Action: func(c *cli.Context) error {
+				id, err := uuid.Parse(c.Args().First())
+				if err != nil {
+					return fmt.Errorf("invalid job id %q", c.Args().First())
+				}
+
+				ctx, manageJobs, stop, err := startManageJobsUseCase(c)
+				if err != nil {
+					return err
+				}
+				defer stop()
+
+				job, err := manageJobs.Cancel(ctx, id)
+				if err != nil {
+					return err
+				}
+				log.Info().Str("job_id", job.ID.String()).Str("kind", job.Kind).Msg("Job cancelled")
+				return nil
+			},
+		},
+	},
+}
+
+func startManageJobsUseCase(
+	c *cli.Context,
+) (ctx context.Context, manageJobs *usecase.ManageJobsUseCase, stop func(), err error) {
+	ctx, appInstance, err := app.StartCLI(c)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	// the queue holds the jobs of every tenant
+	ctx = tenant.ContextWithAllTenants(ctx)
+	manageJobs = usecase.NewManageJobsUseCase(repository.NewDBJobRepository(appInstance.DB()))
+	return ctx, manageJobs, appInstance.Stop, nil
+}
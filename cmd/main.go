@@ -5,7 +5,10 @@ import (
 	"online-registration/app"
 	"online-registration/cmd/migrations"
 	"online-registration/internal/interview/domain/handler"
+	"online-registration/internal/interview/domain/policy"
+	"online-registration/internal/interview/domain/tenant"
 	"online-registration/internal/interview/domain/usecase"
+	"online-registration/internal/interview/infrastructure/avanpost"
 	repository2 "online-registration/internal/interview/infrastructure/db/repository"
 
 	"fmt"
@@ -15,6 +18,8 @@ import (
 	"strings"
 	"syscall"
 	"time"
+	// event time zones must load where the system has no zone database
+	_ "time/tzdata"
 
 	"github.com/gin-gonic/gin"
 	"github.com/rs/zerolog/log"
@@ -41,6 +46,15 @@ func main() {
 		},
 		Commands: []*cli.Command{
 			httpCommand,
+			workerCommand,
+			avanpostFakeCommand,
+			smtpFakeCommand,
+			mockGRPCCommand,
+			apiKeyCommand,
+			auditCommand,
+			searchCommand,
+			jobsCommand,
+			openAPICommand,
 			newDBCommand(migrations.Migrations),
 		},
 	}
@@ -60,6 +74,8 @@ var httpCommand = &cli.Command{
 		},
 	},
 	Action: func(c *cli.Context) error {
+		registerOutboxRelay()
+
 		servicesAndDependencies, err := startAppAndServices(
 			c.Context,
 			c.String("env"),
@@ -69,25 +85,167 @@ var httpCommand = &cli.Command{
 		}
 		defer servicesAndDependencies.gracefulShutdown()
 
-		repository := repository2.NewDBEventRepository(servicesAndDependencies.app.DB())
-		usecase := usecase.NewCreateEventUseCase(
+		accessPolicy, err := policy.Load(servicesAndDependencies.app.Config().Auth.PolicyFile)
+		if err != nil {
+			return err
+		}
+
+		idempotencyHandler := handler.NewIdempotencyHandler(
+			usecase.NewIdempotencyUseCase(
+				repository2.NewDBIdempotencyKeyRepository(servicesAndDependencies.app.DB()),
+				servicesAndDependencies.app.Config().Idempotency.TTL,
+				servicesAndDependencies.app.Config().Idempotency.RequestTimeout,
+			),
+		)
+
+		repository, err := newEventRepository(servicesAndDependencies.app)
+		if err != nil {
+			return err
+		}
+		updateEventUseCase := usecase.NewUpdateEventUseCase(repository, accessPolicy)
+		deleteEventUseCase := usecase.NewDeleteEventUseCase(repository, accessPolicy)
+		createEventUseCase := usecase.NewCreateEventUseCase(
 			repository,
+			accessPolicy,
 		)
 
 		proxyHandlerInstance := handler.NewHandler(
-			usecase,
+			createEventUseCase,
+			updateEventUseCase,
+			deleteEventUseCase,
+			usecase.NewGetEventsUseCase(repository, accessPolicy),
+			usecase.NewEventLifecycleUseCase(
+				repository, accessPolicy, servicesAndDependencies.app.Config().DB.BatchSize,
+			),
 		)
 
-		router := gin.Default()
-		v1 := router.Group("/api/v1")
-		{
-			v1.POST("/events", proxyHandlerInstance.CreateEvent)
-		}
+		registrationHandler := handler.NewRegistrationHandler(
+			usecase.NewRegisterEventUseCase(
+				repository2.NewDBRegistrationRepository(servicesAndDependencies.app.DB()),
+				accessPolicy,
+			),
+		)
+
+		auditLogHandler := handler.NewAuditLogHandler(
+			usecase.NewAuditLogUseCase(
+				repository,
+				repository2.NewDBAuditLogRepository(servicesAndDependencies.app.DB()),
+				accessPolicy,
+			),
+		)
+
+		webhookHandler := handler.NewWebhookHandler(
+			usecase.NewManageWebhooksUseCase(
+				repository2.NewDBWebhookRepository(servicesAndDependencies.app.DB()),
+				accessPolicy,
+			),
+		)
+
+		participantRepository := repository2.NewDBParticipantRepository(servicesAndDependencies.app.DB())
+		participantHandler := handler.NewParticipantHandler(
+			usecase.NewEventParticipantsUseCase(repository, participantRepository, accessPolicy),
+		)
+		freeBusyHandler := handler.NewFreeBusyHandler(
+			usecase.NewFreeBusyUseCase(participantRepository, accessPolicy),
+		)
+
+		venueHandler := handler.NewVenueHandler(
+			usecase.NewManageVenuesUseCase(
+				repository2.NewDBVenueRepository(servicesAndDependencies.app.DB()),
+				accessPolicy,
+			),
+		)
+
+		tagHandler := handler.NewTagHandler(
+			usecase.NewManageTagsUseCase(
+				repository2.NewDBTagRepository(servicesAndDependencies.app.DB()),
+				accessPolicy,
+			),
+		)
+
+		scheduleHandler := handler.NewScheduleHandler(
+			usecase.NewScheduledTasksUseCase(
+				repository2.NewDBScheduledTaskRepository(servicesAndDependencies.app.DB()),
+				accessPolicy,
+			),
+		)
 
 		srv := &http.Server{
-			Addr:    c.String("addr"),
-			Handler: router,
+			Addr: c.String("addr"),
+		}
+		eventStreamHandler := handler.NewEventStreamHandler(
+			startEventStream(servicesAndDependencies.ctx, servicesAndDependencies.app, accessPolicy, srv),
+			servicesAndDependencies.app.Config().EventStream.Heartbeat,
+		)
+		seatAvailabilityHandler := handler.NewSeatAvailabilityHandler(
+			startSeatAvailability(servicesAndDependencies.ctx, servicesAndDependencies.app, accessPolicy, srv),
+			servicesAndDependencies.app.Config().SeatAvailability.Heartbeat,
+		)
+
+		openAPIHandler, err := handler.NewOpenAPIHandler(apiBasePath)
+		if err != nil {
+			return err
+		}
+
+		router := gin.Default()
+		router.Use(handler.RequestIDMiddleware())
+		router.GET("/openapi.json", openAPIHandler.Spec)
+		router.GET("/docs", openAPIHandler.Docs)
+		v1 := router.Group(apiBasePath)
+		var rateLimitHandler *handler.RateLimitHandler
+		if servicesAndDependencies.app.Config().RateLimit.Enabled {
+			rateLimitHandler, err = newRateLimitHandler(servicesAndDependencies.app)
+			if err != nil {
+				return err
+			}
+			// before authentication, so that floods of invalid credentials
+			// are limited too
+			v1.Use(rateLimitHandler.IPMiddleware())
+		}
+		if cfg := servicesAndDependencies.app.Config(); cfg.Auth.Disabled {
+			log.Warn().Msg("Authentication is disabled, the API is open to anyone")
+			v1.Use(handler.AnonymousMiddleware())
+		} else {
+			// tokens issued for any client of the issuer would be accepted
+			// without an audience, refuse to start instead
+			tokenVerifier, err := avanpost.NewTokenVerifier(avanpost.TokenVerifierConfig{
+				Issuer:      cfg.Auth.Issuer,
+				Audience:    cfg.Auth.Audience,
+				RolesClaim:  cfg.Auth.RolesClaim,
+				TenantClaim: cfg.Auth.TenantClaim,
+			})
+			if err != nil {
+				return fmt.Errorf("AUTH_ISSUER and AUTH_AUDIENCE are required unless AUTH_DISABLED is set: %w", err)
+			}
+			authHandler := handler.NewAuthHandler(
+				tokenVerifier,
+				usecase.NewAPIKeyUseCase(
+					repository2.NewDBAPIKeyRepository(servicesAndDependencies.app.DB()),
+					accessPolicy,
+				),
+			)
+			v1.Use(authHandler.Middleware())
 		}
+		v1.Use(handler.TenantMiddleware(servicesAndDependencies.app.Config().Tenant.Default, accessPolicy))
+		if rateLimitHandler != nil {
+			v1.Use(rateLimitHandler.Middleware())
+		}
+		registerAPIRoutes(v1, &apiHandlers{
+			events:           proxyHandlerInstance,
+			idempotency:      idempotencyHandler,
+			eventStream:      eventStreamHandler,
+			seatAvailability: seatAvailabilityHandler,
+			auditLog:         auditLogHandler,
+			registrations:    registrationHandler,
+			participants:     participantHandler,
+			freeBusy:         freeBusyHandler,
+			webhooks:         webhookHandler,
+			venues:           venueHandler,
+			tags:             tagHandler,
+			schedule:         scheduleHandler,
+		})
+
+		srv.Handler = router
 
 		go func() {
 			log.Info().
@@ -171,6 +329,8 @@ func newDBCommand(migrations *migrate.Migrations) *cli.Command {
 						return err
 					}
 					defer app.Stop()
+					// data migrations see the rows of every tenant
+					ctx = tenant.ContextWithAllTenants(ctx)
 					migrator := migrate.NewMigrator(app.DB(), migrations)
 					group, err := migrator.Migrate(ctx)
 					if err != nil {
@@ -193,6 +353,8 @@ func newDBCommand(migrations *migrate.Migrations) *cli.Command {
 						return err
 					}
 					defer app.Stop()
+					// data migrations see the rows of every tenant
+					ctx = tenant.ContextWithAllTenants(ctx)
 					migrator := migrate.NewMigrator(app.DB(), migrations)
 					group, err := migrator.Rollback(ctx)
 					if err != nil {
@@ -0,0 +1,37 @@
+package migrations
+
+import (
+	"context"
+
+	"github.com/uptrace/bun"
+)
+
+func init() {
+	Migrations.MustRegister(func(ctx context.Context, db *bun.DB) error {
+		_, err := db.ExecContext(ctx, `
+			CREATE TABLE "idempotency_keys" (
+				"key" TEXT NOT NULL PRIMARY KEY,
+				"fingerprint" TEXT NOT NULL,
+				"status_code" INTEGER,
+				"response_body" BYTEA,
+				"created_at" TIMESTAMPTZ NOT NULL DEFAULT current_timestamp,
+				"expires_at" TIMESTAMPTZ NOT NULL
+			)
+		`)
+		if err != nil {
+			return err
+		}
+
+		_, err = db.ExecContext(ctx, `
+			CREATE INDEX "idempotency_keys_expires_at_idx" ON "idempotency_keys" ("expires_at")
+		`)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	}, func(ctx context.Context, db *bun.DB) error {
+		_, err := db.ExecContext(ctx, `DROP TABLE IF EXISTS "idempotency_keys"`)
+		return err
+	})
+}
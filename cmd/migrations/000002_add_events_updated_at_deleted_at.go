@@ -0,0 +1,29 @@
+package migrations
+
+import (
+	"context"
+
+	"github.com/uptrace/bun"
+)
+
+func init() {
+	Migrations.MustRegister(func(ctx context.Context, db *bun.DB) error {
+		_, err := db.ExecContext(ctx, `
+			ALTER TABLE "events"
+				ADD COLUMN "updated_at" TIMESTAMPTZ NOT NULL DEFAULT current_timestamp,
+				ADD COLUMN "deleted_at" TIMESTAMPTZ
+		`)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	}, func(ctx context.Context, db *bun.DB) error {
+		_, err := db.ExecContext(ctx, `
+			ALTER TABLE "events"
+				DROP COLUMN IF EXISTS "updated_at",
+				DROP COLUMN IF EXISTS "deleted_at"
+		`)
+		return err
+	})
+}
@@ -0,0 +1,41 @@
+package migrations
+
+import (
+	"context"
+
+	"github.com/uptrace/bun"
+)
+
+func init() {
+	Migrations.MustRegister(func(ctx context.Context, db *bun.DB) error {
+		_, err := db.ExecContext(ctx, `
+			CREATE TABLE "outbox" (
+				"id" BIGSERIAL PRIMARY KEY,
+				"message_id" UUID NOT NULL UNIQUE,
+				"subject" TEXT NOT NULL,
+				"payload" JSONB NOT NULL,
+				"attempts" INTEGER NOT NULL DEFAULT 0,
+				"last_error" TEXT,
+				"created_at" TIMESTAMPTZ NOT NULL DEFAULT current_timestamp,
+				"next_attempt_at" TIMESTAMPTZ NOT NULL DEFAULT current_timestamp,
+				"published_at" TIMESTAMPTZ
+			)
+		`)
+		if err != nil {
+			return err
+		}
+
+		_, err = db.ExecContext(ctx, `
+			CREATE INDEX "outbox_pending_idx" ON "outbox" ("next_attempt_at")
+			WHERE "published_at" IS NULL
+		`)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	}, func(ctx context.Context, db *bun.DB) error {
+		_, err := db.ExecContext(ctx, `DROP TABLE IF EXISTS "outbox"`)
+		return err
+	})
+}
@@ -0,0 +1,81 @@
+package migrations
+
+import (
+	"context"
+
+	"github.com/uptrace/bun"
+)
+
+func init() {
+	Migrations.MustRegister(func(ctx context.Context, db *bun.DB) error {
+		_, err := db.ExecContext(ctx, `
+			CREATE TABLE "webhook_subscriptions" (
+				"id" UUID NOT NULL PRIMARY KEY,
+				"url" TEXT NOT NULL,
+				"secret" TEXT NOT NULL,
+				"event_types" TEXT[] NOT NULL,
+				"active" BOOLEAN NOT NULL DEFAULT TRUE,
+				"consecutive_failures" INTEGER NOT NULL DEFAULT 0,
+				"disabled_at" TIMESTAMPTZ,
+				"created_at" TIMESTAMPTZ NOT NULL DEFAULT current_timestamp,
+				"updated_at" TIMESTAMPTZ NOT NULL DEFAULT current_timestamp
+			)
+		`)
+		if err != nil {
+			return err
+		}
+
+		_, err = db.ExecContext(ctx, `
+			CREATE TABLE "webhook_deliveries" (
+				"id" UUID NOT NULL PRIMARY KEY,
+				"subscription_id" UUID NOT NULL REFERENCES "webhook_subscriptions" ("id") ON DELETE CASCADE,
+				"message_id" UUID NOT NULL,
+				"redelivery_of" UUID,
+				"event_type" TEXT NOT NULL,
+				"payload" JSONB NOT NULL,
+				"status" TEXT NOT NULL DEFAULT 'pending',
+				"attempts" INTEGER NOT NULL DEFAULT 0,
+				"last_status_code" INTEGER,
+				"last_error" TEXT,
+				"next_attempt_at" TIMESTAMPTZ NOT NULL DEFAULT current_timestamp,
+				"created_at" TIMESTAMPTZ NOT NULL DEFAULT current_timestamp,
+				"delivered_at" TIMESTAMPTZ
+			)
+		`)
+		if err != nil {
+			return err
+		}
+
+		_, err = db.ExecContext(ctx, `
+			CREATE UNIQUE INDEX "webhook_deliveries_message_idx" ON "webhook_deliveries" ("subscription_id", "message_id")
+			WHERE "redelivery_of" IS NULL
+		`)
+		if err != nil {
+			return err
+		}
+
+		_, err = db.ExecContext(ctx, `
+			CREATE INDEX "webhook_deliveries_pending_idx" ON "webhook_deliveries" ("next_attempt_at")
+			WHERE "status" = 'pending'
+		`)
+		if err != nil {
+			return err
+		}
+
+		_, err = db.ExecContext(ctx, `
+			CREATE INDEX "webhook_deliveries_subscription_idx" ON "webhook_deliveries" ("subscription_id", "created_at" DESC)
+		`)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	}, func(ctx context.Context, db *bun.DB) error {
+		_, err := db.ExecContext(ctx, `DROP TABLE IF EXISTS "webhook_deliveries"`)
+		if err != nil {
+			return err
+		}
+		_, err = db.ExecContext(ctx, `DROP TABLE IF EXISTS "webhook_subscriptions"`)
+		return err
+	})
+}
@@ -0,0 +1,21 @@
+package migrations
+
+import (
+	"context"
+
+	"github.com/uptrace/bun"
+)
+
+func init() {
+	Migrations.MustRegister(func(ctx context.Context, db *bun.DB) error {
+		_, err := db.ExecContext(ctx, `ALTER TABLE "events" ADD COLUMN "created_by" TEXT`)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	}, func(ctx context.Context, db *bun.DB) error {
+		_, err := db.ExecContext(ctx, `ALTER TABLE "events" DROP COLUMN IF EXISTS "created_by"`)
+		return err
+	})
+}
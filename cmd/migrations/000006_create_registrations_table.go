@@ -0,0 +1,56 @@
+package migrations
+
+import (
+	"context"
+
+	"github.com/uptrace/bun"
+)
+
+func init() {
+	Migrations.MustRegister(func(ctx context.Context, db *bun.DB) error {
+		_, err := db.ExecContext(ctx, `ALTER TABLE "events" ADD COLUMN "onboarding" BOOLEAN NOT NULL DEFAULT FALSE`)
+		if err != nil {
+			return err
+		}
+
+		_, err = db.ExecContext(ctx, `
+			CREATE TABLE "registrations" (
+				"id" UUID NOT NULL PRIMARY KEY,
+				"event_id" UUID NOT NULL REFERENCES "events" ("id"),
+				"user_id" TEXT NOT NULL,
+				"email" TEXT,
+				"status" TEXT NOT NULL DEFAULT 'registered',
+				"created_at" TIMESTAMPTZ NOT NULL DEFAULT current_timestamp,
+				"cancelled_at" TIMESTAMPTZ
+			)
+		`)
+		if err != nil {
+			return err
+		}
+
+		_, err = db.ExecContext(ctx, `
+			CREATE UNIQUE INDEX "registrations_active_idx" ON "registrations" ("event_id", "user_id")
+			WHERE "status" = 'registered'
+		`)
+		if err != nil {
+			return err
+		}
+
+		_, err = db.ExecContext(ctx, `
+			CREATE INDEX "registrations_user_idx" ON "registrations" ("user_id")
+			WHERE "status" = 'registered'
+		`)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	}, func(ctx context.Context, db *bun.DB) error {
+		_, err := db.ExecContext(ctx, `DROP TABLE IF EXISTS "registrations"`)
+		if err != nil {
+			return err
+		}
+		_, err = db.ExecContext(ctx, `ALTER TABLE "events" DROP COLUMN IF EXISTS "onboarding"`)
+		return err
+	})
+}
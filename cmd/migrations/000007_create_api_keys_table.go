@@ -0,0 +1,33 @@
+package migrations
+
+import (
+	"context"
+
+	"github.com/uptrace/bun"
+)
+
+func init() {
+	Migrations.MustRegister(func(ctx context.Context, db *bun.DB) error {
+		_, err := db.ExecContext(ctx, `
+			CREATE TABLE "api_keys" (
+				"id" UUID NOT NULL PRIMARY KEY,
+				"name" TEXT NOT NULL,
+				"prefix" TEXT NOT NULL UNIQUE,
+				"key_hash" TEXT NOT NULL,
+				"scopes" TEXT[] NOT NULL,
+				"created_at" TIMESTAMPTZ NOT NULL DEFAULT current_timestamp,
+				"last_used_at" TIMESTAMPTZ,
+				"expires_at" TIMESTAMPTZ,
+				"revoked_at" TIMESTAMPTZ
+			)
+		`)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	}, func(ctx context.Context, db *bun.DB) error {
+		_, err := db.ExecContext(ctx, `DROP TABLE IF EXISTS "api_keys"`)
+		return err
+	})
+}
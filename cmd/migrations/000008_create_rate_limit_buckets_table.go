@@ -0,0 +1,30 @@
+package migrations
+
+import (
+	"context"
+
+	"github.com/uptrace/bun"
+)
+
+func init() {
+	Migrations.MustRegister(func(ctx context.Context, db *bun.DB) error {
+		// losing the buckets on a crash only resets the limits, so the table
+		// skips the WAL
+		_, err := db.ExecContext(ctx, `
+			CREATE UNLOGGED TABLE "rate_limit_buckets" (
+				"key" TEXT NOT NULL PRIMARY KEY,
+				"tokens" DOUBLE PRECISION NOT NULL,
+				"allowed" BOOLEAN NOT NULL,
+				"updated_at" TIMESTAMPTZ NOT NULL DEFAULT current_timestamp
+			)
+		`)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	}, func(ctx context.Context, db *bun.DB) error {
+		_, err := db.ExecContext(ctx, `DROP TABLE IF EXISTS "rate_limit_buckets"`)
+		return err
+	})
+}
@@ -0,0 +1,59 @@
+package migrations
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/uptrace/bun"
+)
+
+// tenantTables are the tables whose rows belong to a tenant.
+var tenantTables = []string{"events", "registrations", "webhook_subscriptions"}
+
+func init() {
+	Migrations.MustRegister(func(ctx context.Context, db *bun.DB) error {
+		// existing rows go to the "default" tenant, the default of
+		// TENANT_DEFAULT
+		for _, table := range tenantTables {
+			_, err := db.ExecContext(ctx, fmt.Sprintf(
+				`ALTER TABLE %q ADD COLUMN "tenant_id" TEXT NOT NULL DEFAULT 'default'`, table,
+			))
+			if err != nil {
+				return err
+			}
+
+			_, err = db.ExecContext(ctx, fmt.Sprintf(`ALTER TABLE %q ALTER COLUMN "tenant_id" DROP DEFAULT`, table))
+			if err != nil {
+				return err
+			}
+
+			_, err = db.ExecContext(ctx, fmt.Sprintf(
+				`CREATE INDEX %q ON %q ("tenant_id")`, table+"_tenant_idx", table,
+			))
+			if err != nil {
+				return err
+			}
+		}
+
+		_, err := db.ExecContext(ctx, `ALTER TABLE "api_keys" ADD COLUMN "tenant_id" TEXT`)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	}, func(ctx context.Context, db *bun.DB) error {
+		_, err := db.ExecContext(ctx, `ALTER TABLE "api_keys" DROP COLUMN IF EXISTS "tenant_id"`)
+		if err != nil {
+			return err
+		}
+
+		for _, table := range tenantTables {
+			_, err := db.ExecContext(ctx, fmt.Sprintf(`ALTER TABLE %q DROP COLUMN IF EXISTS "tenant_id"`, table))
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
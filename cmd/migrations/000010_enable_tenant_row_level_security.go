@@ -0,0 +1,61 @@
+package migrations
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/uptrace/bun"
+)
+
+// The row level security policies back up the tenant scoping of the
+// repositories. Transactions that set app.tenant_id only see and write rows
+// of that tenant. Connections that don't set it, like the background workers
+// that serve every tenant, are not restricted. Superusers bypass the policies,
+// so the service has to connect as a regular role for them to apply.
+func init() {
+	Migrations.MustRegister(func(ctx context.Context, db *bun.DB) error {
+		for _, table := range tenantTables {
+			_, err := db.ExecContext(ctx, fmt.Sprintf(`ALTER TABLE %q ENABLE ROW LEVEL SECURITY`, table))
+			if err != nil {
+				return err
+			}
+
+			// the service usually owns the tables, owners are exempt unless
+			// the policies are forced
+			_, err = db.ExecContext(ctx, fmt.Sprintf(`ALTER TABLE %q FORCE ROW LEVEL SECURITY`, table))
+			if err != nil {
+				return err
+			}
+
+			_, err = db.ExecContext(ctx, fmt.Sprintf(`
+				CREATE POLICY "tenant_isolation" ON %q
+				USING (COALESCE(current_setting('app.tenant_id', true), '') IN ('', "tenant_id"))
+				WITH CHECK (COALESCE(current_setting('app.tenant_id', true), '') IN ('', "tenant_id"))
+			`, table))
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}, func(ctx context.Context, db *bun.DB) error {
+		for _, table := range tenantTables {
+			_, err := db.ExecContext(ctx, fmt.Sprintf(`DROP POLICY IF EXISTS "tenant_isolation" ON %q`, table))
+			if err != nil {
+				return err
+			}
+
+			_, err = db.ExecContext(ctx, fmt.Sprintf(`ALTER TABLE %q NO FORCE ROW LEVEL SECURITY`, table))
+			if err != nil {
+				return err
+			}
+
+			_, err = db.ExecContext(ctx, fmt.Sprintf(`ALTER TABLE %q DISABLE ROW LEVEL SECURITY`, table))
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
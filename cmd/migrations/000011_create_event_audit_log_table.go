@@ -0,0 +1,97 @@
+package migrations
+
+import (
+	"context"
+
+	"github.com/uptrace/bun"
+)
+
+func init() {
+	Migrations.MustRegister(func(ctx context.Context, db *bun.DB) error {
+		// no foreign key to events, the history outlives the event
+		_, err := db.ExecContext(ctx, `
+			CREATE TABLE "event_audit_log" (
+				"id" BIGSERIAL PRIMARY KEY,
+				"tenant_id" TEXT NOT NULL,
+				"event_id" UUID NOT NULL,
+				"action" TEXT NOT NULL,
+				"actor" TEXT NOT NULL,
+				"request_id" TEXT,
+				"changes" JSONB NOT NULL,
+				"created_at" TIMESTAMPTZ NOT NULL DEFAULT current_timestamp
+			)
+		`)
+		if err != nil {
+			return err
+		}
+
+		_, err = db.ExecContext(ctx, `CREATE INDEX "event_audit_log_event_idx" ON "event_audit_log" ("event_id", "id")`)
+		if err != nil {
+			return err
+		}
+
+		_, err = db.ExecContext(ctx, `CREATE INDEX "event_audit_log_created_at_idx" ON "event_audit_log" ("created_at")`)
+		if err != nil {
+			return err
+		}
+
+		// the log is append-only, even for the owner of the table
+		_, err = db.ExecContext(ctx, `
+			CREATE FUNCTION "event_audit_log_append_only"() RETURNS trigger AS $$
+			BEGIN
+				RAISE EXCEPTION 'event_audit_log is append-only';
+			END
+			$$ LANGUAGE plpgsql
+		`)
+		if err != nil {
+			return err
+		}
+
+		_, err = db.ExecContext(ctx, `
+			CREATE TRIGGER "event_audit_log_append_only"
+			BEFORE UPDATE OR DELETE ON "event_audit_log"
+			FOR EACH ROW EXECUTE FUNCTION "event_audit_log_append_only"()
+		`)
+		if err != nil {
+			return err
+		}
+
+		_, err = db.ExecContext(ctx, `
+			CREATE TRIGGER "event_audit_log_no_truncate"
+			BEFORE TRUNCATE ON "event_audit_log"
+			FOR EACH STATEMENT EXECUTE FUNCTION "event_audit_log_append_only"()
+		`)
+		if err != nil {
+			return err
+		}
+
+		// same row level security as the other tenant scoped tables
+		_, err = db.ExecContext(ctx, `ALTER TABLE "event_audit_log" ENABLE ROW LEVEL SECURITY`)
+		if err != nil {
+			return err
+		}
+
+		_, err = db.ExecContext(ctx, `ALTER TABLE "event_audit_log" FORCE ROW LEVEL SECURITY`)
+		if err != nil {
+			return err
+		}
+
+		_, err = db.ExecContext(ctx, `
+			CREATE POLICY "tenant_isolation" ON "event_audit_log"
+			USING (COALESCE(current_setting('app.tenant_id', true), '') IN ('', "tenant_id"))
+			WITH CHECK (COALESCE(current_setting('app.tenant_id', true), '') IN ('', "tenant_id"))
+		`)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	}, func(ctx context.Context, db *bun.DB) error {
+		_, err := db.ExecContext(ctx, `DROP TABLE IF EXISTS "event_audit_log"`)
+		if err != nil {
+			return err
+		}
+		_, err = db.ExecContext(ctx, `DROP FUNCTION IF EXISTS "event_audit_log_append_only"()`)
+		return err
+	})
+}
@@ -0,0 +1,42 @@
+package migrations
+
+import (
+	"context"
+
+	"github.com/uptrace/bun"
+)
+
+func init() {
+	Migrations.MustRegister(func(ctx context.Context, db *bun.DB) error {
+		// events created before the lifecycle existed were live right away
+		_, err := db.ExecContext(ctx, `
+			ALTER TABLE "events"
+			ADD COLUMN "status" TEXT NOT NULL DEFAULT 'published'
+			CONSTRAINT "events_status_check" CHECK ("status" IN ('draft', 'published', 'cancelled', 'completed')),
+			ADD COLUMN "cancellation_reason" TEXT
+		`)
+		if err != nil {
+			return err
+		}
+
+		_, err = db.ExecContext(ctx, `ALTER TABLE "events" ALTER COLUMN "status" SET DEFAULT 'draft'`)
+		if err != nil {
+			return err
+		}
+
+		_, err = db.ExecContext(ctx, `
+			CREATE INDEX "events_published_end_time_idx" ON "events" ("end_time")
+			WHERE "status" = 'published' AND "deleted_at" IS NULL
+		`)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	}, func(ctx context.Context, db *bun.DB) error {
+		_, err := db.ExecContext(ctx, `
+			ALTER TABLE "events" DROP COLUMN IF EXISTS "status", DROP COLUMN IF EXISTS "cancellation_reason"
+		`)
+		return err
+	})
+}
@@ -0,0 +1,99 @@
+package migrations
+
+import (
+	"context"
+
+	"github.com/uptrace/bun"
+)
+
+func init() {
+	Migrations.MustRegister(func(ctx context.Context, db *bun.DB) error {
+		_, err := db.ExecContext(ctx, `
+			CREATE TABLE "venues" (
+				"id" UUID NOT NULL PRIMARY KEY,
+				"tenant_id" TEXT NOT NULL,
+				"name" TEXT NOT NULL,
+				"address" TEXT,
+				"created_at" TIMESTAMPTZ NOT NULL DEFAULT current_timestamp,
+				"updated_at" TIMESTAMPTZ NOT NULL DEFAULT current_timestamp
+			)
+		`)
+		if err != nil {
+			return err
+		}
+
+		_, err = db.ExecContext(ctx, `CREATE INDEX "venues_tenant_idx" ON "venues" ("tenant_id")`)
+		if err != nil {
+			return err
+		}
+
+		_, err = db.ExecContext(ctx, `
+			CREATE TABLE "rooms" (
+				"id" UUID NOT NULL PRIMARY KEY,
+				"tenant_id" TEXT NOT NULL,
+				"venue_id" UUID NOT NULL,
+				"name" TEXT NOT NULL,
+				"capacity" INTEGER CHECK ("capacity" >= 0),
+				"created_at" TIMESTAMPTZ NOT NULL DEFAULT current_timestamp,
+				"updated_at" TIMESTAMPTZ NOT NULL DEFAULT current_timestamp,
+				CONSTRAINT "rooms_venue_id_fkey" FOREIGN KEY ("venue_id") REFERENCES "venues" ("id"),
+				CONSTRAINT "rooms_venue_name_key" UNIQUE ("venue_id", "name")
+			)
+		`)
+		if err != nil {
+			return err
+		}
+
+		_, err = db.ExecContext(ctx, `CREATE INDEX "rooms_tenant_idx" ON "rooms" ("tenant_id")`)
+		if err != nil {
+			return err
+		}
+
+		// past and deleted events keep their history when a room is removed
+		_, err = db.ExecContext(ctx, `
+			ALTER TABLE "events" ADD COLUMN "room_id" UUID REFERENCES "rooms" ("id") ON DELETE SET NULL
+		`)
+		if err != nil {
+			return err
+		}
+
+		_, err = db.ExecContext(ctx, `CREATE EXTENSION IF NOT EXISTS "btree_gist"`)
+		if err != nil {
+			return err
+		}
+
+		// a room holds one live event at a time, events that only touch
+		// each other don't overlap
+		_, err = db.ExecContext(ctx, `
+			ALTER TABLE "events" ADD CONSTRAINT "events_room_overlap_excl"
+			EXCLUDE USING gist ("room_id" WITH =, tstzrange("start_time", "end_time", '[)') WITH &&)
+			WHERE ("room_id" IS NOT NULL AND "deleted_at" IS NULL AND "status" <> 'cancelled')
+		`)
+		if err != nil {
+			return err
+		}
+
+		for _, table := range []string{"venues", "rooms"} {
+			if err := enableTenantRowLevelSecurity(ctx, db, table); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}, func(ctx context.Context, db *bun.DB) error {
+		_, err := db.ExecContext(ctx, `
+			ALTER TABLE "events" DROP CONSTRAINT IF EXISTS "events_room_overlap_excl", DROP COLUMN IF EXISTS "room_id"
+		`)
+		if err != nil {
+			return err
+		}
+
+		_, err = db.ExecContext(ctx, `DROP TABLE IF EXISTS "rooms"`)
+		if err != nil {
+			return err
+		}
+
+		_, err = db.ExecContext(ctx, `DROP TABLE IF EXISTS "venues"`)
+		return err
+	})
+}
@@ -0,0 +1,52 @@
+package migrations
+
+import (
+	"context"
+
+	"github.com/uptrace/bun"
+)
+
+func init() {
+	Migrations.MustRegister(func(ctx context.Context, db *bun.DB) error {
+		_, err := db.ExecContext(ctx, `
+			CREATE TABLE "event_participants" (
+				"event_id" UUID NOT NULL REFERENCES "events" ("id"),
+				"tenant_id" TEXT NOT NULL,
+				"user_id" TEXT NOT NULL,
+				"role" TEXT NOT NULL
+					CONSTRAINT "event_participants_role_check" CHECK ("role" IN ('organizer', 'interviewer')),
+				"created_at" TIMESTAMPTZ NOT NULL DEFAULT current_timestamp,
+				PRIMARY KEY ("event_id", "user_id")
+			)
+		`)
+		if err != nil {
+			return err
+		}
+
+		// free/busy looks people up across their events
+		_, err = db.ExecContext(ctx, `
+			CREATE INDEX "event_participants_user_idx" ON "event_participants" ("tenant_id", "user_id")
+		`)
+		if err != nil {
+			return err
+		}
+
+		_, err = db.ExecContext(ctx, `
+			CREATE INDEX "registrations_user_idx" ON "registrations" ("tenant_id", "user_id")
+			WHERE "status" = 'registered'
+		`)
+		if err != nil {
+			return err
+		}
+
+		return enableTenantRowLevelSecurity(ctx, db, "event_participants")
+	}, func(ctx context.Context, db *bun.DB) error {
+		_, err := db.ExecContext(ctx, `DROP INDEX IF EXISTS "registrations_user_idx"`)
+		if err != nil {
+			return err
+		}
+
+		_, err = db.ExecContext(ctx, `DROP TABLE IF EXISTS "event_participants"`)
+		return err
+	})
+}
@@ -0,0 +1,23 @@
+package migrations
+
+import (
+	"context"
+
+	"github.com/uptrace/bun"
+)
+
+func init() {
+	Migrations.MustRegister(func(ctx context.Context, db *bun.DB) error {
+		_, err := db.ExecContext(ctx, `
+			ALTER TABLE "events"
+			ADD COLUMN "time_zone" TEXT NOT NULL DEFAULT 'UTC',
+			ADD COLUMN "all_day" BOOLEAN NOT NULL DEFAULT false
+		`)
+		return err
+	}, func(ctx context.Context, db *bun.DB) error {
+		_, err := db.ExecContext(ctx, `
+			ALTER TABLE "events" DROP COLUMN IF EXISTS "all_day", DROP COLUMN IF EXISTS "time_zone"
+		`)
+		return err
+	})
+}
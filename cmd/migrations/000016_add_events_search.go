@@ -0,0 +1,70 @@
+package migrations
+
+import (
+	"context"
+
+	"github.com/uptrace/bun"
+)
+
+func init() {
+	Migrations.MustRegister(func(ctx context.Context, db *bun.DB) error {
+		// the text search configuration is kept per row, so that rows stay
+		// searchable while a deployment is reindexed in another language
+		_, err := db.ExecContext(ctx, `
+			ALTER TABLE "events"
+			ADD COLUMN "search_language" REGCONFIG NOT NULL DEFAULT 'english',
+			ADD COLUMN "search_vector" TSVECTOR
+		`)
+		if err != nil {
+			return err
+		}
+
+		// titles rank above descriptions
+		_, err = db.ExecContext(ctx, `
+			CREATE FUNCTION "events_search_vector"() RETURNS TRIGGER AS $$
+			BEGIN
+				NEW."search_vector" :=
+					setweight(to_tsvector(NEW."search_language", coalesce(NEW."title", '')), 'A') ||
+					setweight(to_tsvector(NEW."search_language", coalesce(NEW."description", '')), 'B');
+				RETURN NEW;
+			END
+			$$ LANGUAGE plpgsql
+		`)
+		if err != nil {
+			return err
+		}
+
+		_, err = db.ExecContext(ctx, `
+			CREATE TRIGGER "events_search_vector_update"
+			BEFORE INSERT OR UPDATE OF "title", "description", "search_language" ON "events"
+			FOR EACH ROW EXECUTE FUNCTION "events_search_vector"()
+		`)
+		if err != nil {
+			return err
+		}
+
+		// fires the trigger for the existing rows
+		_, err = db.ExecContext(ctx, `UPDATE "events" SET "search_language" = "search_language"`)
+		if err != nil {
+			return err
+		}
+
+		_, err = db.ExecContext(ctx, `CREATE INDEX "events_search_idx" ON "events" USING gin ("search_vector")`)
+		return err
+	}, func(ctx context.Context, db *bun.DB) error {
+		_, err := db.ExecContext(ctx, `DROP TRIGGER IF EXISTS "events_search_vector_update" ON "events"`)
+		if err != nil {
+			return err
+		}
+
+		_, err = db.ExecContext(ctx, `DROP FUNCTION IF EXISTS "events_search_vector"()`)
+		if err != nil {
+			return err
+		}
+
+		_, err = db.ExecContext(ctx, `
+			ALTER TABLE "events" DROP COLUMN IF EXISTS "search_vector", DROP COLUMN IF EXISTS "search_language"
+		`)
+		return err
+	})
+}
@@ -0,0 +1,96 @@
+package migrations
+
+import (
+	"context"
+
+	"github.com/uptrace/bun"
+)
+
+func init() {
+	Migrations.MustRegister(func(ctx context.Context, db *bun.DB) error {
+		_, err := db.ExecContext(ctx, `
+			CREATE TABLE "categories" (
+				"id" UUID NOT NULL PRIMARY KEY,
+				"tenant_id" TEXT NOT NULL,
+				"name" TEXT NOT NULL,
+				"created_at" TIMESTAMPTZ NOT NULL DEFAULT current_timestamp,
+				"updated_at" TIMESTAMPTZ NOT NULL DEFAULT current_timestamp,
+				CONSTRAINT "categories_tenant_name_key" UNIQUE ("tenant_id", "name")
+			)
+		`)
+		if err != nil {
+			return err
+		}
+
+		_, err = db.ExecContext(ctx, `
+			CREATE TABLE "tags" (
+				"id" UUID NOT NULL PRIMARY KEY,
+				"tenant_id" TEXT NOT NULL,
+				"name" TEXT NOT NULL,
+				"created_at" TIMESTAMPTZ NOT NULL DEFAULT current_timestamp,
+				"updated_at" TIMESTAMPTZ NOT NULL DEFAULT current_timestamp,
+				CONSTRAINT "tags_tenant_name_key" UNIQUE ("tenant_id", "name")
+			)
+		`)
+		if err != nil {
+			return err
+		}
+
+		// events leave a category when it is removed
+		_, err = db.ExecContext(ctx, `
+			ALTER TABLE "events" ADD COLUMN "category_id" UUID REFERENCES "categories" ("id") ON DELETE SET NULL
+		`)
+		if err != nil {
+			return err
+		}
+
+		_, err = db.ExecContext(ctx, `CREATE INDEX "events_category_idx" ON "events" ("category_id")`)
+		if err != nil {
+			return err
+		}
+
+		_, err = db.ExecContext(ctx, `
+			CREATE TABLE "event_tags" (
+				"event_id" UUID NOT NULL REFERENCES "events" ("id"),
+				"tag_id" UUID NOT NULL REFERENCES "tags" ("id") ON DELETE CASCADE,
+				"tenant_id" TEXT NOT NULL,
+				PRIMARY KEY ("event_id", "tag_id")
+			)
+		`)
+		if err != nil {
+			return err
+		}
+
+		// tag filters and facets go from the tags to their events
+		_, err = db.ExecContext(ctx, `CREATE INDEX "event_tags_tag_idx" ON "event_tags" ("tag_id", "event_id")`)
+		if err != nil {
+			return err
+		}
+
+		for _, table := range []string{"categories", "tags", "event_tags"} {
+			if err := enableTenantRowLevelSecurity(ctx, db, table); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}, func(ctx context.Context, db *bun.DB) error {
+		_, err := db.ExecContext(ctx, `DROP TABLE IF EXISTS "event_tags"`)
+		if err != nil {
+			return err
+		}
+
+		_, err = db.ExecContext(ctx, `ALTER TABLE "events" DROP COLUMN IF EXISTS "category_id"`)
+		if err != nil {
+			return err
+		}
+
+		_, err = db.ExecContext(ctx, `DROP TABLE IF EXISTS "tags"`)
+		if err != nil {
+			return err
+		}
+
+		_, err = db.ExecContext(ctx, `DROP TABLE IF EXISTS "categories"`)
+		return err
+	})
+}
@@ -0,0 +1,62 @@
+package migrations
+
+import (
+	"context"
+
+	"github.com/uptrace/bun"
+)
+
+func init() {
+	Migrations.MustRegister(func(ctx context.Context, db *bun.DB) error {
+		_, err := db.ExecContext(ctx, `
+			CREATE TABLE "notifications" (
+				"id" UUID NOT NULL PRIMARY KEY,
+				"tenant_id" TEXT NOT NULL,
+				"kind" TEXT NOT NULL,
+				"registration_id" UUID NOT NULL REFERENCES "registrations" ("id"),
+				"event_id" UUID NOT NULL REFERENCES "events" ("id"),
+				"recipient" TEXT NOT NULL,
+				"dedupe_key" TEXT NOT NULL,
+				"status" TEXT NOT NULL DEFAULT 'pending',
+				"attempts" INTEGER NOT NULL DEFAULT 0,
+				"last_error" TEXT,
+				"send_at" TIMESTAMPTZ NOT NULL DEFAULT current_timestamp,
+				"created_at" TIMESTAMPTZ NOT NULL DEFAULT current_timestamp,
+				"sent_at" TIMESTAMPTZ,
+				CONSTRAINT "notifications_dedupe_key" UNIQUE ("dedupe_key")
+			)
+		`)
+		if err != nil {
+			return err
+		}
+
+		_, err = db.ExecContext(ctx, `
+			CREATE INDEX "notifications_pending_idx" ON "notifications" ("send_at")
+			WHERE "status" = 'pending'
+		`)
+		if err != nil {
+			return err
+		}
+
+		// reminders are moved and cancelled with their event or registration
+		_, err = db.ExecContext(ctx, `
+			CREATE INDEX "notifications_event_idx" ON "notifications" ("event_id")
+			WHERE "status" = 'pending'
+		`)
+		if err != nil {
+			return err
+		}
+
+		_, err = db.ExecContext(ctx, `
+			CREATE INDEX "notifications_registration_idx" ON "notifications" ("registration_id")
+		`)
+		if err != nil {
+			return err
+		}
+
+		return enableTenantRowLevelSecurity(ctx, db, "notifications")
+	}, func(ctx context.Context, db *bun.DB) error {
+		_, err := db.ExecContext(ctx, `DROP TABLE IF EXISTS "notifications"`)
+		return err
+	})
+}
@@ -0,0 +1,72 @@
+package migrations
+
+import (
+	"context"
+
+	"github.com/uptrace/bun"
+)
+
+func init() {
+	Migrations.MustRegister(func(ctx context.Context, db *bun.DB) error {
+		_, err := db.ExecContext(ctx, `
+			CREATE TABLE "jobs" (
+				"id" UUID NOT NULL PRIMARY KEY,
+				"kind" TEXT NOT NULL,
+				"tenant_id" TEXT,
+				"payload" JSONB NOT NULL,
+				"unique_key" TEXT,
+				"status" TEXT NOT NULL DEFAULT 'pending',
+				"attempts" INTEGER NOT NULL DEFAULT 0,
+				"max_attempts" INTEGER NOT NULL,
+				"last_error" TEXT,
+				"run_at" TIMESTAMPTZ NOT NULL DEFAULT current_timestamp,
+				"locked_until" TIMESTAMPTZ,
+				"created_at" TIMESTAMPTZ NOT NULL DEFAULT current_timestamp,
+				"updated_at" TIMESTAMPTZ NOT NULL DEFAULT current_timestamp,
+				"finished_at" TIMESTAMPTZ
+			)
+		`)
+		if err != nil {
+			return err
+		}
+
+		// a unique key is free again once its job is finished
+		_, err = db.ExecContext(ctx, `
+			CREATE UNIQUE INDEX "jobs_unique_key_idx" ON "jobs" ("unique_key")
+			WHERE "status" IN ('pending', 'running')
+		`)
+		if err != nil {
+			return err
+		}
+
+		_, err = db.ExecContext(ctx, `
+			CREATE INDEX "jobs_pending_idx" ON "jobs" ("run_at")
+			WHERE "status" = 'pending'
+		`)
+		if err != nil {
+			return err
+		}
+
+		// running jobs whose worker died are taken over once their lease passes
+		_, err = db.ExecContext(ctx, `
+			CREATE INDEX "jobs_running_idx" ON "jobs" ("locked_until")
+			WHERE "status" = 'running'
+		`)
+		if err != nil {
+			return err
+		}
+
+		_, err = db.ExecContext(ctx, `
+			CREATE INDEX "jobs_finished_idx" ON "jobs" ("finished_at")
+			WHERE "finished_at" IS NOT NULL
+		`)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	}, func(ctx context.Context, db *bun.DB) error {
+		_, err := db.ExecContext(ctx, `DROP TABLE IF EXISTS "jobs"`)
+		return err
+	})
+}
@@ -0,0 +1,32 @@
+package migrations
+
+import (
+	"context"
+
+	"github.com/uptrace/bun"
+)
+
+func init() {
+	Migrations.MustRegister(func(ctx context.Context, db *bun.DB) error {
+		// last_run_at is the tick of the last run, the scheduler only claims
+		// a tick later than it so that every tick runs once
+		_, err := db.ExecContext(ctx, `
+			CREATE TABLE "scheduled_tasks" (
+				"name" TEXT NOT NULL PRIMARY KEY,
+				"schedule" TEXT NOT NULL,
+				"next_run_at" TIMESTAMPTZ,
+				"last_run_at" TIMESTAMPTZ,
+				"last_started_at" TIMESTAMPTZ,
+				"last_finished_at" TIMESTAMPTZ,
+				"last_status" TEXT,
+				"last_error" TEXT,
+				"last_instance" TEXT,
+				"updated_at" TIMESTAMPTZ NOT NULL DEFAULT current_timestamp
+			)
+		`)
+		return err
+	}, func(ctx context.Context, db *bun.DB) error {
+		_, err := db.ExecContext(ctx, `DROP TABLE IF EXISTS "scheduled_tasks"`)
+		return err
+	})
+}
@@ -0,0 +1,49 @@
+package migrations
+
+import (
+	"context"
+
+	"github.com/uptrace/bun"
+)
+
+func init() {
+	Migrations.MustRegister(func(ctx context.Context, db *bun.DB) error {
+		// only the id is sent, a notification can't carry more than 8000
+		// bytes; the listeners read the message from the outbox. Postgres
+		// sends the notification when the transaction commits.
+		_, err := db.ExecContext(ctx, `
+			CREATE FUNCTION "outbox_notify_event_change"() RETURNS trigger AS $$
+			BEGIN
+				PERFORM pg_notify('event_changes', NEW."id"::text);
+				RETURN NULL;
+			END
+			$$ LANGUAGE plpgsql
+		`)
+		if err != nil {
+			return err
+		}
+
+		_, err = db.ExecContext(ctx, `
+			CREATE TRIGGER "outbox_notify_event_change"
+			AFTER INSERT ON "outbox"
+			FOR EACH ROW
+			WHEN (NEW."subject" IN (
+				'events.created', 'events.updated', 'events.deleted',
+				'events.published', 'events.cancelled', 'events.completed'
+			))
+			EXECUTE FUNCTION "outbox_notify_event_change"()
+		`)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	}, func(ctx context.Context, db *bun.DB) error {
+		_, err := db.ExecContext(ctx, `DROP TRIGGER IF EXISTS "outbox_notify_event_change" ON "outbox"`)
+		if err != nil {
+			return err
+		}
+		_, err = db.ExecContext(ctx, `DROP FUNCTION IF EXISTS "outbox_notify_event_change"()`)
+		return err
+	})
+}
@@ -0,0 +1,90 @@
+package migrations
+
+import (
+	"context"
+
+	"github.com/uptrace/bun"
+)
+
+func init() {
+	Migrations.MustRegister(func(ctx context.Context, db *bun.DB) error {
+		// the id of the event is sent for the registration and lifecycle
+		// messages. Postgres sends identical notifications of a transaction
+		// once, so cancelling an event notifies it once for all of its
+		// registrants.
+		_, err := db.ExecContext(ctx, `
+			CREATE FUNCTION "outbox_notify_seat_change"() RETURNS trigger AS $$
+			BEGIN
+				PERFORM pg_notify('seat_changes', COALESCE(
+					NEW."payload"->'registration'->>'event_id',
+					NEW."payload"->'event'->>'id'
+				));
+				RETURN NULL;
+			END
+			$$ LANGUAGE plpgsql
+		`)
+		if err != nil {
+			return err
+		}
+
+		_, err = db.ExecContext(ctx, `
+			CREATE TRIGGER "outbox_notify_seat_change"
+			AFTER INSERT ON "outbox"
+			FOR EACH ROW
+			WHEN (NEW."subject" LIKE 'events.%')
+			EXECUTE FUNCTION "outbox_notify_seat_change"()
+		`)
+		if err != nil {
+			return err
+		}
+
+		// a new room capacity changes the seats of the events to come in
+		// the room
+		_, err = db.ExecContext(ctx, `
+			CREATE FUNCTION "rooms_notify_seat_change"() RETURNS trigger AS $$
+			DECLARE
+				event_id UUID;
+			BEGIN
+				FOR event_id IN
+					SELECT "id" FROM "events"
+					WHERE "room_id" = NEW."id" AND "end_time" > now() AND "deleted_at" IS NULL
+				LOOP
+					PERFORM pg_notify('seat_changes', event_id::text);
+				END LOOP;
+				RETURN NULL;
+			END
+			$$ LANGUAGE plpgsql
+		`)
+		if err != nil {
+			return err
+		}
+
+		_, err = db.ExecContext(ctx, `
+			CREATE TRIGGER "rooms_notify_seat_change"
+			AFTER UPDATE OF "capacity" ON "rooms"
+			FOR EACH ROW
+			WHEN (NEW."capacity" IS DISTINCT FROM OLD."capacity")
+			EXECUTE FUNCTION "rooms_notify_seat_change"()
+		`)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	}, func(ctx context.Context, db *bun.DB) error {
+		_, err := db.ExecContext(ctx, `DROP TRIGGER IF EXISTS "rooms_notify_seat_change" ON "rooms"`)
+		if err != nil {
+			return err
+		}
+		_, err = db.ExecContext(ctx, `DROP FUNCTION IF EXISTS "rooms_notify_seat_change"()`)
+		if err != nil {
+			return err
+		}
+		_, err = db.ExecContext(ctx, `DROP TRIGGER IF EXISTS "outbox_notify_seat_change" ON "outbox"`)
+		if err != nil {
+			return err
+		}
+		_, err = db.ExecContext(ctx, `DROP FUNCTION IF EXISTS "outbox_notify_seat_change"()`)
+		return err
+	})
+}
@@ -0,0 +1,21 @@
+package migrations
+
+import (
+	"context"
+
+	"github.com/uptrace/bun"
+)
+
+func init() {
+	Migrations.MustRegister(func(ctx context.Context, db *bun.DB) error {
+		// the owner is the token of the request holding the key, only it may
+		// complete or release the key
+		_, err := db.ExecContext(ctx, `
+			ALTER TABLE "idempotency_keys" ADD COLUMN "owner" UUID
+		`)
+		return err
+	}, func(ctx context.Context, db *bun.DB) error {
+		_, err := db.ExecContext(ctx, `ALTER TABLE "idempotency_keys" DROP COLUMN IF EXISTS "owner"`)
+		return err
+	})
+}
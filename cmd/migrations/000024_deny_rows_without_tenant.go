@@ -0,0 +1,33 @@
+package migrations
+
+import (
+	"context"
+
+	"github.com/uptrace/bun"
+)
+
+func init() {
+	Migrations.MustRegister(func(ctx context.Context, db *bun.DB) error {
+		// the policies let every row through while app.tenant_id was unset,
+		// every query sets it now so an empty one denies
+		for _, table := range tenantScopedTables {
+			if err := dropTenantPolicy(ctx, db, table); err != nil {
+				return err
+			}
+			if err := restrictToTenant(ctx, db, table); err != nil {
+				return err
+			}
+		}
+		return nil
+	}, func(ctx context.Context, db *bun.DB) error {
+		for _, table := range tenantScopedTables {
+			if err := dropTenantPolicy(ctx, db, table); err != nil {
+				return err
+			}
+			if err := enableTenantRowLevelSecurity(ctx, db, table); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,57 @@
+package migrations
+
+import (
+	"context"
+
+	"github.com/uptrace/bun"
+)
+
+func init() {
+	Migrations.MustRegister(func(ctx context.Context, db *bun.DB) error {
+		// a user is either registered or waitlisted for an event, not both
+		_, err := db.ExecContext(ctx, `DROP INDEX IF EXISTS "registrations_active_idx"`)
+		if err != nil {
+			return err
+		}
+
+		_, err = db.ExecContext(ctx, `
+			CREATE UNIQUE INDEX "registrations_active_idx" ON "registrations" ("event_id", "user_id")
+			WHERE "status" IN ('registered', 'waitlisted')
+		`)
+		if err != nil {
+			return err
+		}
+
+		// the waitlist is promoted in the order it was joined
+		_, err = db.ExecContext(ctx, `
+			CREATE INDEX "registrations_waitlist_idx" ON "registrations" ("event_id", "created_at")
+			WHERE "status" = 'waitlisted'
+		`)
+		return err
+	}, func(ctx context.Context, db *bun.DB) error {
+		_, err := db.ExecContext(ctx, `DROP INDEX IF EXISTS "registrations_waitlist_idx"`)
+		if err != nil {
+			return err
+		}
+
+		// the registrations of the waitlist can't be kept without it
+		_, err = db.ExecContext(ctx, `
+			UPDATE "registrations" SET "status" = 'cancelled', "cancelled_at" = current_timestamp
+			WHERE "status" = 'waitlisted'
+		`)
+		if err != nil {
+			return err
+		}
+
+		_, err = db.ExecContext(ctx, `DROP INDEX IF EXISTS "registrations_active_idx"`)
+		if err != nil {
+			return err
+		}
+
+		_, err = db.ExecContext(ctx, `
+			CREATE UNIQUE INDEX "registrations_active_idx" ON "registrations" ("event_id", "user_id")
+			WHERE "status" = 'registered'
+		`)
+		return err
+	})
+}
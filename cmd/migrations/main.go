@@ -4,6 +4,9 @@ import (
 	"github.com/uptrace/bun/migrate"
 )
 
+// Migrations are run in the order of their file names. A registered
+// migration may have run on any database already, so it is never changed,
+// nor is the SQL of the helpers it calls: fixes go in a new migration.
 var Migrations = migrate.NewMigrations()
 
 ////go:embed *.sql
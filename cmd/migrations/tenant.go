@@ -0,0 +1,77 @@
+package migrations
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/uptrace/bun"
+)
+
+// tenantScopedTables are the tables restricted to the tenant set in
+// app.tenant_id by 000024. The list is part of that migration and is not
+// extended, tables created later call restrictToTenant in their own
+// migration.
+var tenantScopedTables = []string{
+	"events",
+	"registrations",
+	"webhook_subscriptions",
+	"event_audit_log",
+	"venues",
+	"rooms",
+	"event_participants",
+	"categories",
+	"tags",
+	"event_tags",
+	"notifications",
+}
+
+// enableTenantRowLevelSecurity restricts table to the tenant set in
+// app.tenant_id, like 000010 did for the first tenant scoped tables. It lets
+// every row through while the setting is empty and is only kept for the
+// migrations that ran it, 000024 replaces the policies it created. New
+// tenant scoped tables call restrictToTenant instead.
+func enableTenantRowLevelSecurity(ctx context.Context, db *bun.DB, table string) error {
+	if err := forceRowLevelSecurity(ctx, db, table); err != nil {
+		return err
+	}
+
+	_, err := db.ExecContext(ctx, fmt.Sprintf(`
+		CREATE POLICY "tenant_isolation" ON %q
+		USING (COALESCE(current_setting('app.tenant_id', true), '') IN ('', "tenant_id"))
+		WITH CHECK (COALESCE(current_setting('app.tenant_id', true), '') IN ('', "tenant_id"))
+	`, table))
+	return err
+}
+
+// restrictToTenant restricts table to the tenant set in app.tenant_id. No
+// row is visible while the setting is unset or empty, '*' is set by the
+// queries of all tenants.
+func restrictToTenant(ctx context.Context, db *bun.DB, table string) error {
+	if err := forceRowLevelSecurity(ctx, db, table); err != nil {
+		return err
+	}
+
+	_, err := db.ExecContext(ctx, fmt.Sprintf(`
+		CREATE POLICY "tenant_isolation" ON %q
+		USING (current_setting('app.tenant_id', true) IN ('*', "tenant_id"))
+		WITH CHECK (current_setting('app.tenant_id', true) IN ('*', "tenant_id"))
+	`, table))
+	return err
+}
+
+func forceRowLevelSecurity(ctx context.Context, db *bun.DB, table string) error {
+	_, err := db.ExecContext(ctx, fmt.Sprintf(`ALTER TABLE %q ENABLE ROW LEVEL SECURITY`, table))
+	if err != nil {
+		return err
+	}
+
+	// the service usually owns the tables, owners are exempt unless the
+	// policies are forced
+	_, err = db.ExecContext(ctx, fmt.Sprintf(`ALTER TABLE %q FORCE ROW LEVEL SECURITY`, table))
+	return err
+}
+
+func dropTenantPolicy(ctx context.Context, db *bun.DB, table string) error {
+	_, err := db.ExecContext(ctx, fmt.Sprintf(`DROP POLICY IF EXISTS "tenant_isolation" ON %q`, table))
+	return err
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net"
+
+	"online-registration/app"
+	"online-registration/internal/interview/infrastructure/grpcmock"
+
+	"github.com/rs/zerolog/log"
+	"github.com/urfave/cli/v2"
+)
+
+var mockGRPCCommand = &cli.Command{
+	Name:  "mock-grpc",
+	Usage: "serve canned responses for external gRPC dependencies from fixture files",
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:  "addr",
+			Value: ":9090",
+			Usage: "serve address",
+		},
+		&cli.StringFlag{
+			Name:  "fixtures",
+			Usage: "fixture file or directory, defaults to MOCK_GRPC",
+		},
+	},
+	Action: func(c *cli.Context) error {
+		_, appInstance, err := app.StartCLI(c)
+		if err != nil {
+			return err
+		}
+		defer appInstance.Stop()
+
+		path := c.String("fixtures")
+		if path == "" {
+			path = appInstance.Config().MockGRPC
+		}
+		if path == "" {
+			return errors.New("no fixtures: set MOCK_GRPC or --fixtures")
+		}
+
+		fixtures, err := grpcmock.Load(path)
+		if err != nil {
+			return err
+		}
+		if err := fixtures.Validate(); err != nil {
+			return err
+		}
+
+		listener, err := net.Listen("tcp", c.String("addr"))
+		if err != nil {
+			return fmt.Errorf("listen %s: %w", c.String("addr"), err)
+		}
+
+		server := grpcmock.NewServer(fixtures)
+		go func() {
+			log.Info().
+				Str("addr", c.String("addr")).
+				Strs("methods", fixtures.Methods()).
+				Msg("Starting mock gRPC server...")
+
+			if err := server.Serve(listener); err != nil {
+				log.Fatal().Msg(fmt.Sprintf("mock gRPC server error: %v", err))
+			}
+		}()
+
+		sig := appInstance.WaitExitSignal()
+		log.Info().Msgf("Shutting down mock gRPC server on %s...", sig)
+		server.GracefulStop()
+
+		return nil
+	},
+}
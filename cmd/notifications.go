@@ -0,0 +1,126 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"online-registration/app"
+	"online-registration/internal/i18n"
+	"online-registration/internal/interview/domain/dto"
+	"online-registration/internal/interview/domain/notification"
+	"online-registration/internal/interview/domain/repository"
+	"online-registration/internal/interview/domain/tenant"
+	"online-registration/internal/interview/domain/usecase"
+	dbrepository "online-registration/internal/interview/infrastructure/db/repository"
+	"online-registration/internal/interview/infrastructure/messaging"
+	sender "online-registration/internal/interview/infrastructure/notification"
+	"online-registration/internal/interview/infrastructure/notification/fake"
+
+	"github.com/rs/zerolog/log"
+	"github.com/urfave/cli/v2"
+)
+
+const notificationDispatchConsumer = "notifications-dispatch"
+
+// startNotificationWorkers enqueues emails to registrants for registration
+// messages and sends them until ctx is cancelled.
+func startNotificationWorkers(ctx context.Context, a *app.App) error {
+	cfg := a.Config()
+	// emails of every tenant are sent by the same workers
+	ctx = tenant.ContextWithAllTenants(ctx)
+
+	language := i18n.Lang(cfg.Notifications.Language)
+	if !notification.Supported(language) {
+		return fmt.Errorf("unsupported NOTIFICATIONS_LANGUAGE %q, use en or ru", cfg.Notifications.Language)
+	}
+
+	notificationSender, err := newNotificationSender(cfg)
+	if err != nil {
+		return err
+	}
+
+	eventRepository, err := newEventRepository(a)
+	if err != nil {
+		return err
+	}
+	notificationRepository := dbrepository.NewDBNotificationRepository(a.DB())
+
+	subscriber, err := messaging.NewEventSubscriber(a.NATS(), cfg.Nats.Stream, dto.EventSubjects)
+	if err != nil {
+		return err
+	}
+
+	dispatcher := usecase.NewNotifyRegistrantsUseCase(
+		notificationRepository, eventRepository, cfg.Notifications.ReminderBefore, cfg.Tenant.Default,
+	)
+	err = subscriber.Subscribe(
+		ctx, notificationDispatchConsumer, dispatcher.Dispatch, dto.RegistrationSubjects, dto.EventUpdatedSubject,
+	)
+	if err != nil {
+		return err
+	}
+
+	deliverer := usecase.NewDeliverNotificationsUseCase(
+		notificationRepository,
+		eventRepository,
+		notificationSender,
+		usecase.DeliverNotificationsConfig{
+			BatchSize:   cfg.DB.BatchSize,
+			MaxAttempts: cfg.Notifications.MaxAttempts,
+			From:        cfg.Notifications.From,
+			Language:    language,
+		},
+	)
+	go deliverer.Run(ctx, cfg.Notifications.PollInterval)
+
+	return nil
+}
+
+func newNotificationSender(cfg *app.Config) (repository.INotificationSender, error) {
+	switch cfg.Notifications.Sender {
+	case "smtp":
+		smtp := cfg.Notifications.SMTP
+		return sender.NewSMTPSender(sender.SMTPConfig{
+			Host:     smtp.Host,
+			Port:     smtp.Port,
+			Username: smtp.Username,
+			Password: smtp.Password,
+			Timeout:  smtp.Timeout,
+		}, cfg.Notifications.From), nil
+	case "file":
+		return sender.NewFileSender(cfg.Notifications.FileDir, cfg.Notifications.From)
+	case "log":
+		return sender.NewLogSender(), nil
+	default:
+		return nil, fmt.Errorf("unknown NOTIFICATIONS_SENDER %q, use smtp, file or log", cfg.Notifications.Sender)
+	}
+}
+
+var smtpFakeCommand = &cli.Command{
+	Name:  "smtp-fake",
+	Usage: "serve an in-memory fake SMTP server that logs the emails it receives, for local development",
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:  "addr",
+			Value: ":2525",
+			Usage: "serve address",
+		},
+		&cli.BoolFlag{
+			Name:  "print",
+			Usage: "print the received messages in full",
+		},
+	},
+	Action: func(c *cli.Context) error {
+		server := fake.NewServer()
+		server.OnMessage = func(message fake.Message) {
+			log.Info().Str("from", message.From).Strs("to", message.To).Int("size", len(message.Data)).
+				Msg("Email received")
+			if c.Bool("print") {
+				fmt.Println(string(message.Data))
+			}
+		}
+
+		log.Info().Str("addr", c.String("addr")).Msg("Starting fake SMTP server...")
+		return server.ListenAndServe(c.String("addr"))
+	},
+}
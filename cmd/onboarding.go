@@ -0,0 +1,83 @@
+package main
+
+import (
+	"context"
+	"net/http"
+
+	"online-registration/app"
+	"online-registration/internal/interview/domain/dto"
+	"online-registration/internal/interview/domain/usecase"
+	"online-registration/internal/interview/infrastructure/avanpost"
+	"online-registration/internal/interview/infrastructure/avanpost/fake"
+	"online-registration/internal/interview/infrastructure/db/repository"
+	"online-registration/internal/interview/infrastructure/messaging"
+
+	"github.com/rs/zerolog/log"
+	"github.com/urfave/cli/v2"
+)
+
+const onboardingSyncConsumer = "avanpost-onboarding-sync"
+
+// startOnboardingSync adds attendees of onboarding events to the Avanpost
+// onboarding group, and removes them once they cancel, until ctx is cancelled.
+func startOnboardingSync(ctx context.Context, a *app.App) error {
+	cfg := a.Config().ExternalServices.Avanpost
+	if cfg.OnboardingGroupUuid == "" {
+		log.Warn().Msg("AVANPOST_ONBOARDING_GROUP_UUID is not set, onboarding group sync is disabled")
+		return nil
+	}
+
+	subscriber, err := messaging.NewEventSubscriber(a.NATS(), a.Config().Nats.Stream, dto.EventSubjects)
+	if err != nil {
+		return err
+	}
+
+	syncer := usecase.NewSyncOnboardingGroupUseCase(
+		repository.NewDBRegistrationRepository(a.DB()),
+		avanpost.NewClient(avanpost.ClientConfig{
+			BaseURL:          cfg.Url,
+			ClientID:         cfg.ClientID,
+			ClientSecret:     cfg.ClientSecret,
+			TokenURL:         cfg.TokenUrl,
+			Timeout:          cfg.Timeout,
+			MaxRetries:       cfg.MaxRetries,
+			RetryBackoff:     cfg.RetryBackoff,
+			BreakerThreshold: cfg.BreakerThreshold,
+			BreakerCooldown:  cfg.BreakerCooldown,
+		}),
+		cfg.OnboardingGroupUuid,
+	)
+
+	return subscriber.Subscribe(ctx, onboardingSyncConsumer, syncer.Sync, dto.RegistrationSubjects)
+}
+
+var avanpostFakeCommand = &cli.Command{
+	Name:  "avanpost-fake",
+	Usage: "serve an in-memory fake of the Avanpost API for local development",
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:  "addr",
+			Value: ":8087",
+			Usage: "serve address",
+		},
+		&cli.StringFlag{
+			Name:  "client-id",
+			Usage: "OAuth2 client id to accept, requests are not authenticated when empty",
+		},
+		&cli.StringFlag{
+			Name:  "client-secret",
+			Usage: "OAuth2 client secret to accept",
+		},
+		&cli.DurationFlag{
+			Name:  "latency",
+			Usage: "delay every response",
+		},
+	},
+	Action: func(c *cli.Context) error {
+		server := fake.NewServer(c.String("client-id"), c.String("client-secret"))
+		server.SetLatency(c.Duration("latency"))
+
+		log.Info().Str("addr", c.String("addr")).Msg("Starting fake Avanpost server...")
+		return http.ListenAndServe(c.String("addr"), server)
+	},
+}
@@ -0,0 +1,26 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+
+	"online-registration/internal/interview/domain/handler"
+
+	"github.com/urfave/cli/v2"
+)
+
+var openAPICommand = &cli.Command{
+	Name:  "openapi",
+	Usage: "inspect the OpenAPI document of the API",
+	Subcommands: []*cli.Command{
+		{
+			Name:  "print",
+			Usage: "print the OpenAPI document served at /openapi.json",
+			Action: func(c *cli.Context) error {
+				encoder := json.NewEncoder(os.Stdout)
+				encoder.SetIndent("", "  ")
+				return encoder.Encode(handler.OpenAPI(apiBasePath))
+			},
+		},
+	},
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"context"
+
+	"online-registration/app"
+	"online-registration/internal/interview/domain/dto"
+	"online-registration/internal/interview/domain/usecase"
+	"online-registration/internal/interview/infrastructure/db/repository"
+	"online-registration/internal/interview/infrastructure/messaging"
+)
+
+// registerOutboxRelay starts publishing outbox messages to NATS JetStream
+// once the app is started. The relay stops when the app context is cancelled.
+func registerOutboxRelay() {
+	app.OnStart("outbox.relay", func(ctx context.Context, a *app.App) error {
+		publisher, err := messaging.NewJetStreamPublisher(a.NATS(), a.Config().Nats.Stream, dto.EventSubjects)
+		if err != nil {
+			return err
+		}
+
+		relay := usecase.NewRelayOutboxUseCase(
+			repository.NewDBOutboxRepository(a.DB()),
+			publisher,
+			a.Config().DB.BatchSize,
+		)
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			relay.Run(ctx, a.Config().Outbox.PollInterval)
+		}()
+
+		a.OnStop("outbox.relay", func(ctx context.Context, _ *app.App) error {
+			<-done
+			return nil
+		})
+
+		return nil
+	})
+}
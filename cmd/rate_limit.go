@@ -0,0 +1,70 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"online-registration/app"
+	"online-registration/internal/interview/domain/entity"
+	"online-registration/internal/interview/domain/handler"
+	"online-registration/internal/interview/domain/repository"
+	"online-registration/internal/interview/domain/usecase"
+	dbrepository "online-registration/internal/interview/infrastructure/db/repository"
+	"online-registration/internal/interview/infrastructure/ratelimit"
+)
+
+// newRateLimitHandler builds the rate limiter from the configuration.
+func newRateLimitHandler(a *app.App) (*handler.RateLimitHandler, error) {
+	cfg := a.Config().RateLimit
+
+	limits, err := parseRateLimits(a)
+	if err != nil {
+		return nil, err
+	}
+
+	var store repository.IRateLimitStore
+	switch cfg.Store {
+	case "postgres":
+		store = dbrepository.NewDBRateLimitRepository(a.DB())
+	case "memory":
+		store = ratelimit.NewMemoryStore()
+	default:
+		return nil, fmt.Errorf("RATE_LIMIT_STORE: unknown store %q", cfg.Store)
+	}
+
+	return handler.NewRateLimitHandler(usecase.NewRateLimitUseCase(store), limits.ip, limits.read, limits.write), nil
+}
+
+type rateLimits struct {
+	ip, read, write entity.RateLimit
+}
+
+func parseRateLimits(a *app.App) (limits rateLimits, err error) {
+	cfg := a.Config().RateLimit
+
+	if limits.ip, err = entity.ParseRateLimit(cfg.IP); err != nil {
+		return limits, fmt.Errorf("RATE_LIMIT_IP: %w", err)
+	}
+	if limits.read, err = entity.ParseRateLimit(cfg.Read); err != nil {
+		return limits, fmt.Errorf("RATE_LIMIT_READ: %w", err)
+	}
+	if limits.write, err = entity.ParseRateLimit(cfg.Write); err != nil {
+		return limits, fmt.Errorf("RATE_LIMIT_WRITE: %w", err)
+	}
+	return limits, nil
+}
+
+// rateLimitRefill is the longest period of the rate limits, after which the
+// buckets of the postgres store are full again and purged. It is zero when
+// the store is not in use, or the limits are invalid and the API refuses to
+// start anyway.
+func rateLimitRefill(a *app.App) time.Duration {
+	if cfg := a.Config().RateLimit; !cfg.Enabled || cfg.Store != "postgres" {
+		return 0
+	}
+	limits, err := parseRateLimits(a)
+	if err != nil {
+		return 0
+	}
+	return max(limits.ip.Period, limits.read.Period, limits.write.Period)
+}
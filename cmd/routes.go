@@ -0,0 +1,77 @@
+package main
+
+import (
+	"online-registration/internal/interview/domain/handler"
+
+	"github.com/gin-gonic/gin"
+)
+
+// apiBasePath is the path the API is served under.
+const apiBasePath = "/api/v1"
+
+// apiHandlers are the handlers of the API routes.
+type apiHandlers struct {
+	events           *handler.Handler
+	idempotency      *handler.IdempotencyHandler
+	eventStream      *handler.EventStreamHandler
+	seatAvailability *handler.SeatAvailabilityHandler
+	auditLog         *handler.AuditLogHandler
+	registrations    *handler.RegistrationHandler
+	participants     *handler.ParticipantHandler
+	freeBusy         *handler.FreeBusyHandler
+	webhooks         *handler.WebhookHandler
+	venues           *handler.VenueHandler
+	tags             *handler.TagHandler
+	schedule         *handler.ScheduleHandler
+}
+
+// registerAPIRoutes registers the routes of the API on v1. Every route must
+// be described in the OpenAPI document, see handler.OpenAPI.
+func registerAPIRoutes(v1 *gin.RouterGroup, h *apiHandlers) {
+	v1.POST("/events", h.idempotency.Middleware(), h.events.CreateEvent)
+	v1.GET("/events", h.events.ListEvents)
+	v1.GET("/events/facets", h.events.EventFacets)
+	v1.GET("/events/stream", h.eventStream.Stream)
+	v1.GET("/events/availability", h.seatAvailability.Watch)
+	v1.GET("/events/:id", h.events.GetEvent)
+	v1.GET("/events/:id/ics", h.events.GetEventICS)
+	v1.PUT("/events/:id", h.events.UpdateEvent)
+	v1.DELETE("/events/:id", h.events.DeleteEvent)
+	v1.POST("/events/:id/publish", h.events.PublishEvent)
+	v1.POST("/events/:id/cancel", h.events.CancelEvent)
+	v1.GET("/events/:id/history", h.auditLog.EventHistory)
+	v1.POST("/events/:id/registrations", h.registrations.Register)
+	v1.DELETE("/events/:id/registrations", h.registrations.Cancel)
+	v1.GET("/events/:id/participants", h.participants.ListParticipants)
+	v1.PUT("/events/:id/participants/:user_id", h.participants.SetParticipant)
+	v1.DELETE("/events/:id/participants/:user_id", h.participants.RemoveParticipant)
+	v1.POST("/freebusy", h.freeBusy.FreeBusy)
+
+	v1.POST("/webhooks", h.webhooks.CreateSubscription)
+	v1.GET("/webhooks", h.webhooks.ListSubscriptions)
+	v1.GET("/webhooks/:id", h.webhooks.GetSubscription)
+	v1.PUT("/webhooks/:id", h.webhooks.UpdateSubscription)
+	v1.DELETE("/webhooks/:id", h.webhooks.DeleteSubscription)
+	v1.GET("/webhooks/:id/deliveries", h.webhooks.ListDeliveries)
+	v1.POST("/webhooks/:id/deliveries/:delivery_id/redeliver", h.webhooks.Redeliver)
+
+	v1.POST("/venues", h.venues.CreateVenue)
+	v1.GET("/venues", h.venues.ListVenues)
+	v1.GET("/venues/:id", h.venues.GetVenue)
+	v1.PUT("/venues/:id", h.venues.UpdateVenue)
+	v1.DELETE("/venues/:id", h.venues.DeleteVenue)
+	v1.POST("/venues/:id/rooms", h.venues.CreateRoom)
+	v1.PUT("/rooms/:id", h.venues.UpdateRoom)
+	v1.DELETE("/rooms/:id", h.venues.DeleteRoom)
+
+	v1.GET("/tags", h.tags.ListTags)
+	v1.POST("/tags", h.tags.CreateTag)
+	v1.PUT("/tags/:id", h.tags.UpdateTag)
+	v1.DELETE("/tags/:id", h.tags.DeleteTag)
+	v1.GET("/categories", h.tags.ListCategories)
+	v1.POST("/categories", h.tags.CreateCategory)
+	v1.PUT("/categories/:id", h.tags.UpdateCategory)
+	v1.DELETE("/categories/:id", h.tags.DeleteCategory)
+
+	v1.GET("/admin/schedule", h.schedule.ListScheduledTasks)
+}
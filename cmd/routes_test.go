@@ -0,0 +1,97 @@
+package main
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"online-registration/internal/interview/domain/handler"
+
+	"github.com/gin-gonic/gin"
+)
+
+// apiRoutes returns the routes registerAPIRoutes registers, by method and
+// OpenAPI path.
+func apiRoutes(t *testing.T) map[string]gin.RouteInfo {
+	t.Helper()
+
+	gin.SetMode(gin.ReleaseMode)
+	router := gin.New()
+	// the handlers are only registered, never called
+	registerAPIRoutes(router.Group(apiBasePath), &apiHandlers{})
+
+	routes := make(map[string]gin.RouteInfo)
+	for _, route := range router.Routes() {
+		if path, ok := strings.CutPrefix(route.Path, apiBasePath); ok {
+			routes[route.Method+" "+openAPIPath(path)] = route
+		}
+	}
+	return routes
+}
+
+// openAPIPath turns the parameters of a gin path into OpenAPI ones, e.g.
+// /events/:id into /events/{id}.
+func openAPIPath(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if name, ok := strings.CutPrefix(segment, ":"); ok {
+			segments[i] = "{" + name + "}"
+		} else if name, ok := strings.CutPrefix(segment, "*"); ok {
+			segments[i] = "{" + name + "}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+func TestOpenAPIDescribesEveryRoute(t *testing.T) {
+	routes := apiRoutes(t)
+
+	documented := make(map[string]bool)
+	for path, item := range handler.OpenAPI(apiBasePath).Paths {
+		for _, method := range item.Methods() {
+			documented[method+" "+path] = true
+		}
+	}
+
+	for route := range routes {
+		if !documented[route] {
+			t.Errorf("%s is not documented", route)
+		}
+	}
+	for operation := range documented {
+		if _, ok := routes[operation]; !ok {
+			t.Errorf("%s is documented but not routed", operation)
+		}
+	}
+}
+
+func TestOpenAPIDocumentsPathParameters(t *testing.T) {
+	document := handler.OpenAPI(apiBasePath)
+
+	for route := range apiRoutes(t) {
+		method, path, _ := strings.Cut(route, " ")
+		item, ok := document.Paths[path]
+		if !ok || item.Operation(method) == nil {
+			// reported by TestOpenAPIDescribesEveryRoute
+			continue
+		}
+
+		var routed []string
+		for _, segment := range strings.Split(path, "/") {
+			if name, ok := strings.CutPrefix(segment, "{"); ok {
+				routed = append(routed, strings.TrimSuffix(name, "}"))
+			}
+		}
+		var documented []string
+		for _, param := range item.Operation(method).Parameters {
+			if param.In == "path" {
+				documented = append(documented, param.Name)
+			}
+		}
+		slices.Sort(routed)
+		slices.Sort(documented)
+		if !slices.Equal(routed, documented) {
+			t.Errorf("%s has the path parameters %v, the document has %v", route, routed, documented)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"online-registration/app"
+	"online-registration/internal/interview/domain/cron"
+	"online-registration/internal/interview/domain/jobs"
+	"online-registration/internal/interview/domain/usecase"
+	"online-registration/internal/interview/infrastructure/db/repository"
+)
+
+// schedulerLock names the advisory lock held by the worker leading the
+// scheduler.
+const schedulerLock = "online-registration.scheduler"
+
+// scheduleTasks registers the periodic tasks of the worker.
+func scheduleTasks(a *app.App, eventLifecycle *usecase.EventLifecycleUseCase) {
+	cfg := a.Config()
+
+	a.Schedule("events.complete", cfg.Events.CompleteSchedule, func(ctx context.Context, _ *app.App) error {
+		_, err := eventLifecycle.CompleteEnded(ctx)
+		return err
+	})
+
+	// the purge itself runs as a job, which is retried when it fails
+	a.Schedule("purge", cfg.Jobs.PurgeSchedule, func(ctx context.Context, a *app.App) error {
+		_, _, err := usecase.PurgeJob.Enqueue(
+			ctx, repository.NewDBJobRepository(a.DB()), usecase.PurgePayload{},
+			jobs.Options{UniqueKey: usecase.PurgeJob.Name()},
+		)
+		return err
+	})
+}
+
+// startScheduler runs the tasks registered with App.Schedule on the worker
+// leading the scheduler, until ctx is cancelled. The app waits for the
+// running tasks when it stops.
+func startScheduler(ctx context.Context, a *app.App) error {
+	cfg := a.Config()
+
+	location, err := time.LoadLocation(cfg.Scheduler.TimeZone)
+	if err != nil {
+		return fmt.Errorf("SCHEDULER_TIME_ZONE: %w", err)
+	}
+
+	var tasks []usecase.ScheduledTask
+	for _, task := range a.ScheduledTasks() {
+		schedule, err := cron.Parse(task.Spec)
+		if err != nil {
+			return fmt.Errorf("scheduled task %s: %w", task.Name, err)
+		}
+		if schedule.Next(time.Now().In(location)).IsZero() {
+			return fmt.Errorf("scheduled task %s: %q never fires", task.Name, task.Spec)
+		}
+
+		tasks = append(tasks, usecase.ScheduledTask{
+			Name:     task.Name,
+			Schedule: schedule,
+			Run: func(ctx context.Context) error {
+				return task.Fn(ctx, a)
+			},
+		})
+	}
+
+	hostname, _ := os.Hostname()
+	scheduler := usecase.NewRunSchedulerUseCase(
+		repository.NewDBScheduledTaskRepository(a.DB()),
+		repository.NewDBAdvisoryLock(a.DB(), schedulerLock),
+		usecase.SchedulerConfig{
+			Instance:     fmt.Sprintf("%s/%d", hostname, os.Getpid()),
+			PollInterval: cfg.Scheduler.PollInterval,
+			Location:     location,
+		},
+		tasks...,
+	)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		scheduler.Run(ctx)
+	}()
+
+	a.OnStop("scheduler", func(ctx context.Context, _ *app.App) error {
+		<-done
+		return nil
+	})
+
+	return nil
+}
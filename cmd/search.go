@@ -0,0 +1,59 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+
+	"online-registration/app"
+	"online-registration/internal/interview/domain/entity"
+	"online-registration/internal/interview/domain/tenant"
+	"online-registration/internal/interview/infrastructure/db/repository"
+
+	"github.com/rs/zerolog/log"
+	"github.com/urfave/cli/v2"
+)
+
+// newEventRepository builds the event repository with the search language
+// of the configuration.
+func newEventRepository(a *app.App) (*repository.EventRepository, error) {
+	language := a.Config().Events.SearchLanguage
+	if !slices.Contains(entity.SearchLanguages, language) {
+		return nil, fmt.Errorf("EVENTS_SEARCH_LANGUAGE: unknown language %q, use one of %v", language, entity.SearchLanguages)
+	}
+
+	return repository.NewDBEventRepository(a.DB(), language), nil
+}
+
+var searchCommand = &cli.Command{
+	Name:  "search",
+	Usage: "manage the event search index",
+	Subcommands: []*cli.Command{
+		{
+			Name:  "reindex",
+			Usage: "index the events of all tenants in the configured search language",
+			Action: func(c *cli.Context) error {
+				ctx, appInstance, err := app.StartCLI(c)
+				if err != nil {
+					return err
+				}
+				defer appInstance.Stop()
+
+				eventRepository, err := newEventRepository(appInstance)
+				if err != nil {
+					return err
+				}
+
+				reindexed, err := eventRepository.ReindexSearch(tenant.ContextWithAllTenants(ctx))
+				if err != nil {
+					return err
+				}
+
+				log.Info().
+					Int("events", reindexed).
+					Str("language", appInstance.Config().Events.SearchLanguage).
+					Msg("Reindexed event search")
+				return nil
+			},
+		},
+	},
+}
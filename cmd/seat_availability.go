@@ -0,0 +1,44 @@
+package main
+
+import (
+	"context"
+	"net/http"
+
+	"online-registration/app"
+	"online-registration/internal/interview/domain/policy"
+	"online-registration/internal/interview/domain/usecase"
+	"online-registration/internal/interview/infrastructure/db/repository"
+)
+
+// startSeatAvailability starts pushing seat changes to the WebSocket
+// connections of srv. srv doesn't track hijacked connections, so they are
+// closed once it shuts down, and the listener stops before the app closes
+// the database.
+func startSeatAvailability(
+	ctx context.Context, a *app.App, accessPolicy *policy.Policy, srv *http.Server,
+) *usecase.WatchSeatAvailabilityUseCase {
+	seatAvailability := usecase.NewWatchSeatAvailabilityUseCase(
+		repository.NewDBSeatAvailabilityRepository(a.DB()),
+		accessPolicy,
+		usecase.SeatAvailabilityConfig{
+			MaxConnections: a.Config().SeatAvailability.MaxConnections,
+		},
+	)
+
+	ctx, stop := context.WithCancel(ctx)
+	srv.RegisterOnShutdown(stop)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		seatAvailability.Run(ctx)
+	}()
+
+	a.OnStop("seats.availability", func(context.Context, *app.App) error {
+		stop()
+		<-done
+		return nil
+	})
+
+	return seatAvailability
+}
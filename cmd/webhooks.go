@@ -0,0 +1,48 @@
+package main
+
+import (
+	"context"
+
+	"online-registration/app"
+	"online-registration/internal/interview/domain/dto"
+	"online-registration/internal/interview/domain/tenant"
+	"online-registration/internal/interview/domain/usecase"
+	"online-registration/internal/interview/infrastructure/db/repository"
+	"online-registration/internal/interview/infrastructure/messaging"
+	"online-registration/internal/interview/infrastructure/webhook"
+)
+
+const webhookDispatchConsumer = "webhooks-dispatch"
+
+// startWebhookWorkers enqueues webhook deliveries for event lifecycle
+// messages and sends them until ctx is cancelled.
+func startWebhookWorkers(ctx context.Context, a *app.App) error {
+	cfg := a.Config()
+	// deliveries of every tenant are sent by the same workers
+	ctx = tenant.ContextWithAllTenants(ctx)
+	webhookRepository := repository.NewDBWebhookRepository(a.DB())
+
+	subscriber, err := messaging.NewEventSubscriber(a.NATS(), cfg.Nats.Stream, dto.EventSubjects)
+	if err != nil {
+		return err
+	}
+
+	dispatcher := usecase.NewDispatchWebhooksUseCase(webhookRepository, cfg.Tenant.Default)
+	if err := subscriber.Subscribe(ctx, webhookDispatchConsumer, dispatcher.Dispatch, dto.EventSubjects); err != nil {
+		return err
+	}
+
+	deliverer := usecase.NewDeliverWebhooksUseCase(
+		webhookRepository,
+		webhook.NewHTTPSender(cfg.Webhooks.Timeout),
+		usecase.DeliverWebhooksConfig{
+			BatchSize:    cfg.DB.BatchSize,
+			Timeout:      cfg.Webhooks.Timeout,
+			MaxAttempts:  cfg.Webhooks.MaxAttempts,
+			DisableAfter: cfg.Webhooks.DisableAfter,
+		},
+	)
+	go deliverer.Run(ctx, cfg.Webhooks.PollInterval)
+
+	return nil
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"online-registration/internal/interview/domain/auth"
+	"online-registration/internal/interview/domain/handler"
+	"online-registration/internal/interview/domain/policy"
+	"online-registration/internal/interview/domain/usecase"
+	"online-registration/internal/interview/infrastructure/db/repository"
+	"online-registration/internal/interview/infrastructure/messaging"
+
+	"github.com/rs/zerolog/log"
+	"github.com/urfave/cli/v2"
+)
+
+var workerCommand = &cli.Command{
+	Name:  "worker",
+	Usage: "consume event commands from NATS, deliver webhooks, email registrants, run scheduled tasks and background jobs and sync the onboarding group",
+	Action: func(c *cli.Context) error {
+		registerOutboxRelay()
+		registerJobWorkers()
+
+		servicesAndDependencies, err := startAppAndServices(
+			c.Context,
+			c.String("env"),
+		)
+		if err != nil {
+			return err
+		}
+		defer servicesAndDependencies.gracefulShutdown()
+
+		appInstance := servicesAndDependencies.app
+		cfg := appInstance.Config()
+
+		accessPolicy, err := policy.Load(cfg.Auth.PolicyFile)
+		if err != nil {
+			return err
+		}
+
+		eventRepository, err := newEventRepository(appInstance)
+		if err != nil {
+			return err
+		}
+		eventLifecycle := usecase.NewEventLifecycleUseCase(eventRepository, accessPolicy, cfg.DB.BatchSize)
+		commandHandler := handler.NewCommandHandler(
+			usecase.NewCreateEventUseCase(eventRepository, accessPolicy),
+			eventLifecycle,
+			usecase.NewIdempotencyUseCase(
+				repository.NewDBIdempotencyKeyRepository(appInstance.DB()),
+				cfg.Idempotency.TTL,
+				cfg.Idempotency.RequestTimeout,
+			),
+			cfg.Tenant.Default,
+		)
+
+		consumer, err := messaging.NewCommandConsumer(appInstance.NATS(), messaging.CommandConsumerConfig{
+			Stream:            cfg.Worker.CommandStream,
+			ResultSubject:     cfg.Worker.ResultSubject,
+			DeadLetterSubject: cfg.Worker.DeadLetterSubject,
+			MaxDeliver:        cfg.Worker.MaxDeliver,
+		})
+		if err != nil {
+			return err
+		}
+		consumer.Handle(cfg.Worker.CreateSubject, commandHandler.CreateEvent)
+		consumer.Handle(cfg.Worker.CancelSubject, commandHandler.CancelEvent)
+
+		// commands come from trusted services, they are not subject to roles
+		commandCtx := auth.ContextWithPrincipal(servicesAndDependencies.ctx, auth.SystemPrincipal("worker"))
+		if err := consumer.Start(commandCtx); err != nil {
+			return err
+		}
+
+		scheduleTasks(appInstance, eventLifecycle)
+		if err := startScheduler(servicesAndDependencies.ctx, appInstance); err != nil {
+			return err
+		}
+
+		if err := startWebhookWorkers(servicesAndDependencies.ctx, appInstance); err != nil {
+			return err
+		}
+
+		if err := startNotificationWorkers(servicesAndDependencies.ctx, appInstance); err != nil {
+			return err
+		}
+
+		if err := startOnboardingSync(servicesAndDependencies.ctx, appInstance); err != nil {
+			return err
+		}
+
+		log.Info().
+			Str("create_subject", cfg.Worker.CreateSubject).
+			Str("cancel_subject", cfg.Worker.CancelSubject).
+			Msg("Worker started")
+
+		sig := appInstance.WaitExitSignal()
+		log.Info().Msgf("Shutting down worker on %s...", sig)
+
+		return nil
+	},
+}
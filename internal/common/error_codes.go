@@ -0,0 +1,79 @@
+package common
+
+// Machine-readable error codes returned in the "code" field of API error
+// responses. They are stable and must not be changed once published.
+const (
+	CodeInternalError      = "internal_error"
+	CodeNotFound           = "not_found"
+	CodeInvalidRequestBody = "invalid_request_body"
+	CodeEmptyFields        = "empty_fields"
+	CodeInvalidTimeRange   = "invalid_time_range"
+	CodeEventCreateFailed  = "event_create_failed"
+	CodeEventUpdateFailed  = "event_update_failed"
+	CodeEventDeleteFailed  = "event_delete_failed"
+	CodeInvalidEventID     = "invalid_event_id"
+	CodeInvalidTimeZone    = "invalid_time_zone"
+	CodeInvalidDate        = "invalid_date"
+	CodeInvalidEventTypes  = "invalid_event_types"
+	CodeInvalidLastEventID = "invalid_last_event_id"
+
+	CodeInvalidEventStatus        = "invalid_event_status"
+	CodeEventTransitionNotAllowed = "event_transition_not_allowed"
+	CodeEventNotEditable          = "event_not_editable"
+	CodeEventNotOpen              = "event_not_open"
+	CodeCancellationReasonMissing = "cancellation_reason_missing"
+
+	CodeInvalidVenueID      = "invalid_venue_id"
+	CodeInvalidRoomID       = "invalid_room_id"
+	CodeInvalidRoomCapacity = "invalid_room_capacity"
+	CodeUnknownRoom         = "unknown_room"
+	CodeRoomAlreadyBooked   = "room_already_booked"
+	CodeRoomNameTaken       = "room_name_taken"
+	CodeRoomInUse           = "room_in_use"
+	CodeVenueNotEmpty       = "venue_not_empty"
+
+	CodeInvalidCategoryID = "invalid_category_id"
+	CodeInvalidTagID      = "invalid_tag_id"
+	CodeInvalidTag        = "invalid_tag"
+	CodeTooManyTags       = "too_many_tags"
+	CodeInvalidTagMatch   = "invalid_tag_match"
+	CodeUnknownCategory   = "unknown_category"
+	CodeTagNameTaken      = "tag_name_taken"
+	CodeCategoryNameTaken = "category_name_taken"
+
+	CodeInvalidParticipant     = "invalid_participant"
+	CodeInvalidParticipantRole = "invalid_participant_role"
+
+	CodeFreeBusyWindowTooLong    = "freebusy_window_too_long"
+	CodeFreeBusyInvalidResources = "freebusy_invalid_resources"
+	CodeInvalidSlotLength        = "invalid_slot_length"
+
+	CodeAlreadyRegistered    = "already_registered"
+	CodeRegistrationNotFound = "registration_not_found"
+	CodeRegistrationFailed   = "registration_failed"
+	CodeTooManyWatchedEvents = "too_many_watched_events"
+	CodeTooManyConnections   = "too_many_connections"
+
+	CodeUnauthenticated = "unauthenticated"
+	CodeInvalidToken    = "invalid_token"
+	CodeAuthUnavailable = "auth_unavailable"
+	CodeForbidden       = "forbidden"
+
+	CodeAPIKeyInvalidName   = "api_key_invalid_name"
+	CodeAPIKeyInvalidScopes = "api_key_invalid_scopes"
+
+	CodeRateLimited = "rate_limited"
+
+	CodeInvalidTenant   = "invalid_tenant"
+	CodeTenantForbidden = "tenant_forbidden"
+
+	CodeIdempotencyKeyInvalid    = "idempotency_key_invalid"
+	CodeIdempotencyKeyReused     = "idempotency_key_reused"
+	CodeIdempotencyKeyInProgress = "idempotency_key_in_progress"
+
+	CodeInvalidWebhookID         = "invalid_webhook_id"
+	CodeInvalidWebhookDeliveryID = "invalid_webhook_delivery_id"
+	CodeWebhookInvalidURL        = "webhook_invalid_url"
+	CodeWebhookInvalidEventTypes = "webhook_invalid_event_types"
+	CodeWebhookForbiddenHost     = "webhook_forbidden_host"
+)
@@ -4,6 +4,8 @@ import "fmt"
 
 type ProcessingError struct {
 	Status uint8
+	Code   string
+	Args   []any
 	Err    error
 }
 
@@ -21,3 +23,15 @@ func NewError(status uint8, err error) *ProcessingError {
 		Err:    err,
 	}
 }
+
+// NewCodedError creates a ProcessingError carrying a machine-readable code
+// that is used to look up the localized message for API responses. Args are
+// substituted into the message.
+func NewCodedError(status uint8, code string, err error, args ...any) *ProcessingError {
+	return &ProcessingError{
+		Status: status,
+		Code:   code,
+		Args:   args,
+		Err:    err,
+	}
+}
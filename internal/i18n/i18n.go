@@ -0,0 +1,65 @@
+package i18n
+
+import (
+	"fmt"
+
+	"golang.org/x/text/language"
+)
+
+type Lang string
+
+const (
+	English Lang = "en"
+	Russian Lang = "ru"
+
+	DefaultLang = English
+)
+
+var (
+	supported = []language.Tag{language.English, language.Russian}
+	matcher   = language.NewMatcher(supported)
+
+	catalogs = map[Lang]map[string]string{
+		English: messagesEN,
+		Russian: messagesRU,
+	}
+)
+
+// ParseAcceptLanguage picks the best supported language for the value of an
+// Accept-Language header, falling back to DefaultLang.
+func ParseAcceptLanguage(header string) Lang {
+	if header == "" {
+		return DefaultLang
+	}
+
+	tags, _, err := language.ParseAcceptLanguage(header)
+	if err != nil || len(tags) == 0 {
+		return DefaultLang
+	}
+
+	_, index, confidence := matcher.Match(tags...)
+	if confidence == language.No {
+		return DefaultLang
+	}
+
+	base, _ := supported[index].Base()
+	return Lang(base.String())
+}
+
+// Translate returns the message for code in the given language. Messages
+// missing in the language fall back to DefaultLang, and unknown codes are
+// returned as is.
+func Translate(lang Lang, code string, args ...any) string {
+	format, ok := catalogs[lang][code]
+	if !ok {
+		format, ok = catalogs[DefaultLang][code]
+	}
+	if !ok {
+		return code
+	}
+
+	if len(args) == 0 {
+		return format
+	}
+	return fmt.Sprintf(format, args...)
+}
@@ -0,0 +1,72 @@
+package i18n
+
+import (
+	"testing"
+
+	"online-registration/internal/common"
+)
+
+func TestParseAcceptLanguage(t *testing.T) {
+	tests := []struct {
+		header string
+		want   Lang
+	}{
+		{"", English},
+		{"ru", Russian},
+		{"ru-RU", Russian},
+		{"ru-RU,ru;q=0.9,en;q=0.8", Russian},
+		{"en-GB", English},
+		{"en;q=0.2, ru;q=0.8", Russian},
+		{"en-GB;q=0, ru;q=0.1", Russian},
+		{"fr, ru;q=0.1", Russian},
+		{"*", English},
+		{"fr, *", English},
+		{"ru;q=0.5, *;q=0.9", Russian},
+		{"de", English},
+		{"not a language", English},
+	}
+	for _, tt := range tests {
+		if got := ParseAcceptLanguage(tt.header); got != tt.want {
+			t.Errorf("ParseAcceptLanguage(%q) = %s, want %s", tt.header, got, tt.want)
+		}
+	}
+}
+
+func TestTranslate(t *testing.T) {
+	// a message not translated yet
+	const untranslated = "test_untranslated"
+	messagesEN[untranslated] = "Only in English: %d"
+	t.Cleanup(func() { delete(messagesEN, untranslated) })
+
+	tests := []struct {
+		lang Lang
+		code string
+		args []any
+		want string
+	}{
+		{English, common.CodeNotFound, nil, "Nothing found"},
+		{Russian, common.CodeNotFound, nil, "Ничего не найдено"},
+		{Russian, common.CodeTooManyTags, []any{10}, "У события может быть не больше 10 тегов"},
+		{Russian, untranslated, []any{1}, "Only in English: 1"},
+		{Lang("de"), common.CodeNotFound, nil, "Nothing found"},
+		{English, "unknown_code", nil, "unknown_code"},
+	}
+	for _, tt := range tests {
+		if got := Translate(tt.lang, tt.code, tt.args...); got != tt.want {
+			t.Errorf("Translate(%s, %s) = %q, want %q", tt.lang, tt.code, got, tt.want)
+		}
+	}
+}
+
+func TestCatalogsTranslateEveryCode(t *testing.T) {
+	for code := range messagesEN {
+		if _, ok := messagesRU[code]; !ok {
+			t.Errorf("%s has no Russian message", code)
+		}
+	}
+	for code := range messagesRU {
+		if _, ok := messagesEN[code]; !ok {
+			t.Errorf("%s has no English message", code)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package i18n
+
+import "online-registration/internal/common"
+
+var messagesEN = map[string]string{
+	common.CodeInternalError:      "Internal server error",
+	common.CodeNotFound:           "Nothing found",
+	common.CodeInvalidRequestBody: "Uncorrected data",
+	common.CodeEmptyFields:        "Data cannot be empty",
+	common.CodeInvalidTimeRange:   "Start time cannot be after end time",
+	common.CodeEventCreateFailed:  "Failed to create event",
+	common.CodeEventUpdateFailed:  "Failed to update event",
+	common.CodeEventDeleteFailed:  "Failed to delete event",
+	common.CodeInvalidEventID:     "Invalid event id",
+	common.CodeInvalidTimeZone:    "Unknown time zone %q, use an IANA name such as Europe/Moscow",
+	common.CodeInvalidDate:        "Dates must be written as YYYY-MM-DD, the end date not before the start date",
+	common.CodeInvalidEventTypes:  "Event types must be a list of: %s",
+	common.CodeInvalidLastEventID: "Last-Event-ID must be the id of an event received from the stream",
+
+	common.CodeInvalidEventStatus:        "Event status must be one of: %s",
+	common.CodeEventTransitionNotAllowed: "A %s event can't be %s",
+	common.CodeEventNotEditable:          "A %s event can no longer be changed",
+	common.CodeEventNotOpen:              "The event is not open for registration",
+	common.CodeCancellationReasonMissing: "A reason is required to cancel an event",
+
+	common.CodeInvalidVenueID:      "Invalid venue id",
+	common.CodeInvalidRoomID:       "Invalid room id",
+	common.CodeInvalidRoomCapacity: "Room capacity cannot be negative",
+	common.CodeUnknownRoom:         "The room does not exist",
+	common.CodeRoomAlreadyBooked:   "The room is already booked at that time by event %q (%s)",
+	common.CodeRoomNameTaken:       "The venue already has a room named %q",
+	common.CodeRoomInUse:           "The room is booked by events that are not over yet",
+	common.CodeVenueNotEmpty:       "The venue still has rooms",
+
+	common.CodeInvalidCategoryID: "Invalid category id",
+	common.CodeInvalidTagID:      "Invalid tag id",
+	common.CodeInvalidTag:        "Tag %q must be 1 to %d characters",
+	common.CodeTooManyTags:       "An event can have at most %d tags",
+	common.CodeInvalidTagMatch:   "Tag match must be one of: %s",
+	common.CodeUnknownCategory:   "The category does not exist",
+	common.CodeTagNameTaken:      "A tag named %q already exists",
+	common.CodeCategoryNameTaken: "A category named %q already exists",
+
+	common.CodeInvalidParticipant:     "Participant user id must be 1 to %d characters",
+	common.CodeInvalidParticipantRole: "Participant role must be one of: %s",
+
+	common.CodeFreeBusyWindowTooLong:    "The time window can't be longer than %d days",
+	common.CodeFreeBusyInvalidResources: "List from 1 to %d users and rooms",
+	common.CodeInvalidSlotLength:        "Slot length must be a positive duration that fits the time window",
+
+	common.CodeAlreadyRegistered:    "You are already registered for this event",
+	common.CodeRegistrationNotFound: "You are not registered for this event",
+	common.CodeRegistrationFailed:   "Failed to update registration",
+	common.CodeTooManyWatchedEvents: "A connection can watch at most %d events",
+	common.CodeTooManyConnections:   "Too many live connections, retry later",
+
+	common.CodeUnauthenticated: "Authentication required",
+	common.CodeInvalidToken:    "Access token is invalid or expired",
+	common.CodeAuthUnavailable: "Authentication service is unavailable",
+	common.CodeForbidden:       "You are not allowed to perform this action",
+
+	common.CodeAPIKeyInvalidName:   "API key name cannot be empty",
+	common.CodeAPIKeyInvalidScopes: "API key scopes must be a non-empty list of: %s",
+
+	common.CodeRateLimited: "Too many requests, retry later",
+
+	common.CodeInvalidTenant:   "Invalid tenant %q",
+	common.CodeTenantForbidden: "You are not allowed to access tenant %q",
+
+	common.CodeIdempotencyKeyInvalid:    "Idempotency-Key header must be at most %d characters",
+	common.CodeIdempotencyKeyReused:     "Idempotency-Key has already been used with a different request",
+	common.CodeIdempotencyKeyInProgress: "A request with this Idempotency-Key is still being processed",
+
+	common.CodeInvalidWebhookID:         "Invalid webhook id",
+	common.CodeInvalidWebhookDeliveryID: "Invalid webhook delivery id",
+	common.CodeWebhookInvalidURL:        "Webhook URL must be an absolute http or https URL",
+	common.CodeWebhookInvalidEventTypes: "Webhook event types must be a non-empty list of: %s",
+	common.CodeWebhookForbiddenHost:     "Webhook host %s must resolve to public addresses only",
+}
@@ -0,0 +1,79 @@
+package i18n
+
+import "online-registration/internal/common"
+
+var messagesRU = map[string]string{
+	common.CodeInternalError:      "Внутренняя ошибка сервера",
+	common.CodeNotFound:           "Ничего не найдено",
+	common.CodeInvalidRequestBody: "Некорректные данные",
+	common.CodeEmptyFields:        "Данные не могут быть пустыми",
+	common.CodeInvalidTimeRange:   "Время начала не может быть позже времени окончания",
+	common.CodeEventCreateFailed:  "Не удалось создать событие",
+	common.CodeEventUpdateFailed:  "Не удалось обновить событие",
+	common.CodeEventDeleteFailed:  "Не удалось удалить событие",
+	common.CodeInvalidEventID:     "Некорректный идентификатор события",
+	common.CodeInvalidTimeZone:    "Неизвестный часовой пояс %q, укажите имя IANA, например Europe/Moscow",
+	common.CodeInvalidDate:        "Даты указываются в формате ГГГГ-ММ-ДД, дата окончания не раньше даты начала",
+	common.CodeInvalidEventTypes:  "Типы событий должны быть списком из: %s",
+	common.CodeInvalidLastEventID: "Last-Event-ID должен быть идентификатором события, полученного из потока",
+
+	common.CodeInvalidEventStatus:        "Статус события должен быть одним из: %s",
+	common.CodeEventTransitionNotAllowed: "Событие в статусе %s нельзя перевести в статус %s",
+	common.CodeEventNotEditable:          "Событие в статусе %s больше нельзя изменить",
+	common.CodeEventNotOpen:              "Регистрация на событие закрыта",
+	common.CodeCancellationReasonMissing: "Для отмены события нужно указать причину",
+
+	common.CodeInvalidVenueID:      "Некорректный идентификатор площадки",
+	common.CodeInvalidRoomID:       "Некорректный идентификатор помещения",
+	common.CodeInvalidRoomCapacity: "Вместимость помещения не может быть отрицательной",
+	common.CodeUnknownRoom:         "Помещение не существует",
+	common.CodeRoomAlreadyBooked:   "Помещение на это время уже занято событием %q (%s)",
+	common.CodeRoomNameTaken:       "На площадке уже есть помещение с названием %q",
+	common.CodeRoomInUse:           "Помещение забронировано событиями, которые ещё не завершились",
+	common.CodeVenueNotEmpty:       "На площадке ещё есть помещения",
+
+	common.CodeInvalidCategoryID: "Некорректный идентификатор категории",
+	common.CodeInvalidTagID:      "Некорректный идентификатор тега",
+	common.CodeInvalidTag:        "Тег %q должен содержать от 1 до %d символов",
+	common.CodeTooManyTags:       "У события может быть не больше %d тегов",
+	common.CodeInvalidTagMatch:   "Режим сопоставления тегов должен быть одним из: %s",
+	common.CodeUnknownCategory:   "Категория не существует",
+	common.CodeTagNameTaken:      "Тег с названием %q уже существует",
+	common.CodeCategoryNameTaken: "Категория с названием %q уже существует",
+
+	common.CodeInvalidParticipant:     "Идентификатор участника должен содержать от 1 до %d символов",
+	common.CodeInvalidParticipantRole: "Роль участника должна быть одной из: %s",
+
+	common.CodeFreeBusyWindowTooLong:    "Интервал не может быть длиннее %d дней",
+	common.CodeFreeBusyInvalidResources: "Укажите от 1 до %d пользователей и помещений",
+	common.CodeInvalidSlotLength:        "Длительность слота должна быть положительной и укладываться в интервал",
+
+	common.CodeAlreadyRegistered:    "Вы уже зарегистрированы на это событие",
+	common.CodeRegistrationNotFound: "Вы не зарегистрированы на это событие",
+	common.CodeRegistrationFailed:   "Не удалось обновить регистрацию",
+	common.CodeTooManyWatchedEvents: "Одно соединение может отслеживать не более %d событий",
+	common.CodeTooManyConnections:   "Слишком много активных соединений, повторите позже",
+
+	common.CodeUnauthenticated: "Требуется аутентификация",
+	common.CodeInvalidToken:    "Токен доступа недействителен или истёк",
+	common.CodeAuthUnavailable: "Сервис аутентификации недоступен",
+	common.CodeForbidden:       "У вас нет прав на это действие",
+
+	common.CodeAPIKeyInvalidName:   "Имя API-ключа не может быть пустым",
+	common.CodeAPIKeyInvalidScopes: "Области действия API-ключа должны быть непустым списком из: %s",
+
+	common.CodeRateLimited: "Слишком много запросов, повторите позже",
+
+	common.CodeInvalidTenant:   "Некорректный тенант %q",
+	common.CodeTenantForbidden: "У вас нет доступа к тенанту %q",
+
+	common.CodeIdempotencyKeyInvalid:    "Заголовок Idempotency-Key должен содержать не более %d символов",
+	common.CodeIdempotencyKeyReused:     "Idempotency-Key уже использован для другого запроса",
+	common.CodeIdempotencyKeyInProgress: "Запрос с этим Idempotency-Key ещё обрабатывается",
+
+	common.CodeInvalidWebhookID:         "Некорректный идентификатор вебхука",
+	common.CodeInvalidWebhookDeliveryID: "Некорректный идентификатор доставки вебхука",
+	common.CodeWebhookInvalidURL:        "URL вебхука должен быть абсолютным http или https адресом",
+	common.CodeWebhookInvalidEventTypes: "Типы событий вебхука должны быть непустым списком из: %s",
+	common.CodeWebhookForbiddenHost:     "Хост вебхука %s должен разрешаться только в публичные адреса",
+}
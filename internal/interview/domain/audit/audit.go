@@ -0,0 +1,33 @@
+// Package audit carries who is behind a change, so that the repositories can
+// record it next to the change.
+package audit
+
+import (
+	"context"
+
+	"online-registration/internal/interview/domain/auth"
+)
+
+// UnknownActor is recorded for changes made without a principal.
+const UnknownActor = "unknown"
+
+type requestIDCtxKey struct{}
+
+// ContextWithRequestID attaches the id of the request or command that causes
+// the changes made with ctx.
+func ContextWithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDCtxKey{}, requestID)
+}
+
+func RequestIDFromContext(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDCtxKey{}).(string)
+	return requestID
+}
+
+// Actor returns the subject of the principal of ctx.
+func Actor(ctx context.Context) string {
+	if principal := auth.PrincipalFromContext(ctx); principal != nil {
+		return principal.Subject
+	}
+	return UnknownActor
+}
@@ -0,0 +1,47 @@
+package auth
+
+import (
+	"context"
+	"slices"
+)
+
+// Principal is the authenticated caller of a request.
+type Principal struct {
+	Subject string
+	Email   string
+	Name    string
+	Roles   []string
+	// Scopes are set instead of Roles for API keys.
+	Scopes []string
+	// Tenant binds the principal to a tenant. Principals without one act on
+	// the default tenant unless the policy lets them switch tenants.
+	Tenant string
+	// System principals act on behalf of the service itself, e.g. when
+	// commands arrive from a trusted broker, and are not subject to policies.
+	System bool
+}
+
+// SystemPrincipal returns the principal of an internal actor.
+func SystemPrincipal(name string) *Principal {
+	return &Principal{
+		Subject: "system:" + name,
+		System:  true,
+	}
+}
+
+func (p *Principal) HasRole(role string) bool {
+	return slices.Contains(p.Roles, role)
+}
+
+type principalCtxKey struct{}
+
+func ContextWithPrincipal(ctx context.Context, principal *Principal) context.Context {
+	return context.WithValue(ctx, principalCtxKey{}, principal)
+}
+
+// PrincipalFromContext returns the principal of the request, or nil when the
+// request is not authenticated.
+func PrincipalFromContext(ctx context.Context) *Principal {
+	principal, _ := ctx.Value(principalCtxKey{}).(*Principal)
+	return principal
+}
@@ -0,0 +1,256 @@
+// Package cron parses cron expressions and finds the times they fire at.
+package cron
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule is a parsed cron expression. It is either five fields, minute,
+// hour, day of month, month and day of week, one of the @yearly, @monthly,
+// @weekly, @daily and @hourly shorthands, or @every followed by a duration.
+type Schedule struct {
+	spec  string
+	every time.Duration
+
+	minute, hour, dom, month, dow uint64
+	// a day matches when both of its fields do if either is *, and when
+	// one of them does otherwise, as in Vixie cron
+	anyDom, anyDow bool
+}
+
+var shorthands = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+type field struct {
+	name     string
+	min, max int
+	names    []string
+}
+
+var (
+	minuteField = field{name: "minute", min: 0, max: 59}
+	hourField   = field{name: "hour", min: 0, max: 23}
+	domField    = field{name: "day of month", min: 1, max: 31}
+	monthField  = field{name: "month", min: 1, max: 12, names: []string{
+		"jan", "feb", "mar", "apr", "may", "jun", "jul", "aug", "sep", "oct", "nov", "dec",
+	}}
+	// 7 is Sunday as well as 0
+	dowField = field{name: "day of week", min: 0, max: 7, names: []string{
+		"sun", "mon", "tue", "wed", "thu", "fri", "sat",
+	}}
+)
+
+// maxSearch bounds the search for the next time of expressions that match
+// rarely or never, like "0 0 30 2 *".
+const maxSearch = 5 * 366 * 24 * time.Hour
+
+// Parse parses a cron expression, see Schedule for the forms it takes.
+func Parse(spec string) (*Schedule, error) {
+	spec = strings.TrimSpace(spec)
+	schedule := &Schedule{spec: spec}
+
+	expr := spec
+	if strings.HasPrefix(expr, "@") {
+		if duration, ok := strings.CutPrefix(expr, "@every "); ok {
+			every, err := time.ParseDuration(strings.TrimSpace(duration))
+			if err != nil {
+				return nil, fmt.Errorf("cron %q: %w", spec, err)
+			}
+			if every < time.Second {
+				return nil, fmt.Errorf("cron %q: interval is shorter than a second", spec)
+			}
+			schedule.every = every
+			return schedule, nil
+		}
+
+		fields, ok := shorthands[strings.ToLower(expr)]
+		if !ok {
+			return nil, fmt.Errorf("cron %q: unknown shorthand", spec)
+		}
+		expr = fields
+	}
+
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("cron %q: expected 5 fields, got %d", spec, len(fields))
+	}
+
+	var err error
+	if schedule.minute, err = minuteField.parse(fields[0]); err != nil {
+		return nil, fmt.Errorf("cron %q: %w", spec, err)
+	}
+	if schedule.hour, err = hourField.parse(fields[1]); err != nil {
+		return nil, fmt.Errorf("cron %q: %w", spec, err)
+	}
+	if schedule.dom, err = domField.parse(fields[2]); err != nil {
+		return nil, fmt.Errorf("cron %q: %w", spec, err)
+	}
+	if schedule.month, err = monthField.parse(fields[3]); err != nil {
+		return nil, fmt.Errorf("cron %q: %w", spec, err)
+	}
+	if schedule.dow, err = dowField.parse(fields[4]); err != nil {
+		return nil, fmt.Errorf("cron %q: %w", spec, err)
+	}
+	if schedule.dow&(1<<7) != 0 {
+		schedule.dow |= 1
+	}
+	schedule.anyDom = strings.HasPrefix(fields[2], "*")
+	schedule.anyDow = strings.HasPrefix(fields[4], "*")
+
+	return schedule, nil
+}
+
+// parse turns a comma separated list of values, ranges and steps into a set
+// of bits.
+func (f field) parse(expr string) (uint64, error) {
+	var set uint64
+	for _, item := range strings.Split(expr, ",") {
+		rangeExpr, stepExpr, hasStep := strings.Cut(item, "/")
+
+		step := 1
+		if hasStep {
+			var err error
+			step, err = strconv.Atoi(stepExpr)
+			if err != nil || step <= 0 {
+				return 0, fmt.Errorf("%s: invalid step %q", f.name, stepExpr)
+			}
+		}
+
+		var low, high int
+		switch lowExpr, highExpr, isRange := strings.Cut(rangeExpr, "-"); {
+		case rangeExpr == "*":
+			low, high = f.min, f.max
+		case isRange:
+			var err error
+			if low, err = f.value(lowExpr); err != nil {
+				return 0, err
+			}
+			if high, err = f.value(highExpr); err != nil {
+				return 0, err
+			}
+			if low > high {
+				return 0, fmt.Errorf("%s: range %q is backwards", f.name, rangeExpr)
+			}
+		default:
+			var err error
+			if low, err = f.value(rangeExpr); err != nil {
+				return 0, err
+			}
+			high = low
+			// "5/15" runs from 5 to the end of the range
+			if hasStep {
+				high = f.max
+			}
+		}
+
+		for value := low; value <= high; value += step {
+			set |= 1 << value
+		}
+	}
+	return set, nil
+}
+
+func (f field) value(expr string) (int, error) {
+	for i, name := range f.names {
+		if strings.EqualFold(expr, name) {
+			return f.min + i, nil
+		}
+	}
+	value, err := strconv.Atoi(expr)
+	if err != nil || value < f.min || value > f.max {
+		return 0, fmt.Errorf("%s: %q is not between %d and %d", f.name, expr, f.min, f.max)
+	}
+	return value, nil
+}
+
+// String returns the expression the schedule was parsed from.
+func (s *Schedule) String() string {
+	return s.spec
+}
+
+// Next returns the first time after t the schedule fires at, in the
+// location of t. Times skipped when the clocks go forward are not run. It
+// returns the zero time when the schedule doesn't fire in the next five
+// years.
+func (s *Schedule) Next(t time.Time) time.Time {
+	if s.every > 0 {
+		return t.Truncate(time.Second).Add(s.every)
+	}
+
+	loc := t.Location()
+	limit := t.Add(maxSearch)
+	after := wallClock(t)
+	t = t.Truncate(time.Minute).Add(time.Minute)
+
+	for t.Before(limit) {
+		if !has(s.month, int(t.Month())) {
+			t = forward(t, time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc))
+			continue
+		}
+		if !s.dayMatches(t) {
+			t = forward(t, time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc))
+			continue
+		}
+		if !has(s.hour, t.Hour()) {
+			t = nextHour(t)
+			continue
+		}
+		if !has(s.minute, t.Minute()) {
+			t = t.Add(time.Minute)
+			continue
+		}
+		// when the clocks go back, a task pinned to some hours runs once in
+		// the repeated hour, like in Vixie cron
+		if s.hour != everyHour && !wallClock(t).After(after) {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+const everyHour = 1<<24 - 1
+
+// forward moves t on to next. time.Date puts a time skipped when the clocks
+// go forward an hour early, before t at times, so t then moves on to the
+// next hour instead.
+func forward(t, next time.Time) time.Time {
+	if next.After(t) {
+		return next
+	}
+	return nextHour(t)
+}
+
+func nextHour(t time.Time) time.Time {
+	return t.Add(time.Duration(60-t.Minute()) * time.Minute)
+}
+
+// wallClock returns the date and time t shows in its location, read as UTC
+// so that the repeated hour of a DST change compares as the same time.
+func wallClock(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.UTC)
+}
+
+func (s *Schedule) dayMatches(t time.Time) bool {
+	dom := has(s.dom, t.Day())
+	dow := has(s.dow, int(t.Weekday()))
+	if s.anyDom || s.anyDow {
+		return dom && dow
+	}
+	return dom || dow
+}
+
+func has(set uint64, value int) bool {
+	return set&(1<<value) != 0
+}
@@ -0,0 +1,184 @@
+package cron
+
+import (
+	"testing"
+	"time"
+	_ "time/tzdata"
+)
+
+func TestParseRejects(t *testing.T) {
+	for _, spec := range []string{
+		"",
+		"* * * *",
+		"* * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * 32 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"-1 * * * *",
+		"5-1 * * * *",
+		"* * * dec-jan *",
+		"*/0 * * * *",
+		"*/-5 * * * *",
+		"*/x * * * *",
+		"1,,2 * * * *",
+		"mon * * * *",
+		"* * * * funday",
+		"@fortnightly",
+		"@every",
+		"@every nonsense",
+		"@every 500ms",
+		"@every -1m",
+	} {
+		if _, err := Parse(spec); err == nil {
+			t.Errorf("Parse(%q) succeeded", spec)
+		}
+	}
+}
+
+func mustParse(t *testing.T, spec string) *Schedule {
+	t.Helper()
+
+	schedule, err := Parse(spec)
+	if err != nil {
+		t.Fatalf("Parse(%q): %v", spec, err)
+	}
+	return schedule
+}
+
+func TestNext(t *testing.T) {
+	// a Sunday
+	from := time.Date(2026, time.March, 1, 10, 17, 30, 0, time.UTC)
+	at := func(month time.Month, day, hour, minute int) time.Time {
+		return time.Date(2026, month, day, hour, minute, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		spec string
+		want time.Time
+	}{
+		{"* * * * *", at(time.March, 1, 10, 18)},
+		{"17 * * * *", at(time.March, 1, 11, 17)},
+		{"*/15 * * * *", at(time.March, 1, 10, 30)},
+		{"5/15 * * * *", at(time.March, 1, 10, 20)},
+		{"5/15 11 * * *", at(time.March, 1, 11, 5)},
+		{"0,45 10-12 * * *", at(time.March, 1, 10, 45)},
+		{"0 9 * * *", at(time.March, 2, 9, 0)},
+		{"@hourly", at(time.March, 1, 11, 0)},
+		{"@Daily", at(time.March, 2, 0, 0)},
+		{"@weekly", at(time.March, 8, 0, 0)},
+		{"@monthly", at(time.April, 1, 0, 0)},
+		{"@yearly", time.Date(2027, time.January, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 9 * jan,jul *", at(time.July, 1, 9, 0)},
+		{"0 9 * * mon-fri", at(time.March, 2, 9, 0)},
+		{"0 9 * * SAT", at(time.March, 7, 9, 0)},
+		// 7 is Sunday, as is 0
+		{"0 9 * * 7", at(time.March, 8, 9, 0)},
+		{"0 11 * * 0", at(time.March, 1, 11, 0)},
+		{"0 9 * * 5-7", at(time.March, 6, 9, 0)},
+		// days match when either field does unless one of them is *
+		{"0 0 13 * fri", at(time.March, 6, 0, 0)},
+		{"0 0 4 * fri", at(time.March, 4, 0, 0)},
+		{"0 0 13 * *", at(time.March, 13, 0, 0)},
+		{"0 0 * * fri", at(time.March, 6, 0, 0)},
+		{"0 0 */2 * fri", at(time.March, 13, 0, 0)},
+		{"0 0 31 * *", at(time.March, 31, 0, 0)},
+		{"0 0 29 2 *", time.Date(2028, time.February, 29, 0, 0, 0, 0, time.UTC)},
+		// never fires
+		{"0 0 30 2 *", time.Time{}},
+		{"0 0 31 4,6,9,11 *", time.Time{}},
+		{"@every 90s", time.Date(2026, time.March, 1, 10, 19, 0, 0, time.UTC)},
+		{"@every 1s", time.Date(2026, time.March, 1, 10, 17, 31, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			schedule := mustParse(t, tt.spec)
+			if got := schedule.Next(from); !got.Equal(tt.want) {
+				t.Errorf("Next(%s) = %s, want %s", from, got, tt.want)
+			}
+			if schedule.String() != tt.spec {
+				t.Errorf("String() = %q, want %q", schedule.String(), tt.spec)
+			}
+		})
+	}
+}
+
+func TestNextKeepsLocation(t *testing.T) {
+	loc, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Fatalf("LoadLocation: %v", err)
+	}
+
+	next := mustParse(t, "0 9 * * *").Next(time.Date(2026, time.March, 1, 10, 0, 0, 0, loc))
+	if want := time.Date(2026, time.March, 2, 9, 0, 0, 0, loc); !next.Equal(want) || next.Location() != loc {
+		t.Errorf("Next = %s, want %s", next, want)
+	}
+}
+
+func TestNextAcrossDaylightSaving(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatalf("LoadLocation: %v", err)
+	}
+	// in 2026 the clocks go from 2:00 to 3:00 on March 29, and from 3:00
+	// back to 2:00 on October 25
+	local := func(month time.Month, day, hour, minute int) time.Time {
+		return time.Date(2026, month, day, hour, minute, 0, 0, berlin)
+	}
+	utc := func(month time.Month, day, hour, minute int) time.Time {
+		return time.Date(2026, month, day, hour, minute, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name string
+		spec string
+		from time.Time
+		want []time.Time
+	}{
+		{
+			name: "skipped time is not run",
+			spec: "30 2 * * *",
+			from: local(time.March, 28, 12, 0),
+			want: []time.Time{local(time.March, 30, 2, 30)},
+		},
+		{
+			name: "hours after the gap run",
+			spec: "30 3 * * *",
+			from: local(time.March, 28, 12, 0),
+			want: []time.Time{utc(time.March, 29, 1, 30)},
+		},
+		{
+			name: "hourly runs on through the gap",
+			spec: "0 * * * *",
+			from: utc(time.March, 28, 23, 30).In(berlin),
+			want: []time.Time{utc(time.March, 29, 0, 0), utc(time.March, 29, 1, 0), utc(time.March, 29, 2, 0)},
+		},
+		{
+			name: "repeated hour runs once",
+			spec: "30 2 * * *",
+			from: local(time.October, 24, 12, 0),
+			want: []time.Time{utc(time.October, 25, 0, 30), utc(time.October, 26, 1, 30)},
+		},
+		{
+			name: "hourly runs in both copies of the repeated hour",
+			spec: "0 * * * *",
+			from: utc(time.October, 24, 23, 30).In(berlin),
+			want: []time.Time{utc(time.October, 25, 0, 0), utc(time.October, 25, 1, 0), utc(time.October, 25, 2, 0)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule := mustParse(t, tt.spec)
+			from := tt.from
+			for i, want := range tt.want {
+				got := schedule.Next(from)
+				if !got.Equal(want) {
+					t.Fatalf("run %d: Next(%s) = %s, want %s", i, from, got, want.In(berlin))
+				}
+				from = got
+			}
+		})
+	}
+}
@@ -1,10 +1,132 @@
 package dto
 
-import "time"
+import (
+	"errors"
+	"fmt"
+	"online-registration/internal/common"
+	"online-registration/internal/interview/domain/entity"
+	"online-registration/internal/interview/helper"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
 
 type CreateEventRequestDTO struct {
+	Title       string    `json:"title"`
+	Description string    `json:"description"`
+	StartTime   time.Time `json:"start_time"`
+	EndTime     time.Time `json:"end_time"`
+	// TimeZone is an IANA time zone name, UTC when empty.
+	TimeZone string `json:"time_zone"`
+	// AllDay events are given by StartDate and EndDate, both included,
+	// written as YYYY-MM-DD, instead of StartTime and EndTime.
+	AllDay     bool   `json:"all_day"`
+	StartDate  string `json:"start_date,omitempty"`
+	EndDate    string `json:"end_date,omitempty"`
+	Onboarding bool   `json:"onboarding"`
+	// RoomID books the event into a room. A room holds one event at a time.
+	RoomID *uuid.UUID `json:"room_id,omitempty"`
+	// CategoryID puts the event in a category.
+	CategoryID *uuid.UUID `json:"category_id,omitempty"`
+	// Tags are tag names, missing tags are created.
+	Tags []string `json:"tags,omitempty"`
+	// Publish opens the event for registration right away instead of
+	// creating a draft.
+	Publish bool `json:"publish"`
+}
+
+// Validate checks the required fields and that start time is not after end
+// time. It fills in the default time zone, and the times of all-day events,
+// and normalizes the tags.
+func (d *CreateEventRequestDTO) Validate() error {
+	if d.TimeZone == "" {
+		d.TimeZone = entity.DefaultTimeZone
+	}
+	loc, err := entity.LoadTimeZone(d.TimeZone)
+	if err != nil {
+		return common.NewCodedError(helper.InvalidArgument, common.CodeInvalidTimeZone, err, d.TimeZone)
+	}
+
+	if d.AllDay {
+		if d.StartDate == "" || d.EndDate == "" {
+			return common.NewCodedError(
+				helper.InvalidArgument, common.CodeEmptyFields, errors.New("all-day event dates are empty"),
+			)
+		}
+
+		d.StartTime, d.EndTime, err = entity.AllDayTimes(d.StartDate, d.EndDate, loc)
+		if err != nil {
+			return common.NewCodedError(helper.InvalidArgument, common.CodeInvalidDate, err)
+		}
+	}
+
+	if d.StartTime.After(d.EndTime) {
+		return common.NewCodedError(
+			helper.InvalidArgument, common.CodeInvalidTimeRange, errors.New("start time cannot be after end time"),
+		)
+	}
+
+	if d.Title == "" || d.Description == "" || d.StartTime.IsZero() || d.EndTime.IsZero() {
+		return common.NewCodedError(
+			helper.InvalidArgument, common.CodeEmptyFields, errors.New("data cannot be empty"),
+		)
+	}
+
+	d.Tags = entity.NormalizeTags(d.Tags)
+	for _, tag := range d.Tags {
+		if utf8.RuneCountInString(tag) > entity.MaxTagLength {
+			return common.NewCodedError(
+				helper.InvalidArgument, common.CodeInvalidTag, fmt.Errorf("tag %q is too long", tag),
+				tag, entity.MaxTagLength,
+			)
+		}
+	}
+	if len(d.Tags) > entity.MaxEventTags {
+		return common.NewCodedError(
+			helper.InvalidArgument, common.CodeTooManyTags, fmt.Errorf("%d tags", len(d.Tags)), entity.MaxEventTags,
+		)
+	}
+
+	return nil
+}
+
+type ListEventsRequestDTO struct {
+	// Query searches the titles and descriptions of the events.
+	Query    string
+	Statuses []string
+	// Tags selects the events with any of the tags, or with all of them when
+	// TagMatch is "all".
+	Tags        []string
+	TagMatch    string
+	CategoryIDs []uuid.UUID
+	Limit       int
+	Offset      int
+}
+
+// EventStreamRequestDTO selects the event changes streamed to a client.
+// Empty lists select every change.
+type EventStreamRequestDTO struct {
+	Types    []string
+	EventIDs []uuid.UUID
+	// Tags selects the changes of events with any of the tags.
+	Tags        []string
+	CategoryIDs []uuid.UUID
+	// LastEventID resumes the stream after the last change the client
+	// received, it starts with the next change when zero.
+	LastEventID int64
+}
+
+type UpdateEventRequestDTO struct {
+	ID          uuid.UUID
 	Title       string
 	Description string
 	StartTime   time.Time
 	EndTime     time.Time
+	TimeZone    string
+	AllDay      bool
+	Onboarding  bool
+	RoomID      *uuid.UUID
+	CategoryID  *uuid.UUID
+	Tags        []string
 }
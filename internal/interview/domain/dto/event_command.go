@@ -0,0 +1,38 @@
+package dto
+
+import "github.com/google/uuid"
+
+// Commands act on the tenant named by TenantID, or on the default tenant of
+// the worker when it is empty.
+
+type CreateEventCommandDTO struct {
+	CommandID string `json:"command_id"`
+	TenantID  string `json:"tenant_id"`
+	CreateEventRequestDTO
+}
+
+type CancelEventCommandDTO struct {
+	CommandID string    `json:"command_id"`
+	TenantID  string    `json:"tenant_id"`
+	EventID   uuid.UUID `json:"event_id"`
+	Reason    string    `json:"reason"`
+}
+
+const (
+	CommandStatusOK    = "ok"
+	CommandStatusError = "error"
+)
+
+// CommandResultDTO is published in response to every processed command.
+type CommandResultDTO struct {
+	CommandID string              `json:"command_id"`
+	Command   string              `json:"command"`
+	Status    string              `json:"status"`
+	Event     *EventPayload       `json:"event,omitempty"`
+	Error     *CommandErrorResult `json:"error,omitempty"`
+}
+
+type CommandErrorResult struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
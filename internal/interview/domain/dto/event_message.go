@@ -0,0 +1,81 @@
+package dto
+
+import (
+	"time"
+
+	"online-registration/internal/interview/domain/entity"
+
+	"github.com/google/uuid"
+)
+
+// Subjects the event lifecycle messages are published to.
+const (
+	EventSubjects       = "events.>"
+	EventCreatedSubject = "events.created"
+	EventUpdatedSubject = "events.updated"
+	EventDeletedSubject = "events.deleted"
+
+	EventPublishedSubject = "events.published"
+	EventCancelledSubject = "events.cancelled"
+	EventCompletedSubject = "events.completed"
+)
+
+// EventTypes lists the lifecycle subjects clients can subscribe to.
+var EventTypes = []string{
+	EventCreatedSubject,
+	EventUpdatedSubject,
+	EventDeletedSubject,
+	EventPublishedSubject,
+	EventCancelledSubject,
+	EventCompletedSubject,
+}
+
+type EventMessage struct {
+	ID         uuid.UUID    `json:"id"`
+	Type       string       `json:"type"`
+	OccurredAt time.Time    `json:"occurred_at"`
+	Event      EventPayload `json:"event"`
+}
+
+type EventPayload struct {
+	ID                 uuid.UUID  `json:"id"`
+	TenantID           string     `json:"tenant_id"`
+	Title              string     `json:"title"`
+	Description        string     `json:"description"`
+	StartTime          time.Time  `json:"start_time"`
+	EndTime            time.Time  `json:"end_time"`
+	TimeZone           string     `json:"time_zone"`
+	AllDay             bool       `json:"all_day"`
+	Onboarding         bool       `json:"onboarding"`
+	RoomID             *uuid.UUID `json:"room_id,omitempty"`
+	CategoryID         *uuid.UUID `json:"category_id,omitempty"`
+	Tags               []string   `json:"tags,omitempty"`
+	Status             string     `json:"status"`
+	CancellationReason string     `json:"cancellation_reason,omitempty"`
+	CreatedBy          string     `json:"created_by,omitempty"`
+	CreatedAt          time.Time  `json:"created_at"`
+	UpdatedAt          time.Time  `json:"updated_at"`
+}
+
+// NewEventPayload is the payload carrying event in messages and results.
+func NewEventPayload(event *entity.Event) EventPayload {
+	return EventPayload{
+		ID:                 event.ID,
+		TenantID:           event.TenantID,
+		Title:              event.Title,
+		Description:        event.Description,
+		StartTime:          event.StartTime,
+		EndTime:            event.EndTime,
+		TimeZone:           event.TimeZone,
+		AllDay:             event.AllDay,
+		Onboarding:         event.Onboarding,
+		RoomID:             event.RoomID,
+		CategoryID:         event.CategoryID,
+		Tags:               event.Tags,
+		Status:             event.Status,
+		CancellationReason: event.CancellationReason,
+		CreatedBy:          event.CreatedBy,
+		CreatedAt:          event.CreatedAt,
+		UpdatedAt:          event.UpdatedAt,
+	}
+}
@@ -0,0 +1,43 @@
+package dto
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"online-registration/internal/interview/domain/entity"
+
+	"github.com/google/uuid"
+)
+
+func TestNewEventPayloadCopiesEveryField(t *testing.T) {
+	roomID, categoryID := uuid.New(), uuid.New()
+	start := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	event := &entity.Event{
+		ID:                 uuid.New(),
+		TenantID:           "acme",
+		Title:              "Onboarding",
+		Description:        "First day",
+		StartTime:          start,
+		EndTime:            start.Add(time.Hour),
+		TimeZone:           "Europe/Berlin",
+		AllDay:             true,
+		Onboarding:         true,
+		RoomID:             &roomID,
+		CategoryID:         &categoryID,
+		Tags:               []string{"hr"},
+		Status:             entity.EventCancelled,
+		CancellationReason: "moved",
+		CreatedBy:          "alice",
+		CreatedAt:          start.Add(-time.Hour),
+		UpdatedAt:          start.Add(-time.Minute),
+	}
+
+	// a field missing from NewEventPayload stays zero
+	payload := reflect.ValueOf(NewEventPayload(event))
+	for i := 0; i < payload.NumField(); i++ {
+		if payload.Field(i).IsZero() {
+			t.Errorf("NewEventPayload leaves %s empty", payload.Type().Field(i).Name)
+		}
+	}
+}
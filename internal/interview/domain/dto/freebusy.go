@@ -0,0 +1,17 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type FreeBusyRequestDTO struct {
+	UserIDs []string
+	RoomIDs []uuid.UUID
+	Start   time.Time
+	End     time.Time
+	// SlotLength asks for the first common free slot of this length, none is
+	// looked for when it is zero.
+	SlotLength time.Duration
+}
@@ -0,0 +1,43 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Subjects the registration messages are published to. They live under the
+// events stream so the outbox relay publishes them without extra setup.
+const (
+	RegistrationSubjects         = "events.registrations.>"
+	RegistrationCreatedSubject   = "events.registrations.created"
+	RegistrationCancelledSubject = "events.registrations.cancelled"
+	// RegistrationWaitlistedSubject notifies that a registrant joined the
+	// waitlist of a full event.
+	RegistrationWaitlistedSubject = "events.registrations.waitlisted"
+	// RegistrationEventCancelledSubject notifies a registrant that the
+	// event was cancelled. The registration itself is left as it is.
+	RegistrationEventCancelledSubject = "events.registrations.event_cancelled"
+	// RegistrationPromotedSubject notifies a registrant that they were
+	// moved from the waitlist to the event.
+	RegistrationPromotedSubject = "events.registrations.promoted"
+)
+
+type RegistrationMessage struct {
+	ID           uuid.UUID           `json:"id"`
+	Type         string              `json:"type"`
+	OccurredAt   time.Time           `json:"occurred_at"`
+	Registration RegistrationPayload `json:"registration"`
+}
+
+type RegistrationPayload struct {
+	ID         uuid.UUID `json:"id"`
+	TenantID   string    `json:"tenant_id"`
+	EventID    uuid.UUID `json:"event_id"`
+	UserID     string    `json:"user_id"`
+	Email      string    `json:"email,omitempty"`
+	Status     string    `json:"status"`
+	Onboarding bool      `json:"onboarding"`
+	// Reason is the cancellation reason of the event.
+	Reason string `json:"reason,omitempty"`
+}
@@ -0,0 +1,9 @@
+package dto
+
+type TagRequestDTO struct {
+	Name string
+}
+
+type CategoryRequestDTO struct {
+	Name string
+}
@@ -0,0 +1,11 @@
+package dto
+
+type VenueRequestDTO struct {
+	Name    string
+	Address string
+}
+
+type RoomRequestDTO struct {
+	Name     string
+	Capacity int
+}
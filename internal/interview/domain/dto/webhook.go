@@ -0,0 +1,8 @@
+package dto
+
+type WebhookSubscriptionRequestDTO struct {
+	URL        string
+	Secret     string
+	EventTypes []string
+	Active     bool
+}
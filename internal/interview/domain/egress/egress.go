@@ -0,0 +1,94 @@
+// Package egress decides which addresses the service may call on behalf of
+// its users, e.g. webhook receivers. Internal addresses are refused so that
+// a user can't make the service reach hosts only it can reach.
+package egress
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"syscall"
+)
+
+// ErrForbiddenAddress is returned for hosts that resolve to an internal
+// address.
+var ErrForbiddenAddress = errors.New("address is not public")
+
+// unroutablePrefixes are the ranges netip doesn't count as private that are
+// still not public addresses.
+var unroutablePrefixes = []netip.Prefix{
+	// "this network" of RFC 1122
+	netip.MustParsePrefix("0.0.0.0/8"),
+	// carrier-grade NAT of RFC 6598
+	netip.MustParsePrefix("100.64.0.0/10"),
+	// benchmarking of RFC 2544
+	netip.MustParsePrefix("198.18.0.0/15"),
+	// reserved of RFC 1112, including the limited broadcast address
+	netip.MustParsePrefix("240.0.0.0/4"),
+	// NAT64 of RFC 6052, whose addresses may embed any IPv4 address
+	netip.MustParsePrefix("64:ff9b::/96"),
+}
+
+// Allowed reports whether addr may be called: it is neither loopback,
+// link-local, private nor otherwise not routed on the internet.
+func Allowed(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	return addr.IsValid() &&
+		!addr.IsUnspecified() &&
+		!addr.IsLoopback() &&
+		!addr.IsPrivate() &&
+		!addr.IsLinkLocalUnicast() &&
+		!addr.IsLinkLocalMulticast() &&
+		!addr.IsInterfaceLocalMulticast() &&
+		!addr.IsMulticast() &&
+		!unroutable(addr)
+}
+
+func unroutable(addr netip.Addr) bool {
+	for _, prefix := range unroutablePrefixes {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckHost resolves host and returns ErrForbiddenAddress when any of its
+// addresses may not be called.
+func CheckHost(ctx context.Context, host string) error {
+	if addr, err := netip.ParseAddr(host); err == nil {
+		return check(addr)
+	}
+
+	addrs, err := net.DefaultResolver.LookupNetIP(ctx, "ip", host)
+	if err != nil {
+		return fmt.Errorf("resolve %s: %w", host, err)
+	}
+	for _, addr := range addrs {
+		if err := check(addr); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Control is a net.Dialer Control function refusing connections to the
+// addresses that may not be called. It checks the address actually dialed,
+// so a host that resolved to a public address when it was registered can't
+// be pointed at an internal one later.
+func Control(_, address string, _ syscall.RawConn) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return fmt.Errorf("parse dialed address %s: %w", address, err)
+	}
+	return check(addrPort.Addr())
+}
+
+func check(addr netip.Addr) error {
+	if !Allowed(addr) {
+		return fmt.Errorf("%s: %w", addr, ErrForbiddenAddress)
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package egress
+
+import (
+	"context"
+	"errors"
+	"net/netip"
+	"testing"
+)
+
+func TestAllowed(t *testing.T) {
+	tests := []struct {
+		addr    string
+		allowed bool
+	}{
+		{"93.184.216.34", true},
+		{"2606:2800:220:1:248:1893:25c8:1946", true},
+		{"127.0.0.1", false},
+		{"::1", false},
+		{"0.0.0.0", false},
+		{"0.1.2.3", false},
+		{"10.1.2.3", false},
+		{"172.16.0.1", false},
+		{"192.168.1.1", false},
+		{"169.254.169.254", false},
+		{"fe80::1", false},
+		{"fc00::1", false},
+		{"100.64.0.1", false},
+		{"100.127.255.254", false},
+		{"100.128.0.1", true},
+		{"198.18.0.1", false},
+		{"198.19.255.254", false},
+		{"198.20.0.1", true},
+		{"240.0.0.1", false},
+		{"255.255.255.255", false},
+		{"64:ff9b::7f00:1", false},
+		{"64:ff9b::5db8:d822", false},
+		{"224.0.0.1", false},
+		{"::ffff:127.0.0.1", false},
+		{"::ffff:10.0.0.1", false},
+	}
+
+	for _, tt := range tests {
+		if got := Allowed(netip.MustParseAddr(tt.addr)); got != tt.allowed {
+			t.Errorf("Allowed(%s) = %v, want %v", tt.addr, got, tt.allowed)
+		}
+	}
+}
+
+func TestCheckHost(t *testing.T) {
+	ctx := context.Background()
+
+	for _, host := range []string{"127.0.0.1", "::1", "localhost", "169.254.169.254"} {
+		if err := CheckHost(ctx, host); !errors.Is(err, ErrForbiddenAddress) {
+			t.Errorf("CheckHost(%s) = %v, want ErrForbiddenAddress", host, err)
+		}
+	}
+
+	if err := CheckHost(ctx, "93.184.216.34"); err != nil {
+		t.Errorf("CheckHost of a public address: %v", err)
+	}
+}
+
+func TestControl(t *testing.T) {
+	if err := Control("tcp4", "127.0.0.1:80", nil); !errors.Is(err, ErrForbiddenAddress) {
+		t.Errorf("Control of a loopback address = %v, want ErrForbiddenAddress", err)
+	}
+	if err := Control("tcp6", "[fe80::1%eth0]:443", nil); !errors.Is(err, ErrForbiddenAddress) {
+		t.Errorf("Control of a link-local address = %v, want ErrForbiddenAddress", err)
+	}
+	if err := Control("tcp4", "93.184.216.34:443", nil); err != nil {
+		t.Errorf("Control of a public address: %v", err)
+	}
+}
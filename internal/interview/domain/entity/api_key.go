@@ -0,0 +1,29 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// APIKey gives a service access to the API without OIDC. Only the hash of
+// the key is stored, the prefix identifies the key when it is presented.
+type APIKey struct {
+	ID      uuid.UUID `json:"id"`
+	Name    string    `json:"name"`
+	Prefix  string    `json:"prefix"`
+	KeyHash string    `json:"-"`
+	Scopes  []string  `json:"scopes"`
+	// Tenant binds the key to a tenant. Keys without one act on the default
+	// tenant unless they hold the tenants:switch scope.
+	Tenant     string     `json:"tenant,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+}
+
+// Active reports whether the key may still be used at now.
+func (k *APIKey) Active(now time.Time) bool {
+	return k.RevokedAt == nil && (k.ExpiresAt == nil || now.Before(*k.ExpiresAt))
+}
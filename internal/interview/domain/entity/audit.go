@@ -0,0 +1,94 @@
+package entity
+
+import (
+	"reflect"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	AuditActionCreated = "created"
+	AuditActionUpdated = "updated"
+	AuditActionDeleted = "deleted"
+
+	AuditActionPublished = "published"
+	AuditActionCancelled = "cancelled"
+	AuditActionCompleted = "completed"
+)
+
+// EventAuditEntry records a change of an event. Entries are never changed
+// once written.
+type EventAuditEntry struct {
+	ID        int64                  `json:"id"`
+	TenantID  string                 `json:"tenant_id"`
+	EventID   uuid.UUID              `json:"event_id"`
+	Action    string                 `json:"action"`
+	Actor     string                 `json:"actor"`
+	RequestID string                 `json:"request_id,omitempty"`
+	Changes   map[string]AuditChange `json:"changes"`
+	CreatedAt time.Time              `json:"created_at"`
+}
+
+// AuditChange holds the values of a field before and after a change. From is
+// nil for created events, To for deleted ones.
+type AuditChange struct {
+	From any `json:"from"`
+	To   any `json:"to"`
+}
+
+// AuditLogFilter selects audit entries for export. Entries are returned in
+// the order they were written, starting after AfterID.
+type AuditLogFilter struct {
+	AfterID int64
+	Since   time.Time
+	Until   time.Time
+	Limit   int
+}
+
+// EventAuditDiff returns the audited fields that differ between before and
+// after, either of which may be nil.
+func EventAuditDiff(before, after *Event) map[string]AuditChange {
+	from, to := eventAuditFields(before), eventAuditFields(after)
+
+	changes := make(map[string]AuditChange)
+	for field := range from {
+		if !reflect.DeepEqual(from[field], to[field]) {
+			changes[field] = AuditChange{From: from[field], To: to[field]}
+		}
+	}
+	for field := range to {
+		if _, ok := from[field]; !ok {
+			changes[field] = AuditChange{To: to[field]}
+		}
+	}
+	return changes
+}
+
+func eventAuditFields(event *Event) map[string]any {
+	if event == nil {
+		return nil
+	}
+	// events without tags compare equal however their tags were read
+	var tags []string
+	if len(event.Tags) > 0 {
+		tags = event.Tags
+	}
+
+	// times are compared in UTC, the database and clients may use other
+	// locations for the same instant
+	return map[string]any{
+		"title":               event.Title,
+		"description":         event.Description,
+		"start_time":          event.StartTime.UTC(),
+		"end_time":            event.EndTime.UTC(),
+		"time_zone":           event.TimeZone,
+		"all_day":             event.AllDay,
+		"onboarding":          event.Onboarding,
+		"room_id":             event.RoomID,
+		"category_id":         event.CategoryID,
+		"tags":                tags,
+		"status":              event.Status,
+		"cancellation_reason": event.CancellationReason,
+	}
+}
@@ -0,0 +1,105 @@
+package entity
+
+import (
+	"slices"
+	"testing"
+	"time"
+	_ "time/tzdata"
+
+	"github.com/google/uuid"
+)
+
+func auditedEvent() *Event {
+	start := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	return &Event{
+		ID: uuid.New(), Title: "Onboarding day", Description: "Welcome", StartTime: start,
+		EndTime: start.Add(time.Hour), TimeZone: "UTC", Status: EventDraft, CreatedBy: "bob",
+	}
+}
+
+func diffFields(changes map[string]AuditChange) []string {
+	fields := make([]string, 0, len(changes))
+	for field := range changes {
+		fields = append(fields, field)
+	}
+	slices.Sort(fields)
+	return fields
+}
+
+func TestEventAuditDiff(t *testing.T) {
+	room := uuid.New()
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatalf("LoadLocation: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		change func(*Event)
+		want   []string
+	}{
+		{"nothing", func(*Event) {}, []string{}},
+		{"title", func(e *Event) { e.Title = "Onboarding week" }, []string{"title"}},
+		{"times", func(e *Event) {
+			e.StartTime = e.StartTime.Add(time.Hour)
+			e.EndTime = e.EndTime.Add(time.Hour)
+		}, []string{"end_time", "start_time"}},
+		{"same instant elsewhere", func(e *Event) {
+			e.StartTime = e.StartTime.In(berlin)
+			e.EndTime = e.EndTime.In(berlin)
+		}, []string{}},
+		{"room", func(e *Event) { e.RoomID = &room }, []string{"room_id"}},
+		{"no tags read as empty", func(e *Event) { e.Tags = []string{} }, []string{}},
+		{"tags", func(e *Event) { e.Tags = []string{"remote"} }, []string{"tags"}},
+		{"cancelled", func(e *Event) {
+			e.Status = EventCancelled
+			e.CancellationReason = "snow"
+		}, []string{"cancellation_reason", "status"}},
+		{"not audited", func(e *Event) {
+			e.UpdatedAt = time.Now()
+			e.CreatedBy = "alice"
+		}, []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := auditedEvent()
+			after := *before
+			tt.change(&after)
+
+			if got := diffFields(EventAuditDiff(before, &after)); !slices.Equal(got, tt.want) {
+				t.Errorf("EventAuditDiff changed %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEventAuditDiffOfUpdate(t *testing.T) {
+	before := auditedEvent()
+	after := *before
+	after.Title = "Onboarding week"
+
+	change := EventAuditDiff(before, &after)["title"]
+	if change.From != "Onboarding day" || change.To != "Onboarding week" {
+		t.Errorf("title changed from %v to %v, want from Onboarding day to Onboarding week", change.From, change.To)
+	}
+}
+
+func TestEventAuditDiffOfCreateAndDelete(t *testing.T) {
+	event := auditedEvent()
+
+	created := EventAuditDiff(nil, event)
+	deleted := EventAuditDiff(event, nil)
+	if len(created) != len(eventAuditFields(event)) || len(deleted) != len(created) {
+		t.Fatalf("changed %d fields on create and %d on delete, want all %d",
+			len(created), len(deleted), len(eventAuditFields(event)))
+	}
+
+	for field, change := range created {
+		if change.From != nil || deleted[field].To != nil {
+			t.Errorf("%s changed from %v on create and to %v on delete, want nil", field, change.From, deleted[field].To)
+		}
+	}
+	if created["title"].To != event.Title || deleted["title"].From != event.Title {
+		t.Errorf("title created as %v and deleted as %v, want %s", created["title"].To, deleted["title"].From, event.Title)
+	}
+}
@@ -1,6 +1,7 @@
 package entity
 
 import (
+	"slices"
 	"time"
 
 	"github.com/google/uuid"
@@ -10,9 +11,73 @@ import (
 type Event struct {
 	bun.BaseModel `bun:"table:events,alias:s"`
 	ID            uuid.UUID
+	TenantID      string
 	Title         string
 	Description   string
 	StartTime     time.Time
 	EndTime       time.Time
-	CreatedAt     time.Time
+	// TimeZone is the IANA zone the event was planned in. Its times are shown
+	// in it, and the days of all-day events are counted in it.
+	TimeZone string
+	// AllDay events take whole days, from the midnight StartTime to the
+	// midnight EndTime after their last day.
+	AllDay     bool
+	Onboarding bool
+	// RoomID is the room the event is booked into, if any.
+	RoomID *uuid.UUID
+	// CategoryID is the category the event is in, if any.
+	CategoryID *uuid.UUID
+	// Tags are the names of the tags of the event, in name order.
+	Tags   []string
+	Status string
+	// CancellationReason is set once the event is cancelled.
+	CancellationReason string
+	CreatedBy          string
+	CreatedAt          time.Time
+	UpdatedAt          time.Time
+}
+
+// Events start as drafts, are published to open them for registration, and
+// end up cancelled, or completed once they are over.
+const (
+	EventDraft     = "draft"
+	EventPublished = "published"
+	EventCancelled = "cancelled"
+	EventCompleted = "completed"
+)
+
+// EventStatuses lists the statuses in lifecycle order.
+var EventStatuses = []string{EventDraft, EventPublished, EventCancelled, EventCompleted}
+
+var eventTransitions = map[string][]string{
+	EventDraft:     {EventPublished, EventCancelled},
+	EventPublished: {EventCancelled, EventCompleted},
+}
+
+// CanTransitionEvent reports whether an event may move from one status to
+// another. Cancelled and completed events are final.
+func CanTransitionEvent(from, to string) bool {
+	return slices.Contains(eventTransitions[from], to)
+}
+
+// Editable reports whether the details of the event may still be changed.
+func (e *Event) Editable() bool {
+	return e.Status == EventDraft || e.Status == EventPublished
+}
+
+// EventFilter selects the events of a listing. Drafts are only included
+// when AllDrafts is set, or when they were created by DraftsOf.
+type EventFilter struct {
+	// Query searches the titles and descriptions, see SearchTerms.
+	Query    string
+	Statuses []string
+	// Tags selects the events with any of the tags, or with all of them
+	// when AllTags is set.
+	Tags        []string
+	AllTags     bool
+	CategoryIDs []uuid.UUID
+	AllDrafts   bool
+	DraftsOf    string
+	Limit       int
+	Offset      int
 }
@@ -0,0 +1,25 @@
+package entity
+
+import (
+	"encoding/json"
+
+	"github.com/google/uuid"
+)
+
+// EventChange is a change of an event streamed to clients, read from the
+// lifecycle message the change wrote to the outbox.
+type EventChange struct {
+	// ID is the id of the outbox message. Clients resume a stream after the
+	// last id they received.
+	ID int64
+	// Type is the subject of the message, e.g. events.updated.
+	Type       string
+	EventID    uuid.UUID
+	TenantID   string
+	Status     string
+	CategoryID *uuid.UUID
+	Tags       []string
+	CreatedBy  string
+	// Message is the lifecycle message as published to NATS.
+	Message json.RawMessage
+}
@@ -0,0 +1,33 @@
+package entity
+
+import "testing"
+
+func TestCanTransitionEvent(t *testing.T) {
+	allowed := map[[2]string]bool{
+		{EventDraft, EventPublished}:     true,
+		{EventDraft, EventCancelled}:     true,
+		{EventPublished, EventCancelled}: true,
+		{EventPublished, EventCompleted}: true,
+	}
+
+	for _, from := range EventStatuses {
+		for _, to := range EventStatuses {
+			if got := CanTransitionEvent(from, to); got != allowed[[2]string{from, to}] {
+				t.Errorf("CanTransitionEvent(%s, %s) = %v, want %v", from, to, got, !got)
+			}
+		}
+	}
+	if CanTransitionEvent("", EventPublished) || CanTransitionEvent(EventDraft, "archived") {
+		t.Error("CanTransitionEvent allows unknown statuses")
+	}
+}
+
+func TestEventEditable(t *testing.T) {
+	for status, want := range map[string]bool{
+		EventDraft: true, EventPublished: true, EventCancelled: false, EventCompleted: false,
+	} {
+		if got := (&Event{Status: status}).Editable(); got != want {
+			t.Errorf("%s event Editable = %v, want %v", status, got, want)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package entity
+
+import (
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Period is a half-open time interval [Start, End).
+type Period struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+// BusyPeriod is a period a user or a room is taken by an event. Exactly one
+// of UserID and RoomID is set.
+type BusyPeriod struct {
+	UserID string
+	RoomID uuid.UUID
+	Period
+}
+
+// FreeBusy describes when users and rooms are busy within a window.
+type FreeBusy struct {
+	Start time.Time              `json:"start"`
+	End   time.Time              `json:"end"`
+	Users map[string][]Period    `json:"users"`
+	Rooms map[uuid.UUID][]Period `json:"rooms"`
+	// Busy merges the busy periods of all users and rooms.
+	Busy []Period `json:"busy"`
+	// FreeSlot is the first period of the requested length in which everyone
+	// is free, nil when there is none or no length was requested.
+	FreeSlot *Period `json:"free_slot"`
+}
+
+// MergePeriods sorts periods and merges the ones that overlap or touch.
+func MergePeriods(periods []Period) []Period {
+	sorted := slices.Clone(periods)
+	slices.SortFunc(sorted, func(a, b Period) int {
+		return a.Start.Compare(b.Start)
+	})
+
+	merged := make([]Period, 0, len(sorted))
+	for _, period := range sorted {
+		if last := len(merged) - 1; last >= 0 && !period.Start.After(merged[last].End) {
+			if period.End.After(merged[last].End) {
+				merged[last].End = period.End
+			}
+			continue
+		}
+		merged = append(merged, period)
+	}
+	return merged
+}
+
+// ClipPeriods cuts periods down to the window between start and end,
+// dropping the ones outside of it.
+func ClipPeriods(periods []Period, start, end time.Time) []Period {
+	clipped := make([]Period, 0, len(periods))
+	for _, period := range periods {
+		if period.Start.Before(start) {
+			period.Start = start
+		}
+		if period.End.After(end) {
+			period.End = end
+		}
+		if period.Start.Before(period.End) {
+			clipped = append(clipped, period)
+		}
+	}
+	return clipped
+}
+
+// FirstFreeSlot returns the first period of length between start and end
+// that doesn't overlap busy, which must be merged.
+func FirstFreeSlot(busy []Period, start, end time.Time, length time.Duration) *Period {
+	free := start
+	for _, period := range busy {
+		if period.Start.Sub(free) >= length {
+			break
+		}
+		if period.End.After(free) {
+			free = period.End
+		}
+	}
+
+	if free.Add(length).After(end) {
+		return nil
+	}
+	return &Period{Start: free, End: free.Add(length)}
+}
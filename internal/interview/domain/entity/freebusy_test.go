@@ -0,0 +1,114 @@
+package entity
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+var freeBusyDay = time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+
+// at is the period between the hours from and to of freeBusyDay.
+func at(from, to float64) Period {
+	hour := func(h float64) time.Time { return freeBusyDay.Add(time.Duration(h * float64(time.Hour))) }
+	return Period{Start: hour(from), End: hour(to)}
+}
+
+func formatPeriods(periods []Period) string {
+	formatted := make([]string, 0, len(periods))
+	for _, period := range periods {
+		formatted = append(formatted, period.Start.Format("15:04")+"-"+period.End.Format("15:04"))
+	}
+	return fmt.Sprint(formatted)
+}
+
+func TestMergePeriods(t *testing.T) {
+	tests := []struct {
+		name    string
+		periods []Period
+		want    []Period
+	}{
+		{"none", nil, nil},
+		{"apart", []Period{at(9, 10), at(11, 12)}, []Period{at(9, 10), at(11, 12)}},
+		{"touching", []Period{at(9, 10), at(10, 11)}, []Period{at(9, 11)}},
+		{"overlapping", []Period{at(9, 10.5), at(10, 11)}, []Period{at(9, 11)}},
+		{"contained", []Period{at(9, 12), at(10, 11)}, []Period{at(9, 12)}},
+		{"same", []Period{at(9, 10), at(9, 10)}, []Period{at(9, 10)}},
+		{"unsorted", []Period{at(14, 15), at(9, 10), at(9.5, 11), at(13, 14)}, []Period{at(9, 11), at(13, 15)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			periods := append([]Period(nil), tt.periods...)
+			got := MergePeriods(periods)
+			if formatPeriods(got) != formatPeriods(tt.want) {
+				t.Errorf("MergePeriods = %s, want %s", formatPeriods(got), formatPeriods(tt.want))
+			}
+			if formatPeriods(periods) != formatPeriods(tt.periods) {
+				t.Errorf("MergePeriods changed its input to %s", formatPeriods(periods))
+			}
+		})
+	}
+}
+
+func TestClipPeriods(t *testing.T) {
+	window := at(9, 17)
+
+	tests := []struct {
+		name    string
+		periods []Period
+		want    []Period
+	}{
+		{"inside", []Period{at(10, 11)}, []Period{at(10, 11)}},
+		{"over the start", []Period{at(8, 10)}, []Period{at(9, 10)}},
+		{"over the end", []Period{at(16, 18)}, []Period{at(16, 17)}},
+		{"over the window", []Period{at(8, 18)}, []Period{at(9, 17)}},
+		{"before", []Period{at(7, 8)}, nil},
+		{"after", []Period{at(18, 19)}, nil},
+		{"ending at the start", []Period{at(8, 9)}, nil},
+		{"starting at the end", []Period{at(17, 18)}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ClipPeriods(tt.periods, window.Start, window.End)
+			if formatPeriods(got) != formatPeriods(tt.want) {
+				t.Errorf("ClipPeriods = %s, want %s", formatPeriods(got), formatPeriods(tt.want))
+			}
+		})
+	}
+}
+
+func TestFirstFreeSlot(t *testing.T) {
+	window := at(9, 17)
+	slot := func(period Period) *Period { return &period }
+
+	tests := []struct {
+		name   string
+		busy   []Period
+		length time.Duration
+		want   *Period
+	}{
+		{"free day", nil, time.Hour, slot(at(9, 10))},
+		{"at the start", []Period{at(10, 11)}, time.Hour, slot(at(9, 10))},
+		{"too short before", []Period{at(9.5, 11)}, time.Hour, slot(at(11, 12))},
+		{"between", []Period{at(9, 10), at(11, 12)}, time.Hour, slot(at(10, 11))},
+		{"gap too short", []Period{at(9, 10), at(10.5, 12)}, time.Hour, slot(at(12, 13))},
+		{"at the end", []Period{at(9, 16)}, time.Hour, slot(at(16, 17))},
+		{"busy before the window", []Period{at(8, 10)}, time.Hour, slot(at(10, 11))},
+		{"whole window", nil, 8 * time.Hour, slot(window)},
+		{"past the end", []Period{at(9, 16.5)}, time.Hour, nil},
+		{"busy all day", []Period{at(9, 17)}, time.Minute, nil},
+		{"no gap long enough", []Period{at(9, 10), at(10.5, 12), at(12.5, 14), at(14.5, 17)}, time.Hour, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := FirstFreeSlot(tt.busy, window.Start, window.End, tt.length)
+			switch {
+			case got == nil && tt.want == nil:
+			case got == nil || tt.want == nil:
+				t.Errorf("FirstFreeSlot = %v, want %v", got, tt.want)
+			case !got.Start.Equal(tt.want.Start) || !got.End.Equal(tt.want.End):
+				t.Errorf("FirstFreeSlot = %s, want %s", formatPeriods([]Period{*got}), formatPeriods([]Period{*tt.want}))
+			}
+		})
+	}
+}
@@ -0,0 +1,25 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type IdempotencyKey struct {
+	Key         string
+	Fingerprint string
+	// Owner is a token of the request holding the key. Only that request
+	// completes or releases the key, so a request whose key was taken over
+	// after it stalled can't overwrite the result of the retry.
+	Owner        uuid.UUID
+	StatusCode   int
+	ResponseBody []byte
+	CreatedAt    time.Time
+	ExpiresAt    time.Time
+}
+
+// Completed reports whether the response for the key has been stored.
+func (k *IdempotencyKey) Completed() bool {
+	return k.StatusCode != 0
+}
@@ -0,0 +1,51 @@
+package entity
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Jobs wait as pending until their RunAt, are running while a worker holds
+// them, and end up succeeded, failed once they run out of attempts, or
+// cancelled.
+const (
+	JobPending   = "pending"
+	JobRunning   = "running"
+	JobSucceeded = "succeeded"
+	JobFailed    = "failed"
+	JobCancelled = "cancelled"
+)
+
+// JobStatuses lists the statuses of jobs.
+var JobStatuses = []string{JobPending, JobRunning, JobSucceeded, JobFailed, JobCancelled}
+
+// Job is a unit of background work, run by the handler of its Kind.
+type Job struct {
+	ID   uuid.UUID `json:"id"`
+	Kind string    `json:"kind"`
+	// TenantID is the tenant the job runs for, every tenant when empty.
+	TenantID string          `json:"tenant_id,omitempty"`
+	Payload  json.RawMessage `json:"payload"`
+	// UniqueKey keeps a second job with the same key from being enqueued
+	// while the first is pending or running.
+	UniqueKey   string    `json:"unique_key,omitempty"`
+	Status      string    `json:"status"`
+	Attempts    int       `json:"attempts"`
+	MaxAttempts int       `json:"max_attempts"`
+	LastError   string    `json:"last_error,omitempty"`
+	RunAt       time.Time `json:"run_at"`
+	// LockedUntil is when the lease of the worker running the job passes.
+	LockedUntil *time.Time `json:"locked_until,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	FinishedAt  *time.Time `json:"finished_at,omitempty"`
+}
+
+// JobFilter selects the jobs of a listing, the newest first.
+type JobFilter struct {
+	Kind     string
+	Statuses []string
+	Limit    int
+}
@@ -0,0 +1,66 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Registrants are emailed when they register, before the event starts, when
+// the registration or the event is cancelled, and when they are promoted
+// from the waitlist.
+const (
+	NotificationConfirmation      = "confirmation"
+	NotificationReminder          = "reminder"
+	NotificationCancellation      = "cancellation"
+	NotificationWaitlistPromotion = "waitlist_promotion"
+)
+
+// NotificationKinds lists the kinds of notifications.
+var NotificationKinds = []string{
+	NotificationConfirmation,
+	NotificationReminder,
+	NotificationCancellation,
+	NotificationWaitlistPromotion,
+}
+
+// Notifications are pending until they are sent, or given up as failed.
+// Pending reminders are cancelled with their registration.
+const (
+	NotificationPending   = "pending"
+	NotificationSent      = "sent"
+	NotificationFailed    = "failed"
+	NotificationCancelled = "cancelled"
+)
+
+// Notification is an email to a registrant, sent at SendAt.
+type Notification struct {
+	ID             uuid.UUID
+	TenantID       string
+	Kind           string
+	RegistrationID uuid.UUID
+	EventID        uuid.UUID
+	Recipient      string
+	// DedupeKey keeps the same notification from being enqueued twice.
+	DedupeKey string
+	Status    string
+	Attempts  int
+	LastError string
+	SendAt    time.Time
+	CreatedAt time.Time
+	SentAt    *time.Time
+}
+
+// Email is a plain text message with attachments.
+type Email struct {
+	To          string
+	Subject     string
+	Text        string
+	Attachments []*Attachment
+}
+
+type Attachment struct {
+	Filename    string
+	ContentType string
+	Content     []byte
+}
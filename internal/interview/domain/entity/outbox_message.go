@@ -0,0 +1,19 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type OutboxMessage struct {
+	ID            int64
+	MessageID     uuid.UUID
+	Subject       string
+	Payload       []byte
+	Attempts      int
+	LastError     string
+	CreatedAt     time.Time
+	NextAttemptAt time.Time
+	PublishedAt   time.Time
+}
@@ -0,0 +1,24 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	ParticipantOrganizer   = "organizer"
+	ParticipantInterviewer = "interviewer"
+)
+
+var ParticipantRoles = []string{ParticipantOrganizer, ParticipantInterviewer}
+
+// Participant is a person taking part in running an event. Participants,
+// like registrants, are busy while the event takes place.
+type Participant struct {
+	EventID   uuid.UUID `json:"event_id"`
+	TenantID  string    `json:"tenant_id"`
+	UserID    string    `json:"user_id"`
+	Role      string    `json:"role"`
+	CreatedAt time.Time `json:"created_at"`
+}
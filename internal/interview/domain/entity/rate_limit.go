@@ -0,0 +1,46 @@
+package entity
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RateLimit is a token bucket holding up to Requests tokens, refilled at
+// Requests per Period.
+type RateLimit struct {
+	Requests int
+	Period   time.Duration
+}
+
+// ParseRateLimit parses a limit written as "<requests>/<period>", e.g.
+// "30/1m".
+func ParseRateLimit(value string) (RateLimit, error) {
+	requests, period, ok := strings.Cut(value, "/")
+	if !ok {
+		return RateLimit{}, fmt.Errorf("rate limit %q must look like <requests>/<period>", value)
+	}
+
+	limit := RateLimit{}
+	var err error
+	if limit.Requests, err = strconv.Atoi(strings.TrimSpace(requests)); err != nil || limit.Requests <= 0 {
+		return RateLimit{}, fmt.Errorf("rate limit %q: requests must be a positive integer", value)
+	}
+	if limit.Period, err = time.ParseDuration(strings.TrimSpace(period)); err != nil || limit.Period <= 0 {
+		return RateLimit{}, fmt.Errorf("rate limit %q: period must be a positive duration", value)
+	}
+	return limit, nil
+}
+
+// TokensPerSecond is the refill rate of the bucket.
+func (l RateLimit) TokensPerSecond() float64 {
+	return float64(l.Requests) / l.Period.Seconds()
+}
+
+// RateLimitBucket is the state of a bucket after a request took a token
+// from it, or failed to.
+type RateLimitBucket struct {
+	Tokens  float64
+	Allowed bool
+}
@@ -0,0 +1,30 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	RegistrationRegistered = "registered"
+	// RegistrationWaitlisted registrations wait for a seat of a full event,
+	// they are registered in the order they joined as seats free up.
+	RegistrationWaitlisted = "waitlisted"
+	RegistrationCancelled  = "cancelled"
+)
+
+// ActiveRegistrationStatuses are the statuses of the registrations that
+// weren't cancelled. A user has at most one of them per event.
+var ActiveRegistrationStatuses = []string{RegistrationRegistered, RegistrationWaitlisted}
+
+type Registration struct {
+	ID          uuid.UUID  `json:"id"`
+	TenantID    string     `json:"tenant_id"`
+	EventID     uuid.UUID  `json:"event_id"`
+	UserID      string     `json:"user_id"`
+	Email       string     `json:"email,omitempty"`
+	Status      string     `json:"status"`
+	CreatedAt   time.Time  `json:"created_at"`
+	CancelledAt *time.Time `json:"cancelled_at,omitempty"`
+}
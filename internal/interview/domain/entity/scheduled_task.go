@@ -0,0 +1,35 @@
+package entity
+
+import "time"
+
+// The statuses of the last run of a scheduled task.
+const (
+	TaskRunning   = "running"
+	TaskSucceeded = "succeeded"
+	TaskFailed    = "failed"
+)
+
+// ScheduledTask is a task run by the scheduler on a cron schedule, once per
+// tick across every instance.
+type ScheduledTask struct {
+	Name     string `json:"name"`
+	Schedule string `json:"schedule"`
+	// NextRunAt is the next tick, as planned by the instance leading the
+	// scheduler.
+	NextRunAt *time.Time `json:"next_run_at"`
+	// LastRun is nil until the task first runs.
+	LastRun   *TaskRun  `json:"last_run"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TaskRun is a run of a scheduled task.
+type TaskRun struct {
+	// ScheduledAt is the tick the run is for.
+	ScheduledAt time.Time  `json:"scheduled_at"`
+	StartedAt   time.Time  `json:"started_at"`
+	FinishedAt  *time.Time `json:"finished_at"`
+	Status      string     `json:"status"`
+	Error       string     `json:"error,omitempty"`
+	// Instance is the instance that ran the task.
+	Instance string `json:"instance"`
+}
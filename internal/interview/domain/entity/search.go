@@ -0,0 +1,49 @@
+package entity
+
+import (
+	"strings"
+	"unicode"
+)
+
+// SearchLanguages are the text search configurations events can be indexed
+// with.
+var SearchLanguages = []string{"english", "russian"}
+
+const maxSearchTerms = 8
+
+// EventMatch is an event found by a search, with the matching words of its
+// title and description highlighted.
+type EventMatch struct {
+	*Event
+	Rank                 float64
+	TitleHighlight       string
+	DescriptionHighlight string
+}
+
+// Highlights of matches are wrapped in these.
+const (
+	HighlightStart = "<mark>"
+	HighlightStop  = "</mark>"
+)
+
+// SearchTerms splits a search query into its words. Punctuation separates
+// words, so that the terms can't form tsquery operators.
+func SearchTerms(query string) []string {
+	terms := strings.FieldsFunc(strings.ToLower(query), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	if len(terms) > maxSearchTerms {
+		terms = terms[:maxSearchTerms]
+	}
+	return terms
+}
+
+// PrefixQuery is the tsquery matching events that have all terms, each as
+// a word or the start of one, so that queries match while being typed.
+func PrefixQuery(terms []string) string {
+	prefixes := make([]string, 0, len(terms))
+	for _, term := range terms {
+		prefixes = append(prefixes, term+":*")
+	}
+	return strings.Join(prefixes, " & ")
+}
@@ -0,0 +1,50 @@
+package entity
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestSearchTerms(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  []string
+	}{
+		{"words", "Backend Interview", []string{"backend", "interview"}},
+		{"spaces", "  backend \t interview\n", []string{"backend", "interview"}},
+		{"punctuation", "backend, interview!", []string{"backend", "interview"}},
+		{"tsquery operators", "backend & !interview | (go:*) <-> 'x'", []string{"backend", "interview", "go", "x"}},
+		{"digits", "Q3 2026", []string{"q3", "2026"}},
+		{"cyrillic", "Собеседование, Java-разработчик", []string{"собеседование", "java", "разработчик"}},
+		// stop-words are left for the text search configuration to drop
+		{"stop-words", "the interview and the offer", []string{"the", "interview", "and", "the", "offer"}},
+		{"empty", "", []string{}},
+		{"punctuation only", "&|!:*()", []string{}},
+		{"too many", "a b c d e f g h i j", []string{"a", "b", "c", "d", "e", "f", "g", "h"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SearchTerms(tt.query); !slices.Equal(got, tt.want) {
+				t.Errorf("SearchTerms(%q) = %q, want %q", tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPrefixQuery(t *testing.T) {
+	tests := []struct {
+		terms []string
+		want  string
+	}{
+		{nil, ""},
+		{[]string{"interv"}, "interv:*"},
+		{[]string{"backend", "interv"}, "backend:* & interv:*"},
+		{SearchTerms("backend | !interview:*"), "backend:* & interview:*"},
+	}
+	for _, tt := range tests {
+		if got := PrefixQuery(tt.terms); got != tt.want {
+			t.Errorf("PrefixQuery(%q) = %q, want %q", tt.terms, got, tt.want)
+		}
+	}
+}
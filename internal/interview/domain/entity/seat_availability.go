@@ -0,0 +1,22 @@
+package entity
+
+import "github.com/google/uuid"
+
+// SeatAvailability is the number of seats left at an event and the length
+// of its waitlist. The seats are those of the room of the event, the
+// capacity is unknown when it has no room or the room has no capacity.
+type SeatAvailability struct {
+	EventID uuid.UUID `json:"event_id"`
+	Status  string    `json:"status"`
+	// Capacity is 0 when unknown.
+	Capacity   int `json:"capacity,omitempty"`
+	Registered int `json:"registered"`
+	// Waitlisted is the number of registrants waiting for a seat of the
+	// full event.
+	Waitlisted int `json:"waitlisted"`
+	// Remaining is nil when the capacity is unknown, and 0 rather than
+	// negative when the capacity was lowered below the registrations.
+	Remaining *int `json:"remaining,omitempty"`
+	// CreatedBy decides who may see the availability of a draft.
+	CreatedBy string `json:"-"`
+}
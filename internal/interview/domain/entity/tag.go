@@ -0,0 +1,78 @@
+package entity
+
+import (
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// MaxTagLength is the longest tag name, in characters.
+	MaxTagLength = 50
+	// MaxEventTags is the number of tags an event may have.
+	MaxEventTags = 20
+)
+
+// Listings match tags in either way.
+const (
+	TagMatchAny = "any"
+	TagMatchAll = "all"
+)
+
+// TagMatches lists the ways of matching tags, the default first.
+var TagMatches = []string{TagMatchAny, TagMatchAll}
+
+// Category groups events by their kind, e.g. interview, onboarding or
+// training. An event is in at most one category.
+type Category struct {
+	ID        uuid.UUID `json:"id"`
+	TenantID  string    `json:"tenant_id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Tag is a free-form label of events. Tags are created when events first
+// use them.
+type Tag struct {
+	ID        uuid.UUID `json:"id"`
+	TenantID  string    `json:"tenant_id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Facet counts the events with a tag or in a category.
+type Facet struct {
+	ID    uuid.UUID `json:"id"`
+	Name  string    `json:"name"`
+	Count int       `json:"count"`
+}
+
+// EventFacets counts the events of a listing by tag and by category, the
+// most used first.
+type EventFacets struct {
+	Tags       []*Facet `json:"tags"`
+	Categories []*Facet `json:"categories"`
+}
+
+// NormalizeTag lower-cases the tag name and collapses its white space, so
+// that tags are matched regardless of how they were typed.
+func NormalizeTag(name string) string {
+	return strings.ToLower(strings.Join(strings.Fields(name), " "))
+}
+
+// NormalizeTags normalizes the tag names and removes empty and repeated
+// ones, in name order.
+func NormalizeTags(names []string) []string {
+	tags := make([]string, 0, len(names))
+	for _, name := range names {
+		if tag := NormalizeTag(name); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	slices.Sort(tags)
+	return slices.Compact(tags)
+}
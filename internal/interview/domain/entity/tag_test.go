@@ -0,0 +1,27 @@
+package entity
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestNormalizeTags(t *testing.T) {
+	tests := []struct {
+		name  string
+		names []string
+		want  []string
+	}{
+		{"none", nil, []string{}},
+		{"case and space", []string{"  Remote  Work ", "GO"}, []string{"go", "remote work"}},
+		{"repeated", []string{"go", "Go", " go "}, []string{"go"}},
+		{"empty", []string{"", "   ", "go"}, []string{"go"}},
+		{"sorted", []string{"senior", "go", "remote"}, []string{"go", "remote", "senior"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NormalizeTags(tt.names); !slices.Equal(got, tt.want) {
+				t.Errorf("NormalizeTags(%q) = %q, want %q", tt.names, got, tt.want)
+			}
+		})
+	}
+}
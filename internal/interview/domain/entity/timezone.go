@@ -0,0 +1,56 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// DefaultTimeZone is the zone of events created without one.
+const DefaultTimeZone = "UTC"
+
+// DateLayout is how the dates of all-day events are written.
+const DateLayout = time.DateOnly
+
+// LoadTimeZone returns the location of an IANA time zone name. The local
+// zone of the server is not accepted, it differs between deployments.
+func LoadTimeZone(name string) (*time.Location, error) {
+	if name == "" || name == "Local" {
+		return nil, fmt.Errorf("invalid time zone %q", name)
+	}
+	return time.LoadLocation(name)
+}
+
+// AllDayTimes returns the span of the all-day event from startDate to
+// endDate, both included, as the midnights that start and end it in loc.
+func AllDayTimes(startDate, endDate string, loc *time.Location) (time.Time, time.Time, error) {
+	start, err := time.ParseInLocation(DateLayout, startDate, loc)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid start date: %w", err)
+	}
+	end, err := time.ParseInLocation(DateLayout, endDate, loc)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid end date: %w", err)
+	}
+	if end.Before(start) {
+		return time.Time{}, time.Time{}, errors.New("end date is before start date")
+	}
+
+	// AddDate keeps the wall clock at midnight across DST changes
+	return start, end.AddDate(0, 0, 1), nil
+}
+
+// Location returns the time zone of the event, UTC when it is unknown.
+func (e *Event) Location() *time.Location {
+	loc, err := LoadTimeZone(e.TimeZone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// Dates returns the first and last day of an all-day event.
+func (e *Event) Dates() (string, string) {
+	loc := e.Location()
+	return e.StartTime.In(loc).Format(DateLayout), e.EndTime.In(loc).AddDate(0, 0, -1).Format(DateLayout)
+}
@@ -0,0 +1,30 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Venue is a place events are held at. Events are booked into its rooms.
+type Venue struct {
+	ID        uuid.UUID `json:"id"`
+	TenantID  string    `json:"tenant_id"`
+	Name      string    `json:"name"`
+	Address   string    `json:"address,omitempty"`
+	Rooms     []*Room   `json:"rooms,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Room holds at most one event at a time.
+type Room struct {
+	ID       uuid.UUID `json:"id"`
+	TenantID string    `json:"tenant_id"`
+	VenueID  uuid.UUID `json:"venue_id"`
+	Name     string    `json:"name"`
+	// Capacity is the number of seats, 0 when unknown.
+	Capacity  int       `json:"capacity,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
@@ -0,0 +1,43 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type WebhookSubscription struct {
+	ID                  uuid.UUID  `json:"id"`
+	TenantID            string     `json:"tenant_id"`
+	URL                 string     `json:"url"`
+	Secret              string     `json:"secret,omitempty"`
+	EventTypes          []string   `json:"event_types"`
+	Active              bool       `json:"active"`
+	ConsecutiveFailures int        `json:"consecutive_failures"`
+	DisabledAt          *time.Time `json:"disabled_at,omitempty"`
+	CreatedAt           time.Time  `json:"created_at"`
+	UpdatedAt           time.Time  `json:"updated_at"`
+}
+
+const (
+	WebhookDeliveryPending   = "pending"
+	WebhookDeliverySucceeded = "succeeded"
+	WebhookDeliveryFailed    = "failed"
+)
+
+type WebhookDelivery struct {
+	ID             uuid.UUID            `json:"id"`
+	SubscriptionID uuid.UUID            `json:"subscription_id"`
+	MessageID      uuid.UUID            `json:"message_id"`
+	RedeliveryOf   *uuid.UUID           `json:"redelivery_of,omitempty"`
+	EventType      string               `json:"event_type"`
+	Payload        []byte               `json:"-"`
+	Status         string               `json:"status"`
+	Attempts       int                  `json:"attempts"`
+	LastStatusCode int                  `json:"last_status_code,omitempty"`
+	LastError      string               `json:"last_error,omitempty"`
+	NextAttemptAt  time.Time            `json:"next_attempt_at"`
+	CreatedAt      time.Time            `json:"created_at"`
+	DeliveredAt    *time.Time           `json:"delivered_at,omitempty"`
+	Subscription   *WebhookSubscription `json:"-"`
+}
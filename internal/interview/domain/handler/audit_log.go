@@ -0,0 +1,40 @@
+package handler
+
+import (
+	"net/http"
+	"online-registration/internal/interview/domain/usecase"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+)
+
+type AuditLogHandler struct {
+	auditLogUseCase *usecase.AuditLogUseCase
+}
+
+// NewAuditLogHandler creates a new HTTP handler for the history of events
+func NewAuditLogHandler(auditLogUseCase *usecase.AuditLogUseCase) *AuditLogHandler {
+	return &AuditLogHandler{
+		auditLogUseCase: auditLogUseCase,
+	}
+}
+
+// EventHistory lists the changes of an event, newest first.
+func (h *AuditLogHandler) EventHistory(c *gin.Context) {
+	eventID, ok := bindEventID(c)
+	if !ok {
+		return
+	}
+
+	limit, _ := strconv.Atoi(c.Query("limit"))
+
+	entries, err := h.auditLogUseCase.EventHistory(c.Request.Context(), eventID, limit)
+	if err != nil {
+		log.Error().Msgf("Failed to list event history: %v", err)
+		respondProcessingError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, entries)
+}
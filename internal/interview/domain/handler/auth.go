@@ -0,0 +1,80 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"online-registration/internal/common"
+	"online-registration/internal/interview/domain/auth"
+	"online-registration/internal/interview/domain/repository"
+	"online-registration/internal/interview/domain/usecase"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+)
+
+type AuthHandler struct {
+	verifier       repository.ITokenVerifier
+	apiKeyVerifier repository.ITokenVerifier
+}
+
+// NewAuthHandler creates a middleware handler that authenticates requests
+// with bearer tokens or API keys
+func NewAuthHandler(verifier, apiKeyVerifier repository.ITokenVerifier) *AuthHandler {
+	return &AuthHandler{
+		verifier:       verifier,
+		apiKeyVerifier: apiKeyVerifier,
+	}
+}
+
+// AnonymousMiddleware is used instead of Middleware when authentication is
+// disabled. Requests run as a system principal that passes every policy check.
+func AnonymousMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Request = c.Request.WithContext(
+			auth.ContextWithPrincipal(c.Request.Context(), auth.SystemPrincipal("anonymous")),
+		)
+		c.Next()
+	}
+}
+
+// Middleware rejects requests without a valid bearer token or API key and
+// puts the principal of the credential into the request context. API keys
+// are accepted with the ApiKey scheme, or as bearer tokens.
+func (h *AuthHandler) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		scheme, token, ok := strings.Cut(c.GetHeader("Authorization"), " ")
+		isBearer, isAPIKey := strings.EqualFold(scheme, "Bearer"), strings.EqualFold(scheme, "ApiKey")
+		if !ok || !(isBearer || isAPIKey) || token == "" {
+			c.Header("WWW-Authenticate", "Bearer")
+			respondError(c, http.StatusUnauthorized, common.CodeUnauthenticated)
+			c.Abort()
+			return
+		}
+
+		verifier := h.verifier
+		if isAPIKey || usecase.IsAPIKey(token) {
+			verifier = h.apiKeyVerifier
+		}
+
+		principal, err := verifier.Verify(c.Request.Context(), token)
+		if err != nil {
+			var processingErr *common.ProcessingError
+			if errors.As(err, &processingErr) {
+				log.Error().Msgf("Failed to verify token: %v", err)
+				respondProcessingError(c, err)
+				c.Abort()
+				return
+			}
+
+			log.Info().Msgf("Rejected token: %v", err)
+			c.Header("WWW-Authenticate", `Bearer error="invalid_token"`)
+			respondError(c, http.StatusUnauthorized, common.CodeInvalidToken)
+			c.Abort()
+			return
+		}
+
+		c.Request = c.Request.WithContext(auth.ContextWithPrincipal(c.Request.Context(), principal))
+		c.Next()
+	}
+}
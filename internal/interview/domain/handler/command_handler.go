@@ -0,0 +1,243 @@
+package handler
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"online-registration/internal/common"
+	"online-registration/internal/i18n"
+	"online-registration/internal/interview/domain/audit"
+	"online-registration/internal/interview/domain/dto"
+	"online-registration/internal/interview/domain/entity"
+	"online-registration/internal/interview/domain/tenant"
+	"online-registration/internal/interview/domain/usecase"
+	"online-registration/internal/interview/helper"
+
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	CreateEventCommand = "create_event"
+	CancelEventCommand = "cancel_event"
+
+	// defaultCancellationReason is used for cancel commands without a
+	// reason, which were accepted before events had a lifecycle.
+	defaultCancellationReason = "Cancelled by an external service"
+)
+
+// CommandHandler runs event commands received from a message broker through
+// the same use cases as the HTTP handler. Commands that were processed get a
+// result, including business failures. An error is returned when the command
+// was not processed: an InvalidArgument ProcessingError if the message can't
+// be decoded and will never succeed, any other error if it may be retried.
+//
+// A command is run once per command_id, the repeats get the result of the
+// first run back.
+type CommandHandler struct {
+	createEventUseCase    *usecase.CreateEventUseCase
+	eventLifecycleUseCase *usecase.EventLifecycleUseCase
+	idempotencyUseCase    *usecase.IdempotencyUseCase
+	defaultTenant         string
+}
+
+// NewCommandHandler creates a new broker command handler. Commands without a
+// tenant act on defaultTenant.
+func NewCommandHandler(
+	createEventUseCase *usecase.CreateEventUseCase,
+	eventLifecycleUseCase *usecase.EventLifecycleUseCase,
+	idempotencyUseCase *usecase.IdempotencyUseCase,
+	defaultTenant string,
+) *CommandHandler {
+	return &CommandHandler{
+		createEventUseCase:    createEventUseCase,
+		eventLifecycleUseCase: eventLifecycleUseCase,
+		idempotencyUseCase:    idempotencyUseCase,
+		defaultTenant:         defaultTenant,
+	}
+}
+
+func (h *CommandHandler) CreateEvent(ctx context.Context, data []byte) (*dto.CommandResultDTO, error) {
+	var command dto.CreateEventCommandDTO
+	if err := json.Unmarshal(data, &command); err != nil {
+		return nil, malformedCommand(err)
+	}
+
+	ctx, err := h.commandContext(ctx, command.CommandID, command.TenantID)
+	if err != nil {
+		return commandResult(command.CommandID, CreateEventCommand, nil, err)
+	}
+
+	if err := command.Validate(); err != nil {
+		return commandResult(command.CommandID, CreateEventCommand, nil, err)
+	}
+
+	return h.runOnce(ctx, command.CommandID, CreateEventCommand, data,
+		func(ctx context.Context) (*dto.CommandResultDTO, error) {
+			event, err := h.createEventUseCase.CreateEvent(ctx, &command.CreateEventRequestDTO)
+			return commandResult(command.CommandID, CreateEventCommand, event, err)
+		},
+	)
+}
+
+func (h *CommandHandler) CancelEvent(ctx context.Context, data []byte) (*dto.CommandResultDTO, error) {
+	var command dto.CancelEventCommandDTO
+	if err := json.Unmarshal(data, &command); err != nil {
+		return nil, malformedCommand(err)
+	}
+
+	ctx, err := h.commandContext(ctx, command.CommandID, command.TenantID)
+	if err != nil {
+		return commandResult(command.CommandID, CancelEventCommand, nil, err)
+	}
+
+	reason := command.Reason
+	if reason == "" {
+		reason = defaultCancellationReason
+	}
+
+	return h.runOnce(ctx, command.CommandID, CancelEventCommand, data,
+		func(ctx context.Context) (*dto.CommandResultDTO, error) {
+			event, err := h.eventLifecycleUseCase.Cancel(ctx, command.EventID, reason)
+			return commandResult(command.CommandID, CancelEventCommand, event, err)
+		},
+	)
+}
+
+// runOnce runs a command unless a command with the same id was run before,
+// in which case the stored result is returned. The ids are held as
+// idempotency keys, so a repeat with a different payload is rejected.
+func (h *CommandHandler) runOnce(
+	ctx context.Context,
+	commandID string,
+	command string,
+	data []byte,
+	run func(ctx context.Context) (*dto.CommandResultDTO, error),
+) (*dto.CommandResultDTO, error) {
+	// commands sent before ids were required can't be told apart
+	if commandID == "" {
+		return run(ctx)
+	}
+
+	tenantID, _ := tenant.FromContext(ctx)
+	key := usecase.IdempotencyScope(tenantID, "command", command, commandID)
+	fingerprint := sha256.Sum256(data)
+
+	record, acquired, err := h.idempotencyUseCase.Begin(ctx, key, hex.EncodeToString(fingerprint[:]))
+	if err != nil {
+		var processingErr *common.ProcessingError
+		if errors.As(err, &processingErr) && processingErr.Code == common.CodeIdempotencyKeyInProgress {
+			// retried once the worker running it is done or has given up
+			return nil, err
+		}
+		return commandResult(commandID, command, nil, err)
+	}
+
+	if !acquired {
+		var result dto.CommandResultDTO
+		if err := json.Unmarshal(record.ResponseBody, &result); err != nil {
+			return nil, fmt.Errorf("decode result of command %s: %w", commandID, err)
+		}
+		log.Info().Msgf("Command %s %s repeated, replaying its result", command, commandID)
+		return &result, nil
+	}
+
+	// the key is only held for a while after the timeout, the command must
+	// be over before a repeat takes it over
+	runCtx, cancel := context.WithTimeout(ctx, h.idempotencyUseCase.RequestTimeout())
+	defer cancel()
+
+	result, err := run(runCtx)
+
+	// the command has run, its key must be settled even if the worker is
+	// stopping
+	ctx = context.WithoutCancel(ctx)
+	if err != nil {
+		if err := h.idempotencyUseCase.Release(ctx, key, record.Owner); err != nil {
+			log.Error().Msgf("Failed to release command id: %v", err)
+		}
+		return nil, err
+	}
+
+	body, err := json.Marshal(result)
+	if err != nil {
+		return nil, fmt.Errorf("encode result of command %s: %w", commandID, err)
+	}
+	if err := h.idempotencyUseCase.Complete(ctx, key, record.Owner, http.StatusOK, body); err != nil {
+		log.Error().Msgf("Failed to complete command id: %v", err)
+	}
+
+	return result, nil
+}
+
+// commandContext puts the tenant of a command into ctx, and its id as the
+// request id of the changes it makes.
+func (h *CommandHandler) commandContext(
+	ctx context.Context, commandID string, tenantID string,
+) (context.Context, error) {
+	ctx = audit.ContextWithRequestID(ctx, commandID)
+
+	if tenantID == "" {
+		tenantID = h.defaultTenant
+	}
+
+	if !tenant.Valid(tenantID) {
+		return ctx, common.NewCodedError(
+			helper.InvalidArgument, common.CodeInvalidTenant, fmt.Errorf("invalid tenant %q", tenantID), tenantID,
+		)
+	}
+
+	return tenant.ContextWithTenant(ctx, tenantID), nil
+}
+
+func malformedCommand(err error) error {
+	return common.NewCodedError(
+		helper.InvalidArgument, common.CodeInvalidRequestBody, fmt.Errorf("decode command: %w", err),
+	)
+}
+
+// commandResult turns the outcome of a use case into a command result, or
+// returns err back when the failure is transient and the command should be
+// retried.
+func commandResult(
+	commandID string, command string, event *entity.Event, err error,
+) (*dto.CommandResultDTO, error) {
+	result := &dto.CommandResultDTO{
+		CommandID: commandID,
+		Command:   command,
+		Status:    dto.CommandStatusOK,
+	}
+
+	if err == nil {
+		if event != nil {
+			payload := dto.NewEventPayload(event)
+			result.Event = &payload
+		}
+		return result, nil
+	}
+
+	var processingErr *common.ProcessingError
+	if !errors.As(err, &processingErr) || isTransientStatus(processingErr.Status) {
+		return nil, err
+	}
+
+	log.Info().Msgf("Command %s %s rejected: %v", command, commandID, err)
+
+	result.Status = dto.CommandStatusError
+	result.Error = &dto.CommandErrorResult{
+		Code:    processingErr.Code,
+		Message: i18n.Translate(i18n.DefaultLang, processingErr.Code, processingErr.Args...),
+	}
+	return result, nil
+}
+
+func isTransientStatus(status uint8) bool {
+	switch status {
+	case helper.Unknown, helper.DeadlineExceeded, helper.InternalError, helper.Unavailable:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,241 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"online-registration/internal/common"
+	"online-registration/internal/interview/domain/dto"
+	"online-registration/internal/interview/domain/entity"
+	"online-registration/internal/interview/domain/repository"
+	"online-registration/internal/interview/domain/tenant"
+	"online-registration/internal/interview/domain/usecase"
+
+	"github.com/google/uuid"
+)
+
+// memoryIdempotencyKeys holds idempotency keys like the database does.
+type memoryIdempotencyKeys struct {
+	mu   sync.Mutex
+	keys map[string]*entity.IdempotencyKey
+}
+
+func newMemoryIdempotencyKeys() *memoryIdempotencyKeys {
+	return &memoryIdempotencyKeys{keys: make(map[string]*entity.IdempotencyKey)}
+}
+
+func (r *memoryIdempotencyKeys) Acquire(
+	_ context.Context, key, fingerprint string, ttl, lockTimeout time.Duration,
+) (*entity.IdempotencyKey, bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	if existing, ok := r.keys[key]; ok {
+		abandoned := !existing.Completed() && existing.CreatedAt.Before(now.Add(-lockTimeout))
+		if !existing.ExpiresAt.Before(now) && !abandoned {
+			copied := *existing
+			return &copied, false, nil
+		}
+	}
+
+	record := &entity.IdempotencyKey{
+		Key: key, Fingerprint: fingerprint, Owner: uuid.New(), CreatedAt: now, ExpiresAt: now.Add(ttl),
+	}
+	r.keys[key] = record
+	copied := *record
+	return &copied, true, nil
+}
+
+// held returns the record of key held by owner, before it completed.
+func (r *memoryIdempotencyKeys) held(key string, owner uuid.UUID) (*entity.IdempotencyKey, error) {
+	record, ok := r.keys[key]
+	if !ok || record.Owner != owner || record.Completed() {
+		return nil, repository.ErrIdempotencyKeyLost
+	}
+	return record, nil
+}
+
+func (r *memoryIdempotencyKeys) Complete(
+	_ context.Context, key string, owner uuid.UUID, statusCode int, responseBody []byte,
+) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	record, err := r.held(key, owner)
+	if err != nil {
+		return err
+	}
+	record.StatusCode = statusCode
+	record.ResponseBody = responseBody
+	return nil
+}
+
+func (r *memoryIdempotencyKeys) Release(_ context.Context, key string, owner uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, err := r.held(key, owner); err != nil {
+		return err
+	}
+	delete(r.keys, key)
+	return nil
+}
+
+func (r *memoryIdempotencyKeys) DeleteExpired(context.Context, time.Time) (int, error) {
+	return 0, errors.New("not implemented")
+}
+
+// countedRun counts its runs and returns the result for the run number it
+// is at.
+type countedRun struct {
+	runs    int
+	results func(run int) (*dto.CommandResultDTO, error)
+}
+
+func (r *countedRun) run(context.Context) (*dto.CommandResultDTO, error) {
+	r.runs++
+	return r.results(r.runs)
+}
+
+func okCommandResult(run int) (*dto.CommandResultDTO, error) {
+	return &dto.CommandResultDTO{CommandID: "c1", Command: CreateEventCommand, Status: dto.CommandStatusOK}, nil
+}
+
+func newTestCommandHandler(keys *memoryIdempotencyKeys) *CommandHandler {
+	return &CommandHandler{
+		idempotencyUseCase: usecase.NewIdempotencyUseCase(keys, time.Hour, time.Minute),
+		defaultTenant:      "default",
+	}
+}
+
+func tenantContext(tenantID string) context.Context {
+	return tenant.ContextWithTenant(context.Background(), tenantID)
+}
+
+func TestCommandRunOnceReplaysResults(t *testing.T) {
+	h := newTestCommandHandler(newMemoryIdempotencyKeys())
+	run := &countedRun{results: okCommandResult}
+
+	for range 2 {
+		result, err := h.runOnce(tenantContext("acme"), "c1", CreateEventCommand, []byte(`{"a":1}`), run.run)
+		if err != nil {
+			t.Fatalf("runOnce: %v", err)
+		}
+		if result.CommandID != "c1" || result.Status != dto.CommandStatusOK {
+			t.Errorf("runOnce = %+v, want the ok result", result)
+		}
+	}
+	if run.runs != 1 {
+		t.Errorf("command ran %d times, want once", run.runs)
+	}
+
+	// the ids of every tenant are apart
+	if _, err := h.runOnce(tenantContext("globex"), "c1", CreateEventCommand, []byte(`{"a":1}`), run.run); err != nil {
+		t.Fatalf("runOnce: %v", err)
+	}
+	if run.runs != 2 {
+		t.Errorf("command of another tenant ran %d times in total, want 2", run.runs)
+	}
+}
+
+func TestCommandRunOnceRejectsReusedIDs(t *testing.T) {
+	h := newTestCommandHandler(newMemoryIdempotencyKeys())
+	run := &countedRun{results: okCommandResult}
+
+	if _, err := h.runOnce(tenantContext("acme"), "c1", CreateEventCommand, []byte(`{"a":1}`), run.run); err != nil {
+		t.Fatalf("runOnce: %v", err)
+	}
+	result, err := h.runOnce(tenantContext("acme"), "c1", CreateEventCommand, []byte(`{"a":2}`), run.run)
+	if err != nil {
+		t.Fatalf("runOnce of another payload: %v", err)
+	}
+	if result.Status != dto.CommandStatusError || result.Error.Code != common.CodeIdempotencyKeyReused {
+		t.Errorf("runOnce of another payload = %+v, want a %s error", result, common.CodeIdempotencyKeyReused)
+	}
+	if run.runs != 1 {
+		t.Errorf("command ran %d times, want once", run.runs)
+	}
+}
+
+func TestCommandRunOnceRetriesCommandsInProgress(t *testing.T) {
+	keys := newMemoryIdempotencyKeys()
+	h := newTestCommandHandler(keys)
+
+	// another worker runs the command
+	running := make(chan struct{})
+	finish := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		h.runOnce(tenantContext("acme"), "c1", CreateEventCommand, []byte(`{}`),
+			func(context.Context) (*dto.CommandResultDTO, error) {
+				close(running)
+				<-finish
+				return okCommandResult(1)
+			},
+		)
+	}()
+	<-running
+
+	run := &countedRun{results: okCommandResult}
+	_, err := h.runOnce(tenantContext("acme"), "c1", CreateEventCommand, []byte(`{}`), run.run)
+	var processingErr *common.ProcessingError
+	if !errors.As(err, &processingErr) || processingErr.Code != common.CodeIdempotencyKeyInProgress {
+		t.Errorf("runOnce while the command runs = %v, want it in progress", err)
+	}
+
+	close(finish)
+	<-done
+	result, err := h.runOnce(tenantContext("acme"), "c1", CreateEventCommand, []byte(`{}`), run.run)
+	if err != nil || result.Status != dto.CommandStatusOK {
+		t.Errorf("runOnce after the command ran = %+v, %v, want its result", result, err)
+	}
+	if run.runs != 0 {
+		t.Errorf("repeats ran the command %d times", run.runs)
+	}
+}
+
+func TestCommandRunOnceReleasesFailedCommands(t *testing.T) {
+	keys := newMemoryIdempotencyKeys()
+	h := newTestCommandHandler(keys)
+	run := &countedRun{results: func(run int) (*dto.CommandResultDTO, error) {
+		if run == 1 {
+			return nil, errors.New("database unavailable")
+		}
+		return okCommandResult(run)
+	}}
+
+	if _, err := h.runOnce(tenantContext("acme"), "c1", CreateEventCommand, []byte(`{}`), run.run); err == nil {
+		t.Fatal("runOnce of a failing command succeeded")
+	}
+	if len(keys.keys) != 0 {
+		t.Errorf("failed command kept its id")
+	}
+
+	result, err := h.runOnce(tenantContext("acme"), "c1", CreateEventCommand, []byte(`{}`), run.run)
+	if err != nil || result.Status != dto.CommandStatusOK {
+		t.Errorf("runOnce of the retry = %+v, %v, want ok", result, err)
+	}
+	if run.runs != 2 {
+		t.Errorf("command ran %d times, want 2", run.runs)
+	}
+}
+
+func TestCommandRunOnceWithoutID(t *testing.T) {
+	keys := newMemoryIdempotencyKeys()
+	h := newTestCommandHandler(keys)
+	run := &countedRun{results: okCommandResult}
+
+	for range 2 {
+		if _, err := h.runOnce(tenantContext("acme"), "", CreateEventCommand, []byte(`{}`), run.run); err != nil {
+			t.Fatalf("runOnce: %v", err)
+		}
+	}
+	if run.runs != 2 || len(keys.keys) != 0 {
+		t.Errorf("commands without an id ran %d times holding %d keys, want 2 runs without keys", run.runs, len(keys.keys))
+	}
+}
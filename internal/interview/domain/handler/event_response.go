@@ -0,0 +1,91 @@
+package handler
+
+import (
+	"net/http"
+	"online-registration/internal/common"
+	"online-registration/internal/interview/domain/entity"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// EventResponse is an event with its times in UTC, and also in the zone of
+// the event or the one asked for by the client.
+type EventResponse struct {
+	*entity.Event
+	DisplayTimeZone string
+	LocalStartTime  time.Time
+	LocalEndTime    time.Time
+	// StartDate and EndDate are the first and last day of all-day events.
+	StartDate string `json:",omitempty"`
+	EndDate   string `json:",omitempty"`
+	// Search is set on the events found by a search.
+	Search *EventSearchResult `json:",omitempty"`
+}
+
+// EventSearchResult tells how well an event matched a search. The matching
+// words in the highlights are wrapped in <mark> tags, the rest of the text
+// is not escaped.
+type EventSearchResult struct {
+	Rank                 float64
+	TitleHighlight       string
+	DescriptionHighlight string
+}
+
+// newEventResponse shows the local times of event in loc, or in the zone of
+// the event when loc is nil.
+func newEventResponse(event *entity.Event, loc *time.Location) *EventResponse {
+	if loc == nil {
+		loc = event.Location()
+	}
+
+	utc := *event
+	utc.StartTime = event.StartTime.UTC()
+	utc.EndTime = event.EndTime.UTC()
+
+	response := &EventResponse{
+		Event:           &utc,
+		DisplayTimeZone: loc.String(),
+		LocalStartTime:  event.StartTime.In(loc),
+		LocalEndTime:    event.EndTime.In(loc),
+	}
+	if event.AllDay {
+		response.StartDate, response.EndDate = event.Dates()
+	}
+	return response
+}
+
+// newEventResponses lists the events, with the search results of events
+// found by a search.
+func newEventResponses(matches []*entity.EventMatch, loc *time.Location, searched bool) []*EventResponse {
+	responses := make([]*EventResponse, 0, len(matches))
+	for _, match := range matches {
+		response := newEventResponse(match.Event, loc)
+		if searched {
+			response.Search = &EventSearchResult{
+				Rank:                 match.Rank,
+				TitleHighlight:       match.TitleHighlight,
+				DescriptionHighlight: match.DescriptionHighlight,
+			}
+		}
+		responses = append(responses, response)
+	}
+	return responses
+}
+
+// bindDisplayZone parses the tz query parameter, the zone the client wants
+// times shown in. It returns nil when there is none, and writes the error
+// response itself when the zone is unknown.
+func bindDisplayZone(c *gin.Context) (*time.Location, bool) {
+	name := c.Query("tz")
+	if name == "" {
+		return nil, true
+	}
+
+	loc, err := entity.LoadTimeZone(name)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, common.CodeInvalidTimeZone, name)
+		return nil, false
+	}
+	return loc, true
+}
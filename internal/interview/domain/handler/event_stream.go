@@ -0,0 +1,148 @@
+package handler
+
+import (
+	"net/http"
+	"online-registration/internal/common"
+	"online-registration/internal/interview/domain/dto"
+	"online-registration/internal/interview/domain/entity"
+	"online-registration/internal/interview/domain/usecase"
+	"strconv"
+	"time"
+
+	"github.com/gin-contrib/sse"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// defaultEventStreamHeartbeat is the heartbeat of streams configured
+// without a positive one.
+const defaultEventStreamHeartbeat = 15 * time.Second
+
+type EventStreamHandler struct {
+	streamEventChangesUseCase *usecase.StreamEventChangesUseCase
+	heartbeat                 time.Duration
+}
+
+// NewEventStreamHandler creates a new HTTP handler streaming event changes
+// as server-sent events. A comment is sent every heartbeat so that proxies
+// keep idle streams open.
+func NewEventStreamHandler(
+	streamEventChangesUseCase *usecase.StreamEventChangesUseCase,
+	heartbeat time.Duration,
+) *EventStreamHandler {
+	if heartbeat <= 0 {
+		heartbeat = defaultEventStreamHeartbeat
+	}
+	return &EventStreamHandler{
+		streamEventChangesUseCase: streamEventChangesUseCase,
+		heartbeat:                 heartbeat,
+	}
+}
+
+// Stream sends the changes of events as server-sent events, named after the
+// type of the change, with the lifecycle message as data. The type, event,
+// tag and category query parameters select the changes, they may be
+// repeated or hold comma separated lists. A client reconnecting with the
+// Last-Event-ID header, or the last_event_id query parameter, first gets
+// the changes it missed, or a reset event when it missed too many and must
+// reload the events.
+func (h *EventStreamHandler) Stream(c *gin.Context) {
+	requestDTO, ok := bindEventStreamRequest(c)
+	if !ok {
+		return
+	}
+
+	subscription, err := h.streamEventChangesUseCase.Subscribe(c.Request.Context(), requestDTO)
+	if err != nil {
+		respondProcessingError(c, err)
+		return
+	}
+	defer subscription.Close()
+
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	// nginx buffers responses unless told otherwise
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+
+	if subscription.Reset {
+		c.Render(-1, sse.Event{Event: "reset", Data: "{}"})
+	}
+	for _, change := range subscription.Replay {
+		renderEventChange(c, change)
+	}
+	c.Writer.Flush()
+
+	heartbeat := time.NewTicker(h.heartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case change, ok := <-subscription.Changes():
+			if !ok {
+				// the client resumes after reconnecting
+				return
+			}
+			renderEventChange(c, change)
+		case <-heartbeat.C:
+			_, _ = c.Writer.WriteString(":\n\n")
+		}
+		c.Writer.Flush()
+	}
+}
+
+func renderEventChange(c *gin.Context, change *entity.EventChange) {
+	c.Render(-1, sse.Event{
+		Id:    strconv.FormatInt(change.ID, 10),
+		Event: change.Type,
+		Data:  []byte(change.Message),
+	})
+}
+
+// bindEventStreamRequest reads the changes a client streams from the query
+// parameters, writing the error response itself when it is rejected.
+func bindEventStreamRequest(c *gin.Context) (*dto.EventStreamRequestDTO, bool) {
+	var eventIDs []uuid.UUID
+	for _, value := range queryList(c, "event") {
+		id, err := uuid.Parse(value)
+		if err != nil {
+			respondError(c, http.StatusBadRequest, common.CodeInvalidEventID)
+			return nil, false
+		}
+		eventIDs = append(eventIDs, id)
+	}
+
+	var categoryIDs []uuid.UUID
+	for _, value := range queryList(c, "category") {
+		id, err := uuid.Parse(value)
+		if err != nil {
+			respondError(c, http.StatusBadRequest, common.CodeInvalidCategoryID)
+			return nil, false
+		}
+		categoryIDs = append(categoryIDs, id)
+	}
+
+	var lastEventID int64
+	lastEventIDValue := c.GetHeader("Last-Event-ID")
+	if lastEventIDValue == "" {
+		lastEventIDValue = c.Query("last_event_id")
+	}
+	if lastEventIDValue != "" {
+		var err error
+		lastEventID, err = strconv.ParseInt(lastEventIDValue, 10, 64)
+		if err != nil || lastEventID < 0 {
+			respondError(c, http.StatusBadRequest, common.CodeInvalidLastEventID)
+			return nil, false
+		}
+	}
+
+	return &dto.EventStreamRequestDTO{
+		Types:       queryList(c, "type"),
+		EventIDs:    eventIDs,
+		Tags:        queryList(c, "tag"),
+		CategoryIDs: categoryIDs,
+		LastEventID: lastEventID,
+	}, true
+}
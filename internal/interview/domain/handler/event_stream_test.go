@@ -0,0 +1,23 @@
+package handler
+
+import (
+	"testing"
+	"time"
+)
+
+func TestEventStreamHeartbeat(t *testing.T) {
+	tests := []struct {
+		heartbeat time.Duration
+		want      time.Duration
+	}{
+		{5 * time.Second, 5 * time.Second},
+		// unparsable durations are read as 0
+		{0, defaultEventStreamHeartbeat},
+		{-time.Second, defaultEventStreamHeartbeat},
+	}
+	for _, tt := range tests {
+		if got := NewEventStreamHandler(nil, tt.heartbeat).heartbeat; got != tt.want {
+			t.Errorf("heartbeat of %s = %s, want %s", tt.heartbeat, got, tt.want)
+		}
+	}
+}
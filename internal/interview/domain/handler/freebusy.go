@@ -0,0 +1,64 @@
+package handler
+
+import (
+	"net/http"
+	"online-registration/internal/common"
+	"online-registration/internal/interview/domain/dto"
+	"online-registration/internal/interview/domain/usecase"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type FreeBusyRequest struct {
+	Users []string    `json:"users"`
+	Rooms []uuid.UUID `json:"rooms"`
+	Start time.Time   `json:"start"`
+	End   time.Time   `json:"end"`
+	// SlotLength is a duration such as "45m". When given, the first slot of
+	// that length in which everyone is free is returned.
+	SlotLength string `json:"slot_length"`
+}
+
+type FreeBusyHandler struct {
+	freeBusyUseCase *usecase.FreeBusyUseCase
+}
+
+// NewFreeBusyHandler creates a new HTTP handler for free/busy lookups
+func NewFreeBusyHandler(freeBusyUseCase *usecase.FreeBusyUseCase) *FreeBusyHandler {
+	return &FreeBusyHandler{
+		freeBusyUseCase: freeBusyUseCase,
+	}
+}
+
+func (h *FreeBusyHandler) FreeBusy(c *gin.Context) {
+	var req FreeBusyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, common.CodeInvalidRequestBody)
+		return
+	}
+
+	var slotLength time.Duration
+	if req.SlotLength != "" {
+		var err error
+		if slotLength, err = time.ParseDuration(req.SlotLength); err != nil {
+			respondError(c, http.StatusBadRequest, common.CodeInvalidSlotLength)
+			return
+		}
+	}
+
+	freeBusy, err := h.freeBusyUseCase.FreeBusy(c.Request.Context(), &dto.FreeBusyRequestDTO{
+		UserIDs:    req.Users,
+		RoomIDs:    req.Rooms,
+		Start:      req.Start,
+		End:        req.End,
+		SlotLength: slotLength,
+	})
+	if err != nil {
+		respondProcessingError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, freeBusy)
+}
@@ -1,14 +1,19 @@
 package handler
 
 import (
-	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
+	"online-registration/internal/common"
 	"online-registration/internal/interview/domain/dto"
+	"online-registration/internal/interview/domain/ical"
 	"online-registration/internal/interview/domain/usecase"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 
 	"github.com/rs/zerolog/log"
 )
@@ -18,44 +23,96 @@ type CreateEventRequest struct {
 	Description string    `json:"description"`
 	StartTime   time.Time `json:"start_time"`
 	EndTime     time.Time `json:"end_time"`
+	TimeZone    string    `json:"time_zone"`
+	// AllDay events are given by StartDate and EndDate instead of StartTime
+	// and EndTime.
+	AllDay     bool   `json:"all_day"`
+	StartDate  string `json:"start_date"`
+	EndDate    string `json:"end_date"`
+	Onboarding bool   `json:"onboarding"`
+	// RoomID books the event into a room, it is cleared when omitted.
+	RoomID *uuid.UUID `json:"room_id"`
+	// CategoryID and Tags are cleared when omitted, like RoomID.
+	CategoryID *uuid.UUID `json:"category_id"`
+	Tags       []string   `json:"tags"`
+	// Publish is only used when creating events.
+	Publish bool `json:"publish"`
+}
+
+type UpdateEventRequest = CreateEventRequest
+
+type CancelEventRequest struct {
+	Reason string `json:"reason"`
 }
 
 type Handler struct {
-	createEventUseCase *usecase.CreateEventUseCase
+	createEventUseCase    *usecase.CreateEventUseCase
+	updateEventUseCase    *usecase.UpdateEventUseCase
+	deleteEventUseCase    *usecase.DeleteEventUseCase
+	getEventsUseCase      *usecase.GetEventsUseCase
+	eventLifecycleUseCase *usecase.EventLifecycleUseCase
 }
 
 // NewHandler creates a new HTTP handler
-func NewHandler(proxyUseCase *usecase.CreateEventUseCase) *Handler {
+func NewHandler(
+	proxyUseCase *usecase.CreateEventUseCase,
+	updateEventUseCase *usecase.UpdateEventUseCase,
+	deleteEventUseCase *usecase.DeleteEventUseCase,
+	getEventsUseCase *usecase.GetEventsUseCase,
+	eventLifecycleUseCase *usecase.EventLifecycleUseCase,
+) *Handler {
 	return &Handler{
-		createEventUseCase: proxyUseCase,
+		createEventUseCase:    proxyUseCase,
+		updateEventUseCase:    updateEventUseCase,
+		deleteEventUseCase:    deleteEventUseCase,
+		getEventsUseCase:      getEventsUseCase,
+		eventLifecycleUseCase: eventLifecycleUseCase,
 	}
 }
 
-func (h *Handler) CreateEvent(c *gin.Context) {
+// bindEventRequest binds and validates the event payload, writing the error
+// response itself when the payload is rejected.
+func bindEventRequest(c *gin.Context) (*dto.CreateEventRequestDTO, bool) {
 	var req CreateEventRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"ErrorData": "Uncorrected data"})
-		return
-	}
-
-	// validating that starttime less then endtime
-	if req.StartTime.After(req.EndTime) {
-		log.Error().Msgf("Start time %s cannot be after end time %s", req.StartTime, req.EndTime)
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Start time cannot be after end time"})
-		return
+		respondError(c, http.StatusBadRequest, common.CodeInvalidRequestBody)
+		return nil, false
 	}
 
-	if req.Title == "" || req.Description == "" || req.StartTime.IsZero() || req.EndTime.IsZero() {
-		log.Error().Msg("Data cannot be empty")
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Data  cannot be empty"})
-		return
-	}
-
-	requestDTO := dto.CreateEventRequestDTO{
+	requestDTO := &dto.CreateEventRequestDTO{
 		Title:       req.Title,
 		Description: req.Description,
 		StartTime:   req.StartTime,
 		EndTime:     req.EndTime,
+		TimeZone:    req.TimeZone,
+		AllDay:      req.AllDay,
+		StartDate:   req.StartDate,
+		EndDate:     req.EndDate,
+		Onboarding:  req.Onboarding,
+		RoomID:      req.RoomID,
+		CategoryID:  req.CategoryID,
+		Tags:        req.Tags,
+		Publish:     req.Publish,
+	}
+	if err := requestDTO.Validate(); err != nil {
+		log.Error().Msgf("Invalid event request: %v", err)
+		respondProcessingError(c, err)
+		return nil, false
+	}
+
+	return requestDTO, true
+}
+
+// bindEventID parses the :id path parameter, writing the error response
+// itself when it is not a valid UUID.
+func bindEventID(c *gin.Context) (uuid.UUID, bool) {
+	return bindUUIDParam(c, "id", common.CodeInvalidEventID)
+}
+
+func (h *Handler) CreateEvent(c *gin.Context) {
+	requestDTO, ok := bindEventRequest(c)
+	if !ok {
+		return
 	}
 
 	reqBody, err := json.Marshal(requestDTO)
@@ -66,17 +123,237 @@ func (h *Handler) CreateEvent(c *gin.Context) {
 
 	log.Info().Msgf("Handler creating event request: %s", string(reqBody))
 
-	event, err := h.createEventUseCase.CreateEvent(context.Background(), &requestDTO)
+	event, err := h.createEventUseCase.CreateEvent(c.Request.Context(), requestDTO)
 	if err != nil {
 		log.Error().Msgf("Failed to create event: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create event"})
+		respondProcessingError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusCreated, event)
+	c.JSON(http.StatusCreated, newEventResponse(event, nil))
 	return
 }
 
+func (h *Handler) UpdateEvent(c *gin.Context) {
+	id, ok := bindEventID(c)
+	if !ok {
+		return
+	}
+
+	requestDTO, ok := bindEventRequest(c)
+	if !ok {
+		return
+	}
+
+	event, err := h.updateEventUseCase.UpdateEvent(c.Request.Context(), &dto.UpdateEventRequestDTO{
+		ID:          id,
+		Title:       requestDTO.Title,
+		Description: requestDTO.Description,
+		StartTime:   requestDTO.StartTime,
+		EndTime:     requestDTO.EndTime,
+		TimeZone:    requestDTO.TimeZone,
+		AllDay:      requestDTO.AllDay,
+		Onboarding:  requestDTO.Onboarding,
+		RoomID:      requestDTO.RoomID,
+		CategoryID:  requestDTO.CategoryID,
+		Tags:        requestDTO.Tags,
+	})
+	if err != nil {
+		log.Error().Msgf("Failed to update event: %v", err)
+		respondProcessingError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newEventResponse(event, nil))
+}
+
+func (h *Handler) DeleteEvent(c *gin.Context) {
+	id, ok := bindEventID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.deleteEventUseCase.DeleteEvent(c.Request.Context(), id); err != nil {
+		log.Error().Msgf("Failed to delete event: %v", err)
+		respondProcessingError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// GetEvent shows the event. Its local times are in the zone given by the tz
+// query parameter, or in the zone of the event.
+func (h *Handler) GetEvent(c *gin.Context) {
+	id, ok := bindEventID(c)
+	if !ok {
+		return
+	}
+
+	loc, ok := bindDisplayZone(c)
+	if !ok {
+		return
+	}
+
+	event, err := h.getEventsUseCase.GetEvent(c.Request.Context(), id)
+	if err != nil {
+		respondProcessingError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newEventResponse(event, loc))
+}
+
+// GetEventICS returns the event as an iCalendar file, with times in the zone
+// given by the tz query parameter, or in the zone of the event.
+func (h *Handler) GetEventICS(c *gin.Context) {
+	id, ok := bindEventID(c)
+	if !ok {
+		return
+	}
+
+	loc, ok := bindDisplayZone(c)
+	if !ok {
+		return
+	}
+
+	event, err := h.getEventsUseCase.GetEvent(c.Request.Context(), id)
+	if err != nil {
+		respondProcessingError(c, err)
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.ics"`, event.ID))
+	c.Data(http.StatusOK, "text/calendar; charset=utf-8", ical.Encode(event, ical.Options{
+		Method:   ical.MethodPublish,
+		Location: loc,
+		Stamp:    time.Now(),
+	}))
+}
+
+// ListEvents lists events ordered by start time. The status query parameter
+// filters by status, it may be repeated or hold a comma separated list, and
+// so may the tag and category parameters. Events with any of the tags are
+// listed, or with all of them when tag_match is all. The q query parameter
+// searches titles and descriptions, words match as prefixes so that it can
+// be used while typing. The tz query parameter picks the zone of the local
+// times.
+func (h *Handler) ListEvents(c *gin.Context) {
+	loc, ok := bindDisplayZone(c)
+	if !ok {
+		return
+	}
+
+	requestDTO, ok := bindListEventsRequest(c)
+	if !ok {
+		return
+	}
+
+	events, err := h.getEventsUseCase.ListEvents(c.Request.Context(), requestDTO)
+	if err != nil {
+		respondProcessingError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newEventResponses(events, loc, strings.TrimSpace(requestDTO.Query) != ""))
+}
+
+// EventFacets counts the events ListEvents lists with the same query
+// parameters, without paging, by tag and by category.
+func (h *Handler) EventFacets(c *gin.Context) {
+	requestDTO, ok := bindListEventsRequest(c)
+	if !ok {
+		return
+	}
+
+	facets, err := h.getEventsUseCase.Facets(c.Request.Context(), requestDTO)
+	if err != nil {
+		respondProcessingError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, facets)
+}
+
+// bindListEventsRequest reads the filter of an event listing from the query
+// parameters, writing the error response itself when it is rejected.
+func bindListEventsRequest(c *gin.Context) (*dto.ListEventsRequestDTO, bool) {
+	var categoryIDs []uuid.UUID
+	for _, value := range queryList(c, "category") {
+		id, err := uuid.Parse(value)
+		if err != nil {
+			respondError(c, http.StatusBadRequest, common.CodeInvalidCategoryID)
+			return nil, false
+		}
+		categoryIDs = append(categoryIDs, id)
+	}
+
+	limit, _ := strconv.Atoi(c.Query("limit"))
+	offset, _ := strconv.Atoi(c.Query("offset"))
+
+	return &dto.ListEventsRequestDTO{
+		Query:       c.Query("q"),
+		Statuses:    queryList(c, "status"),
+		Tags:        queryList(c, "tag"),
+		TagMatch:    c.Query("tag_match"),
+		CategoryIDs: categoryIDs,
+		Limit:       limit,
+		Offset:      offset,
+	}, true
+}
+
+// queryList returns the values of a query parameter that may be repeated or
+// hold a comma separated list.
+func queryList(c *gin.Context, name string) []string {
+	var values []string
+	for _, value := range c.QueryArray(name) {
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				values = append(values, item)
+			}
+		}
+	}
+	return values
+}
+
+func (h *Handler) PublishEvent(c *gin.Context) {
+	id, ok := bindEventID(c)
+	if !ok {
+		return
+	}
+
+	event, err := h.eventLifecycleUseCase.Publish(c.Request.Context(), id)
+	if err != nil {
+		log.Error().Msgf("Failed to publish event: %v", err)
+		respondProcessingError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newEventResponse(event, nil))
+}
+
+func (h *Handler) CancelEvent(c *gin.Context) {
+	id, ok := bindEventID(c)
+	if !ok {
+		return
+	}
+
+	var req CancelEventRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, common.CodeInvalidRequestBody)
+		return
+	}
+
+	event, err := h.eventLifecycleUseCase.Cancel(c.Request.Context(), id, req.Reason)
+	if err != nil {
+		log.Error().Msgf("Failed to cancel event: %v", err)
+		respondProcessingError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newEventResponse(event, nil))
+}
+
 //
 //func (h *Handler) GetEvents(c *gin.Context) {
 //
@@ -0,0 +1,138 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"online-registration/internal/common"
+	"online-registration/internal/interview/domain/auth"
+	"online-registration/internal/interview/domain/tenant"
+	"online-registration/internal/interview/domain/usecase"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	IdempotencyKeyHeader      = "Idempotency-Key"
+	IdempotentReplayedHeader  = "Idempotent-Replayed"
+	maxIdempotencyKeyLength   = 255
+	idempotencyReplayMIMEType = "application/json; charset=utf-8"
+)
+
+type IdempotencyHandler struct {
+	idempotencyUseCase *usecase.IdempotencyUseCase
+}
+
+// NewIdempotencyHandler creates a middleware handler that deduplicates
+// retried requests carrying an Idempotency-Key header
+func NewIdempotencyHandler(idempotencyUseCase *usecase.IdempotencyUseCase) *IdempotencyHandler {
+	return &IdempotencyHandler{
+		idempotencyUseCase: idempotencyUseCase,
+	}
+}
+
+// responseRecorder keeps a copy of the response body written by the handler.
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *responseRecorder) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *responseRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+func (h *IdempotencyHandler) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(IdempotencyKeyHeader)
+		if key == "" {
+			c.Next()
+			return
+		}
+
+		if len(key) > maxIdempotencyKeyLength {
+			respondError(c, http.StatusBadRequest, common.CodeIdempotencyKeyInvalid, maxIdempotencyKeyLength)
+			c.Abort()
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			respondError(c, http.StatusBadRequest, common.CodeInvalidRequestBody)
+			c.Abort()
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		// keys are chosen by clients, so they are only unique per principal
+		// and tenant
+		var subject string
+		if principal := auth.PrincipalFromContext(c.Request.Context()); principal != nil {
+			subject = principal.Subject
+		}
+		tenantID, _ := tenant.FromContext(c.Request.Context())
+		key = usecase.IdempotencyScope(tenantID, subject, key)
+
+		ctx := c.Request.Context()
+		record, acquired, err := h.idempotencyUseCase.Begin(ctx, key, requestFingerprint(c.Request, body))
+		if err != nil {
+			respondProcessingError(c, err)
+			c.Abort()
+			return
+		}
+
+		if !acquired {
+			c.Header(IdempotentReplayedHeader, "true")
+			c.Data(record.StatusCode, idempotencyReplayMIMEType, record.ResponseBody)
+			c.Abort()
+			return
+		}
+
+		// the key is only held for a while after the timeout, the request
+		// must be over before a retry takes the key over
+		handlerCtx, cancel := context.WithTimeout(ctx, h.idempotencyUseCase.RequestTimeout())
+		defer cancel()
+		c.Request = c.Request.WithContext(handlerCtx)
+
+		recorder := &responseRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+
+		c.Next()
+
+		// the response is already sent, so the key must be settled even if
+		// the client has gone away in the meantime
+		ctx = context.WithoutCancel(ctx)
+		if recorder.Status() >= http.StatusInternalServerError {
+			if err := h.idempotencyUseCase.Release(ctx, key, record.Owner); err != nil {
+				log.Error().Msgf("Failed to release idempotency key: %v", err)
+			}
+			return
+		}
+
+		if err := h.idempotencyUseCase.Complete(
+			ctx, key, record.Owner, recorder.Status(), recorder.body.Bytes(),
+		); err != nil {
+			log.Error().Msgf("Failed to complete idempotency key: %v", err)
+		}
+	}
+}
+
+// requestFingerprint identifies the request a key was first used with.
+func requestFingerprint(r *http.Request, body []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(r.Method))
+	hash.Write([]byte{0})
+	hash.Write([]byte(r.URL.Path))
+	hash.Write([]byte{0})
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
@@ -0,0 +1,227 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"online-registration/internal/common"
+	"online-registration/internal/interview/domain/auth"
+	"online-registration/internal/interview/domain/tenant"
+	"online-registration/internal/interview/domain/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+// idempotentRouter serves POST / through the idempotency middleware, with
+// the principal and tenant named by the X-Subject and X-Tenant headers.
+func idempotentRouter(keys *memoryIdempotencyKeys, handle gin.HandlerFunc) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		ctx := auth.ContextWithPrincipal(c.Request.Context(), &auth.Principal{Subject: c.GetHeader("X-Subject")})
+		c.Request = c.Request.WithContext(tenant.ContextWithTenant(ctx, c.GetHeader("X-Tenant")))
+	})
+	router.Use(NewIdempotencyHandler(usecase.NewIdempotencyUseCase(keys, time.Hour, time.Minute)).Middleware())
+	router.POST("/", handle)
+	return router
+}
+
+func postIdempotent(router *gin.Engine, key, body string, headers ...string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	req.Header.Set(IdempotencyKeyHeader, key)
+	req.Header.Set("X-Subject", "alice")
+	req.Header.Set("X-Tenant", "acme")
+	for i := 0; i+1 < len(headers); i += 2 {
+		req.Header.Set(headers[i], headers[i+1])
+	}
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec
+}
+
+func errorCode(t *testing.T, rec *httptest.ResponseRecorder) string {
+	t.Helper()
+
+	var body ErrorResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode error %s: %v", rec.Body, err)
+	}
+	return body.Code
+}
+
+func TestIdempotencyReplaysResponses(t *testing.T) {
+	var calls atomic.Int32
+	router := idempotentRouter(newMemoryIdempotencyKeys(), func(c *gin.Context) {
+		c.JSON(http.StatusCreated, gin.H{"call": calls.Add(1)})
+	})
+
+	first := postIdempotent(router, "k1", `{"title":"a"}`)
+	second := postIdempotent(router, "k1", `{"title":"a"}`)
+
+	if first.Code != http.StatusCreated || second.Code != http.StatusCreated {
+		t.Fatalf("statuses = %d, %d, want 201 twice", first.Code, second.Code)
+	}
+	if second.Body.String() != first.Body.String() {
+		t.Errorf("replayed %s, want %s", second.Body, first.Body)
+	}
+	if first.Header().Get(IdempotentReplayedHeader) != "" || second.Header().Get(IdempotentReplayedHeader) != "true" {
+		t.Errorf("%s headers = %q, %q, want only the replay marked", IdempotentReplayedHeader,
+			first.Header().Get(IdempotentReplayedHeader), second.Header().Get(IdempotentReplayedHeader))
+	}
+	if calls.Load() != 1 {
+		t.Errorf("handler ran %d times, want once", calls.Load())
+	}
+
+	// requests without a key are not deduplicated
+	for range 2 {
+		postIdempotent(router, "", `{"title":"a"}`)
+	}
+	if calls.Load() != 3 {
+		t.Errorf("handler ran %d times, want every request without a key", calls.Load())
+	}
+}
+
+func TestIdempotencyKeysAreScoped(t *testing.T) {
+	var calls atomic.Int32
+	router := idempotentRouter(newMemoryIdempotencyKeys(), func(c *gin.Context) {
+		calls.Add(1)
+		c.Status(http.StatusNoContent)
+	})
+
+	postIdempotent(router, "k1", `{}`)
+	postIdempotent(router, "k1", `{}`, "X-Subject", "bob")
+	postIdempotent(router, "k1", `{}`, "X-Tenant", "globex")
+	// the parts of the scope don't run into one another
+	postIdempotent(router, "x:k1", `{}`)
+	postIdempotent(router, "k1", `{}`, "X-Subject", "alice:x")
+
+	if calls.Load() != 5 {
+		t.Errorf("handler ran %d times, want once per principal and tenant", calls.Load())
+	}
+}
+
+func TestIdempotencyRejectsReusedKeys(t *testing.T) {
+	router := idempotentRouter(newMemoryIdempotencyKeys(), func(c *gin.Context) {
+		c.Status(http.StatusCreated)
+	})
+
+	postIdempotent(router, "k1", `{"title":"a"}`)
+	rec := postIdempotent(router, "k1", `{"title":"b"}`)
+
+	if rec.Code != http.StatusUnprocessableEntity || errorCode(t, rec) != common.CodeIdempotencyKeyReused {
+		t.Errorf("reused key = %d %s, want 422 %s", rec.Code, rec.Body, common.CodeIdempotencyKeyReused)
+	}
+}
+
+func TestIdempotencyRejectsConcurrentDuplicates(t *testing.T) {
+	running := make(chan struct{})
+	finish := make(chan struct{})
+	router := idempotentRouter(newMemoryIdempotencyKeys(), func(c *gin.Context) {
+		close(running)
+		<-finish
+		c.Status(http.StatusCreated)
+	})
+
+	done := make(chan *httptest.ResponseRecorder)
+	go func() { done <- postIdempotent(router, "k1", `{}`) }()
+	<-running
+
+	rec := postIdempotent(router, "k1", `{}`)
+	if rec.Code != http.StatusConflict || errorCode(t, rec) != common.CodeIdempotencyKeyInProgress {
+		t.Errorf("duplicate = %d %s, want 409 %s", rec.Code, rec.Body, common.CodeIdempotencyKeyInProgress)
+	}
+
+	close(finish)
+	if first := <-done; first.Code != http.StatusCreated {
+		t.Errorf("first request = %d, want 201", first.Code)
+	}
+}
+
+func TestIdempotencyReleasesKeysOfServerErrors(t *testing.T) {
+	var calls atomic.Int32
+	router := idempotentRouter(newMemoryIdempotencyKeys(), func(c *gin.Context) {
+		if calls.Add(1) == 1 {
+			c.Status(http.StatusServiceUnavailable)
+			return
+		}
+		c.Status(http.StatusCreated)
+	})
+
+	if rec := postIdempotent(router, "k1", `{}`); rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("first request = %d, want 503", rec.Code)
+	}
+	if rec := postIdempotent(router, "k1", `{}`); rec.Code != http.StatusCreated || calls.Load() != 2 {
+		t.Errorf("retry = %d after %d runs, want it run again", rec.Code, calls.Load())
+	}
+}
+
+func TestIdempotencyKeepsClientErrors(t *testing.T) {
+	var calls atomic.Int32
+	router := idempotentRouter(newMemoryIdempotencyKeys(), func(c *gin.Context) {
+		if calls.Add(1) == 1 {
+			respondError(c, http.StatusBadRequest, common.CodeInvalidRequestBody)
+			return
+		}
+		c.Status(http.StatusCreated)
+	})
+
+	postIdempotent(router, "k1", `{}`)
+	rec := postIdempotent(router, "k1", `{}`)
+	if rec.Code != http.StatusBadRequest || calls.Load() != 1 {
+		t.Errorf("retry = %d after %d runs, want the client error replayed", rec.Code, calls.Load())
+	}
+}
+
+func TestIdempotencyKeepsResponseOfTakeover(t *testing.T) {
+	keys := newMemoryIdempotencyKeys()
+	stalled := make(chan struct{})
+	resume := make(chan struct{})
+	var calls atomic.Int32
+	router := idempotentRouter(keys, func(c *gin.Context) {
+		call := calls.Add(1)
+		if call == 1 {
+			close(stalled)
+			<-resume
+		}
+		c.JSON(http.StatusCreated, gin.H{"call": call})
+	})
+
+	done := make(chan *httptest.ResponseRecorder)
+	go func() { done <- postIdempotent(router, "k1", `{}`) }()
+	<-stalled
+
+	// the first request stalled past its lock, a retry takes the key over
+	keys.mu.Lock()
+	for _, record := range keys.keys {
+		record.CreatedAt = record.CreatedAt.Add(-time.Hour)
+	}
+	keys.mu.Unlock()
+	retry := postIdempotent(router, "k1", `{}`)
+	if retry.Code != http.StatusCreated || retry.Body.String() != `{"call":2}` {
+		t.Fatalf("retry = %d %s, want it run", retry.Code, retry.Body)
+	}
+
+	// the stalled request finishing doesn't overwrite the stored response
+	close(resume)
+	<-done
+	if replay := postIdempotent(router, "k1", `{}`); replay.Body.String() != `{"call":2}` {
+		t.Errorf("replay = %s, want the response of the retry", replay.Body)
+	}
+}
+
+func TestIdempotencyRejectsLongKeys(t *testing.T) {
+	router := idempotentRouter(newMemoryIdempotencyKeys(), func(c *gin.Context) {
+		c.Status(http.StatusCreated)
+	})
+
+	rec := postIdempotent(router, strings.Repeat("k", maxIdempotencyKeyLength+1), `{}`)
+	if rec.Code != http.StatusBadRequest || errorCode(t, rec) != common.CodeIdempotencyKeyInvalid {
+		t.Errorf("long key = %d %s, want 400 %s", rec.Code, rec.Body, common.CodeIdempotencyKeyInvalid)
+	}
+}
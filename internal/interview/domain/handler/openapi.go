@@ -0,0 +1,665 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"online-registration/internal/interview/domain/dto"
+	"online-registration/internal/interview/domain/entity"
+	"online-registration/internal/interview/domain/usecase"
+	"online-registration/internal/openapi"
+	"slices"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+const apiVersion = "1.0.0"
+
+// Names of the shared error responses, by HTTP status.
+var errorResponses = map[int]string{
+	http.StatusBadRequest:          "BadRequest",
+	http.StatusUnauthorized:        "Unauthorized",
+	http.StatusForbidden:           "Forbidden",
+	http.StatusNotFound:            "NotFound",
+	http.StatusConflict:            "Conflict",
+	http.StatusUnprocessableEntity: "UnprocessableEntity",
+	http.StatusTooManyRequests:     "TooManyRequests",
+	http.StatusInternalServerError: "InternalError",
+	http.StatusServiceUnavailable:  "Unavailable",
+}
+
+// commonErrors may be returned by every operation, by the middlewares if
+// not by the handler.
+var commonErrors = []int{
+	http.StatusBadRequest,
+	http.StatusUnauthorized,
+	http.StatusForbidden,
+	http.StatusTooManyRequests,
+	http.StatusInternalServerError,
+}
+
+// apiOperation describes a route of the API for the OpenAPI document.
+type apiOperation struct {
+	method, path string
+	id, tag      string
+	summary      string
+	description  string
+	params       []*openapi.Parameter
+	// request is the JSON body, if any
+	request any
+	status  int
+	// response is the JSON body, nil when there is none
+	response any
+	// contentType is the type of the responses that aren't JSON
+	contentType string
+	// errors lists the statuses returned besides commonErrors
+	errors []int
+}
+
+var (
+	eventIDParam = uuidPathParam("id", "The id of the event.")
+	tzParam      = queryParam("tz", "The IANA time zone to show local times in, the zone of the event by default.",
+		&openapi.Schema{Type: "string"})
+	limitParam = queryParam("limit", "The most entries to list.", &openapi.Schema{Type: "integer"})
+)
+
+// apiOperations lists the routes of the API, under the paths the router
+// registers them at. The tests of cmd fail when the two drift apart, or when
+// the request and response types differ from those the handlers bind.
+var apiOperations = []apiOperation{
+	{
+		method: http.MethodPost, path: "/events", id: "createEvent", tag: "events",
+		summary: "Create an event",
+		description: "Creates a draft event, or a published one when publish is set. Retries carrying the same " +
+			"Idempotency-Key header get the response of the first request.",
+		params: []*openapi.Parameter{{
+			Name: IdempotencyKeyHeader, In: "header",
+			Description: fmt.Sprintf("Deduplicates retries of the request, at most %d characters.",
+				maxIdempotencyKeyLength),
+			Schema: &openapi.Schema{Type: "string"},
+		}},
+		request: CreateEventRequest{},
+		status:  http.StatusCreated, response: EventResponse{},
+		errors: []int{http.StatusConflict, http.StatusUnprocessableEntity},
+	},
+	{
+		method: http.MethodGet, path: "/events", id: "listEvents", tag: "events",
+		summary:     "List events",
+		description: "Lists the events ordered by start time. Words of q match as prefixes.",
+		params:      append(listEventsParams(), tzParam),
+		status:      http.StatusOK, response: []*EventResponse{},
+	},
+	{
+		method: http.MethodGet, path: "/events/facets", id: "eventFacets", tag: "events",
+		summary:     "Count events by tag and category",
+		description: "Counts the events listed with the same query parameters by tag and by category.",
+		params:      listEventsParams(),
+		status:      http.StatusOK, response: entity.EventFacets{},
+	},
+	{
+		method: http.MethodGet, path: "/events/stream", id: "streamEvents", tag: "events",
+		summary: "Stream event changes",
+		description: "Streams the changes of events as server-sent events named after the type of the change, " +
+			"with an EventMessage as data and the id to resume after. A client reconnecting with " +
+			"Last-Event-ID first gets the changes it missed, or a reset event when it missed too many and " +
+			"must reload the events. Filters may be repeated or hold comma separated lists.",
+		params: []*openapi.Parameter{
+			listQueryParam("type", "Selects the changes of the types.", dto.EventTypes...),
+			listQueryParam("event", "Selects the changes of the events, by id."),
+			listQueryParam("tag", "Selects the changes of events with any of the tags."),
+			listQueryParam("category", "Selects the changes of events in the categories, by id."),
+			{
+				Name: "Last-Event-ID", In: "header",
+				Description: "Resumes the stream after the change of the id.",
+				Schema:      &openapi.Schema{Type: "integer", Format: "int64"},
+			},
+			queryParam("last_event_id", "Resumes the stream after the change of the id, for clients that can't "+
+				"set Last-Event-ID.", &openapi.Schema{Type: "integer", Format: "int64"}),
+		},
+		status: http.StatusOK, contentType: "text/event-stream",
+	},
+	{
+		method: http.MethodGet, path: "/events/availability", id: "watchSeatAvailability", tag: "events",
+		summary: "Watch seat availability",
+		description: "Upgrades to a WebSocket pushing the seats left at events and the length of their " +
+			"waitlists. Clients send SeatAvailabilityRequest messages to subscribe to events and unsubscribe " +
+			"from them, and get a " +
+			"SeatAvailabilityMessage for every event subscribed to and then for every change of it. " +
+			fmt.Sprintf("A connection watches at most %d events. ", usecase.MaxWatchedEvents) +
+			"Rejected requests are answered with a SeatAvailabilityError message.",
+		status: http.StatusSwitchingProtocols,
+		errors: []int{http.StatusServiceUnavailable},
+	},
+	{
+		method: http.MethodGet, path: "/events/{id}", id: "getEvent", tag: "events",
+		summary: "Get an event",
+		params:  []*openapi.Parameter{eventIDParam, tzParam},
+		status:  http.StatusOK, response: EventResponse{},
+		errors: []int{http.StatusNotFound},
+	},
+	{
+		method: http.MethodGet, path: "/events/{id}/ics", id: "getEventICS", tag: "events",
+		summary: "Get an event as iCalendar",
+		params:  []*openapi.Parameter{eventIDParam, tzParam},
+		status:  http.StatusOK, contentType: "text/calendar",
+		errors: []int{http.StatusNotFound},
+	},
+	{
+		method: http.MethodPut, path: "/events/{id}", id: "updateEvent", tag: "events",
+		summary:     "Update an event",
+		description: "Replaces the details of a draft or published event. Omitted optional fields are cleared.",
+		params:      []*openapi.Parameter{eventIDParam},
+		request:     UpdateEventRequest{},
+		status:      http.StatusOK, response: EventResponse{},
+		errors: []int{http.StatusNotFound, http.StatusConflict, http.StatusUnprocessableEntity},
+	},
+	{
+		method: http.MethodDelete, path: "/events/{id}", id: "deleteEvent", tag: "events",
+		summary: "Delete an event",
+		params:  []*openapi.Parameter{eventIDParam},
+		status:  http.StatusNoContent,
+		errors:  []int{http.StatusNotFound},
+	},
+	{
+		method: http.MethodPost, path: "/events/{id}/publish", id: "publishEvent", tag: "events",
+		summary: "Publish an event",
+		params:  []*openapi.Parameter{eventIDParam},
+		status:  http.StatusOK, response: EventResponse{},
+		errors: []int{http.StatusNotFound, http.StatusUnprocessableEntity},
+	},
+	{
+		method: http.MethodPost, path: "/events/{id}/cancel", id: "cancelEvent", tag: "events",
+		summary: "Cancel an event",
+		params:  []*openapi.Parameter{eventIDParam},
+		request: CancelEventRequest{},
+		status:  http.StatusOK, response: EventResponse{},
+		errors: []int{http.StatusNotFound, http.StatusUnprocessableEntity},
+	},
+	{
+		method: http.MethodGet, path: "/events/{id}/history", id: "eventHistory", tag: "events",
+		summary: "List the changes of an event",
+		params:  []*openapi.Parameter{eventIDParam, limitParam},
+		status:  http.StatusOK, response: []*entity.EventAuditEntry{},
+		errors: []int{http.StatusNotFound},
+	},
+	{
+		method: http.MethodPost, path: "/events/{id}/registrations", id: "register", tag: "registrations",
+		summary: "Register for an event",
+		description: "Registers the user while the room of the event has free seats, and puts them on the " +
+			"waitlist of the event otherwise. Waitlisted users are registered in the order they joined " +
+			"as seats free up.",
+		params: []*openapi.Parameter{eventIDParam},
+		status: http.StatusCreated, response: entity.Registration{},
+		errors: []int{http.StatusNotFound, http.StatusConflict, http.StatusUnprocessableEntity},
+	},
+	{
+		method: http.MethodDelete, path: "/events/{id}/registrations", id: "cancelRegistration",
+		tag: "registrations", summary: "Cancel the registration for an event",
+		params: []*openapi.Parameter{eventIDParam},
+		status: http.StatusNoContent,
+		errors: []int{http.StatusNotFound},
+	},
+	{
+		method: http.MethodGet, path: "/events/{id}/participants", id: "listParticipants", tag: "participants",
+		summary: "List the participants of an event",
+		params:  []*openapi.Parameter{eventIDParam},
+		status:  http.StatusOK, response: []*entity.Participant{},
+		errors: []int{http.StatusNotFound},
+	},
+	{
+		method: http.MethodPut, path: "/events/{id}/participants/{user_id}", id: "setParticipant",
+		tag: "participants", summary: "Add a participant to an event, or change its role",
+		params:  []*openapi.Parameter{eventIDParam, stringPathParam("user_id", "The id of the user.")},
+		request: ParticipantRequest{},
+		status:  http.StatusOK, response: entity.Participant{},
+		errors: []int{http.StatusNotFound, http.StatusUnprocessableEntity},
+	},
+	{
+		method: http.MethodDelete, path: "/events/{id}/participants/{user_id}", id: "removeParticipant",
+		tag: "participants", summary: "Remove a participant from an event",
+		params: []*openapi.Parameter{eventIDParam, stringPathParam("user_id", "The id of the user.")},
+		status: http.StatusNoContent,
+		errors: []int{http.StatusNotFound, http.StatusUnprocessableEntity},
+	},
+	{
+		method: http.MethodPost, path: "/freebusy", id: "freeBusy", tag: "participants",
+		summary: "Get the busy periods of users and rooms",
+		description: "Returns the periods the users and rooms are busy between start and end, and the first " +
+			"free slot of slot_length when given. Recurring events are out of scope, every event keeps its users " +
+			"and room busy once, from its start to its end.",
+		request: FreeBusyRequest{},
+		status:  http.StatusOK, response: entity.FreeBusy{},
+	},
+
+	{
+		method: http.MethodPost, path: "/webhooks", id: "createWebhook", tag: "webhooks",
+		summary:     "Subscribe a webhook",
+		description: "The secret signing the deliveries is generated when omitted, it is only returned here.",
+		request:     WebhookSubscriptionRequest{},
+		status:      http.StatusCreated, response: entity.WebhookSubscription{},
+	},
+	{
+		method: http.MethodGet, path: "/webhooks", id: "listWebhooks", tag: "webhooks",
+		summary: "List the webhooks",
+		status:  http.StatusOK, response: []*entity.WebhookSubscription{},
+	},
+	{
+		method: http.MethodGet, path: "/webhooks/{id}", id: "getWebhook", tag: "webhooks",
+		summary: "Get a webhook",
+		params:  []*openapi.Parameter{uuidPathParam("id", "The id of the webhook.")},
+		status:  http.StatusOK, response: entity.WebhookSubscription{},
+		errors: []int{http.StatusNotFound},
+	},
+	{
+		method: http.MethodPut, path: "/webhooks/{id}", id: "updateWebhook", tag: "webhooks",
+		summary: "Update a webhook",
+		params:  []*openapi.Parameter{uuidPathParam("id", "The id of the webhook.")},
+		request: WebhookSubscriptionRequest{},
+		status:  http.StatusOK, response: entity.WebhookSubscription{},
+		errors: []int{http.StatusNotFound},
+	},
+	{
+		method: http.MethodDelete, path: "/webhooks/{id}", id: "deleteWebhook", tag: "webhooks",
+		summary: "Delete a webhook",
+		params:  []*openapi.Parameter{uuidPathParam("id", "The id of the webhook.")},
+		status:  http.StatusNoContent,
+		errors:  []int{http.StatusNotFound},
+	},
+	{
+		method: http.MethodGet, path: "/webhooks/{id}/deliveries", id: "listWebhookDeliveries", tag: "webhooks",
+		summary: "List the deliveries of a webhook",
+		params:  []*openapi.Parameter{uuidPathParam("id", "The id of the webhook."), limitParam},
+		status:  http.StatusOK, response: []*entity.WebhookDelivery{},
+		errors: []int{http.StatusNotFound},
+	},
+	{
+		method: http.MethodPost, path: "/webhooks/{id}/deliveries/{delivery_id}/redeliver",
+		id: "redeliverWebhook", tag: "webhooks", summary: "Deliver a message to a webhook again",
+		params: []*openapi.Parameter{
+			uuidPathParam("id", "The id of the webhook."),
+			uuidPathParam("delivery_id", "The id of the delivery."),
+		},
+		status: http.StatusAccepted, response: entity.WebhookDelivery{},
+		errors: []int{http.StatusNotFound},
+	},
+
+	{
+		method: http.MethodPost, path: "/venues", id: "createVenue", tag: "venues",
+		summary: "Create a venue",
+		request: VenueRequest{},
+		status:  http.StatusCreated, response: entity.Venue{},
+	},
+	{
+		method: http.MethodGet, path: "/venues", id: "listVenues", tag: "venues",
+		summary: "List the venues",
+		status:  http.StatusOK, response: []*entity.Venue{},
+	},
+	{
+		method: http.MethodGet, path: "/venues/{id}", id: "getVenue", tag: "venues",
+		summary: "Get a venue with its rooms",
+		params:  []*openapi.Parameter{uuidPathParam("id", "The id of the venue.")},
+		status:  http.StatusOK, response: entity.Venue{},
+		errors: []int{http.StatusNotFound},
+	},
+	{
+		method: http.MethodPut, path: "/venues/{id}", id: "updateVenue", tag: "venues",
+		summary: "Update a venue",
+		params:  []*openapi.Parameter{uuidPathParam("id", "The id of the venue.")},
+		request: VenueRequest{},
+		status:  http.StatusOK, response: entity.Venue{},
+		errors: []int{http.StatusNotFound},
+	},
+	{
+		method: http.MethodDelete, path: "/venues/{id}", id: "deleteVenue", tag: "venues",
+		summary: "Delete a venue without rooms",
+		params:  []*openapi.Parameter{uuidPathParam("id", "The id of the venue.")},
+		status:  http.StatusNoContent,
+		errors:  []int{http.StatusNotFound, http.StatusConflict},
+	},
+	{
+		method: http.MethodPost, path: "/venues/{id}/rooms", id: "createRoom", tag: "venues",
+		summary: "Add a room to a venue",
+		params:  []*openapi.Parameter{uuidPathParam("id", "The id of the venue.")},
+		request: RoomRequest{},
+		status:  http.StatusCreated, response: entity.Room{},
+		errors: []int{http.StatusNotFound, http.StatusConflict},
+	},
+	{
+		method: http.MethodPut, path: "/rooms/{id}", id: "updateRoom", tag: "venues",
+		summary: "Update a room",
+		params:  []*openapi.Parameter{uuidPathParam("id", "The id of the room.")},
+		request: RoomRequest{},
+		status:  http.StatusOK, response: entity.Room{},
+		errors: []int{http.StatusNotFound, http.StatusConflict},
+	},
+	{
+		method: http.MethodDelete, path: "/rooms/{id}", id: "deleteRoom", tag: "venues",
+		summary: "Delete a room no event is booked into",
+		params:  []*openapi.Parameter{uuidPathParam("id", "The id of the room.")},
+		status:  http.StatusNoContent,
+		errors:  []int{http.StatusNotFound, http.StatusConflict},
+	},
+
+	{
+		method: http.MethodGet, path: "/tags", id: "listTags", tag: "tags",
+		summary: "List the tags",
+		status:  http.StatusOK, response: []*entity.Tag{},
+	},
+	{
+		method: http.MethodPost, path: "/tags", id: "createTag", tag: "tags",
+		summary: "Create a tag",
+		request: TagRequest{},
+		status:  http.StatusCreated, response: entity.Tag{},
+		errors: []int{http.StatusConflict},
+	},
+	{
+		method: http.MethodPut, path: "/tags/{id}", id: "updateTag", tag: "tags",
+		summary: "Rename a tag",
+		params:  []*openapi.Parameter{uuidPathParam("id", "The id of the tag.")},
+		request: TagRequest{},
+		status:  http.StatusOK, response: entity.Tag{},
+		errors: []int{http.StatusNotFound, http.StatusConflict},
+	},
+	{
+		method: http.MethodDelete, path: "/tags/{id}", id: "deleteTag", tag: "tags",
+		summary: "Delete a tag",
+		params:  []*openapi.Parameter{uuidPathParam("id", "The id of the tag.")},
+		status:  http.StatusNoContent,
+		errors:  []int{http.StatusNotFound},
+	},
+	{
+		method: http.MethodGet, path: "/categories", id: "listCategories", tag: "tags",
+		summary: "List the categories",
+		status:  http.StatusOK, response: []*entity.Category{},
+	},
+	{
+		method: http.MethodPost, path: "/categories", id: "createCategory", tag: "tags",
+		summary: "Create a category",
+		request: CategoryRequest{},
+		status:  http.StatusCreated, response: entity.Category{},
+		errors: []int{http.StatusConflict},
+	},
+	{
+		method: http.MethodPut, path: "/categories/{id}", id: "updateCategory", tag: "tags",
+		summary: "Rename a category",
+		params:  []*openapi.Parameter{uuidPathParam("id", "The id of the category.")},
+		request: CategoryRequest{},
+		status:  http.StatusOK, response: entity.Category{},
+		errors: []int{http.StatusNotFound, http.StatusConflict},
+	},
+	{
+		method: http.MethodDelete, path: "/categories/{id}", id: "deleteCategory", tag: "tags",
+		summary: "Delete a category",
+		params:  []*openapi.Parameter{uuidPathParam("id", "The id of the category.")},
+		status:  http.StatusNoContent,
+		errors:  []int{http.StatusNotFound},
+	},
+
+	{
+		method: http.MethodGet, path: "/admin/schedule", id: "listScheduledTasks", tag: "admin",
+		summary: "List the scheduled tasks with their last run",
+		status:  http.StatusOK, response: []*entity.ScheduledTask{},
+	},
+}
+
+// listEventsParams are the query parameters ListEvents and EventFacets share.
+func listEventsParams() []*openapi.Parameter {
+	return []*openapi.Parameter{
+		queryParam("q", "Searches the titles and descriptions.", &openapi.Schema{Type: "string"}),
+		listQueryParam("status", "Selects the events with the statuses.", entity.EventStatuses...),
+		listQueryParam("tag", "Selects the events with any of the tags, or all of them."),
+		queryParam("tag_match", "Whether events need any of the tags or all of them.",
+			&openapi.Schema{Type: "string", Enum: entity.TagMatches}),
+		listQueryParam("category", "Selects the events in the categories, by id."),
+		limitParam,
+		queryParam("offset", "The number of events to skip.", &openapi.Schema{Type: "integer"}),
+	}
+}
+
+func uuidPathParam(name, description string) *openapi.Parameter {
+	return &openapi.Parameter{
+		Name: name, In: "path", Description: description, Required: true,
+		Schema: &openapi.Schema{Type: "string", Format: "uuid"},
+	}
+}
+
+func stringPathParam(name, description string) *openapi.Parameter {
+	return &openapi.Parameter{
+		Name: name, In: "path", Description: description, Required: true,
+		Schema: &openapi.Schema{Type: "string"},
+	}
+}
+
+func queryParam(name, description string, schema *openapi.Schema) *openapi.Parameter {
+	return &openapi.Parameter{Name: name, In: "query", Description: description, Schema: schema}
+}
+
+// listQueryParam is a query parameter read by queryList, which may be
+// repeated or hold a comma separated list.
+func listQueryParam(name, description string, values ...string) *openapi.Parameter {
+	explode := true
+	return &openapi.Parameter{
+		Name: name, In: "query", Description: description + " May be repeated or hold a comma separated list.",
+		Explode: &explode,
+		Schema: &openapi.Schema{
+			Type:  "array",
+			Items: &openapi.Schema{Type: "string", Enum: values},
+		},
+	}
+}
+
+// OpenAPI returns the OpenAPI document of the API served under basePath.
+func OpenAPI(basePath string) *openapi.Document {
+	schemas := openapi.NewSchemas()
+
+	doc := &openapi.Document{
+		OpenAPI: openapi.Version,
+		Info: openapi.Info{
+			Title: "Online registration API",
+			Description: "Errors are returned as an ErrorResponse, {\"code\": ..., \"error\": ...}, with a code to " +
+				"tell them apart and a message in the language picked by the Accept-Language header. It replaced " +
+				"the former {\"ErrorData\": ...} body. Every response carries the " +
+				RequestIDHeader + " header, the id sent by the client when it is usable.",
+			Version: apiVersion,
+		},
+		Servers: []openapi.Server{{URL: basePath}},
+		Security: []openapi.SecurityRequirement{
+			{"bearerAuth": {}},
+			{"apiKeyAuth": {}},
+		},
+		Tags: []openapi.Tag{
+			{Name: "events"},
+			{Name: "registrations"},
+			{Name: "participants"},
+			{Name: "webhooks"},
+			{Name: "venues"},
+			{Name: "tags", Description: "Tags and categories of events."},
+			{Name: "admin"},
+		},
+	}
+
+	for _, op := range apiOperations {
+		doc.AddOperation(op.path, op.method, op.operation(schemas))
+	}
+
+	schemas.Of(dto.EventMessage{})
+	schemas.Define("SeatAvailabilityRequest", seatRequest{})
+	schemas.Define("SeatAvailabilityMessage", seatMessage{})
+	schemas.Define("SeatAvailabilityError", seatErrorMessage{})
+
+	doc.Components = &openapi.Components{
+		Schemas:   schemas.Components(),
+		Responses: make(map[string]*openapi.Response, len(errorResponses)),
+		Parameters: map[string]*openapi.Parameter{
+			"Tenant": {
+				Name: TenantHeader, In: "header",
+				Description: "The tenant to act on. Principals bound to a tenant may only repeat theirs. " +
+					"The others act on the default tenant, only the admin role, the tenants:switch scope " +
+					"and the anonymous principal of deployments without authentication may name another one.",
+				Schema: &openapi.Schema{Type: "string"},
+			},
+			"AcceptLanguage": {
+				Name: "Accept-Language", In: "header",
+				Description: "The language of the error messages.",
+				Schema:      &openapi.Schema{Type: "string"},
+			},
+			"RequestID": {
+				Name: RequestIDHeader, In: "header",
+				Description: "The id of the request, kept in the audit log. It is generated when omitted.",
+				Schema:      &openapi.Schema{Type: "string"},
+			},
+		},
+		SecuritySchemes: map[string]*openapi.SecurityScheme{
+			"bearerAuth": {
+				Type: "http", Scheme: "bearer", BearerFormat: "JWT",
+				Description: "An access token, or an API key.",
+			},
+			"apiKeyAuth": {
+				Type: "apiKey", In: "header", Name: "Authorization",
+				Description: `An API key with the ApiKey scheme, i.e. "Authorization: ApiKey <key>".`,
+			},
+		},
+	}
+	for status, name := range errorResponses {
+		doc.Components.Responses[name] = errorResponse(schemas, status)
+	}
+	describeSchemas(schemas)
+
+	return doc
+}
+
+func (op apiOperation) operation(schemas *openapi.Schemas) *openapi.Operation {
+	operation := &openapi.Operation{
+		OperationID: op.id,
+		Summary:     op.summary,
+		Description: op.description,
+		Tags:        []string{op.tag},
+		Parameters: slices.Concat(op.params, []*openapi.Parameter{
+			{Ref: "#/components/parameters/Tenant"},
+			{Ref: "#/components/parameters/AcceptLanguage"},
+			{Ref: "#/components/parameters/RequestID"},
+		}),
+		Responses: make(map[string]*openapi.Response),
+	}
+
+	if op.request != nil {
+		operation.RequestBody = &openapi.RequestBody{
+			Required: true,
+			Content: map[string]*openapi.MediaType{
+				"application/json": {Schema: schemas.Of(op.request)},
+			},
+		}
+	}
+
+	response := &openapi.Response{Description: http.StatusText(op.status)}
+	switch {
+	case op.response != nil:
+		response.Content = map[string]*openapi.MediaType{
+			"application/json": {Schema: schemas.Of(op.response)},
+		}
+	case op.contentType != "":
+		response.Content = map[string]*openapi.MediaType{
+			op.contentType: {Schema: &openapi.Schema{Type: "string"}},
+		}
+	}
+	operation.Responses[strconv.Itoa(op.status)] = response
+
+	for _, status := range slices.Concat(commonErrors, op.errors) {
+		operation.Responses[strconv.Itoa(status)] = &openapi.Response{
+			Ref: "#/components/responses/" + errorResponses[status],
+		}
+	}
+	return operation
+}
+
+func errorResponse(schemas *openapi.Schemas, status int) *openapi.Response {
+	response := &openapi.Response{
+		Description: http.StatusText(status),
+		Headers: map[string]*openapi.Header{
+			"Content-Language": {
+				Description: "The language of the error message.",
+				Schema:      &openapi.Schema{Type: "string"},
+			},
+		},
+		Content: map[string]*openapi.MediaType{
+			"application/json": {Schema: schemas.Of(ErrorResponse{})},
+		},
+	}
+	switch status {
+	case http.StatusUnauthorized:
+		response.Headers["WWW-Authenticate"] = &openapi.Header{Schema: &openapi.Schema{Type: "string"}}
+	case http.StatusTooManyRequests:
+		response.Headers["Retry-After"] = &openapi.Header{
+			Description: "The seconds to wait before retrying.",
+			Schema:      &openapi.Schema{Type: "integer"},
+		}
+	}
+	return response
+}
+
+// describeSchemas adds what the Go types of the schemas don't tell.
+func describeSchemas(schemas *openapi.Schemas) {
+	errorSchema := schemas.Component("ErrorResponse")
+	errorSchema.Required = []string{"code", "error"}
+	errorSchema.Properties["code"].Description = "Tells the error apart, the messages may change."
+	errorSchema.Properties["error"].Description = "The message of the error, in the negotiated language."
+
+	schemas.Component("EventResponse").Properties["Status"].Enum = entity.EventStatuses
+	schemas.Component("CreateEventRequest").Description = "All-day events are given by start_date and " +
+		"end_date instead of start_time and end_time. Omitted room_id, category_id and tags are cleared, " +
+		"publish is only used when creating events."
+	schemas.Component("Registration").Properties["status"].Enum = []string{
+		entity.RegistrationRegistered, entity.RegistrationWaitlisted, entity.RegistrationCancelled,
+	}
+	schemas.Component("ParticipantRequest").Properties["role"].Enum = entity.ParticipantRoles
+	schemas.Component("WebhookSubscriptionRequest").Properties["event_types"].Items.Enum = dto.EventTypes
+	schemas.Component("WebhookSubscriptionRequest").Properties["url"].Description = "An http or https URL whose " +
+		"host resolves to public addresses only. Deliveries to hosts resolving to internal addresses fail."
+	schemas.Component("FreeBusyRequest").Properties["slot_length"].Description =
+		`A duration such as "45m". When given, the first slot of that length in which everyone is free is returned.`
+	schemas.Component("SeatAvailabilityRequest").Properties["action"].Enum = []string{
+		seatActionSubscribe, seatActionUnsubscribe,
+	}
+	schemas.Component("SeatAvailabilityMessage").Properties["type"].Enum = []string{seatMessageAvailability}
+	schemas.Component("SeatAvailabilityError").Properties["type"].Enum = []string{seatMessageError}
+}
+
+type OpenAPIHandler struct {
+	spec []byte
+}
+
+// NewOpenAPIHandler creates a new HTTP handler serving the OpenAPI document
+// of the API served under basePath, and a page rendering it.
+func NewOpenAPIHandler(basePath string) (*OpenAPIHandler, error) {
+	spec, err := json.Marshal(OpenAPI(basePath))
+	if err != nil {
+		return nil, fmt.Errorf("NewOpenAPIHandler %w", err)
+	}
+	return &OpenAPIHandler{spec: spec}, nil
+}
+
+// Spec serves the OpenAPI document.
+func (h *OpenAPIHandler) Spec(c *gin.Context) {
+	c.Data(http.StatusOK, "application/json; charset=utf-8", h.spec)
+}
+
+// Docs serves a Redoc page rendering the OpenAPI document, it is expected
+// next to the page.
+func (h *OpenAPIHandler) Docs(c *gin.Context) {
+	c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(docsPage))
+}
+
+const docsPage = `<!DOCTYPE html>
+<html>
+<head>
+<title>Online registration API</title>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+</head>
+<body>
+<redoc spec-url="openapi.json"></redoc>
+<script src="https://cdn.redoc.ly/redoc/v2.1.5/bundles/redoc.standalone.js"></script>
+</body>
+</html>
+`
@@ -0,0 +1,82 @@
+package handler
+
+import (
+	"net/http"
+	"online-registration/internal/common"
+	"online-registration/internal/interview/domain/usecase"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+)
+
+type ParticipantRequest struct {
+	Role string `json:"role"`
+}
+
+type ParticipantHandler struct {
+	eventParticipantsUseCase *usecase.EventParticipantsUseCase
+}
+
+// NewParticipantHandler creates a new HTTP handler for the participants of
+// events
+func NewParticipantHandler(eventParticipantsUseCase *usecase.EventParticipantsUseCase) *ParticipantHandler {
+	return &ParticipantHandler{
+		eventParticipantsUseCase: eventParticipantsUseCase,
+	}
+}
+
+func (h *ParticipantHandler) ListParticipants(c *gin.Context) {
+	eventID, ok := bindEventID(c)
+	if !ok {
+		return
+	}
+
+	participants, err := h.eventParticipantsUseCase.ListParticipants(c.Request.Context(), eventID)
+	if err != nil {
+		respondProcessingError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, participants)
+}
+
+// SetParticipant adds the user of the path to the event, or changes their
+// role.
+func (h *ParticipantHandler) SetParticipant(c *gin.Context) {
+	eventID, ok := bindEventID(c)
+	if !ok {
+		return
+	}
+
+	var req ParticipantRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, common.CodeInvalidRequestBody)
+		return
+	}
+
+	participant, err := h.eventParticipantsUseCase.SetParticipant(
+		c.Request.Context(), eventID, c.Param("user_id"), req.Role,
+	)
+	if err != nil {
+		log.Error().Msgf("Failed to set event participant: %v", err)
+		respondProcessingError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, participant)
+}
+
+func (h *ParticipantHandler) RemoveParticipant(c *gin.Context) {
+	eventID, ok := bindEventID(c)
+	if !ok {
+		return
+	}
+
+	err := h.eventParticipantsUseCase.RemoveParticipant(c.Request.Context(), eventID, c.Param("user_id"))
+	if err != nil {
+		respondProcessingError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,107 @@
+package handler
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"online-registration/internal/common"
+	"online-registration/internal/interview/domain/auth"
+	"online-registration/internal/interview/domain/entity"
+	"online-registration/internal/interview/domain/usecase"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+)
+
+type RateLimitHandler struct {
+	rateLimitUseCase *usecase.RateLimitUseCase
+	ipLimit          entity.RateLimit
+	readLimit        entity.RateLimit
+	writeLimit       entity.RateLimit
+}
+
+// NewRateLimitHandler creates a middleware handler that limits the requests
+// of every IP address and of every client. Reads and writes of clients have
+// separate buckets.
+func NewRateLimitHandler(
+	rateLimitUseCase *usecase.RateLimitUseCase,
+	ipLimit entity.RateLimit,
+	readLimit entity.RateLimit,
+	writeLimit entity.RateLimit,
+) *RateLimitHandler {
+	return &RateLimitHandler{
+		rateLimitUseCase: rateLimitUseCase,
+		ipLimit:          ipLimit,
+		readLimit:        readLimit,
+		writeLimit:       writeLimit,
+	}
+}
+
+// IPMiddleware limits the requests of every IP address. It must run before
+// authentication, so that requests with invalid credentials, which cost a
+// token verification or an API key lookup, are limited too.
+func (h *RateLimitHandler) IPMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		h.limit(c, "ip:"+c.ClientIP(), h.ipLimit)
+	}
+}
+
+// Middleware limits the requests of every client. It must run after
+// authentication so that API keys and users are limited on their own rather
+// than by IP.
+func (h *RateLimitHandler) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		class, limit := "read", h.readLimit
+		switch c.Request.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+		default:
+			class, limit = "write", h.writeLimit
+		}
+
+		h.limit(c, class+":"+rateLimitClient(c), limit)
+	}
+}
+
+// limit answers 429 once key has used up its quota and reports the quota in
+// the RateLimit-* headers. When the store fails, requests are let through.
+func (h *RateLimitHandler) limit(c *gin.Context, key string, limit entity.RateLimit) {
+	decision, err := h.rateLimitUseCase.Take(c.Request.Context(), key, limit)
+	if err != nil {
+		log.Error().Msgf("Rate limiting skipped: %v", err)
+		c.Next()
+		return
+	}
+
+	c.Header("RateLimit-Limit", strconv.Itoa(limit.Requests))
+	c.Header("RateLimit-Remaining", strconv.Itoa(decision.Remaining))
+	c.Header("RateLimit-Reset", strconv.Itoa(ceilSeconds(decision.Reset)))
+	c.Header("RateLimit-Policy", fmt.Sprintf("%d;w=%d", limit.Requests, ceilSeconds(limit.Period)))
+
+	if !decision.Allowed {
+		c.Header("Retry-After", strconv.Itoa(ceilSeconds(decision.RetryAfter)))
+		respondError(c, http.StatusTooManyRequests, common.CodeRateLimited)
+		c.Abort()
+		return
+	}
+
+	c.Next()
+}
+
+// rateLimitClient identifies the client a request is accounted to: the API
+// key or user it is authenticated as, or else its IP address.
+func rateLimitClient(c *gin.Context) string {
+	principal := auth.PrincipalFromContext(c.Request.Context())
+	if principal != nil && !principal.System {
+		if principal.Scopes != nil {
+			return principal.Subject
+		}
+		return "user:" + principal.Subject
+	}
+	return "ip:" + c.ClientIP()
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
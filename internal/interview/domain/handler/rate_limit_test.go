@@ -0,0 +1,157 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"online-registration/internal/common"
+	"online-registration/internal/interview/domain/auth"
+	"online-registration/internal/interview/domain/entity"
+	"online-registration/internal/interview/domain/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+// countingStore lets every key take limit.Requests tokens, none refill.
+type countingStore struct {
+	mu    sync.Mutex
+	taken map[string]int
+	err   error
+}
+
+func (s *countingStore) Take(_ context.Context, key string, limit entity.RateLimit) (*entity.RateLimitBucket, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.taken[key] < limit.Requests {
+		s.taken[key]++
+		return &entity.RateLimitBucket{Tokens: float64(limit.Requests - s.taken[key]), Allowed: true}, nil
+	}
+	return &entity.RateLimitBucket{Tokens: 0, Allowed: false}, nil
+}
+
+func newTestRateLimitHandler(store *countingStore) *RateLimitHandler {
+	return NewRateLimitHandler(
+		usecase.NewRateLimitUseCase(store),
+		entity.RateLimit{Requests: 3, Period: time.Minute},
+		entity.RateLimit{Requests: 2, Period: time.Minute},
+		entity.RateLimit{Requests: 1, Period: time.Minute},
+	)
+}
+
+func serveRateLimited(router *gin.Engine, method, subject string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, "/", nil)
+	req.RemoteAddr = "192.0.2.1:1234"
+	if subject != "" {
+		req.Header.Set("X-Subject", subject)
+	}
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestRateLimitMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		if subject := c.GetHeader("X-Subject"); subject != "" {
+			c.Request = c.Request.WithContext(auth.ContextWithPrincipal(c.Request.Context(), &auth.Principal{Subject: subject}))
+		}
+	})
+	router.Use(newTestRateLimitHandler(&countingStore{taken: make(map[string]int)}).Middleware())
+	router.GET("/", func(c *gin.Context) { c.Status(http.StatusOK) })
+	router.POST("/", func(c *gin.Context) { c.Status(http.StatusCreated) })
+
+	steps := []struct {
+		method, subject string
+		wantStatus      int
+		wantRemaining   string
+	}{
+		{http.MethodGet, "alice", http.StatusOK, "1"},
+		{http.MethodGet, "alice", http.StatusOK, "0"},
+		{http.MethodGet, "alice", http.StatusTooManyRequests, "0"},
+		// writes have a bucket of their own
+		{http.MethodPost, "alice", http.StatusCreated, "0"},
+		{http.MethodPost, "alice", http.StatusTooManyRequests, "0"},
+		// and so has every user
+		{http.MethodGet, "bob", http.StatusOK, "1"},
+		// and anonymous clients by IP
+		{http.MethodGet, "", http.StatusOK, "1"},
+	}
+	for i, step := range steps {
+		rec := serveRateLimited(router, step.method, step.subject)
+		if rec.Code != step.wantStatus {
+			t.Fatalf("step %d: status = %d, want %d", i, rec.Code, step.wantStatus)
+		}
+		if got := rec.Header().Get("RateLimit-Remaining"); got != step.wantRemaining {
+			t.Errorf("step %d: RateLimit-Remaining = %q, want %q", i, got, step.wantRemaining)
+		}
+		if got := rec.Header().Get("RateLimit-Policy"); got == "" {
+			t.Errorf("step %d: no RateLimit-Policy", i)
+		}
+
+		retryAfter := rec.Header().Get("Retry-After")
+		if step.wantStatus != http.StatusTooManyRequests {
+			if retryAfter != "" {
+				t.Errorf("step %d: Retry-After = %q on an allowed request", i, retryAfter)
+			}
+			continue
+		}
+		// the limit of writes refills a token a minute, reads one every 30s
+		if want := map[string]string{http.MethodGet: "30", http.MethodPost: "60"}[step.method]; retryAfter != want {
+			t.Errorf("step %d: Retry-After = %q, want %q", i, retryAfter, want)
+		}
+		var body ErrorResponse
+		if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil || body.Code != common.CodeRateLimited {
+			t.Errorf("step %d: body = %s, want the %s code", i, rec.Body, common.CodeRateLimited)
+		}
+	}
+}
+
+func TestRateLimitIPMiddlewareRunsBeforeAuthentication(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	authenticated := 0
+	router := gin.New()
+	router.Use(newTestRateLimitHandler(&countingStore{taken: make(map[string]int)}).IPMiddleware())
+	// rejects every credential, as a flood of invalid tokens would be
+	router.Use(func(c *gin.Context) {
+		authenticated++
+		respondError(c, http.StatusUnauthorized, common.CodeUnauthenticated)
+		c.Abort()
+	})
+	router.GET("/", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	for i, want := range []int{
+		http.StatusUnauthorized, http.StatusUnauthorized, http.StatusUnauthorized, http.StatusTooManyRequests,
+	} {
+		if rec := serveRateLimited(router, http.MethodGet, "mallory"); rec.Code != want {
+			t.Fatalf("request %d: status = %d, want %d", i, rec.Code, want)
+		}
+	}
+	if authenticated != 3 {
+		t.Errorf("%d requests reached authentication, want 3", authenticated)
+	}
+}
+
+func TestRateLimitMiddlewareWithoutStore(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(newTestRateLimitHandler(&countingStore{err: errors.New("connection refused")}).IPMiddleware())
+	router.GET("/", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	if rec := serveRateLimited(router, http.MethodGet, ""); rec.Code != http.StatusOK {
+		t.Errorf("status = %d, want the request let through", rec.Code)
+	}
+}
@@ -0,0 +1,53 @@
+package handler
+
+import (
+	"net/http"
+	"online-registration/internal/interview/domain/usecase"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+)
+
+type RegistrationHandler struct {
+	registerEventUseCase *usecase.RegisterEventUseCase
+}
+
+// NewRegistrationHandler creates a new HTTP handler for event registrations
+func NewRegistrationHandler(registerEventUseCase *usecase.RegisterEventUseCase) *RegistrationHandler {
+	return &RegistrationHandler{
+		registerEventUseCase: registerEventUseCase,
+	}
+}
+
+// Register registers the authenticated user for the event.
+func (h *RegistrationHandler) Register(c *gin.Context) {
+	eventID, ok := bindEventID(c)
+	if !ok {
+		return
+	}
+
+	registration, err := h.registerEventUseCase.Register(c.Request.Context(), eventID)
+	if err != nil {
+		log.Error().Msgf("Failed to register for event: %v", err)
+		respondProcessingError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, registration)
+}
+
+// Cancel cancels the registration of the authenticated user for the event.
+func (h *RegistrationHandler) Cancel(c *gin.Context) {
+	eventID, ok := bindEventID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.registerEventUseCase.Cancel(c.Request.Context(), eventID); err != nil {
+		log.Error().Msgf("Failed to cancel registration: %v", err)
+		respondProcessingError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,43 @@
+package handler
+
+import (
+	"online-registration/internal/interview/domain/audit"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const (
+	RequestIDHeader       = "X-Request-ID"
+	maxRequestIDLength    = 128
+	minRequestIDPrintable = 0x21
+	maxRequestIDPrintable = 0x7e
+)
+
+// RequestIDMiddleware puts the id of the request into the request context and
+// the response. The id sent by the client, e.g. a proxy, is kept when it is
+// usable, otherwise a new one is generated.
+func RequestIDMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(RequestIDHeader)
+		if !validRequestID(requestID) {
+			requestID = uuid.NewString()
+		}
+
+		c.Header(RequestIDHeader, requestID)
+		c.Request = c.Request.WithContext(audit.ContextWithRequestID(c.Request.Context(), requestID))
+		c.Next()
+	}
+}
+
+func validRequestID(requestID string) bool {
+	if requestID == "" || len(requestID) > maxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(requestID); i++ {
+		if requestID[i] < minRequestIDPrintable || requestID[i] > maxRequestIDPrintable {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,80 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"online-registration/internal/common"
+	"online-registration/internal/i18n"
+	"online-registration/internal/interview/helper"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ErrorResponse is the body of every error response, e.g.
+// {"code": "not_found", "error": "Nothing found"}. It replaced the former
+// {"ErrorData": "..."} body: clients tell errors apart by Code, Error is
+// only meant to be shown and changes with the language.
+type ErrorResponse struct {
+	Code  string `json:"code"`
+	Error string `json:"error"`
+}
+
+var statusToHTTP = map[uint8]int{
+	helper.InvalidArgument:    http.StatusBadRequest,
+	helper.DeadlineExceeded:   http.StatusGatewayTimeout,
+	helper.NotFound:           http.StatusNotFound,
+	helper.AlreadyExists:      http.StatusConflict,
+	helper.PermissionDenied:   http.StatusForbidden,
+	helper.ResourceExhausted:  http.StatusTooManyRequests,
+	helper.FailedPrecondition: http.StatusUnprocessableEntity,
+	helper.InternalError:      http.StatusInternalServerError,
+	helper.Unavailable:        http.StatusServiceUnavailable,
+	helper.Unauthenticated:    http.StatusUnauthorized,
+}
+
+// requestLang returns the response language negotiated from Accept-Language.
+func requestLang(c *gin.Context) i18n.Lang {
+	return i18n.ParseAcceptLanguage(c.GetHeader("Accept-Language"))
+}
+
+// respondError writes a localized error response for the given code.
+func respondError(c *gin.Context, httpStatus int, code string, args ...any) {
+	lang := requestLang(c)
+	c.Header("Content-Language", string(lang))
+	c.JSON(httpStatus, ErrorResponse{
+		Code:  code,
+		Error: i18n.Translate(lang, code, args...),
+	})
+}
+
+// respondProcessingError maps err to an HTTP status and a localized error
+// response. Errors that are not a ProcessingError are reported as internal.
+func respondProcessingError(c *gin.Context, err error) {
+	httpStatus, code, args := processingErrorCode(err)
+	respondError(c, httpStatus, code, args...)
+}
+
+// processingErrorCode maps err to an HTTP status, and to the code and
+// arguments of its message.
+func processingErrorCode(err error) (int, string, []any) {
+	var processingErr *common.ProcessingError
+	if !errors.As(err, &processingErr) {
+		if errors.Is(err, NothingFoundErr) {
+			return http.StatusNotFound, common.CodeNotFound, nil
+		}
+		return http.StatusInternalServerError, common.CodeInternalError, nil
+	}
+
+	httpStatus, ok := statusToHTTP[processingErr.Status]
+	if !ok {
+		httpStatus = http.StatusInternalServerError
+	}
+
+	code := processingErr.Code
+	if code == "" {
+		code = common.CodeInternalError
+	}
+
+	return httpStatus, code, processingErr.Args
+}
@@ -0,0 +1,102 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"online-registration/internal/common"
+	"online-registration/internal/interview/helper"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// processingErrorResponse is the response written for err.
+func processingErrorResponse(t *testing.T, err error, acceptLanguage string) (int, ErrorResponse) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	rec := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(rec)
+	c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+	c.Request.Header.Set("Accept-Language", acceptLanguage)
+	respondProcessingError(c, err)
+
+	var body ErrorResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode %s: %v", rec.Body, err)
+	}
+	return rec.Code, body
+}
+
+func TestRespondRoomBookingErrors(t *testing.T) {
+	booked := uuid.MustParse("7f0c3a52-5d1e-4b8a-9a57-3f4d2c1b0e9f")
+
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantCode   string
+		wantError  string
+	}{
+		{
+			"room already booked",
+			common.NewCodedError(helper.AlreadyExists, common.CodeRoomAlreadyBooked, errors.New("overlap"), "Interview", booked),
+			http.StatusConflict, common.CodeRoomAlreadyBooked,
+			`The room is already booked at that time by event "Interview" (7f0c3a52-5d1e-4b8a-9a57-3f4d2c1b0e9f)`,
+		},
+		{
+			"unknown room",
+			common.NewCodedError(helper.InvalidArgument, common.CodeUnknownRoom, errors.New("room not found")),
+			http.StatusBadRequest, common.CodeUnknownRoom, "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, body := processingErrorResponse(t, tt.err, "en")
+			if status != tt.wantStatus || body.Code != tt.wantCode {
+				t.Errorf("response = %d %s, want %d %s", status, body.Code, tt.wantStatus, tt.wantCode)
+			}
+			if tt.wantError != "" && body.Error != tt.wantError {
+				t.Errorf("error = %q, want %q", body.Error, tt.wantError)
+			}
+		})
+	}
+}
+
+func TestRespondErrorBody(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		acceptLanguage string
+		wantLanguage   string
+		wantError      string
+	}{
+		{"", "en", "Nothing found"},
+		{"ru-RU,ru;q=0.9,en;q=0.8", "ru", "Ничего не найдено"},
+		{"fr, *", "en", "Nothing found"},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(rec)
+		c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+		c.Request.Header.Set("Accept-Language", tt.acceptLanguage)
+		respondError(c, http.StatusNotFound, common.CodeNotFound)
+
+		if got := rec.Header().Get("Content-Language"); got != tt.wantLanguage {
+			t.Errorf("%q: Content-Language = %q, want %q", tt.acceptLanguage, got, tt.wantLanguage)
+		}
+		// the body holds code and error only, the former ErrorData is gone
+		var body map[string]string
+		if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+			t.Fatalf("decode %s: %v", rec.Body, err)
+		}
+		want := map[string]string{"code": common.CodeNotFound, "error": tt.wantError}
+		if len(body) != len(want) || body["code"] != want["code"] || body["error"] != want["error"] {
+			t.Errorf("%q: body = %v, want %v", tt.acceptLanguage, body, want)
+		}
+	}
+}
@@ -0,0 +1,30 @@
+package handler
+
+import (
+	"net/http"
+	"online-registration/internal/interview/domain/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ScheduleHandler struct {
+	scheduledTasksUseCase *usecase.ScheduledTasksUseCase
+}
+
+// NewScheduleHandler creates a new HTTP handler for the status of the
+// scheduled tasks
+func NewScheduleHandler(scheduledTasksUseCase *usecase.ScheduledTasksUseCase) *ScheduleHandler {
+	return &ScheduleHandler{
+		scheduledTasksUseCase: scheduledTasksUseCase,
+	}
+}
+
+func (h *ScheduleHandler) ListScheduledTasks(c *gin.Context) {
+	tasks, err := h.scheduledTasksUseCase.List(c.Request.Context())
+	if err != nil {
+		respondProcessingError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, tasks)
+}
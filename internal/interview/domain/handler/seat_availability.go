@@ -0,0 +1,219 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"online-registration/internal/common"
+	"online-registration/internal/i18n"
+	"online-registration/internal/interview/domain/entity"
+	"online-registration/internal/interview/domain/usecase"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+)
+
+// Actions of the messages clients send, and types of the messages they
+// get.
+const (
+	seatActionSubscribe   = "subscribe"
+	seatActionUnsubscribe = "unsubscribe"
+
+	seatMessageAvailability = "availability"
+	seatMessageError        = "error"
+)
+
+// seatReadLimit bounds the messages of clients, a subscription to
+// usecase.MaxWatchedEvents events fits in it.
+const seatReadLimit = 4096
+
+// defaultSeatHeartbeat is the heartbeat of connections configured without a
+// positive one.
+const defaultSeatHeartbeat = 30 * time.Second
+
+type SeatAvailabilityHandler struct {
+	watchSeatAvailabilityUseCase *usecase.WatchSeatAvailabilityUseCase
+	upgrader                     websocket.Upgrader
+	heartbeat                    time.Duration
+}
+
+// NewSeatAvailabilityHandler creates a new WebSocket handler pushing the
+// seats left at events. Clients are pinged every heartbeat and dropped when
+// they don't answer within two, or don't take a message within one.
+func NewSeatAvailabilityHandler(
+	watchSeatAvailabilityUseCase *usecase.WatchSeatAvailabilityUseCase,
+	heartbeat time.Duration,
+) *SeatAvailabilityHandler {
+	if heartbeat <= 0 {
+		heartbeat = defaultSeatHeartbeat
+	}
+	return &SeatAvailabilityHandler{
+		watchSeatAvailabilityUseCase: watchSeatAvailabilityUseCase,
+		upgrader: websocket.Upgrader{
+			ReadBufferSize:  1024,
+			WriteBufferSize: 1024,
+		},
+		heartbeat: heartbeat,
+	}
+}
+
+type seatRequest struct {
+	Action   string   `json:"action"`
+	EventIDs []string `json:"event_ids"`
+}
+
+type seatMessage struct {
+	Type string `json:"type"`
+	*entity.SeatAvailability
+}
+
+type seatErrorMessage struct {
+	Type    string `json:"type"`
+	EventID string `json:"event_id,omitempty"`
+	Code    string `json:"code"`
+	Error   string `json:"error"`
+}
+
+// Watch upgrades the request to a WebSocket. Clients send
+// {"action": "subscribe", "event_ids": [...]} to get the availability of the
+// events and then every change of it, and unsubscribe the same way. A
+// client that falls behind gets the latest availability only. Rejected
+// messages are answered with an error message.
+func (h *SeatAvailabilityHandler) Watch(c *gin.Context) {
+	watcher, err := h.watchSeatAvailabilityUseCase.Connect(c.Request.Context())
+	if err != nil {
+		respondProcessingError(c, err)
+		return
+	}
+	defer watcher.Close()
+
+	conn, err := h.upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		// the upgrader wrote the error response
+		return
+	}
+	defer conn.Close()
+
+	lang := requestLang(c)
+
+	requests := make(chan seatRequest)
+	stop := make(chan struct{})
+	defer close(stop)
+	readDone := make(chan struct{})
+	go func() {
+		defer close(readDone)
+		h.read(conn, requests, stop)
+	}()
+
+	ping := time.NewTicker(h.heartbeat)
+	defer ping.Stop()
+
+	for {
+		var messages []any
+		select {
+		case <-readDone:
+			return
+		case <-watcher.Done():
+			_ = conn.WriteControl(
+				websocket.CloseMessage,
+				websocket.FormatCloseMessage(websocket.CloseGoingAway, ""),
+				time.Now().Add(h.heartbeat),
+			)
+			return
+		case <-ping.C:
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(h.heartbeat)); err != nil {
+				return
+			}
+		case request := <-requests:
+			messages = h.handle(c.Request.Context(), watcher, lang, request)
+		case <-watcher.Ready():
+			for _, seats := range watcher.Take() {
+				messages = append(messages, seatMessage{Type: seatMessageAvailability, SeatAvailability: seats})
+			}
+		}
+
+		for _, message := range messages {
+			_ = conn.SetWriteDeadline(time.Now().Add(h.heartbeat))
+			if err := conn.WriteJSON(message); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// read passes the messages of the client on to requests until the
+// connection fails or stop is closed. Messages that aren't JSON are passed
+// on as requests without an action.
+func (h *SeatAvailabilityHandler) read(conn *websocket.Conn, requests chan<- seatRequest, stop <-chan struct{}) {
+	extend := func() {
+		_ = conn.SetReadDeadline(time.Now().Add(2 * h.heartbeat))
+	}
+	extend()
+	conn.SetReadLimit(seatReadLimit)
+	conn.SetPongHandler(func(string) error {
+		extend()
+		return nil
+	})
+
+	for {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		extend()
+
+		var request seatRequest
+		_ = json.Unmarshal(data, &request)
+
+		select {
+		case requests <- request:
+		case <-stop:
+			return
+		}
+	}
+}
+
+func (h *SeatAvailabilityHandler) handle(
+	ctx context.Context, watcher *usecase.SeatWatcher, lang i18n.Lang, request seatRequest,
+) []any {
+	if (request.Action != seatActionSubscribe && request.Action != seatActionUnsubscribe) ||
+		len(request.EventIDs) == 0 {
+		return []any{seatError(lang, "", common.CodeInvalidRequestBody)}
+	}
+
+	eventIDs := make([]uuid.UUID, 0, len(request.EventIDs))
+	for _, value := range request.EventIDs {
+		id, err := uuid.Parse(value)
+		if err != nil {
+			return []any{seatError(lang, value, common.CodeInvalidEventID)}
+		}
+		eventIDs = append(eventIDs, id)
+	}
+
+	if request.Action == seatActionUnsubscribe {
+		watcher.Unwatch(eventIDs)
+		return nil
+	}
+
+	availability, err := watcher.Watch(ctx, eventIDs)
+	if err != nil {
+		_, code, args := processingErrorCode(err)
+		return []any{seatError(lang, "", code, args...)}
+	}
+
+	messages := make([]any, 0, len(availability))
+	for _, seats := range availability {
+		messages = append(messages, seatMessage{Type: seatMessageAvailability, SeatAvailability: seats})
+	}
+	return messages
+}
+
+func seatError(lang i18n.Lang, eventID, code string, args ...any) seatErrorMessage {
+	return seatErrorMessage{
+		Type:    seatMessageError,
+		EventID: eventID,
+		Code:    code,
+		Error:   i18n.Translate(lang, code, args...),
+	}
+}
@@ -0,0 +1,159 @@
+package handler
+
+import (
+	"net/http"
+	"online-registration/internal/common"
+	"online-registration/internal/interview/domain/dto"
+	"online-registration/internal/interview/domain/usecase"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+)
+
+type TagRequest struct {
+	Name string `json:"name"`
+}
+
+type CategoryRequest struct {
+	Name string `json:"name"`
+}
+
+type TagHandler struct {
+	manageTagsUseCase *usecase.ManageTagsUseCase
+}
+
+// NewTagHandler creates a new HTTP handler for the tags and categories of
+// events
+func NewTagHandler(manageTagsUseCase *usecase.ManageTagsUseCase) *TagHandler {
+	return &TagHandler{
+		manageTagsUseCase: manageTagsUseCase,
+	}
+}
+
+func (h *TagHandler) ListTags(c *gin.Context) {
+	tags, err := h.manageTagsUseCase.ListTags(c.Request.Context())
+	if err != nil {
+		respondProcessingError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, tags)
+}
+
+func (h *TagHandler) CreateTag(c *gin.Context) {
+	var req TagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, common.CodeInvalidRequestBody)
+		return
+	}
+
+	tag, err := h.manageTagsUseCase.CreateTag(c.Request.Context(), &dto.TagRequestDTO{Name: req.Name})
+	if err != nil {
+		log.Error().Msgf("Failed to create tag: %v", err)
+		respondProcessingError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, tag)
+}
+
+func (h *TagHandler) UpdateTag(c *gin.Context) {
+	id, ok := bindUUIDParam(c, "id", common.CodeInvalidTagID)
+	if !ok {
+		return
+	}
+
+	var req TagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, common.CodeInvalidRequestBody)
+		return
+	}
+
+	tag, err := h.manageTagsUseCase.UpdateTag(c.Request.Context(), id, &dto.TagRequestDTO{Name: req.Name})
+	if err != nil {
+		log.Error().Msgf("Failed to update tag: %v", err)
+		respondProcessingError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, tag)
+}
+
+func (h *TagHandler) DeleteTag(c *gin.Context) {
+	id, ok := bindUUIDParam(c, "id", common.CodeInvalidTagID)
+	if !ok {
+		return
+	}
+
+	if err := h.manageTagsUseCase.DeleteTag(c.Request.Context(), id); err != nil {
+		respondProcessingError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *TagHandler) ListCategories(c *gin.Context) {
+	categories, err := h.manageTagsUseCase.ListCategories(c.Request.Context())
+	if err != nil {
+		respondProcessingError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, categories)
+}
+
+func (h *TagHandler) CreateCategory(c *gin.Context) {
+	var req CategoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, common.CodeInvalidRequestBody)
+		return
+	}
+
+	category, err := h.manageTagsUseCase.CreateCategory(c.Request.Context(), &dto.CategoryRequestDTO{Name: req.Name})
+	if err != nil {
+		log.Error().Msgf("Failed to create category: %v", err)
+		respondProcessingError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, category)
+}
+
+func (h *TagHandler) UpdateCategory(c *gin.Context) {
+	id, ok := bindUUIDParam(c, "id", common.CodeInvalidCategoryID)
+	if !ok {
+		return
+	}
+
+	var req CategoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, common.CodeInvalidRequestBody)
+		return
+	}
+
+	category, err := h.manageTagsUseCase.UpdateCategory(
+		c.Request.Context(), id, &dto.CategoryRequestDTO{Name: req.Name},
+	)
+	if err != nil {
+		log.Error().Msgf("Failed to update category: %v", err)
+		respondProcessingError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, category)
+}
+
+func (h *TagHandler) DeleteCategory(c *gin.Context) {
+	id, ok := bindUUIDParam(c, "id", common.CodeInvalidCategoryID)
+	if !ok {
+		return
+	}
+
+	if err := h.manageTagsUseCase.DeleteCategory(c.Request.Context(), id); err != nil {
+		respondProcessingError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
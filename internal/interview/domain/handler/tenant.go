@@ -0,0 +1,54 @@
+package handler
+
+import (
+	"net/http"
+	"online-registration/internal/common"
+	"online-registration/internal/interview/domain/auth"
+	"online-registration/internal/interview/domain/policy"
+	"online-registration/internal/interview/domain/tenant"
+
+	"github.com/gin-gonic/gin"
+)
+
+const TenantHeader = "X-Tenant"
+
+// TenantMiddleware puts the tenant of the request into the request context,
+// where the repositories pick it up. Principals bound to a tenant act on
+// their tenant and may only repeat it in the X-Tenant header. Principals
+// without a tenant, e.g. tokens lacking the tenant claim or API keys created
+// without one, act on defaultTenant. Only system principals and those allowed
+// to switch tenants choose another one with the header. It must run after
+// authentication.
+func TenantMiddleware(defaultTenant string, accessPolicy *policy.Policy) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requested := c.GetHeader(TenantHeader)
+
+		tenantID := defaultTenant
+		switch principal := auth.PrincipalFromContext(c.Request.Context()); {
+		case principal != nil && principal.Tenant != "":
+			if requested != "" && requested != principal.Tenant {
+				respondError(c, http.StatusForbidden, common.CodeTenantForbidden, requested)
+				c.Abort()
+				return
+			}
+			tenantID = principal.Tenant
+		case requested == "" || requested == defaultTenant:
+		case accessPolicy.Authorize(c.Request.Context(), policy.SwitchTenants, "") == nil:
+			tenantID = requested
+		default:
+			respondError(c, http.StatusForbidden, common.CodeTenantForbidden, requested)
+			c.Abort()
+			return
+		}
+
+		if !tenant.Valid(tenantID) {
+			respondError(c, http.StatusBadRequest, common.CodeInvalidTenant, tenantID)
+			c.Abort()
+			return
+		}
+
+		c.Header(TenantHeader, tenantID)
+		c.Request = c.Request.WithContext(tenant.ContextWithTenant(c.Request.Context(), tenantID))
+		c.Next()
+	}
+}
@@ -0,0 +1,71 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"online-registration/internal/interview/domain/auth"
+	"online-registration/internal/interview/domain/policy"
+	"online-registration/internal/interview/domain/tenant"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestTenantMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	accessPolicy, err := policy.Load("")
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		principal  *auth.Principal
+		header     string
+		wantStatus int
+		wantTenant string
+	}{
+		{"bound", &auth.Principal{Subject: "u", Roles: []string{"admin"}, Tenant: "acme"}, "", http.StatusOK, "acme"},
+		{"bound repeating its tenant", &auth.Principal{Subject: "u", Tenant: "acme"}, "acme", http.StatusOK, "acme"},
+		{"bound admin naming another tenant", &auth.Principal{Subject: "u", Roles: []string{"admin"}, Tenant: "acme"}, "globex", http.StatusForbidden, ""},
+		{"tenantless", &auth.Principal{Subject: "u", Roles: []string{"organizer"}}, "", http.StatusOK, "default"},
+		{"tenantless naming the default tenant", &auth.Principal{Subject: "u", Roles: []string{"viewer"}}, "default", http.StatusOK, "default"},
+		{"tenantless naming another tenant", &auth.Principal{Subject: "u", Roles: []string{"organizer"}}, "globex", http.StatusForbidden, ""},
+		{"tenantless key naming another tenant", &auth.Principal{Subject: "key", Scopes: []string{"events:write"}}, "globex", http.StatusForbidden, ""},
+		{"tenantless admin", &auth.Principal{Subject: "u", Roles: []string{"admin"}}, "globex", http.StatusOK, "globex"},
+		{"tenantless key switching tenants", &auth.Principal{Subject: "key", Scopes: []string{"tenants:switch"}}, "globex", http.StatusOK, "globex"},
+		{"system", auth.SystemPrincipal("anonymous"), "globex", http.StatusOK, "globex"},
+		{"invalid tenant", auth.SystemPrincipal("anonymous"), "*", http.StatusBadRequest, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.Use(func(c *gin.Context) {
+				c.Request = c.Request.WithContext(auth.ContextWithPrincipal(c.Request.Context(), tt.principal))
+			})
+			router.Use(TenantMiddleware("default", accessPolicy))
+
+			var gotTenant string
+			router.GET("/", func(c *gin.Context) {
+				gotTenant, _ = tenant.FromContext(c.Request.Context())
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.header != "" {
+				req.Header.Set(TenantHeader, tt.header)
+			}
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if gotTenant != tt.wantTenant {
+				t.Errorf("tenant = %q, want %q", gotTenant, tt.wantTenant)
+			}
+		})
+	}
+}
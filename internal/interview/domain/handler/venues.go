@@ -0,0 +1,190 @@
+package handler
+
+import (
+	"net/http"
+	"online-registration/internal/common"
+	"online-registration/internal/interview/domain/dto"
+	"online-registration/internal/interview/domain/usecase"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+)
+
+type VenueRequest struct {
+	Name    string `json:"name"`
+	Address string `json:"address"`
+}
+
+type RoomRequest struct {
+	Name     string `json:"name"`
+	Capacity int    `json:"capacity"`
+}
+
+type VenueHandler struct {
+	manageVenuesUseCase *usecase.ManageVenuesUseCase
+}
+
+// NewVenueHandler creates a new HTTP handler for venues and their rooms
+func NewVenueHandler(manageVenuesUseCase *usecase.ManageVenuesUseCase) *VenueHandler {
+	return &VenueHandler{
+		manageVenuesUseCase: manageVenuesUseCase,
+	}
+}
+
+func (h *VenueHandler) CreateVenue(c *gin.Context) {
+	requestDTO, ok := bindVenueRequest(c)
+	if !ok {
+		return
+	}
+
+	venue, err := h.manageVenuesUseCase.CreateVenue(c.Request.Context(), requestDTO)
+	if err != nil {
+		log.Error().Msgf("Failed to create venue: %v", err)
+		respondProcessingError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, venue)
+}
+
+func (h *VenueHandler) ListVenues(c *gin.Context) {
+	venues, err := h.manageVenuesUseCase.ListVenues(c.Request.Context())
+	if err != nil {
+		respondProcessingError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, venues)
+}
+
+func (h *VenueHandler) GetVenue(c *gin.Context) {
+	id, ok := bindUUIDParam(c, "id", common.CodeInvalidVenueID)
+	if !ok {
+		return
+	}
+
+	venue, err := h.manageVenuesUseCase.GetVenue(c.Request.Context(), id)
+	if err != nil {
+		respondProcessingError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, venue)
+}
+
+func (h *VenueHandler) UpdateVenue(c *gin.Context) {
+	id, ok := bindUUIDParam(c, "id", common.CodeInvalidVenueID)
+	if !ok {
+		return
+	}
+
+	requestDTO, ok := bindVenueRequest(c)
+	if !ok {
+		return
+	}
+
+	venue, err := h.manageVenuesUseCase.UpdateVenue(c.Request.Context(), id, requestDTO)
+	if err != nil {
+		log.Error().Msgf("Failed to update venue: %v", err)
+		respondProcessingError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, venue)
+}
+
+func (h *VenueHandler) DeleteVenue(c *gin.Context) {
+	id, ok := bindUUIDParam(c, "id", common.CodeInvalidVenueID)
+	if !ok {
+		return
+	}
+
+	if err := h.manageVenuesUseCase.DeleteVenue(c.Request.Context(), id); err != nil {
+		respondProcessingError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *VenueHandler) CreateRoom(c *gin.Context) {
+	venueID, ok := bindUUIDParam(c, "id", common.CodeInvalidVenueID)
+	if !ok {
+		return
+	}
+
+	requestDTO, ok := bindRoomRequest(c)
+	if !ok {
+		return
+	}
+
+	room, err := h.manageVenuesUseCase.CreateRoom(c.Request.Context(), venueID, requestDTO)
+	if err != nil {
+		log.Error().Msgf("Failed to create room: %v", err)
+		respondProcessingError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, room)
+}
+
+func (h *VenueHandler) UpdateRoom(c *gin.Context) {
+	id, ok := bindUUIDParam(c, "id", common.CodeInvalidRoomID)
+	if !ok {
+		return
+	}
+
+	requestDTO, ok := bindRoomRequest(c)
+	if !ok {
+		return
+	}
+
+	room, err := h.manageVenuesUseCase.UpdateRoom(c.Request.Context(), id, requestDTO)
+	if err != nil {
+		log.Error().Msgf("Failed to update room: %v", err)
+		respondProcessingError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, room)
+}
+
+func (h *VenueHandler) DeleteRoom(c *gin.Context) {
+	id, ok := bindUUIDParam(c, "id", common.CodeInvalidRoomID)
+	if !ok {
+		return
+	}
+
+	if err := h.manageVenuesUseCase.DeleteRoom(c.Request.Context(), id); err != nil {
+		respondProcessingError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func bindVenueRequest(c *gin.Context) (*dto.VenueRequestDTO, bool) {
+	var req VenueRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, common.CodeInvalidRequestBody)
+		return nil, false
+	}
+
+	return &dto.VenueRequestDTO{
+		Name:    req.Name,
+		Address: req.Address,
+	}, true
+}
+
+func bindRoomRequest(c *gin.Context) (*dto.RoomRequestDTO, bool) {
+	var req RoomRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, common.CodeInvalidRequestBody)
+		return nil, false
+	}
+
+	return &dto.RoomRequestDTO{
+		Name:     req.Name,
+		Capacity: req.Capacity,
+	}, true
+}
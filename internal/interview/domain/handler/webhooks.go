@@ -0,0 +1,182 @@
+package handler
+
+import (
+	"net/http"
+	"online-registration/internal/common"
+	"online-registration/internal/interview/domain/dto"
+	"online-registration/internal/interview/domain/entity"
+	"online-registration/internal/interview/domain/usecase"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+type WebhookSubscriptionRequest struct {
+	URL        string   `json:"url"`
+	Secret     string   `json:"secret"`
+	EventTypes []string `json:"event_types"`
+	Active     *bool    `json:"active"`
+}
+
+type WebhookHandler struct {
+	manageWebhooksUseCase *usecase.ManageWebhooksUseCase
+}
+
+// NewWebhookHandler creates a new HTTP handler for webhook subscriptions
+func NewWebhookHandler(manageWebhooksUseCase *usecase.ManageWebhooksUseCase) *WebhookHandler {
+	return &WebhookHandler{
+		manageWebhooksUseCase: manageWebhooksUseCase,
+	}
+}
+
+func (h *WebhookHandler) CreateSubscription(c *gin.Context) {
+	requestDTO, ok := bindWebhookSubscriptionRequest(c)
+	if !ok {
+		return
+	}
+
+	subscription, err := h.manageWebhooksUseCase.CreateSubscription(c.Request.Context(), requestDTO)
+	if err != nil {
+		log.Error().Msgf("Failed to create webhook subscription: %v", err)
+		respondProcessingError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, subscription)
+}
+
+func (h *WebhookHandler) ListSubscriptions(c *gin.Context) {
+	subscriptions, err := h.manageWebhooksUseCase.ListSubscriptions(c.Request.Context())
+	if err != nil {
+		respondProcessingError(c, err)
+		return
+	}
+
+	for _, subscription := range subscriptions {
+		hideSecret(subscription)
+	}
+	c.JSON(http.StatusOK, subscriptions)
+}
+
+func (h *WebhookHandler) GetSubscription(c *gin.Context) {
+	id, ok := bindUUIDParam(c, "id", common.CodeInvalidWebhookID)
+	if !ok {
+		return
+	}
+
+	subscription, err := h.manageWebhooksUseCase.GetSubscription(c.Request.Context(), id)
+	if err != nil {
+		respondProcessingError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, hideSecret(subscription))
+}
+
+func (h *WebhookHandler) UpdateSubscription(c *gin.Context) {
+	id, ok := bindUUIDParam(c, "id", common.CodeInvalidWebhookID)
+	if !ok {
+		return
+	}
+
+	requestDTO, ok := bindWebhookSubscriptionRequest(c)
+	if !ok {
+		return
+	}
+
+	subscription, err := h.manageWebhooksUseCase.UpdateSubscription(c.Request.Context(), id, requestDTO)
+	if err != nil {
+		log.Error().Msgf("Failed to update webhook subscription: %v", err)
+		respondProcessingError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, hideSecret(subscription))
+}
+
+func (h *WebhookHandler) DeleteSubscription(c *gin.Context) {
+	id, ok := bindUUIDParam(c, "id", common.CodeInvalidWebhookID)
+	if !ok {
+		return
+	}
+
+	if err := h.manageWebhooksUseCase.DeleteSubscription(c.Request.Context(), id); err != nil {
+		respondProcessingError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *WebhookHandler) ListDeliveries(c *gin.Context) {
+	id, ok := bindUUIDParam(c, "id", common.CodeInvalidWebhookID)
+	if !ok {
+		return
+	}
+
+	limit, _ := strconv.Atoi(c.Query("limit"))
+
+	deliveries, err := h.manageWebhooksUseCase.ListDeliveries(c.Request.Context(), id, limit)
+	if err != nil {
+		respondProcessingError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, deliveries)
+}
+
+func (h *WebhookHandler) Redeliver(c *gin.Context) {
+	id, ok := bindUUIDParam(c, "id", common.CodeInvalidWebhookID)
+	if !ok {
+		return
+	}
+
+	deliveryID, ok := bindUUIDParam(c, "delivery_id", common.CodeInvalidWebhookDeliveryID)
+	if !ok {
+		return
+	}
+
+	delivery, err := h.manageWebhooksUseCase.Redeliver(c.Request.Context(), id, deliveryID)
+	if err != nil {
+		respondProcessingError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, delivery)
+}
+
+func bindWebhookSubscriptionRequest(c *gin.Context) (*dto.WebhookSubscriptionRequestDTO, bool) {
+	var req WebhookSubscriptionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, common.CodeInvalidRequestBody)
+		return nil, false
+	}
+
+	active := req.Active == nil || *req.Active
+
+	return &dto.WebhookSubscriptionRequestDTO{
+		URL:        req.URL,
+		Secret:     req.Secret,
+		EventTypes: req.EventTypes,
+		Active:     active,
+	}, true
+}
+
+// bindUUIDParam parses a UUID path parameter, writing the error response
+// with code itself when it is not valid.
+func bindUUIDParam(c *gin.Context, name string, code string) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param(name))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, code)
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+// hideSecret clears the signing secret, which is only shown on creation.
+func hideSecret(subscription *entity.WebhookSubscription) *entity.WebhookSubscription {
+	subscription.Secret = ""
+	return subscription
+}
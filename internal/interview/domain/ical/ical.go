@@ -0,0 +1,211 @@
+// Package ical writes events as iCalendar (RFC 5545) documents.
+package ical
+
+import (
+	"fmt"
+	"online-registration/internal/interview/domain/entity"
+	"strings"
+	"time"
+)
+
+const (
+	MethodPublish = "PUBLISH"
+	MethodRequest = "REQUEST"
+	MethodCancel  = "CANCEL"
+
+	prodID = "-//online-registration//events//EN"
+
+	utcLayout   = "20060102T150405Z"
+	localLayout = "20060102T150405"
+	dateLayout  = "20060102"
+
+	// lines are folded after this many octets
+	maxLineLength = 75
+)
+
+// Options control how an event is written.
+type Options struct {
+	// Method is the iTIP method of the calendar, none when empty.
+	Method string
+	// Location is the zone the times are written in, the zone of the event
+	// when nil.
+	Location *time.Location
+	// Stamp is when the calendar was created.
+	Stamp time.Time
+	// Organizer and Attendees are the email addresses of the organizer and
+	// of those the invitation is sent to.
+	Organizer string
+	Attendees []string
+}
+
+// Encode returns the calendar holding event.
+func Encode(event *entity.Event, options Options) []byte {
+	loc := options.Location
+	if loc == nil {
+		loc = event.Location()
+	}
+
+	w := &writer{}
+	w.line("BEGIN:VCALENDAR")
+	w.line("VERSION:2.0")
+	w.line("PRODID:" + prodID)
+	w.line("CALSCALE:GREGORIAN")
+	if options.Method != "" {
+		w.line("METHOD:" + options.Method)
+	}
+
+	zoned := !event.AllDay && loc != time.UTC
+	if zoned {
+		writeTimeZone(w, loc, event.StartTime, event.EndTime)
+	}
+
+	w.line("BEGIN:VEVENT")
+	w.line("UID:" + event.ID.String() + "@online-registration")
+	w.line("DTSTAMP:" + options.Stamp.UTC().Format(utcLayout))
+	switch {
+	case event.AllDay:
+		start, end := event.StartTime.In(event.Location()), event.EndTime.In(event.Location())
+		w.line("DTSTART;VALUE=DATE:" + start.Format(dateLayout))
+		w.line("DTEND;VALUE=DATE:" + end.Format(dateLayout))
+	case zoned:
+		w.line("DTSTART;TZID=" + loc.String() + ":" + event.StartTime.In(loc).Format(localLayout))
+		w.line("DTEND;TZID=" + loc.String() + ":" + event.EndTime.In(loc).Format(localLayout))
+	default:
+		w.line("DTSTART:" + event.StartTime.UTC().Format(utcLayout))
+		w.line("DTEND:" + event.EndTime.UTC().Format(utcLayout))
+	}
+	w.line("SUMMARY:" + escape(event.Title))
+	if event.Description != "" {
+		w.line("DESCRIPTION:" + escape(event.Description))
+	}
+	w.line("STATUS:" + status(event, options.Method))
+	w.line("SEQUENCE:" + fmt.Sprint(sequence(event)))
+	if options.Organizer != "" {
+		w.line("ORGANIZER:mailto:" + options.Organizer)
+	}
+	for _, attendee := range options.Attendees {
+		w.line("ATTENDEE;ROLE=REQ-PARTICIPANT;RSVP=FALSE:mailto:" + attendee)
+	}
+	w.line("END:VEVENT")
+	w.line("END:VCALENDAR")
+
+	return []byte(w.String())
+}
+
+func status(event *entity.Event, method string) string {
+	switch {
+	case method == MethodCancel || event.Status == entity.EventCancelled:
+		return "CANCELLED"
+	case event.Status == entity.EventDraft:
+		return "TENTATIVE"
+	default:
+		return "CONFIRMED"
+	}
+}
+
+// sequence grows with every change of the event, so that calendars replace
+// older copies of it.
+func sequence(event *entity.Event) int64 {
+	return max(0, event.UpdatedAt.Unix()-event.CreatedAt.Unix())
+}
+
+// writeTimeZone describes loc between start and end, the only span the
+// event refers to. A zone changing its offset in that span gets an
+// observance for every offset.
+func writeTimeZone(w *writer, loc *time.Location, start, end time.Time) {
+	w.line("BEGIN:VTIMEZONE")
+	w.line("TZID:" + loc.String())
+
+	at := start.In(loc)
+	_, offset := at.Zone()
+	observance(w, at, time.Date(1970, 1, 1, 0, 0, 0, 0, time.UTC), offset, offset)
+
+	// observances start at the local time of the change as it was before
+	for _, change := range offsetChanges(loc, start, end) {
+		observance(w, change, change.In(time.FixedZone("", offset)), offset, offsetOf(change))
+		offset = offsetOf(change)
+	}
+
+	w.line("END:VTIMEZONE")
+}
+
+func observance(w *writer, at time.Time, since time.Time, from, to int) {
+	kind := "STANDARD"
+	if at.IsDST() {
+		kind = "DAYLIGHT"
+	}
+	name, _ := at.Zone()
+
+	w.line("BEGIN:" + kind)
+	w.line("DTSTART:" + since.Format(localLayout))
+	w.line("TZOFFSETFROM:" + formatOffset(from))
+	w.line("TZOFFSETTO:" + formatOffset(to))
+	w.line("TZNAME:" + name)
+	w.line("END:" + kind)
+}
+
+// offsetChanges returns the instants between start and end at which loc
+// changes its UTC offset. Zones change at most a few times a year, so they
+// are looked for a day at a time and pinned down to the second.
+func offsetChanges(loc *time.Location, start, end time.Time) []time.Time {
+	var changes []time.Time
+	for from := start.In(loc); from.Before(end); {
+		to := from.Add(24 * time.Hour)
+		if to.After(end) {
+			to = end.In(loc)
+		}
+		if offsetOf(from) != offsetOf(to) {
+			low, high := from, to
+			for high.Sub(low) > time.Second {
+				middle := low.Add(high.Sub(low) / 2)
+				if offsetOf(middle) == offsetOf(low) {
+					low = middle
+				} else {
+					high = middle
+				}
+			}
+			changes = append(changes, high.Truncate(time.Second))
+		}
+		from = to
+	}
+	return changes
+}
+
+func offsetOf(t time.Time) int {
+	_, offset := t.Zone()
+	return offset
+}
+
+func formatOffset(offset int) string {
+	sign := '+'
+	if offset < 0 {
+		sign, offset = '-', -offset
+	}
+	return fmt.Sprintf("%c%02d%02d", sign, offset/3600, offset%3600/60)
+}
+
+var escaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+
+func escape(text string) string {
+	return escaper.Replace(text)
+}
+
+type writer struct {
+	strings.Builder
+}
+
+// line writes a content line, folding it so that no line is longer than
+// maxLineLength octets without splitting UTF-8 sequences.
+func (w *writer) line(content string) {
+	length := 0
+	for _, r := range content {
+		size := len(string(r))
+		if length+size > maxLineLength {
+			w.WriteString("\r\n ")
+			length = 1
+		}
+		w.WriteRune(r)
+		length += size
+	}
+	w.WriteString("\r\n")
+}
@@ -0,0 +1,133 @@
+// Package jobs defines typed kinds of background jobs, run by the job
+// workers from the jobs table.
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"online-registration/internal/interview/domain/entity"
+	"online-registration/internal/interview/domain/repository"
+	"time"
+)
+
+// DefaultMaxAttempts is the number of attempts of a job enqueued without
+// MaxAttempts.
+const DefaultMaxAttempts = 10
+
+// Handler runs the jobs of one kind. A job whose handler returns an error is
+// retried with backoff until it runs out of attempts.
+type Handler interface {
+	Kind() string
+	Handle(ctx context.Context, job *entity.Job) error
+}
+
+// Kind is a kind of job whose payload is a T.
+type Kind[T any] struct {
+	name string
+}
+
+func NewKind[T any](name string) Kind[T] {
+	return Kind[T]{name: name}
+}
+
+func (k Kind[T]) Name() string {
+	return k.name
+}
+
+// Options control when and how often a job runs.
+type Options struct {
+	// RunAt is when the job is due, right away when zero.
+	RunAt time.Time
+	// UniqueKey keeps the job from being enqueued while another job with the
+	// same key is pending or running.
+	UniqueKey string
+	// MaxAttempts is DefaultMaxAttempts when zero.
+	MaxAttempts int
+	// TenantID is the tenant the job runs for, every tenant when empty.
+	TenantID string
+}
+
+// Job builds a job of the kind carrying payload.
+func (k Kind[T]) Job(payload T, options Options) (*entity.Job, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("encode %s job: %w", k.name, err)
+	}
+
+	job := &entity.Job{
+		Kind:        k.name,
+		TenantID:    options.TenantID,
+		Payload:     data,
+		UniqueKey:   options.UniqueKey,
+		MaxAttempts: options.MaxAttempts,
+		RunAt:       options.RunAt,
+	}
+	if job.MaxAttempts <= 0 {
+		job.MaxAttempts = DefaultMaxAttempts
+	}
+	if job.RunAt.IsZero() {
+		job.RunAt = time.Now()
+	}
+	return job, nil
+}
+
+// Enqueue stores a job of the kind carrying payload. When a job with the
+// unique key of options is already pending or running, that job is returned
+// with enqueued set to false.
+func (k Kind[T]) Enqueue(
+	ctx context.Context, jobs repository.IJobRepository, payload T, options Options,
+) (job *entity.Job, enqueued bool, err error) {
+	job, err = k.Job(payload, options)
+	if err != nil {
+		return nil, false, err
+	}
+	return jobs.EnqueueJob(ctx, job)
+}
+
+// Handler runs fn with the payload of the jobs of the kind.
+func (k Kind[T]) Handler(fn func(ctx context.Context, payload T) error) Handler {
+	return &handler[T]{kind: k.name, fn: fn}
+}
+
+type handler[T any] struct {
+	kind string
+	fn   func(ctx context.Context, payload T) error
+}
+
+func (h *handler[T]) Kind() string {
+	return h.kind
+}
+
+func (h *handler[T]) Handle(ctx context.Context, job *entity.Job) error {
+	var payload T
+	if err := json.Unmarshal(job.Payload, &payload); err != nil {
+		return Permanent(fmt.Errorf("decode %s job: %w", h.kind, err))
+	}
+	return h.fn(ctx, payload)
+}
+
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string {
+	return e.err.Error()
+}
+
+func (e *permanentError) Unwrap() error {
+	return e.err
+}
+
+// Permanent marks err as one that retrying won't fix, failing the job right
+// away.
+func Permanent(err error) error {
+	return &permanentError{err: err}
+}
+
+// IsPermanent reports whether err was marked with Permanent.
+func IsPermanent(err error) bool {
+	var permanent *permanentError
+	return errors.As(err, &permanent)
+}
@@ -0,0 +1,146 @@
+// Package notification writes the emails sent to registrants.
+package notification
+
+import (
+	"bytes"
+	"fmt"
+	"online-registration/internal/i18n"
+	"online-registration/internal/interview/domain/entity"
+	"online-registration/internal/interview/domain/ical"
+	"text/template"
+	"time"
+)
+
+const (
+	// inviteFilename is the name of the calendar attached to every email.
+	inviteFilename = "invite.ics"
+	inviteType     = "text/calendar; charset=utf-8; method=%s"
+)
+
+// Data is what the templates are executed with.
+type Data struct {
+	Event *entity.Event
+	// When is the time of the event in its zone, written for the language
+	// of the email.
+	When string
+	// EventCancelled is set when a cancellation is sent because the event
+	// was cancelled, rather than the registration.
+	EventCancelled bool
+}
+
+type layouts struct {
+	time  string
+	date  string
+	clock string
+}
+
+type templates struct {
+	layouts  layouts
+	subjects map[string]*template.Template
+	bodies   map[string]*template.Template
+}
+
+var catalogs = map[i18n.Lang]*templates{
+	i18n.English: parse(layoutsEN, subjectsEN, bodiesEN),
+	i18n.Russian: parse(layoutsRU, subjectsRU, bodiesRU),
+}
+
+func parse(layouts layouts, subjects, bodies map[string]string) *templates {
+	t := &templates{
+		layouts:  layouts,
+		subjects: make(map[string]*template.Template, len(subjects)),
+		bodies:   make(map[string]*template.Template, len(bodies)),
+	}
+	for kind, text := range subjects {
+		t.subjects[kind] = template.Must(template.New(kind).Parse(text))
+	}
+	for kind, text := range bodies {
+		t.bodies[kind] = template.Must(template.New(kind).Parse(text))
+	}
+	return t
+}
+
+// Supported reports whether emails can be written in lang.
+func Supported(lang i18n.Lang) bool {
+	_, ok := catalogs[lang]
+	return ok
+}
+
+// Compose writes the email of kind about event to the registrant to, in
+// lang. The event is attached as a calendar invitation from organizer, or as
+// its cancellation for cancellations.
+func Compose(
+	lang i18n.Lang, kind string, event *entity.Event, organizer, to string, stamp time.Time,
+) (*entity.Email, error) {
+	catalog, ok := catalogs[lang]
+	if !ok {
+		catalog = catalogs[i18n.DefaultLang]
+	}
+	subject, body := catalog.subjects[kind], catalog.bodies[kind]
+	if subject == nil || body == nil {
+		return nil, fmt.Errorf("Compose: unknown notification kind %q", kind)
+	}
+
+	data := Data{
+		Event:          event,
+		When:           catalog.layouts.when(event),
+		EventCancelled: kind == entity.NotificationCancellation && event.Status == entity.EventCancelled,
+	}
+
+	var subjectText, bodyText bytes.Buffer
+	if err := subject.Execute(&subjectText, data); err != nil {
+		return nil, fmt.Errorf("Compose %w", err)
+	}
+	if err := body.Execute(&bodyText, data); err != nil {
+		return nil, fmt.Errorf("Compose %w", err)
+	}
+
+	method := inviteMethod(kind)
+	invite := ical.Encode(event, ical.Options{
+		Method:    method,
+		Stamp:     stamp,
+		Organizer: organizer,
+		Attendees: []string{to},
+	})
+
+	return &entity.Email{
+		To:      to,
+		Subject: subjectText.String(),
+		Text:    bodyText.String(),
+		Attachments: []*entity.Attachment{{
+			Filename:    inviteFilename,
+			ContentType: fmt.Sprintf(inviteType, method),
+			Content:     invite,
+		}},
+	}, nil
+}
+
+// inviteMethod is the iTIP method of the invitation attached to emails of
+// kind: cancellations remove the event from the calendar of the registrant,
+// the others add or update it.
+func inviteMethod(kind string) string {
+	if kind == entity.NotificationCancellation {
+		return ical.MethodCancel
+	}
+	return ical.MethodRequest
+}
+
+func (l layouts) when(event *entity.Event) string {
+	if event.AllDay {
+		first, last := event.Dates()
+		start, _ := time.Parse(entity.DateLayout, first)
+		end, _ := time.Parse(entity.DateLayout, last)
+		if first == last {
+			return start.Format(l.date)
+		}
+		return start.Format(l.date) + " – " + end.Format(l.date)
+	}
+
+	loc := event.Location()
+	start, end := event.StartTime.In(loc), event.EndTime.In(loc)
+	endLayout := l.time
+	if start.Format(l.date) == end.Format(l.date) {
+		endLayout = l.clock
+	}
+	return fmt.Sprintf("%s – %s (%s)", start.Format(l.time), end.Format(endLayout), loc)
+}
@@ -0,0 +1,62 @@
+package notification
+
+import "online-registration/internal/interview/domain/entity"
+
+var layoutsEN = layouts{
+	time:  "Mon, 2 Jan 2006 15:04",
+	date:  "Mon, 2 Jan 2006",
+	clock: "15:04",
+}
+
+var subjectsEN = map[string]string{
+	entity.NotificationConfirmation:      `You are registered for {{.Event.Title}}`,
+	entity.NotificationReminder:          `Reminder: {{.Event.Title}} is coming up`,
+	entity.NotificationCancellation:      `{{if .EventCancelled}}Cancelled: {{.Event.Title}}{{else}}Your registration for {{.Event.Title}} is cancelled{{end}}`,
+	entity.NotificationWaitlistPromotion: `A place opened up for {{.Event.Title}}`,
+}
+
+var bodiesEN = map[string]string{
+	entity.NotificationConfirmation: `Hello,
+
+you are registered for "{{.Event.Title}}".
+
+When: {{.When}}
+{{with .Event.Description}}
+{{.}}
+{{end}}
+The invitation is attached, open it to add the event to your calendar.
+`,
+	entity.NotificationReminder: `Hello,
+
+this is a reminder that "{{.Event.Title}}" is coming up.
+
+When: {{.When}}
+{{with .Event.Description}}
+{{.}}
+{{end}}
+If you can no longer attend, please cancel your registration.
+`,
+	entity.NotificationCancellation: `Hello,
+{{if .EventCancelled}}
+unfortunately "{{.Event.Title}}" has been cancelled.
+{{- else}}
+your registration for "{{.Event.Title}}" has been cancelled.
+{{- end}}
+
+When: {{.When}}
+{{- if .EventCancelled}}{{with .Event.CancellationReason}}
+Reason: {{.}}
+{{- end}}{{end}}
+
+The attached update removes the event from your calendar.
+`,
+	entity.NotificationWaitlistPromotion: `Hello,
+
+a place opened up and you have been moved from the waitlist: you are now
+registered for "{{.Event.Title}}".
+
+When: {{.When}}
+
+The invitation is attached, open it to add the event to your calendar.
+`,
+}
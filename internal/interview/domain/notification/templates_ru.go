@@ -0,0 +1,62 @@
+package notification
+
+import "online-registration/internal/interview/domain/entity"
+
+var layoutsRU = layouts{
+	time:  "02.01.2006 15:04",
+	date:  "02.01.2006",
+	clock: "15:04",
+}
+
+var subjectsRU = map[string]string{
+	entity.NotificationConfirmation:      `Вы зарегистрированы на {{.Event.Title}}`,
+	entity.NotificationReminder:          `Напоминание: скоро {{.Event.Title}}`,
+	entity.NotificationCancellation:      `{{if .EventCancelled}}Отменено: {{.Event.Title}}{{else}}Ваша регистрация на {{.Event.Title}} отменена{{end}}`,
+	entity.NotificationWaitlistPromotion: `Для вас освободилось место на {{.Event.Title}}`,
+}
+
+var bodiesRU = map[string]string{
+	entity.NotificationConfirmation: `Здравствуйте!
+
+Вы зарегистрированы на «{{.Event.Title}}».
+
+Когда: {{.When}}
+{{with .Event.Description}}
+{{.}}
+{{end}}
+Приглашение во вложении, откройте его, чтобы добавить событие в календарь.
+`,
+	entity.NotificationReminder: `Здравствуйте!
+
+Напоминаем, что скоро состоится «{{.Event.Title}}».
+
+Когда: {{.When}}
+{{with .Event.Description}}
+{{.}}
+{{end}}
+Если вы не сможете прийти, пожалуйста, отмените регистрацию.
+`,
+	entity.NotificationCancellation: `Здравствуйте!
+{{if .EventCancelled}}
+К сожалению, «{{.Event.Title}}» отменено.
+{{- else}}
+Ваша регистрация на «{{.Event.Title}}» отменена.
+{{- end}}
+
+Когда: {{.When}}
+{{- if .EventCancelled}}{{with .Event.CancellationReason}}
+Причина: {{.}}
+{{- end}}{{end}}
+
+Вложенное обновление удалит событие из вашего календаря.
+`,
+	entity.NotificationWaitlistPromotion: `Здравствуйте!
+
+Освободилось место, и вы переведены из листа ожидания: теперь вы
+зарегистрированы на «{{.Event.Title}}».
+
+Когда: {{.When}}
+
+Приглашение во вложении, откройте его, чтобы добавить событие в календарь.
+`,
+}
@@ -0,0 +1,265 @@
+package policy
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"online-registration/internal/common"
+	"online-registration/internal/interview/domain/auth"
+	"online-registration/internal/interview/helper"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+type Action string
+
+const (
+	ReadEvents     Action = "events:read"
+	CreateEvents   Action = "events:create"
+	UpdateEvents   Action = "events:update"
+	DeleteEvents   Action = "events:delete"
+	RegisterEvents Action = "events:register"
+	PublishEvents  Action = "events:publish"
+	CancelEvents   Action = "events:cancel"
+	ReadHistory    Action = "events:history"
+	ManageWebhooks Action = "webhooks:manage"
+	ManageVenues   Action = "venues:manage"
+	ManageTags     Action = "tags:manage"
+	ReadSchedule   Action = "schedule:read"
+	// SwitchTenants lets principals that aren't bound to a tenant choose the
+	// tenant they act on.
+	SwitchTenants Action = "tenants:switch"
+)
+
+// actions lists the actions a policy may grant.
+var actions = []Action{
+	ReadEvents, CreateEvents, UpdateEvents, DeleteEvents, RegisterEvents, PublishEvents, CancelEvents,
+	ReadHistory, ManageWebhooks, ManageVenues, ManageTags, ReadSchedule, SwitchTenants,
+}
+
+// ownSuffix limits a permission to the resources owned by the principal,
+// e.g. "events:update:own".
+const ownSuffix = ":own"
+
+const (
+	RoleViewer    = "viewer"
+	RoleOrganizer = "organizer"
+	RoleAdmin     = "admin"
+)
+
+// DefaultPolicy is used when no policy file is configured. Roles come from
+// the tokens of users, scopes from API keys.
+const DefaultPolicy = `
+roles:
+  viewer:
+    - events:read
+    - events:register
+  organizer:
+    - events:read
+    - events:register
+    - events:create
+    - events:update:own
+    - events:delete:own
+    - events:publish:own
+    - events:cancel:own
+    - events:history:own
+  admin:
+    - events:read
+    - events:register
+    - events:create
+    - events:update
+    - events:delete
+    - events:publish
+    - events:cancel
+    - events:history
+    - webhooks:manage
+    - venues:manage
+    - tags:manage
+    - schedule:read
+    - tenants:switch
+scopes:
+  events:read:
+    - events:read
+  events:write:
+    - events:read
+    - events:create
+    - events:update
+    - events:delete
+    - events:publish
+    - events:cancel
+  webhooks:manage:
+    - webhooks:manage
+  audit:read:
+    - events:history
+  venues:manage:
+    - events:read
+    - venues:manage
+  tags:manage:
+    - events:read
+    - tags:manage
+  schedule:read:
+    - schedule:read
+  tenants:switch:
+    - tenants:switch
+`
+
+type grant struct {
+	ownOnly bool
+}
+
+// Policy maps roles and API key scopes to the actions they are allowed to
+// perform.
+type Policy struct {
+	roles  map[string]map[Action]grant
+	scopes map[string]map[Action]grant
+}
+
+type policyFile struct {
+	Roles  map[string][]string `yaml:"roles"`
+	Scopes map[string][]string `yaml:"scopes"`
+}
+
+// Load reads the policy from a YAML file, or returns the default policy when
+// path is empty.
+func Load(path string) (*Policy, error) {
+	if path == "" {
+		return Parse([]byte(DefaultPolicy))
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read policy file: %w", err)
+	}
+	return Parse(data)
+}
+
+// Parse reads a policy from YAML. Unknown keys and actions are rejected, so
+// that a typo doesn't silently take a permission away.
+func Parse(data []byte) (*Policy, error) {
+	var file policyFile
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&file); err != nil {
+		if errors.Is(err, io.EOF) {
+			err = errors.New("policy is empty")
+		}
+		return nil, fmt.Errorf("parse policy: %w", err)
+	}
+	if len(file.Roles) == 0 && len(file.Scopes) == 0 {
+		return nil, errors.New("parse policy: no roles or scopes are defined")
+	}
+
+	roles, err := parseGrants("role", file.Roles)
+	if err != nil {
+		return nil, fmt.Errorf("parse policy: %w", err)
+	}
+	scopes, err := parseGrants("scope", file.Scopes)
+	if err != nil {
+		return nil, fmt.Errorf("parse policy: %w", err)
+	}
+
+	return &Policy{
+		roles:  roles,
+		scopes: scopes,
+	}, nil
+}
+
+func parseGrants(kind string, names map[string][]string) (map[string]map[Action]grant, error) {
+	parsed := make(map[string]map[Action]grant, len(names))
+	for name, permissions := range names {
+		grants := make(map[Action]grant, len(permissions))
+		for _, permission := range permissions {
+			action, ownOnly := strings.CutSuffix(permission, ownSuffix)
+			if !slices.Contains(actions, Action(action)) {
+				return nil, fmt.Errorf("%s %s: unknown action %q", kind, name, permission)
+			}
+			if existing, ok := grants[Action(action)]; ok && !existing.ownOnly {
+				continue
+			}
+			grants[Action(action)] = grant{ownOnly: ownOnly}
+		}
+		parsed[name] = grants
+	}
+	return parsed, nil
+}
+
+// Scopes lists the API key scopes defined by the policy.
+func (p *Policy) Scopes() []string {
+	scopes := make([]string, 0, len(p.scopes))
+	for scope := range p.scopes {
+		scopes = append(scopes, scope)
+	}
+	sort.Strings(scopes)
+	return scopes
+}
+
+// HasScope reports whether the API key scope is defined by the policy.
+func (p *Policy) HasScope(scope string) bool {
+	_, ok := p.scopes[scope]
+	return ok
+}
+
+// Authorize checks that the principal of ctx may perform action. owner is the
+// subject owning the resource the action is performed on, or empty when the
+// action doesn't target an existing resource.
+func (p *Policy) Authorize(ctx context.Context, action Action, owner string) error {
+	principal := auth.PrincipalFromContext(ctx)
+	if principal == nil {
+		return common.NewCodedError(
+			helper.Unauthenticated, common.CodeUnauthenticated, errors.New("request is not authenticated"),
+		)
+	}
+
+	if principal.System {
+		return nil
+	}
+
+	if allowed(p.roles, principal.Roles, action, owner, principal.Subject) ||
+		allowed(p.scopes, principal.Scopes, action, owner, principal.Subject) {
+		return nil
+	}
+
+	return common.NewCodedError(
+		helper.PermissionDenied, common.CodeForbidden,
+		fmt.Errorf("%s is not allowed to %s", principal.Subject, action),
+	)
+}
+
+// Reach reports whether the principal of ctx may perform action on resources
+// of any owner, and whether it may on its own ones. It is used to filter
+// listings rather than to reject requests.
+func (p *Policy) Reach(ctx context.Context, action Action) (anyOwner bool, own bool) {
+	principal := auth.PrincipalFromContext(ctx)
+	if principal == nil {
+		return false, false
+	}
+	if principal.System {
+		return true, true
+	}
+
+	anyOwner = allowed(p.roles, principal.Roles, action, "", principal.Subject) ||
+		allowed(p.scopes, principal.Scopes, action, "", principal.Subject)
+	own = anyOwner ||
+		allowed(p.roles, principal.Roles, action, principal.Subject, principal.Subject) ||
+		allowed(p.scopes, principal.Scopes, action, principal.Subject, principal.Subject)
+	return anyOwner, own
+}
+
+func allowed(grants map[string]map[Action]grant, names []string, action Action, owner, subject string) bool {
+	for _, name := range names {
+		grant, ok := grants[name][action]
+		if !ok {
+			continue
+		}
+		if !grant.ownOnly || (owner != "" && owner == subject) {
+			return true
+		}
+	}
+	return false
+}
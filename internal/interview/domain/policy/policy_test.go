@@ -0,0 +1,186 @@
+package policy
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"online-registration/internal/common"
+	"online-registration/internal/interview/domain/auth"
+)
+
+func principalContext(principal *auth.Principal) context.Context {
+	return auth.ContextWithPrincipal(context.Background(), principal)
+}
+
+func loadDefault(t *testing.T) *Policy {
+	t.Helper()
+
+	policy, err := Load("")
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	return policy
+}
+
+func TestAuthorize(t *testing.T) {
+	policy := loadDefault(t)
+
+	alice := &auth.Principal{Subject: "alice", Roles: []string{RoleOrganizer}}
+	admin := &auth.Principal{Subject: "root", Roles: []string{RoleAdmin}}
+	viewer := &auth.Principal{Subject: "vic", Roles: []string{RoleViewer}}
+	key := &auth.Principal{Subject: "key", Scopes: []string{"events:write"}}
+
+	tests := []struct {
+		name      string
+		principal *auth.Principal
+		action    Action
+		owner     string
+		wantCode  string
+	}{
+		{"organizer updates own event", alice, UpdateEvents, "alice", ""},
+		{"organizer updates event of another", alice, UpdateEvents, "bob", common.CodeForbidden},
+		{"organizer deletes own event", alice, DeleteEvents, "alice", ""},
+		{"organizer deletes event of another", alice, DeleteEvents, "bob", common.CodeForbidden},
+		{"organizer deletes event without owner", alice, DeleteEvents, "", common.CodeForbidden},
+		{"organizer creates event", alice, CreateEvents, "", ""},
+		{"organizer manages webhooks", alice, ManageWebhooks, "", common.CodeForbidden},
+		{"admin updates event of another", admin, UpdateEvents, "bob", ""},
+		{"admin deletes event of another", admin, DeleteEvents, "bob", ""},
+		{"viewer reads events", viewer, ReadEvents, "bob", ""},
+		{"viewer updates own event", viewer, UpdateEvents, "vic", common.CodeForbidden},
+		{"api key updates event of another", key, UpdateEvents, "bob", ""},
+		{"api key reads history", key, ReadHistory, "bob", common.CodeForbidden},
+		{"unknown role", &auth.Principal{Subject: "eve", Roles: []string{"root"}}, ReadEvents, "", common.CodeForbidden},
+		{"roles merge", &auth.Principal{Subject: "vic", Roles: []string{RoleViewer, RoleOrganizer}}, CreateEvents, "", ""},
+		{"system", auth.SystemPrincipal("worker"), DeleteEvents, "bob", ""},
+		{"unauthenticated", nil, ReadEvents, "", common.CodeUnauthenticated},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := policy.Authorize(principalContext(tt.principal), tt.action, tt.owner)
+			if tt.wantCode == "" {
+				if err != nil {
+					t.Errorf("Authorize = %v, want allowed", err)
+				}
+				return
+			}
+			var processingErr *common.ProcessingError
+			if !errors.As(err, &processingErr) || processingErr.Code != tt.wantCode {
+				t.Errorf("Authorize = %v, want %s", err, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestReach(t *testing.T) {
+	policy := loadDefault(t)
+
+	tests := []struct {
+		name               string
+		principal          *auth.Principal
+		action             Action
+		wantAny, wantOwned bool
+	}{
+		{"own only", &auth.Principal{Subject: "alice", Roles: []string{RoleOrganizer}}, ReadHistory, false, true},
+		{"any owner", &auth.Principal{Subject: "root", Roles: []string{RoleAdmin}}, ReadHistory, true, true},
+		{"none", &auth.Principal{Subject: "vic", Roles: []string{RoleViewer}}, ReadHistory, false, false},
+		{"system", auth.SystemPrincipal("worker"), ReadHistory, true, true},
+		{"unauthenticated", nil, ReadEvents, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			anyOwner, own := policy.Reach(principalContext(tt.principal), tt.action)
+			if anyOwner != tt.wantAny || own != tt.wantOwned {
+				t.Errorf("Reach = %v, %v, want %v, %v", anyOwner, own, tt.wantAny, tt.wantOwned)
+			}
+		})
+	}
+}
+
+func TestParseMergesGrants(t *testing.T) {
+	policy, err := Parse([]byte(`
+roles:
+  editor:
+    - events:update:own
+    - events:update
+    - events:delete
+    - events:delete:own
+  owner:
+    - events:cancel:own
+scopes:
+  events:read:
+    - events:read
+`))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+
+	editor := principalContext(&auth.Principal{Subject: "ed", Roles: []string{"editor"}})
+	// the unrestricted grant wins, whichever comes first
+	for _, action := range []Action{UpdateEvents, DeleteEvents} {
+		if err := policy.Authorize(editor, action, "bob"); err != nil {
+			t.Errorf("Authorize %s = %v, want allowed", action, err)
+		}
+	}
+
+	// a principal holding a role restricted to its own events, and one that
+	// isn't, gets the union
+	both := principalContext(&auth.Principal{Subject: "ed", Roles: []string{"owner", "editor"}})
+	if err := policy.Authorize(both, CancelEvents, "ed"); err != nil {
+		t.Errorf("Authorize own = %v, want allowed", err)
+	}
+	if err := policy.Authorize(both, CancelEvents, "bob"); err == nil {
+		t.Error("Authorize of another's event allowed")
+	}
+
+	if scopes := policy.Scopes(); len(scopes) != 1 || scopes[0] != "events:read" || !policy.HasScope("events:read") {
+		t.Errorf("Scopes = %v", scopes)
+	}
+}
+
+func TestParseRejectsMalformedPolicies(t *testing.T) {
+	tests := map[string]string{
+		"not yaml":          "roles: [viewer",
+		"empty":             "",
+		"no roles":          "roles: {}\n",
+		"unknown key":       "roles:\n  viewer: [events:read]\nrole:\n  admin: [events:read]\n",
+		"unknown action":    "roles:\n  viewer: [events:raed]\n",
+		"unknown own":       "roles:\n  viewer: [events:raed:own]\n",
+		"scope of scalars":  "scopes:\n  events:read: events:read\n",
+		"roles not mapping": "roles: [viewer]\n",
+	}
+	for name, data := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := Parse([]byte(data)); err == nil {
+				t.Errorf("Parse(%q) succeeded", data)
+			}
+		})
+	}
+}
+
+func TestLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "policy.yaml")
+	if err := os.WriteFile(path, []byte("roles:\n  auditor: [events:history]\n"), 0o600); err != nil {
+		t.Fatalf("write policy: %v", err)
+	}
+
+	policy, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	auditor := principalContext(&auth.Principal{Subject: "a", Roles: []string{"auditor"}})
+	if err := policy.Authorize(auditor, ReadHistory, "bob"); err != nil {
+		t.Errorf("Authorize = %v, want allowed by the file", err)
+	}
+	// the file replaces the default policy
+	if err := policy.Authorize(auditor, ReadEvents, ""); err == nil {
+		t.Error("Authorize of an action missing from the file allowed")
+	}
+
+	if _, err := Load(filepath.Join(t.TempDir(), "missing.yaml")); err == nil {
+		t.Error("Load of a missing file succeeded")
+	}
+}
@@ -0,0 +1,22 @@
+package repository
+
+import (
+	"context"
+	"online-registration/internal/interview/domain/entity"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type IAPIKeyRepository interface {
+	CreateAPIKey(ctx context.Context, key *entity.APIKey) (*entity.APIKey, error)
+	ListAPIKeys(ctx context.Context) ([]*entity.APIKey, error)
+	GetAPIKey(ctx context.Context, id uuid.UUID) (*entity.APIKey, error)
+	GetAPIKeyByPrefix(ctx context.Context, prefix string) (*entity.APIKey, error)
+	RevokeAPIKey(ctx context.Context, id uuid.UUID) error
+	// RotateAPIKey stores replacement and makes the key id expire at
+	// expiresAt, unless it expires earlier.
+	RotateAPIKey(ctx context.Context, id uuid.UUID, replacement *entity.APIKey, expiresAt time.Time) (*entity.APIKey, error)
+	// TouchAPIKey records that the key was used at usedAt.
+	TouchAPIKey(ctx context.Context, id uuid.UUID, usedAt time.Time) error
+}
@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"context"
+	"online-registration/internal/interview/domain/entity"
+
+	"github.com/google/uuid"
+)
+
+// IAuditLogRepository reads the audit log. Entries are written by the event
+// repository in the transaction of the change they record.
+type IAuditLogRepository interface {
+	// ListEventHistory returns the latest entries of an event, newest first.
+	ListEventHistory(ctx context.Context, eventID uuid.UUID, limit int) ([]*entity.EventAuditEntry, error)
+	ListAuditEntries(ctx context.Context, filter entity.AuditLogFilter) ([]*entity.EventAuditEntry, error)
+}
@@ -0,0 +1,12 @@
+package repository
+
+import (
+	"context"
+	"online-registration/internal/interview/domain/auth"
+)
+
+type ITokenVerifier interface {
+	// Verify checks the signature and claims of a bearer token and returns
+	// the principal it was issued to.
+	Verify(ctx context.Context, rawToken string) (*auth.Principal, error)
+}
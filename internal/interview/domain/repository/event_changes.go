@@ -0,0 +1,20 @@
+package repository
+
+import (
+	"context"
+	"online-registration/internal/interview/domain/entity"
+)
+
+type IEventChangeRepository interface {
+	// ListEventChanges returns the changes with the given outbox ids, of
+	// every tenant, in id order.
+	ListEventChanges(ctx context.Context, ids []int64) ([]*entity.EventChange, error)
+	// ListEventChangesAfter returns up to limit changes of the tenant of ctx
+	// with an id greater than afterID, in id order.
+	ListEventChangesAfter(ctx context.Context, afterID int64, limit int) ([]*entity.EventChange, error)
+	// ListenEventChanges calls listening once it listens for changes, then
+	// notify with the id of every change committed, until ctx is cancelled
+	// or the connection fails. Changes committed while it isn't listening
+	// are not notified.
+	ListenEventChanges(ctx context.Context, listening func(), notify func(id int64)) error
+}
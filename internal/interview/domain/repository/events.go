@@ -2,10 +2,78 @@ package repository
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"online-registration/internal/interview/domain/entity"
 	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrEventNotEditable is returned when the details of a cancelled or
+	// completed event are changed.
+	ErrEventNotEditable = errors.New("event is not editable")
+	// ErrEventNotOpen is returned when registering for an event that is not
+	// published.
+	ErrEventNotOpen = errors.New("event is not open for registration")
+	// ErrRoomNotFound is returned when an event is booked into a room that
+	// doesn't exist.
+	ErrRoomNotFound = errors.New("room not found")
+	// ErrCategoryNotFound is returned when an event is put in a category
+	// that doesn't exist.
+	ErrCategoryNotFound = errors.New("category not found")
 )
 
+// RoomConflictError is returned when an event is booked into a room that
+// already holds another event at the same time.
+type RoomConflictError struct {
+	RoomID  uuid.UUID
+	EventID uuid.UUID
+	Title   string
+}
+
+func (e *RoomConflictError) Error() string {
+	return fmt.Sprintf("room %s is already booked by event %s", e.RoomID, e.EventID)
+}
+
+// EventTransitionError is returned when the lifecycle of an event doesn't
+// allow a status change.
+type EventTransitionError struct {
+	From string
+	To   string
+}
+
+func (e *EventTransitionError) Error() string {
+	return fmt.Sprintf("event can't change from %s to %s", e.From, e.To)
+}
+
 type IEventRepository interface {
-	CreateEvent(ctx context.Context, title string, description string, startTime, endTime time.Time) (*entity.Event, error)
+	// CreateEvent stores a new event with the details, status and creator of
+	// event.
+	CreateEvent(ctx context.Context, event *entity.Event) (*entity.Event, error)
+	GetEvent(ctx context.Context, id uuid.UUID) (*entity.Event, error)
+	// GetEventWithDeleted also returns soft deleted events.
+	GetEventWithDeleted(ctx context.Context, id uuid.UUID) (*entity.Event, error)
+	ListEvents(ctx context.Context, filter entity.EventFilter) ([]*entity.Event, error)
+	// SearchEvents lists the events matching filter.Query, the most relevant
+	// first.
+	SearchEvents(ctx context.Context, filter entity.EventFilter) ([]*entity.EventMatch, error)
+	// CountFacets counts the events of filter, searched for when it has a
+	// Query, by tag and by category. The paging of filter is ignored.
+	CountFacets(ctx context.Context, filter entity.EventFilter) (*entity.EventFacets, error)
+	// UpdateEvent replaces the details of the event with the ID of event by
+	// those of event. Its status and creator are kept.
+	UpdateEvent(ctx context.Context, event *entity.Event) (*entity.Event, error)
+	DeleteEvent(ctx context.Context, id uuid.UUID) error
+	// TransitionEvent moves the event to status to. reason is kept for
+	// cancellations, and the registrants of a cancelled event are notified.
+	TransitionEvent(ctx context.Context, id uuid.UUID, to string, reason string) (*entity.Event, error)
+	// CompleteEvents marks up to limit published events that ended before
+	// endedBefore as completed.
+	CompleteEvents(ctx context.Context, endedBefore time.Time, limit int) ([]*entity.Event, error)
+	// PurgeDeletedEvents deletes up to limit events soft deleted before
+	// deletedBefore for good, with their registrations, participants, tags
+	// and notifications, and returns how many it deleted.
+	PurgeDeletedEvents(ctx context.Context, deletedBefore time.Time, limit int) (int, error)
 }
@@ -0,0 +1,29 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"online-registration/internal/interview/domain/entity"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrIdempotencyKeyLost is returned when a request settles a key it no
+// longer holds, because it was taken over or has expired.
+var ErrIdempotencyKeyLost = errors.New("idempotency key is no longer held")
+
+type IIdempotencyKeyRepository interface {
+	// Acquire claims key for the request with the given fingerprint, under
+	// a new owner token. When the key is already held by a live record, that
+	// record is returned together with acquired set to false.
+	Acquire(
+		ctx context.Context, key, fingerprint string, ttl, lockTimeout time.Duration,
+	) (record *entity.IdempotencyKey, acquired bool, err error)
+	// Complete and Release settle the key held by owner, they return
+	// ErrIdempotencyKeyLost when owner doesn't hold it anymore.
+	Complete(ctx context.Context, key string, owner uuid.UUID, statusCode int, responseBody []byte) error
+	Release(ctx context.Context, key string, owner uuid.UUID) error
+	// DeleteExpired deletes the keys that expired before before.
+	DeleteExpired(ctx context.Context, before time.Time) (int, error)
+}
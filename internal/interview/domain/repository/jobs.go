@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"online-registration/internal/interview/domain/entity"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrJobNotRetryable is returned when retrying a job that hasn't failed
+	// or been cancelled.
+	ErrJobNotRetryable = errors.New("job is not failed or cancelled")
+	// ErrJobNotCancellable is returned when cancelling a job that isn't
+	// pending.
+	ErrJobNotCancellable = errors.New("job is not pending")
+	// ErrJobDuplicate is returned when retrying a job while another one with
+	// its unique key is pending or running.
+	ErrJobDuplicate = errors.New("job with the same unique key is pending or running")
+)
+
+type IJobRepository interface {
+	// EnqueueJob stores a pending job. When a job with its unique key is
+	// already pending or running, that job is returned with enqueued set to
+	// false instead.
+	EnqueueJob(ctx context.Context, job *entity.Job) (stored *entity.Job, enqueued bool, err error)
+	GetJob(ctx context.Context, id uuid.UUID) (*entity.Job, error)
+	ListJobs(ctx context.Context, filter entity.JobFilter) ([]*entity.Job, error)
+	// RetryJob makes a failed or cancelled job pending again, with a fresh
+	// set of attempts.
+	RetryJob(ctx context.Context, id uuid.UUID) (*entity.Job, error)
+	CancelJob(ctx context.Context, id uuid.UUID) (*entity.Job, error)
+
+	// ClaimJobs leases up to limit due jobs of kinds to the caller, hiding
+	// them from other workers until lease passes, and counts the attempt.
+	// Running jobs whose lease passed are claimed again.
+	ClaimJobs(ctx context.Context, kinds []string, limit int, lease time.Duration) ([]*entity.Job, error)
+	// CompleteJob and FailJob finish the attempt of a claimed job. They are
+	// no-ops once the lease passed and the job was claimed again.
+	CompleteJob(ctx context.Context, job *entity.Job) error
+	// FailJob records a failed attempt. The job is retried at retryAt, or
+	// given up when it is nil.
+	FailJob(ctx context.Context, job *entity.Job, lastError string, retryAt *time.Time) error
+	// DeleteFinishedJobs deletes the jobs that finished before before.
+	DeleteFinishedJobs(ctx context.Context, before time.Time) (int, error)
+}
@@ -0,0 +1,37 @@
+package repository
+
+import (
+	"context"
+	"online-registration/internal/interview/domain/entity"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type INotificationRepository interface {
+	// EnqueueNotification stores a pending notification. Enqueuing one with
+	// the dedupe key of another is a no-op and returns false.
+	EnqueueNotification(ctx context.Context, notification *entity.Notification) (bool, error)
+	// CancelNotifications cancels the pending notifications of kind for the
+	// registration.
+	CancelNotifications(ctx context.Context, registrationID uuid.UUID, kind string) (int, error)
+	// RescheduleReminders moves the pending reminders for the event to
+	// sendAt.
+	RescheduleReminders(ctx context.Context, eventID uuid.UUID, sendAt time.Time) (int, error)
+
+	// ClaimNotifications leases up to limit due notifications, hiding them
+	// from other workers until lease passes.
+	ClaimNotifications(ctx context.Context, limit int, lease time.Duration) ([]*entity.Notification, error)
+	MarkNotificationSent(ctx context.Context, id uuid.UUID) error
+	// MarkNotificationFailed records a failed attempt. The notification is
+	// retried at nextAttemptAt, or given up when it is nil.
+	MarkNotificationFailed(ctx context.Context, id uuid.UUID, lastError string, nextAttemptAt *time.Time) error
+	// MarkNotificationCancelled drops a notification that is no longer
+	// wanted, e.g. a reminder for an event that was cancelled.
+	MarkNotificationCancelled(ctx context.Context, id uuid.UUID, reason string) error
+}
+
+// INotificationSender sends emails to registrants.
+type INotificationSender interface {
+	Send(ctx context.Context, email *entity.Email) error
+}
@@ -0,0 +1,23 @@
+package repository
+
+import (
+	"context"
+	"online-registration/internal/interview/domain/entity"
+	"time"
+)
+
+type IOutboxRepository interface {
+	// Claim leases up to limit pending messages to the caller. Claimed
+	// messages are hidden from other relays until lease passes.
+	Claim(ctx context.Context, limit int, lease time.Duration) ([]*entity.OutboxMessage, error)
+	MarkPublished(ctx context.Context, ids []int64) error
+	MarkFailed(ctx context.Context, id int64, lastError string, nextAttemptAt time.Time) error
+	// DeletePublished deletes the messages published before before.
+	DeletePublished(ctx context.Context, before time.Time) (int, error)
+}
+
+type IMessagePublisher interface {
+	// Publish delivers payload to subject. messageID is used by the broker
+	// to drop duplicates of a message published more than once.
+	Publish(ctx context.Context, subject string, messageID string, payload []byte) error
+}
@@ -0,0 +1,22 @@
+package repository
+
+import (
+	"context"
+	"online-registration/internal/interview/domain/entity"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type IParticipantRepository interface {
+	// SetParticipant adds the user to the event, or changes their role.
+	SetParticipant(ctx context.Context, eventID uuid.UUID, userID string, role string) (*entity.Participant, error)
+	RemoveParticipant(ctx context.Context, eventID uuid.UUID, userID string) error
+	ListParticipants(ctx context.Context, eventID uuid.UUID) ([]*entity.Participant, error)
+	// ListBusy returns the periods between start and end in which the users
+	// take part in or are registered for events, and the rooms are booked.
+	// Cancelled events keep no one busy.
+	ListBusy(
+		ctx context.Context, userIDs []string, roomIDs []uuid.UUID, start, end time.Time,
+	) ([]*entity.BusyPeriod, error)
+}
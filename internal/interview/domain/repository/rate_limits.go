@@ -0,0 +1,22 @@
+package repository
+
+import (
+	"context"
+	"online-registration/internal/interview/domain/entity"
+	"time"
+)
+
+// IRateLimitStore keeps the token buckets of the rate limiter. Stores shared
+// by all instances enforce the limits across the cluster.
+type IRateLimitStore interface {
+	// Take refills the bucket of key and takes a token from it if one is
+	// available.
+	Take(ctx context.Context, key string, limit entity.RateLimit) (*entity.RateLimitBucket, error)
+}
+
+// IRateLimitBucketRepository deletes the buckets of the stores that keep
+// them until they are purged.
+type IRateLimitBucketRepository interface {
+	// DeleteIdleBuckets deletes the buckets last updated before before.
+	DeleteIdleBuckets(ctx context.Context, before time.Time) (int, error)
+}
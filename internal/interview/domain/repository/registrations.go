@@ -0,0 +1,29 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"online-registration/internal/interview/domain/entity"
+
+	"github.com/google/uuid"
+)
+
+// ErrAlreadyRegistered is returned when the user already has an active
+// registration for the event.
+var ErrAlreadyRegistered = errors.New("already registered")
+
+type IRegistrationRepository interface {
+	Register(ctx context.Context, eventID uuid.UUID, userID, email string) (*entity.Registration, error)
+	CancelRegistration(ctx context.Context, eventID uuid.UUID, userID string) (*entity.Registration, error)
+	// HasOnboardingRegistration reports whether the user has an active
+	// registration for any onboarding event that was neither cancelled nor
+	// deleted.
+	HasOnboardingRegistration(ctx context.Context, userID string) (bool, error)
+}
+
+// IOnboardingGroupClient manages the members of a group in the identity
+// provider. Adding an existing member or removing a missing one succeeds.
+type IOnboardingGroupClient interface {
+	AddGroupMember(ctx context.Context, groupID, userID string) error
+	RemoveGroupMember(ctx context.Context, groupID, userID string) error
+}
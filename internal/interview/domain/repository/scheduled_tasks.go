@@ -0,0 +1,31 @@
+package repository
+
+import (
+	"context"
+	"online-registration/internal/interview/domain/entity"
+	"time"
+)
+
+type IScheduledTaskRepository interface {
+	// SaveScheduledTask stores the schedule and next tick of a task, keeping
+	// its last run.
+	SaveScheduledTask(ctx context.Context, name, schedule string, nextRunAt time.Time) (*entity.ScheduledTask, error)
+	ListScheduledTasks(ctx context.Context) ([]*entity.ScheduledTask, error)
+	// ClaimTaskRun records that instance starts the run of a task for tick,
+	// and reports false when a run for tick or a later one was already
+	// claimed.
+	ClaimTaskRun(ctx context.Context, name string, tick time.Time, instance string) (bool, error)
+	// FinishTaskRun records the outcome of the run of a task for tick, a
+	// failure when runErr is not empty, and plans its next tick.
+	FinishTaskRun(ctx context.Context, name string, tick time.Time, runErr string, nextRunAt time.Time) error
+}
+
+// ILeaderLock is a lock held by at most one instance of the service at a
+// time.
+type ILeaderLock interface {
+	// TryAcquire takes the lock unless another instance holds it, and
+	// reports whether this instance holds it. The holder calls it again to
+	// check that it didn't lose the lock with its connection.
+	TryAcquire(ctx context.Context) (bool, error)
+	Release(ctx context.Context) error
+}
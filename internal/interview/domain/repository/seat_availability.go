@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"context"
+	"online-registration/internal/interview/domain/entity"
+
+	"github.com/google/uuid"
+)
+
+type ISeatAvailabilityRepository interface {
+	// GetSeatAvailability returns the availability of the events with the
+	// given ids, leaving out the missing and deleted ones.
+	GetSeatAvailability(ctx context.Context, eventIDs []uuid.UUID) ([]*entity.SeatAvailability, error)
+	// ListenSeatChanges calls listening once it listens for changes, then
+	// notify with the id of every event whose registrations, status or room
+	// changed, until ctx is cancelled or the connection fails. Changes
+	// committed while it isn't listening are not notified.
+	ListenSeatChanges(ctx context.Context, listening func(), notify func(eventID uuid.UUID)) error
+}
@@ -0,0 +1,34 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"online-registration/internal/interview/domain/entity"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrTagNameTaken is returned when another tag has the name.
+	ErrTagNameTaken = errors.New("tag name is taken")
+	// ErrCategoryNameTaken is returned when another category has the name.
+	ErrCategoryNameTaken = errors.New("category name is taken")
+)
+
+// ITagRepository manages the tags and categories of events. Renaming and
+// deleting them changes the events they are used by without auditing them.
+type ITagRepository interface {
+	// ListTags returns the tags ordered by name.
+	ListTags(ctx context.Context) ([]*entity.Tag, error)
+	CreateTag(ctx context.Context, name string) (*entity.Tag, error)
+	UpdateTag(ctx context.Context, id uuid.UUID, name string) (*entity.Tag, error)
+	// DeleteTag removes the tag from its events.
+	DeleteTag(ctx context.Context, id uuid.UUID) error
+
+	// ListCategories returns the categories ordered by name.
+	ListCategories(ctx context.Context) ([]*entity.Category, error)
+	CreateCategory(ctx context.Context, name string) (*entity.Category, error)
+	UpdateCategory(ctx context.Context, id uuid.UUID, name string) (*entity.Category, error)
+	// DeleteCategory leaves its events without a category.
+	DeleteCategory(ctx context.Context, id uuid.UUID) error
+}
@@ -0,0 +1,35 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"online-registration/internal/interview/domain/entity"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrVenueNotEmpty is returned when deleting a venue that still has
+	// rooms.
+	ErrVenueNotEmpty = errors.New("venue has rooms")
+	// ErrRoomNameTaken is returned when a venue already has a room with the
+	// name.
+	ErrRoomNameTaken = errors.New("room name is taken")
+	// ErrRoomInUse is returned when deleting a room that draft or published
+	// events are booked into.
+	ErrRoomInUse = errors.New("room is in use")
+)
+
+type IVenueRepository interface {
+	CreateVenue(ctx context.Context, name string, address string) (*entity.Venue, error)
+	// ListVenues returns the venues with their rooms, ordered by name.
+	ListVenues(ctx context.Context) ([]*entity.Venue, error)
+	GetVenue(ctx context.Context, id uuid.UUID) (*entity.Venue, error)
+	UpdateVenue(ctx context.Context, id uuid.UUID, name string, address string) (*entity.Venue, error)
+	DeleteVenue(ctx context.Context, id uuid.UUID) error
+
+	CreateRoom(ctx context.Context, venueID uuid.UUID, name string, capacity int) (*entity.Room, error)
+	UpdateRoom(ctx context.Context, id uuid.UUID, name string, capacity int) (*entity.Room, error)
+	// DeleteRoom removes the room from past, cancelled and deleted events.
+	DeleteRoom(ctx context.Context, id uuid.UUID) error
+}
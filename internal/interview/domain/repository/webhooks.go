@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"context"
+	"online-registration/internal/interview/domain/entity"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type IWebhookRepository interface {
+	CreateSubscription(ctx context.Context, subscription *entity.WebhookSubscription) (*entity.WebhookSubscription, error)
+	ListSubscriptions(ctx context.Context) ([]*entity.WebhookSubscription, error)
+	GetSubscription(ctx context.Context, id uuid.UUID) (*entity.WebhookSubscription, error)
+	UpdateSubscription(
+		ctx context.Context, id uuid.UUID, url string, eventTypes []string, active bool,
+	) (*entity.WebhookSubscription, error)
+	DeleteSubscription(ctx context.Context, id uuid.UUID) error
+
+	// EnqueueDeliveries creates a pending delivery of the message for every
+	// active subscription of the tenant to eventType. Enqueuing the same
+	// message twice is a no-op.
+	EnqueueDeliveries(
+		ctx context.Context, tenantID string, messageID uuid.UUID, eventType string, payload []byte,
+	) (int, error)
+	ListDeliveries(ctx context.Context, subscriptionID uuid.UUID, limit int) ([]*entity.WebhookDelivery, error)
+	// Redeliver schedules a copy of a delivery to be sent again.
+	Redeliver(ctx context.Context, subscriptionID, deliveryID uuid.UUID) (*entity.WebhookDelivery, error)
+
+	// ClaimDeliveries leases up to limit due deliveries, with their
+	// subscriptions loaded, hiding them from other workers until lease passes.
+	ClaimDeliveries(ctx context.Context, limit int, lease time.Duration) ([]*entity.WebhookDelivery, error)
+	// RenewDeliveryLease extends the lease of a claimed delivery to lease
+	// from now. It returns false when the lease has been lost to another
+	// worker, or the delivery is no longer pending.
+	RenewDeliveryLease(ctx context.Context, delivery *entity.WebhookDelivery, lease time.Duration) (bool, error)
+	MarkDeliverySucceeded(ctx context.Context, delivery *entity.WebhookDelivery, statusCode int) error
+	// MarkDeliveryFailed records a failed attempt. The delivery is retried at
+	// nextAttemptAt, or given up when it is nil. The subscription is disabled
+	// once it reaches disableAfter consecutive failed attempts.
+	MarkDeliveryFailed(
+		ctx context.Context, delivery *entity.WebhookDelivery, statusCode int, lastError string,
+		nextAttemptAt *time.Time, disableAfter int,
+	) error
+}
+
+type IWebhookSender interface {
+	Send(
+		ctx context.Context, url string, secret string, deliveryID string, eventType string, payload []byte,
+	) (statusCode int, err error)
+}
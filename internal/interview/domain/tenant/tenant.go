@@ -0,0 +1,46 @@
+// Package tenant carries the tenant a request acts on. Repositories scope
+// their queries to it, see the model hooks of the db layer.
+package tenant
+
+import (
+	"context"
+	"errors"
+	"regexp"
+)
+
+// ErrMissing is returned by queries of tenant scoped tables when the context
+// carries no tenant, so a forgotten tenant fails instead of leaking rows.
+var ErrMissing = errors.New("tenant is missing from context")
+
+var idPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,62}$`)
+
+// Valid reports whether id can be used as a tenant id.
+func Valid(id string) bool {
+	return idPattern.MatchString(id)
+}
+
+type tenantCtxKey struct{}
+
+type allTenantsCtxKey struct{}
+
+func ContextWithTenant(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, tenantCtxKey{}, id)
+}
+
+// FromContext returns the tenant of ctx.
+func FromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(tenantCtxKey{}).(string)
+	return id, ok && id != ""
+}
+
+// ContextWithAllTenants lifts the tenant scope, for background jobs that
+// work on the rows of every tenant.
+func ContextWithAllTenants(ctx context.Context) context.Context {
+	return context.WithValue(ctx, allTenantsCtxKey{}, true)
+}
+
+// AllTenants reports whether ctx was lifted with ContextWithAllTenants.
+func AllTenants(ctx context.Context) bool {
+	all, _ := ctx.Value(allTenantsCtxKey{}).(bool)
+	return all
+}
@@ -0,0 +1,244 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"online-registration/internal/common"
+	"online-registration/internal/interview/domain/auth"
+	"online-registration/internal/interview/domain/entity"
+	"online-registration/internal/interview/domain/policy"
+	"online-registration/internal/interview/domain/repository"
+	"online-registration/internal/interview/domain/tenant"
+	"online-registration/internal/interview/helper"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// APIKeyPrefix starts every API key, e.g. evk_1a2b3c4d5e6f_<secret>.
+	APIKeyPrefix = "evk_"
+
+	apiKeyPrefixBytes = 6
+	apiKeySecretBytes = 32
+)
+
+var errInvalidAPIKey = errors.New("invalid api key")
+
+// IssuedAPIKey is a newly created API key. Key is the only copy of the
+// plain key, it can't be recovered later.
+type IssuedAPIKey struct {
+	*entity.APIKey
+	Key string `json:"key"`
+}
+
+// APIKeyUseCase issues API keys and authenticates the requests made with
+// them. It implements repository.ITokenVerifier for the auth middleware.
+type APIKeyUseCase struct {
+	repository repository.IAPIKeyRepository
+	policy     *policy.Policy
+}
+
+func NewAPIKeyUseCase(
+	repository repository.IAPIKeyRepository,
+	policy *policy.Policy,
+) *APIKeyUseCase {
+	return &APIKeyUseCase{
+		repository: repository,
+		policy:     policy,
+	}
+}
+
+// Create issues a key with the given scopes, bound to tenantID unless it is
+// empty. A zero ttl issues a key that doesn't expire.
+func (uc *APIKeyUseCase) Create(
+	ctx context.Context, name string, scopes []string, tenantID string, ttl time.Duration,
+) (*IssuedAPIKey, error) {
+	if strings.TrimSpace(name) == "" {
+		return nil, common.NewCodedError(
+			helper.InvalidArgument, common.CodeAPIKeyInvalidName, errors.New("api key name is empty"),
+		)
+	}
+	if err := uc.validateScopes(scopes); err != nil {
+		return nil, err
+	}
+	if tenantID != "" && !tenant.Valid(tenantID) {
+		return nil, common.NewCodedError(
+			helper.InvalidArgument, common.CodeInvalidTenant, fmt.Errorf("invalid tenant %q", tenantID), tenantID,
+		)
+	}
+
+	key, plain, err := newAPIKey(name, scopes)
+	if err != nil {
+		return nil, apiKeyStorageError("create api key", err)
+	}
+	key.Tenant = tenantID
+	if ttl > 0 {
+		expiresAt := time.Now().Add(ttl)
+		key.ExpiresAt = &expiresAt
+	}
+
+	created, err := uc.repository.CreateAPIKey(ctx, key)
+	if err != nil {
+		return nil, apiKeyStorageError("create api key", err)
+	}
+	return &IssuedAPIKey{APIKey: created, Key: plain}, nil
+}
+
+func (uc *APIKeyUseCase) List(ctx context.Context) ([]*entity.APIKey, error) {
+	keys, err := uc.repository.ListAPIKeys(ctx)
+	if err != nil {
+		return nil, apiKeyStorageError("list api keys", err)
+	}
+	return keys, nil
+}
+
+func (uc *APIKeyUseCase) Revoke(ctx context.Context, id uuid.UUID) error {
+	if err := uc.repository.RevokeAPIKey(ctx, id); err != nil {
+		return apiKeyStorageError("revoke api key", err)
+	}
+	return nil
+}
+
+// Rotate issues a replacement with the same name, scopes and tenant. The old key
+// keeps working for grace so that clients can switch over.
+func (uc *APIKeyUseCase) Rotate(ctx context.Context, id uuid.UUID, grace time.Duration) (*IssuedAPIKey, error) {
+	old, err := uc.repository.GetAPIKey(ctx, id)
+	if err != nil {
+		return nil, apiKeyStorageError("rotate api key", err)
+	}
+
+	replacement, plain, err := newAPIKey(old.Name, old.Scopes)
+	if err != nil {
+		return nil, apiKeyStorageError("rotate api key", err)
+	}
+	replacement.Tenant = old.Tenant
+	if old.ExpiresAt != nil {
+		// keep the lifetime of the old key
+		expiresAt := time.Now().Add(old.ExpiresAt.Sub(old.CreatedAt))
+		replacement.ExpiresAt = &expiresAt
+	}
+
+	created, err := uc.repository.RotateAPIKey(ctx, id, replacement, time.Now().Add(grace))
+	if err != nil {
+		return nil, apiKeyStorageError("rotate api key", err)
+	}
+	return &IssuedAPIKey{APIKey: created, Key: plain}, nil
+}
+
+// Verify authenticates a plain API key and returns the principal acting with
+// its scopes.
+func (uc *APIKeyUseCase) Verify(ctx context.Context, rawKey string) (*auth.Principal, error) {
+	prefix, ok := apiKeyLookupPrefix(rawKey)
+	if !ok {
+		return nil, errInvalidAPIKey
+	}
+
+	key, err := uc.repository.GetAPIKeyByPrefix(ctx, prefix)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errInvalidAPIKey
+	}
+	if err != nil {
+		log.Error().Msgf("APIKeyUseCase.Verify: %v", err)
+		return nil, common.NewCodedError(
+			helper.Unavailable, common.CodeAuthUnavailable, fmt.Errorf("verify api key: %w", err),
+		)
+	}
+
+	now := time.Now()
+	if subtle.ConstantTimeCompare([]byte(hashAPIKey(rawKey)), []byte(key.KeyHash)) != 1 || !key.Active(now) {
+		return nil, errInvalidAPIKey
+	}
+
+	if err := uc.repository.TouchAPIKey(ctx, key.ID, now); err != nil {
+		log.Error().Msgf("APIKeyUseCase.Verify: %v", err)
+	}
+
+	return &auth.Principal{
+		Subject: "apikey:" + key.ID.String(),
+		Name:    key.Name,
+		Scopes:  key.Scopes,
+		Tenant:  key.Tenant,
+	}, nil
+}
+
+// IsAPIKey reports whether a credential looks like an API key rather than a
+// JWT.
+func IsAPIKey(credential string) bool {
+	return strings.HasPrefix(credential, APIKeyPrefix)
+}
+
+func (uc *APIKeyUseCase) validateScopes(scopes []string) error {
+	valid := len(scopes) > 0
+	for _, scope := range scopes {
+		if !uc.policy.HasScope(scope) {
+			valid = false
+		}
+	}
+	if !valid {
+		return common.NewCodedError(
+			helper.InvalidArgument, common.CodeAPIKeyInvalidScopes,
+			fmt.Errorf("invalid api key scopes %v", scopes),
+			strings.Join(uc.policy.Scopes(), ", "),
+		)
+	}
+	return nil
+}
+
+// newAPIKey generates a key and returns it with its plain form.
+func newAPIKey(name string, scopes []string) (*entity.APIKey, string, error) {
+	prefix := make([]byte, apiKeyPrefixBytes)
+	secret := make([]byte, apiKeySecretBytes)
+	if _, err := rand.Read(prefix); err != nil {
+		return nil, "", err
+	}
+	if _, err := rand.Read(secret); err != nil {
+		return nil, "", err
+	}
+
+	lookupPrefix := hex.EncodeToString(prefix)
+	plain := APIKeyPrefix + lookupPrefix + "_" + hex.EncodeToString(secret)
+
+	return &entity.APIKey{
+		ID:      uuid.New(),
+		Name:    name,
+		Prefix:  lookupPrefix,
+		KeyHash: hashAPIKey(plain),
+		Scopes:  slices.Compact(slices.Sorted(slices.Values(scopes))),
+	}, plain, nil
+}
+
+// apiKeyLookupPrefix extracts the public part of a plain key.
+func apiKeyLookupPrefix(rawKey string) (string, bool) {
+	rest, ok := strings.CutPrefix(rawKey, APIKeyPrefix)
+	if !ok {
+		return "", false
+	}
+	prefix, secret, ok := strings.Cut(rest, "_")
+	return prefix, ok && prefix != "" && secret != ""
+}
+
+// hashAPIKey hashes a plain key. The keys are random and long, so a fast hash
+// is enough to make a leaked table useless.
+func hashAPIKey(rawKey string) string {
+	sum := sha256.Sum256([]byte(rawKey))
+	return hex.EncodeToString(sum[:])
+}
+
+func apiKeyStorageError(operation string, err error) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		return common.NewCodedError(helper.NotFound, common.CodeNotFound, fmt.Errorf("%s: %w", operation, err))
+	}
+
+	log.Error().Msgf("APIKeyUseCase: %s: %v", operation, err)
+	return common.NewCodedError(helper.InternalError, common.CodeInternalError, fmt.Errorf("%s: %w", operation, err))
+}
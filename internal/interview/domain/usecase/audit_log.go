@@ -0,0 +1,97 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"online-registration/internal/common"
+	"online-registration/internal/interview/domain/entity"
+	"online-registration/internal/interview/domain/policy"
+	"online-registration/internal/interview/domain/repository"
+	"online-registration/internal/interview/helper"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	maxEventHistoryLimit = 100
+	auditExportBatchSize = 500
+)
+
+type AuditLogUseCase struct {
+	eventRepository    repository.IEventRepository
+	auditLogRepository repository.IAuditLogRepository
+	policy             *policy.Policy
+}
+
+func NewAuditLogUseCase(
+	eventRepository repository.IEventRepository,
+	auditLogRepository repository.IAuditLogRepository,
+	policy *policy.Policy,
+) *AuditLogUseCase {
+	return &AuditLogUseCase{
+		eventRepository:    eventRepository,
+		auditLogRepository: auditLogRepository,
+		policy:             policy,
+	}
+}
+
+// EventHistory returns the latest changes of an event, newest first. The
+// history of deleted events stays available.
+func (uc *AuditLogUseCase) EventHistory(
+	ctx context.Context, eventID uuid.UUID, limit int,
+) ([]*entity.EventAuditEntry, error) {
+	event, err := uc.eventRepository.GetEventWithDeleted(ctx, eventID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, common.NewCodedError(helper.NotFound, common.CodeNotFound, fmt.Errorf("event history: %w", err))
+	}
+	if err != nil {
+		log.Error().Msgf("AuditLogUseCase.EventHistory: %v", err)
+		return nil, common.NewCodedError(helper.InternalError, common.CodeInternalError, fmt.Errorf("event history: %w", err))
+	}
+
+	if err := uc.policy.Authorize(ctx, policy.ReadHistory, event.CreatedBy); err != nil {
+		return nil, err
+	}
+
+	if limit <= 0 || limit > maxEventHistoryLimit {
+		limit = maxEventHistoryLimit
+	}
+
+	entries, err := uc.auditLogRepository.ListEventHistory(ctx, eventID, limit)
+	if err != nil {
+		log.Error().Msgf("AuditLogUseCase.EventHistory: %v", err)
+		return nil, common.NewCodedError(helper.InternalError, common.CodeInternalError, fmt.Errorf("event history: %w", err))
+	}
+	return entries, nil
+}
+
+// Export calls write for every entry matching filter, oldest first, until
+// write fails. It is meant for operators and doesn't check the policy.
+func (uc *AuditLogUseCase) Export(
+	ctx context.Context, filter entity.AuditLogFilter, write func(*entity.EventAuditEntry) error,
+) (int, error) {
+	filter.Limit = auditExportBatchSize
+
+	exported := 0
+	for {
+		entries, err := uc.auditLogRepository.ListAuditEntries(ctx, filter)
+		if err != nil {
+			return exported, fmt.Errorf("export audit log: %w", err)
+		}
+
+		for _, entry := range entries {
+			if err := write(entry); err != nil {
+				return exported, err
+			}
+			exported++
+		}
+
+		if len(entries) < filter.Limit {
+			return exported, nil
+		}
+		filter.AfterID = entries[len(entries)-1].ID
+	}
+}
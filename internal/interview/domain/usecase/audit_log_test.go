@@ -0,0 +1,151 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"online-registration/internal/common"
+	"online-registration/internal/interview/domain/auth"
+	"online-registration/internal/interview/domain/entity"
+	"online-registration/internal/interview/domain/policy"
+
+	"github.com/google/uuid"
+)
+
+// auditLog serves count entries with ids from 1 and records the filters it
+// was listed with.
+type auditLog struct {
+	count   int
+	filters []entity.AuditLogFilter
+	limit   int
+}
+
+func (r *auditLog) ListEventHistory(_ context.Context, _ uuid.UUID, limit int) ([]*entity.EventAuditEntry, error) {
+	r.limit = limit
+	return []*entity.EventAuditEntry{}, nil
+}
+
+func (r *auditLog) ListAuditEntries(_ context.Context, filter entity.AuditLogFilter) ([]*entity.EventAuditEntry, error) {
+	r.filters = append(r.filters, filter)
+
+	var entries []*entity.EventAuditEntry
+	for id := filter.AfterID + 1; id <= int64(r.count) && len(entries) < filter.Limit; id++ {
+		entries = append(entries, &entity.EventAuditEntry{ID: id})
+	}
+	return entries, nil
+}
+
+func TestAuditLogExport(t *testing.T) {
+	tests := []struct {
+		name        string
+		count       int
+		wantBatches int
+	}{
+		{"empty", 0, 1},
+		{"one batch", auditExportBatchSize - 1, 1},
+		{"full batch", auditExportBatchSize, 2},
+		{"batches", 2*auditExportBatchSize + 1, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repository := &auditLog{count: tt.count}
+			uc := NewAuditLogUseCase(nil, repository, nil)
+
+			var last int64
+			exported, err := uc.Export(context.Background(), entity.AuditLogFilter{}, func(entry *entity.EventAuditEntry) error {
+				if entry.ID != last+1 {
+					return fmt.Errorf("entry %d written after %d", entry.ID, last)
+				}
+				last = entry.ID
+				return nil
+			})
+			if err != nil {
+				t.Fatalf("Export: %v", err)
+			}
+			if exported != tt.count || len(repository.filters) != tt.wantBatches {
+				t.Errorf("Export wrote %d entries in %d batches, want %d in %d",
+					exported, len(repository.filters), tt.count, tt.wantBatches)
+			}
+		})
+	}
+}
+
+func TestAuditLogExportStopsOnWriteError(t *testing.T) {
+	repository := &auditLog{count: 2 * auditExportBatchSize}
+	uc := NewAuditLogUseCase(nil, repository, nil)
+	errFull := errors.New("disk full")
+
+	exported, err := uc.Export(context.Background(), entity.AuditLogFilter{}, func(entry *entity.EventAuditEntry) error {
+		if entry.ID == 3 {
+			return errFull
+		}
+		return nil
+	})
+	if !errors.Is(err, errFull) || exported != 2 || len(repository.filters) != 1 {
+		t.Errorf("Export = %d, %v after %d batches, want 2, %v after 1", exported, err, len(repository.filters), errFull)
+	}
+}
+
+func TestAuditLogEventHistory(t *testing.T) {
+	accessPolicy, err := policy.Load("")
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	events := newOwnedEvents()
+	admin := &auth.Principal{Subject: "root", Roles: []string{policy.RoleAdmin}}
+
+	tests := []struct {
+		name      string
+		principal *auth.Principal
+		eventID   uuid.UUID
+		limit     int
+		wantLimit int
+		wantCode  string
+	}{
+		{"limit", admin, events.event.ID, 10, 10, ""},
+		{"no limit", admin, events.event.ID, 0, maxEventHistoryLimit, ""},
+		{"over the limit", admin, events.event.ID, maxEventHistoryLimit + 1, maxEventHistoryLimit, ""},
+		{"missing event", admin, uuid.New(), 10, 0, common.CodeNotFound},
+		{"owner", &auth.Principal{Subject: "bob", Roles: []string{policy.RoleOrganizer}}, events.event.ID, 10, 10, ""},
+		{"another organizer", &auth.Principal{Subject: "alice", Roles: []string{policy.RoleOrganizer}},
+			events.event.ID, 10, 0, common.CodeForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repository := &auditLog{}
+			uc := NewAuditLogUseCase(deletedEvents{events}, repository, accessPolicy)
+			ctx := auth.ContextWithPrincipal(context.Background(), tt.principal)
+
+			_, err := uc.EventHistory(ctx, tt.eventID, tt.limit)
+			if tt.wantCode != "" {
+				var processingErr *common.ProcessingError
+				if !errors.As(err, &processingErr) || processingErr.Code != tt.wantCode {
+					t.Errorf("EventHistory error = %v, want %s", err, tt.wantCode)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("EventHistory: %v", err)
+			}
+			if repository.limit != tt.wantLimit {
+				t.Errorf("EventHistory listed %d entries, want %d", repository.limit, tt.wantLimit)
+			}
+		})
+	}
+}
+
+// deletedEvents serves the events of ownedEvents whether they were deleted
+// or not.
+type deletedEvents struct {
+	*ownedEvents
+}
+
+func (r deletedEvents) GetEventWithDeleted(ctx context.Context, id uuid.UUID) (*entity.Event, error) {
+	event, err := r.GetEvent(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("GetEventWithDeleted %w", err)
+	}
+	return event, nil
+}
@@ -3,22 +3,29 @@ package usecase
 import (
 	"context"
 	"fmt"
+	"online-registration/internal/common"
+	"online-registration/internal/interview/domain/auth"
 	"online-registration/internal/interview/domain/dto"
 	"online-registration/internal/interview/domain/entity"
+	"online-registration/internal/interview/domain/policy"
 	"online-registration/internal/interview/domain/repository"
+	"online-registration/internal/interview/helper"
 
 	"github.com/rs/zerolog/log"
 )
 
 type CreateEventUseCase struct {
 	repository repository.IEventRepository
+	policy     *policy.Policy
 }
 
 func NewCreateEventUseCase(
 	repository repository.IEventRepository,
+	policy *policy.Policy,
 ) *CreateEventUseCase {
 	return &CreateEventUseCase{
 		repository: repository,
+		policy:     policy,
 	}
 }
 
@@ -26,12 +33,45 @@ func (uc *CreateEventUseCase) CreateEvent(
 	ctx context.Context,
 	requestDTO *dto.CreateEventRequestDTO,
 ) (*entity.Event, error) {
-	event, err := uc.repository.CreateEvent(
-		ctx, requestDTO.Title, requestDTO.Description, requestDTO.StartTime, requestDTO.EndTime,
-	)
+	if err := uc.policy.Authorize(ctx, policy.CreateEvents, ""); err != nil {
+		return nil, err
+	}
+
+	var createdBy string
+	if principal := auth.PrincipalFromContext(ctx); principal != nil {
+		createdBy = principal.Subject
+	}
+
+	status := entity.EventDraft
+	if requestDTO.Publish {
+		if err := uc.policy.Authorize(ctx, policy.PublishEvents, createdBy); err != nil {
+			return nil, err
+		}
+		status = entity.EventPublished
+	}
+
+	event, err := uc.repository.CreateEvent(ctx, &entity.Event{
+		Title:       requestDTO.Title,
+		Description: requestDTO.Description,
+		StartTime:   requestDTO.StartTime,
+		EndTime:     requestDTO.EndTime,
+		TimeZone:    requestDTO.TimeZone,
+		AllDay:      requestDTO.AllDay,
+		Onboarding:  requestDTO.Onboarding,
+		RoomID:      requestDTO.RoomID,
+		CategoryID:  requestDTO.CategoryID,
+		Tags:        requestDTO.Tags,
+		Status:      status,
+		CreatedBy:   createdBy,
+	})
+	if err := bookingError("create event", err); err != nil {
+		return nil, err
+	}
 	if err != nil {
 		log.Error().Msgf("CreateEventUseCase.CreateEvent: %v", err)
-		return nil, fmt.Errorf("create event: %w", err)
+		return nil, common.NewCodedError(
+			helper.InternalError, common.CodeEventCreateFailed, fmt.Errorf("create event: %w", err),
+		)
 	}
 	return event, err
 }
@@ -0,0 +1,48 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"online-registration/internal/common"
+	"online-registration/internal/interview/domain/policy"
+	"online-registration/internal/interview/domain/repository"
+	"online-registration/internal/interview/helper"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+type DeleteEventUseCase struct {
+	repository repository.IEventRepository
+	policy     *policy.Policy
+}
+
+func NewDeleteEventUseCase(
+	repository repository.IEventRepository,
+	policy *policy.Policy,
+) *DeleteEventUseCase {
+	return &DeleteEventUseCase{
+		repository: repository,
+		policy:     policy,
+	}
+}
+
+func (uc *DeleteEventUseCase) DeleteEvent(ctx context.Context, id uuid.UUID) error {
+	if _, err := authorizeEvent(ctx, uc.repository, uc.policy, policy.DeleteEvents, id); err != nil {
+		return err
+	}
+
+	err := uc.repository.DeleteEvent(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return common.NewCodedError(helper.NotFound, common.CodeNotFound, fmt.Errorf("delete event: %w", err))
+	}
+	if err != nil {
+		log.Error().Msgf("DeleteEventUseCase.DeleteEvent: %v", err)
+		return common.NewCodedError(
+			helper.InternalError, common.CodeEventDeleteFailed, fmt.Errorf("delete event: %w", err),
+		)
+	}
+	return nil
+}
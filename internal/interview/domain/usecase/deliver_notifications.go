@@ -0,0 +1,167 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"online-registration/internal/i18n"
+	"online-registration/internal/interview/domain/entity"
+	"online-registration/internal/interview/domain/notification"
+	"online-registration/internal/interview/domain/repository"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// notificationLease must outlive the timeout of sending an email.
+	notificationLease = 2 * time.Minute
+
+	notificationMinBackoff = time.Minute
+	notificationMaxBackoff = 6 * time.Hour
+)
+
+type DeliverNotificationsConfig struct {
+	BatchSize int
+	// MaxAttempts is the number of attempts after which an email is given up.
+	MaxAttempts int
+	// From is the address the emails are sent from, and the organizer of the
+	// attached invitations.
+	From string
+	// Language is the language the emails are written in.
+	Language i18n.Lang
+}
+
+type DeliverNotificationsUseCase struct {
+	repository repository.INotificationRepository
+	events     repository.IEventRepository
+	sender     repository.INotificationSender
+	config     DeliverNotificationsConfig
+}
+
+func NewDeliverNotificationsUseCase(
+	repository repository.INotificationRepository,
+	events repository.IEventRepository,
+	sender repository.INotificationSender,
+	config DeliverNotificationsConfig,
+) *DeliverNotificationsUseCase {
+	return &DeliverNotificationsUseCase{
+		repository: repository,
+		events:     events,
+		sender:     sender,
+		config:     config,
+	}
+}
+
+// Run sends due notifications until ctx is cancelled.
+func (uc *DeliverNotificationsUseCase) Run(ctx context.Context, interval time.Duration) {
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+		}
+
+		sent, err := uc.DeliverBatch(ctx)
+		if err != nil {
+			log.Error().Msgf("DeliverNotificationsUseCase.Run: %v", err)
+		}
+
+		if sent == uc.config.BatchSize {
+			timer.Reset(0)
+			continue
+		}
+		timer.Reset(interval)
+	}
+}
+
+// DeliverBatch sends one batch of due notifications and returns how many
+// were claimed.
+func (uc *DeliverNotificationsUseCase) DeliverBatch(ctx context.Context) (int, error) {
+	notifications, err := uc.repository.ClaimNotifications(ctx, uc.config.BatchSize, notificationLease)
+	if err != nil {
+		return 0, fmt.Errorf("claim notifications: %w", err)
+	}
+
+	for _, notification := range notifications {
+		if err := uc.deliver(ctx, notification); err != nil {
+			return len(notifications), err
+		}
+	}
+
+	return len(notifications), nil
+}
+
+func (uc *DeliverNotificationsUseCase) deliver(ctx context.Context, n *entity.Notification) error {
+	event, err := uc.events.GetEventWithDeleted(ctx, n.EventID)
+	if err != nil {
+		return uc.fail(ctx, n, fmt.Errorf("get event: %w", err))
+	}
+
+	// reminders are only worth sending for events that still take place
+	if n.Kind == entity.NotificationReminder {
+		if reason := staleReminder(event, time.Now()); reason != "" {
+			if err := uc.repository.MarkNotificationCancelled(ctx, n.ID, reason); err != nil {
+				return fmt.Errorf("mark notification cancelled: %w", err)
+			}
+			return nil
+		}
+	}
+
+	email, err := notification.Compose(uc.config.Language, n.Kind, event, uc.config.From, n.Recipient, time.Now())
+	if err != nil {
+		return uc.fail(ctx, n, err)
+	}
+
+	if err := uc.sender.Send(ctx, email); err != nil {
+		return uc.fail(ctx, n, err)
+	}
+
+	if err := uc.repository.MarkNotificationSent(ctx, n.ID); err != nil {
+		return fmt.Errorf("mark notification sent: %w", err)
+	}
+
+	log.Info().
+		Str("notification_id", n.ID.String()).
+		Str("kind", n.Kind).
+		Str("event_id", n.EventID.String()).
+		Msg("Notification sent")
+	return nil
+}
+
+// fail records a failed attempt to send the notification, retrying it later
+// unless it ran out of attempts.
+func (uc *DeliverNotificationsUseCase) fail(ctx context.Context, n *entity.Notification, sendErr error) error {
+	attempts := n.Attempts + 1
+	log.Warn().
+		Str("notification_id", n.ID.String()).
+		Str("kind", n.Kind).
+		Int("attempts", attempts).
+		Msgf("Notification failed: %v", sendErr)
+
+	var nextAttemptAt *time.Time
+	if attempts < uc.config.MaxAttempts {
+		next := time.Now().Add(retryBackoff(attempts, notificationMinBackoff, notificationMaxBackoff))
+		nextAttemptAt = &next
+	}
+
+	if err := uc.repository.MarkNotificationFailed(ctx, n.ID, sendErr.Error(), nextAttemptAt); err != nil {
+		return fmt.Errorf("mark notification failed: %w", err)
+	}
+	return nil
+}
+
+// staleReminder tells why a reminder for event is no longer sent at now,
+// or returns "" when it still is.
+func staleReminder(event *entity.Event, now time.Time) string {
+	switch {
+	case event.Status != entity.EventPublished:
+		return "event is " + event.Status
+	case !event.StartTime.After(now):
+		return "event has started"
+	default:
+		return ""
+	}
+}
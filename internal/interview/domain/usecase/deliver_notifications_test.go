@@ -0,0 +1,375 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"online-registration/internal/i18n"
+	"online-registration/internal/interview/domain/entity"
+	"online-registration/internal/interview/domain/repository"
+	"online-registration/internal/interview/infrastructure/notification"
+	"online-registration/internal/interview/infrastructure/notification/fake"
+
+	"github.com/google/uuid"
+)
+
+// memoryNotifications leases notifications like the database does, by
+// moving the send time of the claimed ones past the lease.
+type memoryNotifications struct {
+	mu            sync.Mutex
+	notifications []*entity.Notification
+}
+
+func (r *memoryNotifications) EnqueueNotification(_ context.Context, n *entity.Notification) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, existing := range r.notifications {
+		if existing.DedupeKey == n.DedupeKey {
+			return false, nil
+		}
+	}
+	stored := *n
+	stored.ID = uuid.New()
+	stored.Status = entity.NotificationPending
+	stored.CreatedAt = time.Now()
+	r.notifications = append(r.notifications, &stored)
+	return true, nil
+}
+
+func (r *memoryNotifications) CancelNotifications(context.Context, uuid.UUID, string) (int, error) {
+	return 0, errors.New("not implemented")
+}
+
+func (r *memoryNotifications) RescheduleReminders(context.Context, uuid.UUID, time.Time) (int, error) {
+	return 0, errors.New("not implemented")
+}
+
+func (r *memoryNotifications) ClaimNotifications(
+	_ context.Context, limit int, lease time.Duration,
+) ([]*entity.Notification, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	var claimed []*entity.Notification
+	for _, n := range r.notifications {
+		if len(claimed) == limit {
+			break
+		}
+		if n.Status != entity.NotificationPending || n.SendAt.After(now) {
+			continue
+		}
+		n.SendAt = now.Add(lease)
+		copied := *n
+		claimed = append(claimed, &copied)
+	}
+	return claimed, nil
+}
+
+func (r *memoryNotifications) update(id uuid.UUID, change func(*entity.Notification)) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, n := range r.notifications {
+		if n.ID == id {
+			change(n)
+			return nil
+		}
+	}
+	return errors.New("no such notification")
+}
+
+func (r *memoryNotifications) MarkNotificationSent(_ context.Context, id uuid.UUID) error {
+	return r.update(id, func(n *entity.Notification) {
+		now := time.Now()
+		n.Status = entity.NotificationSent
+		n.Attempts++
+		n.LastError = ""
+		n.SentAt = &now
+	})
+}
+
+func (r *memoryNotifications) MarkNotificationFailed(
+	_ context.Context, id uuid.UUID, lastError string, nextAttemptAt *time.Time,
+) error {
+	return r.update(id, func(n *entity.Notification) {
+		n.Attempts++
+		n.LastError = lastError
+		if nextAttemptAt != nil {
+			n.SendAt = *nextAttemptAt
+		} else {
+			n.Status = entity.NotificationFailed
+		}
+	})
+}
+
+func (r *memoryNotifications) MarkNotificationCancelled(_ context.Context, id uuid.UUID, reason string) error {
+	return r.update(id, func(n *entity.Notification) {
+		n.Status = entity.NotificationCancelled
+		n.LastError = reason
+	})
+}
+
+// get returns a copy of the notification of dedupeKey.
+func (r *memoryNotifications) get(t *testing.T, dedupeKey string) entity.Notification {
+	t.Helper()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, n := range r.notifications {
+		if n.DedupeKey == dedupeKey {
+			return *n
+		}
+	}
+	t.Fatalf("no notification %s", dedupeKey)
+	return entity.Notification{}
+}
+
+// makeDue lets the retry of every pending notification happen now.
+func (r *memoryNotifications) makeDue() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, n := range r.notifications {
+		n.SendAt = time.Now().Add(-time.Second)
+	}
+}
+
+// notificationEvents serves the events the notifications are about.
+type notificationEvents struct {
+	repository.IEventRepository
+	events map[uuid.UUID]*entity.Event
+}
+
+func (r *notificationEvents) GetEventWithDeleted(_ context.Context, id uuid.UUID) (*entity.Event, error) {
+	event, ok := r.events[id]
+	if !ok {
+		return nil, errors.New("no such event")
+	}
+	return event, nil
+}
+
+// flakySender fails the first sends, then hands the emails to its sender.
+type flakySender struct {
+	sender repository.INotificationSender
+
+	mu       sync.Mutex
+	failures int
+}
+
+func (s *flakySender) Send(ctx context.Context, email *entity.Email) error {
+	s.mu.Lock()
+	fail := s.failures > 0
+	if fail {
+		s.failures--
+	}
+	s.mu.Unlock()
+
+	if fail {
+		return errors.New("mail server unavailable")
+	}
+	return s.sender.Send(ctx, email)
+}
+
+type deliverNotificationsTest struct {
+	server        *fake.Server
+	notifications *memoryNotifications
+	events        *notificationEvents
+	sender        *flakySender
+	deliver       *DeliverNotificationsUseCase
+}
+
+func newDeliverNotificationsTest(t *testing.T, maxAttempts int) *deliverNotificationsTest {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	server := fake.NewServer()
+	go server.Serve(listener)
+	t.Cleanup(func() { server.Close() })
+
+	host, port, _ := net.SplitHostPort(listener.Addr().String())
+	sender := &flakySender{sender: notification.NewSMTPSender(
+		notification.SMTPConfig{Host: host, Port: port, Timeout: 5 * time.Second},
+		"registration@example.com",
+	)}
+
+	s := &deliverNotificationsTest{
+		server:        server,
+		notifications: &memoryNotifications{},
+		events:        &notificationEvents{events: make(map[uuid.UUID]*entity.Event)},
+		sender:        sender,
+	}
+	s.deliver = NewDeliverNotificationsUseCase(s.notifications, s.events, sender, DeliverNotificationsConfig{
+		BatchSize:   10,
+		MaxAttempts: maxAttempts,
+		From:        "registration@example.com",
+		Language:    i18n.English,
+	})
+	return s
+}
+
+func (s *deliverNotificationsTest) addEvent(status string) *entity.Event {
+	start := time.Now().Add(24 * time.Hour).Truncate(time.Minute)
+	event := &entity.Event{
+		ID:        uuid.New(),
+		TenantID:  "acme",
+		Title:     "Onboarding day",
+		StartTime: start,
+		EndTime:   start.Add(2 * time.Hour),
+		TimeZone:  "UTC",
+		Status:    status,
+	}
+	s.events.events[event.ID] = event
+	return event
+}
+
+func (s *deliverNotificationsTest) enqueue(t *testing.T, event *entity.Event, kind, recipient string) string {
+	t.Helper()
+
+	dedupeKey := kind + ":" + recipient
+	_, err := s.notifications.EnqueueNotification(context.Background(), &entity.Notification{
+		TenantID:       event.TenantID,
+		Kind:           kind,
+		RegistrationID: uuid.New(),
+		EventID:        event.ID,
+		Recipient:      recipient,
+		DedupeKey:      dedupeKey,
+		SendAt:         time.Now().Add(-time.Second),
+	})
+	if err != nil {
+		t.Fatalf("EnqueueNotification: %v", err)
+	}
+	return dedupeKey
+}
+
+func (s *deliverNotificationsTest) deliverBatch(t *testing.T, want int) {
+	t.Helper()
+
+	claimed, err := s.deliver.DeliverBatch(context.Background())
+	if err != nil {
+		t.Fatalf("DeliverBatch: %v", err)
+	}
+	if claimed != want {
+		t.Fatalf("DeliverBatch claimed %d notifications, want %d", claimed, want)
+	}
+}
+
+func TestDeliverNotifications(t *testing.T) {
+	s := newDeliverNotificationsTest(t, 3)
+	event := s.addEvent(entity.EventPublished)
+	confirmation := s.enqueue(t, event, entity.NotificationConfirmation, "alice@example.com")
+	promotion := s.enqueue(t, event, entity.NotificationWaitlistPromotion, "bob@example.com")
+
+	s.deliverBatch(t, 2)
+	for _, key := range []string{confirmation, promotion} {
+		if n := s.notifications.get(t, key); n.Status != entity.NotificationSent || n.Attempts != 1 {
+			t.Errorf("notification %s is %s after %d attempts, want sent", key, n.Status, n.Attempts)
+		}
+	}
+
+	messages := s.server.Messages()
+	if len(messages) != 2 {
+		t.Fatalf("server received %d messages, want 2", len(messages))
+	}
+	for i, to := range []string{"alice@example.com", "bob@example.com"} {
+		if len(messages[i].To) != 1 || messages[i].To[0] != to {
+			t.Errorf("message %d went to %v, want %s", i, messages[i].To, to)
+		}
+		if !strings.Contains(string(messages[i].Data), "invite.ics") {
+			t.Errorf("message %d has no invitation attached", i)
+		}
+	}
+
+	// sent notifications are not claimed again
+	s.deliverBatch(t, 0)
+}
+
+func TestDeliverNotificationsRetries(t *testing.T) {
+	s := newDeliverNotificationsTest(t, 3)
+	event := s.addEvent(entity.EventPublished)
+	key := s.enqueue(t, event, entity.NotificationConfirmation, "alice@example.com")
+	s.sender.failures = 2
+
+	s.deliverBatch(t, 1)
+	n := s.notifications.get(t, key)
+	if n.Status != entity.NotificationPending || n.Attempts != 1 || n.LastError == "" {
+		t.Fatalf("failed notification is %s after %d attempts (%q), want pending", n.Status, n.Attempts, n.LastError)
+	}
+	if !n.SendAt.After(time.Now().Add(notificationMinBackoff / 2)) {
+		t.Errorf("failed notification is retried at %s, want it backed off", n.SendAt)
+	}
+
+	// not due before its backoff passed
+	s.deliverBatch(t, 0)
+
+	s.notifications.makeDue()
+	s.deliverBatch(t, 1)
+	s.notifications.makeDue()
+	s.deliverBatch(t, 1)
+
+	if n := s.notifications.get(t, key); n.Status != entity.NotificationSent || n.Attempts != 3 {
+		t.Errorf("notification is %s after %d attempts, want sent after 3", n.Status, n.Attempts)
+	}
+	if len(s.server.Messages()) != 1 {
+		t.Errorf("server received %d messages, want 1", len(s.server.Messages()))
+	}
+}
+
+func TestDeliverNotificationsGivesUp(t *testing.T) {
+	s := newDeliverNotificationsTest(t, 2)
+	event := s.addEvent(entity.EventPublished)
+	key := s.enqueue(t, event, entity.NotificationConfirmation, "alice@example.com")
+	s.sender.failures = 2
+
+	s.deliverBatch(t, 1)
+	s.notifications.makeDue()
+	s.deliverBatch(t, 1)
+
+	if n := s.notifications.get(t, key); n.Status != entity.NotificationFailed || n.Attempts != 2 {
+		t.Errorf("notification is %s after %d attempts, want failed after 2", n.Status, n.Attempts)
+	}
+	s.notifications.makeDue()
+	s.deliverBatch(t, 0)
+}
+
+func TestDeliverNotificationsHidesClaimedNotifications(t *testing.T) {
+	s := newDeliverNotificationsTest(t, 3)
+	event := s.addEvent(entity.EventPublished)
+	s.enqueue(t, event, entity.NotificationConfirmation, "alice@example.com")
+
+	// another worker holds the lease
+	claimed, err := s.notifications.ClaimNotifications(context.Background(), 10, notificationLease)
+	if err != nil || len(claimed) != 1 {
+		t.Fatalf("ClaimNotifications = %d, %v", len(claimed), err)
+	}
+	s.deliverBatch(t, 0)
+
+	// and lost it
+	s.notifications.makeDue()
+	s.deliverBatch(t, 1)
+	if len(s.server.Messages()) != 1 {
+		t.Errorf("server received %d messages, want 1", len(s.server.Messages()))
+	}
+}
+
+func TestDeliverNotificationsDropsStaleReminders(t *testing.T) {
+	s := newDeliverNotificationsTest(t, 3)
+	event := s.addEvent(entity.EventCancelled)
+	key := s.enqueue(t, event, entity.NotificationReminder, "alice@example.com")
+
+	s.deliverBatch(t, 1)
+	if n := s.notifications.get(t, key); n.Status != entity.NotificationCancelled {
+		t.Errorf("reminder of a cancelled event is %s, want cancelled", n.Status)
+	}
+	if len(s.server.Messages()) != 0 {
+		t.Errorf("server received %d messages, want none", len(s.server.Messages()))
+	}
+}
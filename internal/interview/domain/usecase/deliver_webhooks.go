@@ -0,0 +1,148 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"online-registration/internal/interview/domain/entity"
+	"online-registration/internal/interview/domain/repository"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// webhookLeaseMargin is how long a delivery stays leased after the HTTP
+	// timeout of its attempt.
+	webhookLeaseMargin = time.Minute
+
+	webhookMinBackoff = 30 * time.Second
+	webhookMaxBackoff = 6 * time.Hour
+)
+
+type DeliverWebhooksConfig struct {
+	BatchSize int
+	// Timeout is the HTTP timeout of a delivery attempt.
+	Timeout time.Duration
+	// MaxAttempts is the number of attempts after which a delivery is given up.
+	MaxAttempts int
+	// DisableAfter is the number of consecutive failed attempts after which a
+	// subscription is disabled.
+	DisableAfter int
+}
+
+type DeliverWebhooksUseCase struct {
+	repository repository.IWebhookRepository
+	sender     repository.IWebhookSender
+	config     DeliverWebhooksConfig
+}
+
+func NewDeliverWebhooksUseCase(
+	repository repository.IWebhookRepository,
+	sender repository.IWebhookSender,
+	config DeliverWebhooksConfig,
+) *DeliverWebhooksUseCase {
+	return &DeliverWebhooksUseCase{
+		repository: repository,
+		sender:     sender,
+		config:     config,
+	}
+}
+
+// Run sends due deliveries until ctx is cancelled.
+func (uc *DeliverWebhooksUseCase) Run(ctx context.Context, interval time.Duration) {
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+		}
+
+		delivered, err := uc.DeliverBatch(ctx)
+		if err != nil {
+			log.Error().Msgf("DeliverWebhooksUseCase.Run: %v", err)
+		}
+
+		if delivered == uc.config.BatchSize {
+			timer.Reset(0)
+			continue
+		}
+		timer.Reset(interval)
+	}
+}
+
+// DeliverBatch sends one batch of due deliveries and returns how many were
+// claimed. The batch is claimed under the lease of a single attempt, which is
+// renewed right before each delivery is sent. A delivery whose lease ran out
+// while the ones before it were sent is left to the worker that took it over.
+func (uc *DeliverWebhooksUseCase) DeliverBatch(ctx context.Context) (int, error) {
+	lease := uc.config.Timeout + webhookLeaseMargin
+	deliveries, err := uc.repository.ClaimDeliveries(ctx, uc.config.BatchSize, lease)
+	if err != nil {
+		return 0, fmt.Errorf("claim webhook deliveries: %w", err)
+	}
+
+	for _, delivery := range deliveries {
+		held, err := uc.repository.RenewDeliveryLease(ctx, delivery, lease)
+		if err != nil {
+			return len(deliveries), fmt.Errorf("renew webhook delivery lease: %w", err)
+		}
+		if !held {
+			log.Debug().Str("delivery_id", delivery.ID.String()).Msg("Webhook delivery lease lost, skipping")
+			continue
+		}
+
+		if err := uc.deliver(ctx, delivery); err != nil {
+			return len(deliveries), err
+		}
+	}
+
+	return len(deliveries), nil
+}
+
+func (uc *DeliverWebhooksUseCase) deliver(ctx context.Context, delivery *entity.WebhookDelivery) error {
+	subscription := delivery.Subscription
+
+	statusCode, sendErr := uc.sender.Send(
+		ctx, subscription.URL, subscription.Secret, delivery.ID.String(), delivery.EventType, delivery.Payload,
+	)
+	if sendErr == nil {
+		if err := uc.repository.MarkDeliverySucceeded(ctx, delivery, statusCode); err != nil {
+			return fmt.Errorf("mark webhook delivery succeeded: %w", err)
+		}
+		return nil
+	}
+
+	attempts := delivery.Attempts + 1
+	log.Warn().
+		Str("delivery_id", delivery.ID.String()).
+		Str("url", subscription.URL).
+		Int("attempts", attempts).
+		Msgf("Webhook delivery failed: %v", sendErr)
+
+	var nextAttemptAt *time.Time
+	if attempts < uc.config.MaxAttempts {
+		next := time.Now().Add(retryBackoff(attempts, webhookMinBackoff, webhookMaxBackoff))
+		nextAttemptAt = &next
+	}
+
+	err := uc.repository.MarkDeliveryFailed(
+		ctx, delivery, statusCode, sendErr.Error(), nextAttemptAt, uc.config.DisableAfter,
+	)
+	if err != nil {
+		return fmt.Errorf("mark webhook delivery failed: %w", err)
+	}
+	return nil
+}
+
+// retryBackoff doubles the delay before the next attempt with every failed
+// one, from minBackoff up to maxBackoff.
+func retryBackoff(attempts int, minBackoff, maxBackoff time.Duration) time.Duration {
+	backoff := minBackoff
+	for i := 1; i < attempts && backoff < maxBackoff; i++ {
+		backoff *= 2
+	}
+	return min(backoff, maxBackoff)
+}
@@ -0,0 +1,53 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"online-registration/internal/interview/domain/dto"
+	"online-registration/internal/interview/domain/repository"
+
+	"github.com/rs/zerolog/log"
+)
+
+// DispatchWebhooksUseCase turns event lifecycle messages into pending
+// deliveries for the subscriptions interested in them.
+type DispatchWebhooksUseCase struct {
+	repository    repository.IWebhookRepository
+	defaultTenant string
+}
+
+// NewDispatchWebhooksUseCase creates the dispatcher. Messages written before
+// events had tenants are dispatched to the subscriptions of defaultTenant.
+func NewDispatchWebhooksUseCase(
+	repository repository.IWebhookRepository,
+	defaultTenant string,
+) *DispatchWebhooksUseCase {
+	return &DispatchWebhooksUseCase{
+		repository:    repository,
+		defaultTenant: defaultTenant,
+	}
+}
+
+func (uc *DispatchWebhooksUseCase) Dispatch(ctx context.Context, subject string, data []byte) error {
+	var message dto.EventMessage
+	if err := json.Unmarshal(data, &message); err != nil {
+		log.Error().Str("subject", subject).Msgf("DispatchWebhooksUseCase.Dispatch: skipping malformed message: %v", err)
+		return nil
+	}
+
+	tenantID := message.Event.TenantID
+	if tenantID == "" {
+		tenantID = uc.defaultTenant
+	}
+
+	enqueued, err := uc.repository.EnqueueDeliveries(ctx, tenantID, message.ID, subject, data)
+	if err != nil {
+		return fmt.Errorf("enqueue webhook deliveries: %w", err)
+	}
+
+	if enqueued > 0 {
+		log.Debug().Str("subject", subject).Int("deliveries", enqueued).Msg("Webhook deliveries enqueued")
+	}
+	return nil
+}
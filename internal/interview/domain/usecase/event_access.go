@@ -0,0 +1,62 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"online-registration/internal/common"
+	"online-registration/internal/interview/domain/entity"
+	"online-registration/internal/interview/domain/policy"
+	"online-registration/internal/interview/domain/repository"
+	"online-registration/internal/interview/helper"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+// authorizeEvent loads the event and checks that the principal of ctx may
+// perform action on it.
+func authorizeEvent(
+	ctx context.Context,
+	repository repository.IEventRepository,
+	eventPolicy *policy.Policy,
+	action policy.Action,
+	id uuid.UUID,
+) (*entity.Event, error) {
+	event, err := repository.GetEvent(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, common.NewCodedError(helper.NotFound, common.CodeNotFound, fmt.Errorf("%s: %w", action, err))
+	}
+	if err != nil {
+		log.Error().Msgf("authorizeEvent: %v", err)
+		return nil, common.NewCodedError(helper.InternalError, common.CodeInternalError, fmt.Errorf("%s: %w", action, err))
+	}
+
+	if err := eventPolicy.Authorize(ctx, action, event.CreatedBy); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+// bookingError maps the errors of booking an event into a room, or putting
+// it in a category, to their responses, or returns nil for other errors.
+func bookingError(operation string, err error) error {
+	if errors.Is(err, repository.ErrRoomNotFound) {
+		return common.NewCodedError(helper.InvalidArgument, common.CodeUnknownRoom, fmt.Errorf("%s: %w", operation, err))
+	}
+	if errors.Is(err, repository.ErrCategoryNotFound) {
+		return common.NewCodedError(
+			helper.InvalidArgument, common.CodeUnknownCategory, fmt.Errorf("%s: %w", operation, err),
+		)
+	}
+
+	var conflict *repository.RoomConflictError
+	if errors.As(err, &conflict) {
+		return common.NewCodedError(
+			helper.AlreadyExists, common.CodeRoomAlreadyBooked, fmt.Errorf("%s: %w", operation, err),
+			conflict.Title, conflict.EventID,
+		)
+	}
+	return nil
+}
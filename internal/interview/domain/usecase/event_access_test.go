@@ -0,0 +1,160 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"online-registration/internal/common"
+	"online-registration/internal/interview/domain/auth"
+	"online-registration/internal/interview/domain/dto"
+	"online-registration/internal/interview/domain/entity"
+	"online-registration/internal/interview/domain/policy"
+	"online-registration/internal/interview/domain/repository"
+	"online-registration/internal/interview/helper"
+
+	"github.com/google/uuid"
+)
+
+// ownedEvents serves an event created by bob and records the changes made
+// to it.
+type ownedEvents struct {
+	repository.IEventRepository
+	event   *entity.Event
+	updated bool
+	deleted bool
+}
+
+func newOwnedEvents() *ownedEvents {
+	start := time.Now().Add(24 * time.Hour)
+	return &ownedEvents{event: &entity.Event{
+		ID: uuid.New(), Title: "Onboarding day", StartTime: start, EndTime: start.Add(time.Hour),
+		TimeZone: "UTC", Status: entity.EventDraft, CreatedBy: "bob",
+	}}
+}
+
+func (r *ownedEvents) GetEvent(_ context.Context, id uuid.UUID) (*entity.Event, error) {
+	if id != r.event.ID {
+		return nil, sql.ErrNoRows
+	}
+	return r.event, nil
+}
+
+func (r *ownedEvents) UpdateEvent(_ context.Context, event *entity.Event) (*entity.Event, error) {
+	r.updated = true
+	return event, nil
+}
+
+func (r *ownedEvents) DeleteEvent(context.Context, uuid.UUID) error {
+	r.deleted = true
+	return nil
+}
+
+func TestEventAccessUnderOwnership(t *testing.T) {
+	accessPolicy, err := policy.Load("")
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		principal *auth.Principal
+		wantCode  string
+	}{
+		{"owner", &auth.Principal{Subject: "bob", Roles: []string{policy.RoleOrganizer}}, ""},
+		{"another organizer", &auth.Principal{Subject: "alice", Roles: []string{policy.RoleOrganizer}}, common.CodeForbidden},
+		{"viewer", &auth.Principal{Subject: "bob", Roles: []string{policy.RoleViewer}}, common.CodeForbidden},
+		{"admin", &auth.Principal{Subject: "root", Roles: []string{policy.RoleAdmin}}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := auth.ContextWithPrincipal(context.Background(), tt.principal)
+			events := newOwnedEvents()
+
+			_, updateErr := NewUpdateEventUseCase(events, accessPolicy).UpdateEvent(ctx, &dto.UpdateEventRequestDTO{
+				ID: events.event.ID, Title: "Renamed", StartTime: events.event.StartTime, EndTime: events.event.EndTime,
+				TimeZone: "UTC",
+			})
+			deleteErr := NewDeleteEventUseCase(events, accessPolicy).DeleteEvent(ctx, events.event.ID)
+
+			for operation, err := range map[string]error{"update": updateErr, "delete": deleteErr} {
+				if tt.wantCode == "" {
+					if err != nil {
+						t.Errorf("%s = %v, want allowed", operation, err)
+					}
+					continue
+				}
+				var processingErr *common.ProcessingError
+				if !errors.As(err, &processingErr) || processingErr.Code != tt.wantCode {
+					t.Errorf("%s = %v, want %s", operation, err, tt.wantCode)
+				}
+			}
+
+			if allowed := tt.wantCode == ""; events.updated != allowed || events.deleted != allowed {
+				t.Errorf("event updated %v, deleted %v, want %v", events.updated, events.deleted, allowed)
+			}
+		})
+	}
+}
+
+func TestEventAccessOfMissingEvent(t *testing.T) {
+	accessPolicy, err := policy.Load("")
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	ctx := auth.ContextWithPrincipal(context.Background(), &auth.Principal{Subject: "root", Roles: []string{policy.RoleAdmin}})
+
+	err = NewDeleteEventUseCase(newOwnedEvents(), accessPolicy).DeleteEvent(ctx, uuid.New())
+	var processingErr *common.ProcessingError
+	if !errors.As(err, &processingErr) || processingErr.Code != common.CodeNotFound {
+		t.Errorf("DeleteEvent of a missing event = %v, want %s", err, common.CodeNotFound)
+	}
+}
+
+func TestBookingError(t *testing.T) {
+	booked := uuid.New()
+	conflict := &repository.RoomConflictError{RoomID: uuid.New(), EventID: booked, Title: "Interview"}
+
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus uint8
+		wantCode   string
+		wantArgs   []any
+	}{
+		{"room conflict", fmt.Errorf("UpdateEvent %w", conflict), helper.AlreadyExists, common.CodeRoomAlreadyBooked,
+			[]any{"Interview", booked}},
+		{"missing room", fmt.Errorf("UpdateEvent %w", repository.ErrRoomNotFound), helper.InvalidArgument,
+			common.CodeUnknownRoom, nil},
+		{"missing category", fmt.Errorf("UpdateEvent %w", repository.ErrCategoryNotFound), helper.InvalidArgument,
+			common.CodeUnknownCategory, nil},
+		{"other error", errors.New("connection reset"), 0, "", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := bookingError("update event", tt.err)
+			if tt.wantCode == "" {
+				if err != nil {
+					t.Errorf("bookingError = %v, want nil", err)
+				}
+				return
+			}
+
+			var processingErr *common.ProcessingError
+			if !errors.As(err, &processingErr) {
+				t.Fatalf("bookingError = %v, want a processing error", err)
+			}
+			if processingErr.Status != tt.wantStatus || processingErr.Code != tt.wantCode ||
+				fmt.Sprint(processingErr.Args) != fmt.Sprint(tt.wantArgs) {
+				t.Errorf("bookingError = %d %s %v, want %d %s %v", processingErr.Status, processingErr.Code,
+					processingErr.Args, tt.wantStatus, tt.wantCode, tt.wantArgs)
+			}
+			if !errors.Is(err, tt.err) {
+				t.Errorf("bookingError doesn't wrap %v", tt.err)
+			}
+		})
+	}
+}
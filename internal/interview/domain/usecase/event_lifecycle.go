@@ -0,0 +1,116 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"online-registration/internal/common"
+	"online-registration/internal/interview/domain/entity"
+	"online-registration/internal/interview/domain/policy"
+	"online-registration/internal/interview/domain/repository"
+	"online-registration/internal/interview/helper"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+// EventLifecycleUseCase moves events through their statuses. Organizers
+// publish and cancel events, completing them is left to CompleteEnded.
+type EventLifecycleUseCase struct {
+	repository repository.IEventRepository
+	policy     *policy.Policy
+	batchSize  int
+}
+
+func NewEventLifecycleUseCase(
+	repository repository.IEventRepository,
+	policy *policy.Policy,
+	batchSize int,
+) *EventLifecycleUseCase {
+	return &EventLifecycleUseCase{
+		repository: repository,
+		policy:     policy,
+		batchSize:  batchSize,
+	}
+}
+
+// Publish opens a draft for registration.
+func (uc *EventLifecycleUseCase) Publish(ctx context.Context, id uuid.UUID) (*entity.Event, error) {
+	if _, err := authorizeEvent(ctx, uc.repository, uc.policy, policy.PublishEvents, id); err != nil {
+		return nil, err
+	}
+
+	return uc.transition(ctx, id, entity.EventPublished, "")
+}
+
+// Cancel cancels a draft or published event for reason. The registrants are
+// notified through the registration messages.
+func (uc *EventLifecycleUseCase) Cancel(ctx context.Context, id uuid.UUID, reason string) (*entity.Event, error) {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return nil, common.NewCodedError(
+			helper.InvalidArgument, common.CodeCancellationReasonMissing, errors.New("cancellation reason is empty"),
+		)
+	}
+
+	if _, err := authorizeEvent(ctx, uc.repository, uc.policy, policy.CancelEvents, id); err != nil {
+		return nil, err
+	}
+
+	return uc.transition(ctx, id, entity.EventCancelled, reason)
+}
+
+func (uc *EventLifecycleUseCase) transition(
+	ctx context.Context, id uuid.UUID, to string, reason string,
+) (*entity.Event, error) {
+	event, err := uc.repository.TransitionEvent(ctx, id, to, reason)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, common.NewCodedError(helper.NotFound, common.CodeNotFound, fmt.Errorf("%s event: %w", to, err))
+	}
+
+	var transitionErr *repository.EventTransitionError
+	if errors.As(err, &transitionErr) {
+		return nil, common.NewCodedError(
+			helper.FailedPrecondition, common.CodeEventTransitionNotAllowed, fmt.Errorf("%s event: %w", to, err),
+			transitionErr.From, transitionErr.To,
+		)
+	}
+	if err != nil {
+		log.Error().Msgf("EventLifecycleUseCase.transition: %v", err)
+		return nil, common.NewCodedError(
+			helper.InternalError, common.CodeEventUpdateFailed, fmt.Errorf("%s event: %w", to, err),
+		)
+	}
+	return event, nil
+}
+
+// CompleteEnded marks the published events that are over as completed, a
+// batch at a time, and returns how many it marked. ctx must carry a system
+// principal and lift the tenant scope.
+func (uc *EventLifecycleUseCase) CompleteEnded(ctx context.Context) (int, error) {
+	total := 0
+	for {
+		completed, err := uc.CompleteBatch(ctx)
+		total += completed
+		if err != nil || completed == 0 || completed < uc.batchSize {
+			return total, err
+		}
+	}
+}
+
+// CompleteBatch marks one batch of published events that are over as
+// completed and returns how many it marked.
+func (uc *EventLifecycleUseCase) CompleteBatch(ctx context.Context) (int, error) {
+	events, err := uc.repository.CompleteEvents(ctx, time.Now(), uc.batchSize)
+	if err != nil {
+		return 0, fmt.Errorf("complete events: %w", err)
+	}
+
+	if len(events) > 0 {
+		log.Info().Int("events", len(events)).Msg("Ended events completed")
+	}
+	return len(events), nil
+}
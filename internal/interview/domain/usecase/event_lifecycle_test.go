@@ -0,0 +1,165 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"online-registration/internal/common"
+	"online-registration/internal/interview/domain/auth"
+	"online-registration/internal/interview/domain/entity"
+	"online-registration/internal/interview/domain/policy"
+	"online-registration/internal/interview/domain/repository"
+
+	"github.com/google/uuid"
+)
+
+// lifecycleEvents moves the event of ownedEvents through its statuses like
+// the repository does, and completes ended events in batches.
+type lifecycleEvents struct {
+	*ownedEvents
+	ended   int
+	batches []int
+	err     error
+}
+
+func (r *lifecycleEvents) TransitionEvent(ctx context.Context, id uuid.UUID, to string, reason string) (*entity.Event, error) {
+	event, err := r.GetEvent(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !entity.CanTransitionEvent(event.Status, to) {
+		return nil, &repository.EventTransitionError{From: event.Status, To: to}
+	}
+	event.Status = to
+	if to == entity.EventCancelled {
+		event.CancellationReason = reason
+	}
+	return event, nil
+}
+
+func (r *lifecycleEvents) CompleteEvents(_ context.Context, _ time.Time, limit int) ([]*entity.Event, error) {
+	r.batches = append(r.batches, limit)
+	if r.err != nil {
+		return nil, r.err
+	}
+
+	completed := make([]*entity.Event, min(r.ended, limit))
+	r.ended -= len(completed)
+	return completed, nil
+}
+
+func lifecycleContext(subject, role string) context.Context {
+	return auth.ContextWithPrincipal(context.Background(), &auth.Principal{Subject: subject, Roles: []string{role}})
+}
+
+func TestEventLifecycleTransitions(t *testing.T) {
+	accessPolicy, err := policy.Load("")
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	owner := lifecycleContext("bob", policy.RoleOrganizer)
+
+	tests := []struct {
+		name       string
+		status     string
+		ctx        context.Context
+		transition func(context.Context, *EventLifecycleUseCase, uuid.UUID) (*entity.Event, error)
+		wantStatus string
+		wantCode   string
+	}{
+		{"publish", entity.EventDraft, owner, publishEvent, entity.EventPublished, ""},
+		{"cancel draft", entity.EventDraft, owner, cancelEvent("snow"), entity.EventCancelled, ""},
+		{"cancel published", entity.EventPublished, owner, cancelEvent("snow"), entity.EventCancelled, ""},
+		{"publish again", entity.EventPublished, owner, publishEvent, "", common.CodeEventTransitionNotAllowed},
+		{"publish cancelled", entity.EventCancelled, owner, publishEvent, "", common.CodeEventTransitionNotAllowed},
+		{"cancel completed", entity.EventCompleted, owner, cancelEvent("snow"), "", common.CodeEventTransitionNotAllowed},
+		{"cancel without reason", entity.EventPublished, owner, cancelEvent(" "), "", common.CodeCancellationReasonMissing},
+		{"another organizer", entity.EventDraft, lifecycleContext("alice", policy.RoleOrganizer), publishEvent, "",
+			common.CodeForbidden},
+		{"admin", entity.EventDraft, lifecycleContext("root", policy.RoleAdmin), publishEvent, entity.EventPublished, ""},
+		{"viewer", entity.EventPublished, lifecycleContext("bob", policy.RoleViewer), cancelEvent("snow"), "",
+			common.CodeForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			events := &lifecycleEvents{ownedEvents: newOwnedEvents()}
+			events.event.Status = tt.status
+			uc := NewEventLifecycleUseCase(events, accessPolicy, 10)
+
+			event, err := tt.transition(tt.ctx, uc, events.event.ID)
+			if tt.wantCode != "" {
+				var processingErr *common.ProcessingError
+				if !errors.As(err, &processingErr) || processingErr.Code != tt.wantCode {
+					t.Fatalf("error = %v, want %s", err, tt.wantCode)
+				}
+				if events.event.Status != tt.status {
+					t.Errorf("status changed to %s", events.event.Status)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("transition: %v", err)
+			}
+			if event.Status != tt.wantStatus {
+				t.Errorf("status = %s, want %s", event.Status, tt.wantStatus)
+			}
+			if tt.wantStatus == entity.EventCancelled && event.CancellationReason != "snow" {
+				t.Errorf("cancellation reason = %q, want snow", event.CancellationReason)
+			}
+		})
+	}
+}
+
+func publishEvent(ctx context.Context, uc *EventLifecycleUseCase, id uuid.UUID) (*entity.Event, error) {
+	return uc.Publish(ctx, id)
+}
+
+func cancelEvent(reason string) func(context.Context, *EventLifecycleUseCase, uuid.UUID) (*entity.Event, error) {
+	return func(ctx context.Context, uc *EventLifecycleUseCase, id uuid.UUID) (*entity.Event, error) {
+		return uc.Cancel(ctx, id, reason)
+	}
+}
+
+func TestEventLifecycleCompleteEnded(t *testing.T) {
+	tests := []struct {
+		name        string
+		ended       int
+		wantBatches int
+	}{
+		{"none", 0, 1},
+		{"one batch", 3, 1},
+		{"full batch", 10, 2},
+		{"batches", 25, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			events := &lifecycleEvents{ownedEvents: newOwnedEvents(), ended: tt.ended}
+			uc := NewEventLifecycleUseCase(events, nil, 10)
+
+			completed, err := uc.CompleteEnded(context.Background())
+			if err != nil {
+				t.Fatalf("CompleteEnded: %v", err)
+			}
+			if completed != tt.ended || len(events.batches) != tt.wantBatches {
+				t.Errorf("CompleteEnded completed %d events in %d batches, want %d in %d",
+					completed, len(events.batches), tt.ended, tt.wantBatches)
+			}
+			for _, limit := range events.batches {
+				if limit != 10 {
+					t.Errorf("batch of %d events, want 10", limit)
+				}
+			}
+		})
+	}
+
+	errDown := errors.New("database is down")
+	events := &lifecycleEvents{ownedEvents: newOwnedEvents(), ended: 25, err: errDown}
+	if _, err := NewEventLifecycleUseCase(events, nil, 10).CompleteEnded(context.Background()); !errors.Is(err, errDown) {
+		t.Errorf("CompleteEnded error = %v, want %v", err, errDown)
+	}
+	if len(events.batches) != 1 {
+		t.Errorf("CompleteEnded went on for %d batches after an error", len(events.batches))
+	}
+}
@@ -0,0 +1,127 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"online-registration/internal/common"
+	"online-registration/internal/interview/domain/entity"
+	"online-registration/internal/interview/domain/policy"
+	"online-registration/internal/interview/domain/repository"
+	"online-registration/internal/interview/helper"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+const maxParticipantUserIDLength = 255
+
+// EventParticipantsUseCase manages the organizers and interviewers of
+// events. Participants are changed by those who may update the event.
+type EventParticipantsUseCase struct {
+	eventRepository       repository.IEventRepository
+	participantRepository repository.IParticipantRepository
+	policy                *policy.Policy
+}
+
+func NewEventParticipantsUseCase(
+	eventRepository repository.IEventRepository,
+	participantRepository repository.IParticipantRepository,
+	policy *policy.Policy,
+) *EventParticipantsUseCase {
+	return &EventParticipantsUseCase{
+		eventRepository:       eventRepository,
+		participantRepository: participantRepository,
+		policy:                policy,
+	}
+}
+
+func (uc *EventParticipantsUseCase) SetParticipant(
+	ctx context.Context, eventID uuid.UUID, userID string, role string,
+) (*entity.Participant, error) {
+	event, err := authorizeEvent(ctx, uc.eventRepository, uc.policy, policy.UpdateEvents, eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validateParticipant(userID, role); err != nil {
+		return nil, err
+	}
+
+	participant, err := uc.participantRepository.SetParticipant(ctx, eventID, userID, role)
+	if err != nil {
+		return nil, participantStorageError("set participant", err, event)
+	}
+	return participant, nil
+}
+
+func (uc *EventParticipantsUseCase) RemoveParticipant(ctx context.Context, eventID uuid.UUID, userID string) error {
+	event, err := authorizeEvent(ctx, uc.eventRepository, uc.policy, policy.UpdateEvents, eventID)
+	if err != nil {
+		return err
+	}
+
+	if err := uc.participantRepository.RemoveParticipant(ctx, eventID, userID); err != nil {
+		return participantStorageError("remove participant", err, event)
+	}
+	return nil
+}
+
+// ListParticipants follows the visibility of the event, the participants of
+// drafts are only shown to those who may edit them.
+func (uc *EventParticipantsUseCase) ListParticipants(
+	ctx context.Context, eventID uuid.UUID,
+) ([]*entity.Participant, error) {
+	event, err := authorizeEvent(ctx, uc.eventRepository, uc.policy, policy.ReadEvents, eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	if event.Status == entity.EventDraft &&
+		uc.policy.Authorize(ctx, policy.UpdateEvents, event.CreatedBy) != nil {
+		return nil, common.NewCodedError(
+			helper.NotFound, common.CodeNotFound, fmt.Errorf("list participants: draft %s is not visible", eventID),
+		)
+	}
+
+	participants, err := uc.participantRepository.ListParticipants(ctx, eventID)
+	if err != nil {
+		return nil, participantStorageError("list participants", err, event)
+	}
+	return participants, nil
+}
+
+func validateParticipant(userID string, role string) error {
+	if strings.TrimSpace(userID) == "" || len(userID) > maxParticipantUserIDLength {
+		return common.NewCodedError(
+			helper.InvalidArgument, common.CodeInvalidParticipant, fmt.Errorf("invalid participant %q", userID),
+			maxParticipantUserIDLength,
+		)
+	}
+
+	if !slices.Contains(entity.ParticipantRoles, role) {
+		return common.NewCodedError(
+			helper.InvalidArgument, common.CodeInvalidParticipantRole,
+			fmt.Errorf("invalid participant role %q", role),
+			strings.Join(entity.ParticipantRoles, ", "),
+		)
+	}
+
+	return nil
+}
+
+func participantStorageError(operation string, err error, event *entity.Event) error {
+	wrapped := fmt.Errorf("%s: %w", operation, err)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return common.NewCodedError(helper.NotFound, common.CodeNotFound, wrapped)
+	case errors.Is(err, repository.ErrEventNotEditable):
+		return common.NewCodedError(helper.FailedPrecondition, common.CodeEventNotEditable, wrapped, event.Status)
+	}
+
+	log.Error().Msgf("EventParticipantsUseCase: %s: %v", operation, err)
+	return common.NewCodedError(helper.InternalError, common.CodeInternalError, wrapped)
+}
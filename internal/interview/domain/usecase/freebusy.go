@@ -0,0 +1,147 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"online-registration/internal/common"
+	"online-registration/internal/interview/domain/dto"
+	"online-registration/internal/interview/domain/entity"
+	"online-registration/internal/interview/domain/policy"
+	"online-registration/internal/interview/domain/repository"
+	"online-registration/internal/interview/helper"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	maxFreeBusyResources = 50
+	maxFreeBusyDays      = 62
+	maxFreeBusyWindow    = maxFreeBusyDays * 24 * time.Hour
+)
+
+// FreeBusyUseCase tells when people and rooms are busy, to find a time
+// everyone can make. Only the busy periods are shown, not the events.
+type FreeBusyUseCase struct {
+	repository repository.IParticipantRepository
+	policy     *policy.Policy
+}
+
+func NewFreeBusyUseCase(
+	repository repository.IParticipantRepository,
+	policy *policy.Policy,
+) *FreeBusyUseCase {
+	return &FreeBusyUseCase{
+		repository: repository,
+		policy:     policy,
+	}
+}
+
+func (uc *FreeBusyUseCase) FreeBusy(ctx context.Context, requestDTO *dto.FreeBusyRequestDTO) (*entity.FreeBusy, error) {
+	if err := uc.policy.Authorize(ctx, policy.ReadEvents, ""); err != nil {
+		return nil, err
+	}
+
+	if err := validateFreeBusy(requestDTO); err != nil {
+		return nil, err
+	}
+
+	userIDs := slices.Compact(slices.Sorted(slices.Values(requestDTO.UserIDs)))
+	roomIDs := slices.Compact(slices.SortedFunc(slices.Values(requestDTO.RoomIDs), func(a, b uuid.UUID) int {
+		return slices.Compare(a[:], b[:])
+	}))
+
+	busy, err := uc.repository.ListBusy(ctx, userIDs, roomIDs, requestDTO.Start, requestDTO.End)
+	if err != nil {
+		log.Error().Msgf("FreeBusyUseCase.FreeBusy: %v", err)
+		return nil, common.NewCodedError(helper.InternalError, common.CodeInternalError, fmt.Errorf("free busy: %w", err))
+	}
+
+	users := make(map[string][]entity.Period, len(userIDs))
+	for _, userID := range userIDs {
+		users[userID] = nil
+	}
+	rooms := make(map[uuid.UUID][]entity.Period, len(roomIDs))
+	for _, roomID := range roomIDs {
+		rooms[roomID] = nil
+	}
+
+	var all []entity.Period
+	for _, period := range busy {
+		if period.UserID != "" {
+			users[period.UserID] = append(users[period.UserID], period.Period)
+		} else {
+			rooms[period.RoomID] = append(rooms[period.RoomID], period.Period)
+		}
+		all = append(all, period.Period)
+	}
+
+	result := &entity.FreeBusy{
+		Start: requestDTO.Start,
+		End:   requestDTO.End,
+		Users: make(map[string][]entity.Period, len(users)),
+		Rooms: make(map[uuid.UUID][]entity.Period, len(rooms)),
+		Busy:  mergeBusy(all, requestDTO.Start, requestDTO.End),
+	}
+	for userID, periods := range users {
+		result.Users[userID] = mergeBusy(periods, requestDTO.Start, requestDTO.End)
+	}
+	for roomID, periods := range rooms {
+		result.Rooms[roomID] = mergeBusy(periods, requestDTO.Start, requestDTO.End)
+	}
+
+	if requestDTO.SlotLength > 0 {
+		result.FreeSlot = entity.FirstFreeSlot(result.Busy, requestDTO.Start, requestDTO.End, requestDTO.SlotLength)
+	}
+
+	return result, nil
+}
+
+func mergeBusy(periods []entity.Period, start, end time.Time) []entity.Period {
+	return entity.MergePeriods(entity.ClipPeriods(periods, start, end))
+}
+
+func validateFreeBusy(requestDTO *dto.FreeBusyRequestDTO) error {
+	if requestDTO.Start.IsZero() || requestDTO.End.IsZero() {
+		return common.NewCodedError(helper.InvalidArgument, common.CodeEmptyFields, errors.New("window is empty"))
+	}
+	if !requestDTO.End.After(requestDTO.Start) {
+		return common.NewCodedError(
+			helper.InvalidArgument, common.CodeInvalidTimeRange, errors.New("start time is not before end time"),
+		)
+	}
+	if requestDTO.End.Sub(requestDTO.Start) > maxFreeBusyWindow {
+		return common.NewCodedError(
+			helper.InvalidArgument, common.CodeFreeBusyWindowTooLong,
+			fmt.Errorf("free busy window %s is too long", requestDTO.End.Sub(requestDTO.Start)), maxFreeBusyDays,
+		)
+	}
+
+	resources := len(requestDTO.UserIDs) + len(requestDTO.RoomIDs)
+	if resources == 0 || resources > maxFreeBusyResources {
+		return common.NewCodedError(
+			helper.InvalidArgument, common.CodeFreeBusyInvalidResources,
+			fmt.Errorf("free busy asked for %d users and rooms", resources), maxFreeBusyResources,
+		)
+	}
+	for _, userID := range requestDTO.UserIDs {
+		if userID == "" {
+			return common.NewCodedError(
+				helper.InvalidArgument, common.CodeFreeBusyInvalidResources, errors.New("empty user id"),
+				maxFreeBusyResources,
+			)
+		}
+	}
+
+	if requestDTO.SlotLength < 0 || requestDTO.SlotLength > requestDTO.End.Sub(requestDTO.Start) {
+		return common.NewCodedError(
+			helper.InvalidArgument, common.CodeInvalidSlotLength,
+			fmt.Errorf("invalid slot length %s", requestDTO.SlotLength),
+		)
+	}
+
+	return nil
+}
@@ -0,0 +1,173 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"online-registration/internal/common"
+	"online-registration/internal/interview/domain/auth"
+	"online-registration/internal/interview/domain/dto"
+	"online-registration/internal/interview/domain/entity"
+	"online-registration/internal/interview/domain/policy"
+	"online-registration/internal/interview/domain/repository"
+
+	"github.com/google/uuid"
+)
+
+// busyCalendar serves its busy periods to every ListBusy and records what
+// it was asked for.
+type busyCalendar struct {
+	repository.IParticipantRepository
+	busy    []*entity.BusyPeriod
+	userIDs []string
+	roomIDs []uuid.UUID
+}
+
+func (r *busyCalendar) ListBusy(
+	_ context.Context, userIDs []string, roomIDs []uuid.UUID, _, _ time.Time,
+) ([]*entity.BusyPeriod, error) {
+	r.userIDs, r.roomIDs = userIDs, roomIDs
+	return r.busy, nil
+}
+
+var freeBusyMonday = time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+
+// hours is the period between the hours from and to of freeBusyMonday.
+func hours(from, to float64) entity.Period {
+	hour := func(h float64) time.Time { return freeBusyMonday.Add(time.Duration(h * float64(time.Hour))) }
+	return entity.Period{Start: hour(from), End: hour(to)}
+}
+
+func formatBusy(periods []entity.Period) string {
+	formatted := make([]string, 0, len(periods))
+	for _, period := range periods {
+		formatted = append(formatted, period.Start.Format("15:04")+"-"+period.End.Format("15:04"))
+	}
+	return fmt.Sprint(formatted)
+}
+
+func freeBusyContext(t *testing.T) (context.Context, *policy.Policy) {
+	t.Helper()
+
+	accessPolicy, err := policy.Load("")
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	ctx := auth.ContextWithPrincipal(context.Background(), &auth.Principal{
+		Subject: "alice", Roles: []string{policy.RoleViewer},
+	})
+	return ctx, accessPolicy
+}
+
+func TestFreeBusy(t *testing.T) {
+	ctx, accessPolicy := freeBusyContext(t)
+	room := uuid.New()
+	idle := uuid.New()
+	calendar := &busyCalendar{busy: []*entity.BusyPeriod{
+		{UserID: "bob", Period: hours(8, 10)},
+		{UserID: "bob", Period: hours(9.5, 11)},
+		{UserID: "carol", Period: hours(11, 12)},
+		{RoomID: room, Period: hours(13, 14)},
+		{RoomID: room, Period: hours(16, 19)},
+	}}
+	uc := NewFreeBusyUseCase(calendar, accessPolicy)
+
+	window := hours(9, 17)
+	freeBusy, err := uc.FreeBusy(ctx, &dto.FreeBusyRequestDTO{
+		UserIDs: []string{"carol", "bob", "carol", "dave"},
+		RoomIDs: []uuid.UUID{room, idle, room},
+		Start:   window.Start, End: window.End,
+		SlotLength: time.Hour,
+	})
+	if err != nil {
+		t.Fatalf("FreeBusy: %v", err)
+	}
+
+	if fmt.Sprint(calendar.userIDs) != "[bob carol dave]" || len(calendar.roomIDs) != 2 {
+		t.Errorf("ListBusy asked for users %v and %d rooms, want each once", calendar.userIDs, len(calendar.roomIDs))
+	}
+
+	tests := []struct {
+		name string
+		got  []entity.Period
+		want []entity.Period
+	}{
+		{"bob", freeBusy.Users["bob"], []entity.Period{hours(9, 11)}},
+		{"carol", freeBusy.Users["carol"], []entity.Period{hours(11, 12)}},
+		{"dave", freeBusy.Users["dave"], nil},
+		{"room", freeBusy.Rooms[room], []entity.Period{hours(13, 14), hours(16, 17)}},
+		{"idle room", freeBusy.Rooms[idle], nil},
+		{"everyone", freeBusy.Busy, []entity.Period{hours(9, 12), hours(13, 14), hours(16, 17)}},
+	}
+	for _, tt := range tests {
+		if formatBusy(tt.got) != formatBusy(tt.want) {
+			t.Errorf("%s busy %s, want %s", tt.name, formatBusy(tt.got), formatBusy(tt.want))
+		}
+	}
+	if _, ok := freeBusy.Users["dave"]; !ok {
+		t.Error("free users are left out")
+	}
+
+	if freeBusy.FreeSlot == nil || formatBusy([]entity.Period{*freeBusy.FreeSlot}) != formatBusy([]entity.Period{hours(12, 13)}) {
+		t.Errorf("FreeSlot = %v, want 12:00-13:00", freeBusy.FreeSlot)
+	}
+}
+
+func TestFreeBusyWithoutFreeSlot(t *testing.T) {
+	ctx, accessPolicy := freeBusyContext(t)
+	uc := NewFreeBusyUseCase(&busyCalendar{busy: []*entity.BusyPeriod{
+		{UserID: "bob", Period: hours(9, 12)},
+		{UserID: "carol", Period: hours(12.5, 17)},
+	}}, accessPolicy)
+
+	window := hours(9, 17)
+	for _, slotLength := range []time.Duration{0, time.Hour} {
+		freeBusy, err := uc.FreeBusy(ctx, &dto.FreeBusyRequestDTO{
+			UserIDs: []string{"bob", "carol"}, Start: window.Start, End: window.End, SlotLength: slotLength,
+		})
+		if err != nil {
+			t.Fatalf("FreeBusy: %v", err)
+		}
+		if freeBusy.FreeSlot != nil {
+			t.Errorf("FreeSlot of %s = %v, want none", slotLength, freeBusy.FreeSlot)
+		}
+	}
+}
+
+func TestFreeBusyValidation(t *testing.T) {
+	ctx, accessPolicy := freeBusyContext(t)
+	uc := NewFreeBusyUseCase(&busyCalendar{}, accessPolicy)
+	window := hours(9, 17)
+	tooMany := make([]string, maxFreeBusyResources+1)
+	for i := range tooMany {
+		tooMany[i] = fmt.Sprint("user", i)
+	}
+
+	tests := []struct {
+		name     string
+		request  dto.FreeBusyRequestDTO
+		wantCode string
+	}{
+		{"no window", dto.FreeBusyRequestDTO{UserIDs: []string{"bob"}}, common.CodeEmptyFields},
+		{"backwards", dto.FreeBusyRequestDTO{UserIDs: []string{"bob"}, Start: window.End, End: window.Start}, common.CodeInvalidTimeRange},
+		{"empty window", dto.FreeBusyRequestDTO{UserIDs: []string{"bob"}, Start: window.Start, End: window.Start}, common.CodeInvalidTimeRange},
+		{"too long", dto.FreeBusyRequestDTO{UserIDs: []string{"bob"}, Start: window.Start, End: window.Start.Add(maxFreeBusyWindow + time.Hour)}, common.CodeFreeBusyWindowTooLong},
+		{"no one", dto.FreeBusyRequestDTO{Start: window.Start, End: window.End}, common.CodeFreeBusyInvalidResources},
+		{"too many", dto.FreeBusyRequestDTO{UserIDs: tooMany, Start: window.Start, End: window.End}, common.CodeFreeBusyInvalidResources},
+		{"empty user", dto.FreeBusyRequestDTO{UserIDs: []string{""}, Start: window.Start, End: window.End}, common.CodeFreeBusyInvalidResources},
+		{"negative slot", dto.FreeBusyRequestDTO{UserIDs: []string{"bob"}, Start: window.Start, End: window.End, SlotLength: -time.Hour}, common.CodeInvalidSlotLength},
+		{"slot over the window", dto.FreeBusyRequestDTO{UserIDs: []string{"bob"}, Start: window.Start, End: window.End, SlotLength: 9 * time.Hour}, common.CodeInvalidSlotLength},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := uc.FreeBusy(ctx, &tt.request)
+			var processingErr *common.ProcessingError
+			if !errors.As(err, &processingErr) || processingErr.Code != tt.wantCode {
+				t.Errorf("FreeBusy error = %v, want %s", err, tt.wantCode)
+			}
+		})
+	}
+}
@@ -0,0 +1,159 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"online-registration/internal/common"
+	"online-registration/internal/interview/domain/auth"
+	"online-registration/internal/interview/domain/dto"
+	"online-registration/internal/interview/domain/entity"
+	"online-registration/internal/interview/domain/policy"
+	"online-registration/internal/interview/domain/repository"
+	"online-registration/internal/interview/helper"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	defaultEventListLimit = 50
+	maxEventListLimit     = 200
+)
+
+// GetEventsUseCase reads events. Drafts are only visible to the principals
+// that may edit them.
+type GetEventsUseCase struct {
+	repository repository.IEventRepository
+	policy     *policy.Policy
+}
+
+func NewGetEventsUseCase(
+	repository repository.IEventRepository,
+	policy *policy.Policy,
+) *GetEventsUseCase {
+	return &GetEventsUseCase{
+		repository: repository,
+		policy:     policy,
+	}
+}
+
+func (uc *GetEventsUseCase) GetEvent(ctx context.Context, id uuid.UUID) (*entity.Event, error) {
+	event, err := authorizeEvent(ctx, uc.repository, uc.policy, policy.ReadEvents, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if event.Status == entity.EventDraft &&
+		uc.policy.Authorize(ctx, policy.UpdateEvents, event.CreatedBy) != nil {
+		return nil, common.NewCodedError(
+			helper.NotFound, common.CodeNotFound, fmt.Errorf("get event: draft %s is not visible", id),
+		)
+	}
+	return event, nil
+}
+
+// ListEvents returns the events with the requested statuses, tags and
+// categories, all of them when none are requested, ordered by start time. With a search query only
+// the matching events are returned, the most relevant first.
+func (uc *GetEventsUseCase) ListEvents(
+	ctx context.Context, requestDTO *dto.ListEventsRequestDTO,
+) ([]*entity.EventMatch, error) {
+	filter, err := uc.eventFilter(ctx, requestDTO)
+	if err != nil {
+		return nil, err
+	}
+
+	if filter.Query != "" {
+		matches, err := uc.repository.SearchEvents(ctx, filter)
+		if err != nil {
+			log.Error().Msgf("GetEventsUseCase.ListEvents: %v", err)
+			return nil, common.NewCodedError(
+				helper.InternalError, common.CodeInternalError, fmt.Errorf("search events: %w", err),
+			)
+		}
+		return matches, nil
+	}
+
+	events, err := uc.repository.ListEvents(ctx, filter)
+	if err != nil {
+		log.Error().Msgf("GetEventsUseCase.ListEvents: %v", err)
+		return nil, common.NewCodedError(
+			helper.InternalError, common.CodeInternalError, fmt.Errorf("list events: %w", err),
+		)
+	}
+
+	matches := make([]*entity.EventMatch, 0, len(events))
+	for _, event := range events {
+		matches = append(matches, &entity.EventMatch{Event: event})
+	}
+	return matches, nil
+}
+
+// Facets counts the events ListEvents would return, without paging, by tag
+// and by category.
+func (uc *GetEventsUseCase) Facets(
+	ctx context.Context, requestDTO *dto.ListEventsRequestDTO,
+) (*entity.EventFacets, error) {
+	filter, err := uc.eventFilter(ctx, requestDTO)
+	if err != nil {
+		return nil, err
+	}
+
+	facets, err := uc.repository.CountFacets(ctx, filter)
+	if err != nil {
+		log.Error().Msgf("GetEventsUseCase.Facets: %v", err)
+		return nil, common.NewCodedError(
+			helper.InternalError, common.CodeInternalError, fmt.Errorf("count facets: %w", err),
+		)
+	}
+	return facets, nil
+}
+
+// eventFilter checks that the principal may list events and builds the
+// filter of the listing, with the drafts the principal may see.
+func (uc *GetEventsUseCase) eventFilter(
+	ctx context.Context, requestDTO *dto.ListEventsRequestDTO,
+) (entity.EventFilter, error) {
+	if err := uc.policy.Authorize(ctx, policy.ReadEvents, ""); err != nil {
+		return entity.EventFilter{}, err
+	}
+
+	for _, status := range requestDTO.Statuses {
+		if !slices.Contains(entity.EventStatuses, status) {
+			return entity.EventFilter{}, common.NewCodedError(
+				helper.InvalidArgument, common.CodeInvalidEventStatus, fmt.Errorf("invalid event status %q", status),
+				strings.Join(entity.EventStatuses, ", "),
+			)
+		}
+	}
+
+	if requestDTO.TagMatch != "" && !slices.Contains(entity.TagMatches, requestDTO.TagMatch) {
+		return entity.EventFilter{}, common.NewCodedError(
+			helper.InvalidArgument, common.CodeInvalidTagMatch, fmt.Errorf("invalid tag match %q", requestDTO.TagMatch),
+			strings.Join(entity.TagMatches, ", "),
+		)
+	}
+
+	filter := entity.EventFilter{
+		Query:       strings.TrimSpace(requestDTO.Query),
+		Statuses:    requestDTO.Statuses,
+		Tags:        entity.NormalizeTags(requestDTO.Tags),
+		AllTags:     requestDTO.TagMatch == entity.TagMatchAll,
+		CategoryIDs: requestDTO.CategoryIDs,
+		Limit:       requestDTO.Limit,
+		Offset:      max(requestDTO.Offset, 0),
+	}
+	if filter.Limit <= 0 {
+		filter.Limit = defaultEventListLimit
+	}
+	filter.Limit = min(filter.Limit, maxEventListLimit)
+
+	anyDraft, ownDrafts := uc.policy.Reach(ctx, policy.UpdateEvents)
+	filter.AllDrafts = anyDraft
+	if principal := auth.PrincipalFromContext(ctx); ownDrafts && principal != nil {
+		filter.DraftsOf = principal.Subject
+	}
+	return filter, nil
+}
@@ -0,0 +1,131 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"online-registration/internal/common"
+	"online-registration/internal/interview/domain/entity"
+	"online-registration/internal/interview/domain/repository"
+	"online-registration/internal/interview/helper"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+// idempotencyLockMargin is how long an unfinished request keeps its key
+// after its timeout, before a retry is allowed to take the key over. The
+// request has been cancelled by then, so the retry doesn't run alongside it.
+const idempotencyLockMargin = time.Minute
+
+// IdempotencyScope joins the parts a key is unique within, e.g. its tenant
+// and principal, and the key itself into the key stored. Every part is
+// prefixed with its length, so that parts holding separators can't run into
+// one another.
+func IdempotencyScope(parts ...string) string {
+	var scope strings.Builder
+	for _, part := range parts {
+		scope.WriteString(strconv.Itoa(len(part)))
+		scope.WriteByte(':')
+		scope.WriteString(part)
+	}
+	return scope.String()
+}
+
+type IdempotencyUseCase struct {
+	repository     repository.IIdempotencyKeyRepository
+	ttl            time.Duration
+	requestTimeout time.Duration
+}
+
+// NewIdempotencyUseCase creates the use case of keys kept for ttl, guarding
+// requests cancelled after requestTimeout.
+func NewIdempotencyUseCase(
+	repository repository.IIdempotencyKeyRepository,
+	ttl time.Duration,
+	requestTimeout time.Duration,
+) *IdempotencyUseCase {
+	return &IdempotencyUseCase{
+		repository:     repository,
+		ttl:            ttl,
+		requestTimeout: requestTimeout,
+	}
+}
+
+// RequestTimeout is how long a request holding a key may run.
+func (uc *IdempotencyUseCase) RequestTimeout() time.Duration {
+	return uc.requestTimeout
+}
+
+// lockTimeout is how long an unfinished request keeps its key.
+func (uc *IdempotencyUseCase) lockTimeout() time.Duration {
+	return uc.requestTimeout + idempotencyLockMargin
+}
+
+// Begin claims key for a request. It returns the record claimed, with
+// acquired set, when the caller should process the request and call Complete
+// or Release with the owner of the record. Otherwise it returns the stored
+// record of a completed request, whose response must be replayed.
+func (uc *IdempotencyUseCase) Begin(
+	ctx context.Context,
+	key string,
+	fingerprint string,
+) (*entity.IdempotencyKey, bool, error) {
+	record, acquired, err := uc.repository.Acquire(ctx, key, fingerprint, uc.ttl, uc.lockTimeout())
+	if err != nil {
+		log.Error().Msgf("IdempotencyUseCase.Begin: %v", err)
+		return nil, false, common.NewCodedError(
+			helper.InternalError, common.CodeInternalError, fmt.Errorf("acquire idempotency key: %w", err),
+		)
+	}
+
+	if acquired {
+		return record, true, nil
+	}
+
+	if record.Fingerprint != fingerprint {
+		return nil, false, common.NewCodedError(
+			helper.FailedPrecondition, common.CodeIdempotencyKeyReused,
+			errors.New("idempotency key reused with a different request"),
+		)
+	}
+
+	if !record.Completed() {
+		return nil, false, common.NewCodedError(
+			helper.AlreadyExists, common.CodeIdempotencyKeyInProgress,
+			errors.New("request with this idempotency key is in progress"),
+		)
+	}
+
+	return record, false, nil
+}
+
+// Complete stores the response so that retries of the request replay it.
+// Nothing is stored when owner lost the key, the request that took it over
+// stores its own response.
+func (uc *IdempotencyUseCase) Complete(
+	ctx context.Context,
+	key string,
+	owner uuid.UUID,
+	statusCode int,
+	responseBody []byte,
+) error {
+	if err := uc.repository.Complete(ctx, key, owner, statusCode, responseBody); err != nil {
+		log.Error().Msgf("IdempotencyUseCase.Complete: %v", err)
+		return fmt.Errorf("complete idempotency key: %w", err)
+	}
+	return nil
+}
+
+// Release frees key after a failure so that the request can be retried,
+// unless owner lost it already.
+func (uc *IdempotencyUseCase) Release(ctx context.Context, key string, owner uuid.UUID) error {
+	if err := uc.repository.Release(ctx, key, owner); err != nil {
+		log.Error().Msgf("IdempotencyUseCase.Release: %v", err)
+		return fmt.Errorf("release idempotency key: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"online-registration/internal/common"
+	"online-registration/internal/interview/domain/entity"
+
+	"github.com/google/uuid"
+)
+
+func TestIdempotencyScope(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b []string
+	}{
+		{"separator in the first part", []string{"acme:alice", "k1"}, []string{"acme", "alice:k1"}},
+		{"separator in the key", []string{"acme", "alice", "x:k1"}, []string{"acme", "alice:x", "k1"}},
+		{"empty part", []string{"", "alice", "k1"}, []string{"alice", "", "k1"}},
+		{"length-like part", []string{"1:a", "b"}, []string{"1", "a:b"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if a, b := IdempotencyScope(tt.a...), IdempotencyScope(tt.b...); a == b {
+				t.Errorf("IdempotencyScope(%q) = IdempotencyScope(%q) = %q", tt.a, tt.b, a)
+			}
+		})
+	}
+
+	if IdempotencyScope("acme", "alice", "k1") != IdempotencyScope("acme", "alice", "k1") {
+		t.Error("IdempotencyScope isn't stable")
+	}
+}
+
+// storedIdempotencyKey answers every Acquire with its record.
+type storedIdempotencyKey struct {
+	record   entity.IdempotencyKey
+	acquired bool
+	err      error
+}
+
+func (s storedIdempotencyKey) Acquire(
+	context.Context, string, string, time.Duration, time.Duration,
+) (*entity.IdempotencyKey, bool, error) {
+	return &s.record, s.acquired, s.err
+}
+
+func (storedIdempotencyKey) Complete(context.Context, string, uuid.UUID, int, []byte) error {
+	return nil
+}
+
+func (storedIdempotencyKey) Release(context.Context, string, uuid.UUID) error {
+	return nil
+}
+
+func (storedIdempotencyKey) DeleteExpired(context.Context, time.Time) (int, error) {
+	return 0, nil
+}
+
+func TestIdempotencyBegin(t *testing.T) {
+	owner := uuid.New()
+
+	tests := []struct {
+		name         string
+		stored       storedIdempotencyKey
+		wantAcquired bool
+		wantStatus   int
+		wantCode     string
+	}{
+		{"new key", storedIdempotencyKey{record: entity.IdempotencyKey{Fingerprint: "f", Owner: owner}, acquired: true}, true, 0, ""},
+		{"completed", storedIdempotencyKey{record: entity.IdempotencyKey{Fingerprint: "f", StatusCode: 201}}, false, 201, ""},
+		{"in progress", storedIdempotencyKey{record: entity.IdempotencyKey{Fingerprint: "f"}}, false, 0, common.CodeIdempotencyKeyInProgress},
+		{"other request", storedIdempotencyKey{record: entity.IdempotencyKey{Fingerprint: "g", StatusCode: 201}}, false, 0, common.CodeIdempotencyKeyReused},
+		{"repository failure", storedIdempotencyKey{err: errors.New("connection reset")}, false, 0, common.CodeInternalError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc := NewIdempotencyUseCase(tt.stored, time.Hour, time.Minute)
+
+			record, acquired, err := uc.Begin(context.Background(), "k1", "f")
+			if tt.wantCode != "" {
+				var processingErr *common.ProcessingError
+				if !errors.As(err, &processingErr) || processingErr.Code != tt.wantCode {
+					t.Fatalf("Begin error = %v, want %s", err, tt.wantCode)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Begin: %v", err)
+			}
+			if acquired != tt.wantAcquired || record.StatusCode != tt.wantStatus {
+				t.Errorf("Begin = status %d, acquired %v, want status %d, acquired %v",
+					record.StatusCode, acquired, tt.wantStatus, tt.wantAcquired)
+			}
+			if acquired && record.Owner != owner {
+				t.Errorf("Begin returned owner %s, want %s", record.Owner, owner)
+			}
+		})
+	}
+}
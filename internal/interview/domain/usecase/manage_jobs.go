@@ -0,0 +1,40 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"online-registration/internal/interview/domain/entity"
+	"online-registration/internal/interview/domain/repository"
+	"slices"
+
+	"github.com/google/uuid"
+)
+
+// ManageJobsUseCase lets operators inspect the job queue and retry or
+// cancel jobs.
+type ManageJobsUseCase struct {
+	repository repository.IJobRepository
+}
+
+func NewManageJobsUseCase(repository repository.IJobRepository) *ManageJobsUseCase {
+	return &ManageJobsUseCase{
+		repository: repository,
+	}
+}
+
+func (uc *ManageJobsUseCase) List(ctx context.Context, filter entity.JobFilter) ([]*entity.Job, error) {
+	for _, status := range filter.Statuses {
+		if !slices.Contains(entity.JobStatuses, status) {
+			return nil, fmt.Errorf("unknown job status %q, use one of %v", status, entity.JobStatuses)
+		}
+	}
+	return uc.repository.ListJobs(ctx, filter)
+}
+
+func (uc *ManageJobsUseCase) Retry(ctx context.Context, id uuid.UUID) (*entity.Job, error) {
+	return uc.repository.RetryJob(ctx, id)
+}
+
+func (uc *ManageJobsUseCase) Cancel(ctx context.Context, id uuid.UUID) (*entity.Job, error) {
+	return uc.repository.CancelJob(ctx, id)
+}
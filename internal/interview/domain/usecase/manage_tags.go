@@ -0,0 +1,193 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"online-registration/internal/common"
+	"online-registration/internal/interview/domain/dto"
+	"online-registration/internal/interview/domain/entity"
+	"online-registration/internal/interview/domain/policy"
+	"online-registration/internal/interview/domain/repository"
+	"online-registration/internal/interview/helper"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+type ManageTagsUseCase struct {
+	repository repository.ITagRepository
+	policy     *policy.Policy
+}
+
+func NewManageTagsUseCase(
+	repository repository.ITagRepository,
+	policy *policy.Policy,
+) *ManageTagsUseCase {
+	return &ManageTagsUseCase{
+		repository: repository,
+		policy:     policy,
+	}
+}
+
+// ListTags is open to everyone who may read events, so that they can be
+// filtered by tag.
+func (uc *ManageTagsUseCase) ListTags(ctx context.Context) ([]*entity.Tag, error) {
+	if err := uc.policy.Authorize(ctx, policy.ReadEvents, ""); err != nil {
+		return nil, err
+	}
+
+	tags, err := uc.repository.ListTags(ctx)
+	if err != nil {
+		return nil, tagStorageError("list tags", err)
+	}
+	return tags, nil
+}
+
+func (uc *ManageTagsUseCase) CreateTag(ctx context.Context, requestDTO *dto.TagRequestDTO) (*entity.Tag, error) {
+	if err := uc.policy.Authorize(ctx, policy.ManageTags, ""); err != nil {
+		return nil, err
+	}
+
+	name, err := validateTagName(requestDTO.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	tag, err := uc.repository.CreateTag(ctx, name)
+	if err != nil {
+		return nil, namedTagError("create tag", err, name)
+	}
+	return tag, nil
+}
+
+func (uc *ManageTagsUseCase) UpdateTag(
+	ctx context.Context, id uuid.UUID, requestDTO *dto.TagRequestDTO,
+) (*entity.Tag, error) {
+	if err := uc.policy.Authorize(ctx, policy.ManageTags, ""); err != nil {
+		return nil, err
+	}
+
+	name, err := validateTagName(requestDTO.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	tag, err := uc.repository.UpdateTag(ctx, id, name)
+	if err != nil {
+		return nil, namedTagError("update tag", err, name)
+	}
+	return tag, nil
+}
+
+func (uc *ManageTagsUseCase) DeleteTag(ctx context.Context, id uuid.UUID) error {
+	if err := uc.policy.Authorize(ctx, policy.ManageTags, ""); err != nil {
+		return err
+	}
+
+	if err := uc.repository.DeleteTag(ctx, id); err != nil {
+		return tagStorageError("delete tag", err)
+	}
+	return nil
+}
+
+func (uc *ManageTagsUseCase) ListCategories(ctx context.Context) ([]*entity.Category, error) {
+	if err := uc.policy.Authorize(ctx, policy.ReadEvents, ""); err != nil {
+		return nil, err
+	}
+
+	categories, err := uc.repository.ListCategories(ctx)
+	if err != nil {
+		return nil, tagStorageError("list categories", err)
+	}
+	return categories, nil
+}
+
+func (uc *ManageTagsUseCase) CreateCategory(
+	ctx context.Context, requestDTO *dto.CategoryRequestDTO,
+) (*entity.Category, error) {
+	if err := uc.policy.Authorize(ctx, policy.ManageTags, ""); err != nil {
+		return nil, err
+	}
+
+	name, err := requireName(requestDTO.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	category, err := uc.repository.CreateCategory(ctx, name)
+	if err != nil {
+		return nil, namedTagError("create category", err, name)
+	}
+	return category, nil
+}
+
+func (uc *ManageTagsUseCase) UpdateCategory(
+	ctx context.Context, id uuid.UUID, requestDTO *dto.CategoryRequestDTO,
+) (*entity.Category, error) {
+	if err := uc.policy.Authorize(ctx, policy.ManageTags, ""); err != nil {
+		return nil, err
+	}
+
+	name, err := requireName(requestDTO.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	category, err := uc.repository.UpdateCategory(ctx, id, name)
+	if err != nil {
+		return nil, namedTagError("update category", err, name)
+	}
+	return category, nil
+}
+
+func (uc *ManageTagsUseCase) DeleteCategory(ctx context.Context, id uuid.UUID) error {
+	if err := uc.policy.Authorize(ctx, policy.ManageTags, ""); err != nil {
+		return err
+	}
+
+	if err := uc.repository.DeleteCategory(ctx, id); err != nil {
+		return tagStorageError("delete category", err)
+	}
+	return nil
+}
+
+// validateTagName normalizes the name like the tags of events.
+func validateTagName(name string) (string, error) {
+	tag := entity.NormalizeTag(name)
+	if tag == "" {
+		return "", common.NewCodedError(helper.InvalidArgument, common.CodeEmptyFields, errors.New("name is empty"))
+	}
+	if utf8.RuneCountInString(tag) > entity.MaxTagLength {
+		return "", common.NewCodedError(
+			helper.InvalidArgument, common.CodeInvalidTag, fmt.Errorf("tag %q is too long", tag),
+			tag, entity.MaxTagLength,
+		)
+	}
+	return tag, nil
+}
+
+// namedTagError is tagStorageError for changes that name a tag or a
+// category.
+func namedTagError(operation string, err error, name string) error {
+	wrapped := fmt.Errorf("%s: %w", operation, err)
+	switch {
+	case errors.Is(err, repository.ErrTagNameTaken):
+		return common.NewCodedError(helper.AlreadyExists, common.CodeTagNameTaken, wrapped, name)
+	case errors.Is(err, repository.ErrCategoryNameTaken):
+		return common.NewCodedError(helper.AlreadyExists, common.CodeCategoryNameTaken, wrapped, name)
+	}
+	return tagStorageError(operation, err)
+}
+
+func tagStorageError(operation string, err error) error {
+	wrapped := fmt.Errorf("%s: %w", operation, err)
+	if errors.Is(err, sql.ErrNoRows) {
+		return common.NewCodedError(helper.NotFound, common.CodeNotFound, wrapped)
+	}
+
+	log.Error().Msgf("ManageTagsUseCase: %s: %v", operation, err)
+	return common.NewCodedError(helper.InternalError, common.CodeInternalError, wrapped)
+}
@@ -0,0 +1,198 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"online-registration/internal/common"
+	"online-registration/internal/interview/domain/dto"
+	"online-registration/internal/interview/domain/entity"
+	"online-registration/internal/interview/domain/policy"
+	"online-registration/internal/interview/domain/repository"
+	"online-registration/internal/interview/helper"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+type ManageVenuesUseCase struct {
+	repository repository.IVenueRepository
+	policy     *policy.Policy
+}
+
+func NewManageVenuesUseCase(
+	repository repository.IVenueRepository,
+	policy *policy.Policy,
+) *ManageVenuesUseCase {
+	return &ManageVenuesUseCase{
+		repository: repository,
+		policy:     policy,
+	}
+}
+
+func (uc *ManageVenuesUseCase) CreateVenue(ctx context.Context, requestDTO *dto.VenueRequestDTO) (*entity.Venue, error) {
+	if err := uc.policy.Authorize(ctx, policy.ManageVenues, ""); err != nil {
+		return nil, err
+	}
+
+	name, err := requireName(requestDTO.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	venue, err := uc.repository.CreateVenue(ctx, name, strings.TrimSpace(requestDTO.Address))
+	if err != nil {
+		return nil, venueStorageError("create venue", err)
+	}
+	return venue, nil
+}
+
+// ListVenues is open to everyone who may read events, so that rooms can be
+// picked when booking.
+func (uc *ManageVenuesUseCase) ListVenues(ctx context.Context) ([]*entity.Venue, error) {
+	if err := uc.policy.Authorize(ctx, policy.ReadEvents, ""); err != nil {
+		return nil, err
+	}
+
+	venues, err := uc.repository.ListVenues(ctx)
+	if err != nil {
+		return nil, venueStorageError("list venues", err)
+	}
+	return venues, nil
+}
+
+func (uc *ManageVenuesUseCase) GetVenue(ctx context.Context, id uuid.UUID) (*entity.Venue, error) {
+	if err := uc.policy.Authorize(ctx, policy.ReadEvents, ""); err != nil {
+		return nil, err
+	}
+
+	venue, err := uc.repository.GetVenue(ctx, id)
+	if err != nil {
+		return nil, venueStorageError("get venue", err)
+	}
+	return venue, nil
+}
+
+func (uc *ManageVenuesUseCase) UpdateVenue(
+	ctx context.Context, id uuid.UUID, requestDTO *dto.VenueRequestDTO,
+) (*entity.Venue, error) {
+	if err := uc.policy.Authorize(ctx, policy.ManageVenues, ""); err != nil {
+		return nil, err
+	}
+
+	name, err := requireName(requestDTO.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	venue, err := uc.repository.UpdateVenue(ctx, id, name, strings.TrimSpace(requestDTO.Address))
+	if err != nil {
+		return nil, venueStorageError("update venue", err)
+	}
+	return venue, nil
+}
+
+func (uc *ManageVenuesUseCase) DeleteVenue(ctx context.Context, id uuid.UUID) error {
+	if err := uc.policy.Authorize(ctx, policy.ManageVenues, ""); err != nil {
+		return err
+	}
+
+	if err := uc.repository.DeleteVenue(ctx, id); err != nil {
+		return venueStorageError("delete venue", err)
+	}
+	return nil
+}
+
+func (uc *ManageVenuesUseCase) CreateRoom(
+	ctx context.Context, venueID uuid.UUID, requestDTO *dto.RoomRequestDTO,
+) (*entity.Room, error) {
+	if err := uc.policy.Authorize(ctx, policy.ManageVenues, ""); err != nil {
+		return nil, err
+	}
+
+	name, err := validateRoom(requestDTO)
+	if err != nil {
+		return nil, err
+	}
+
+	room, err := uc.repository.CreateRoom(ctx, venueID, name, requestDTO.Capacity)
+	if err != nil {
+		return nil, roomStorageError("create room", err, name)
+	}
+	return room, nil
+}
+
+func (uc *ManageVenuesUseCase) UpdateRoom(
+	ctx context.Context, id uuid.UUID, requestDTO *dto.RoomRequestDTO,
+) (*entity.Room, error) {
+	if err := uc.policy.Authorize(ctx, policy.ManageVenues, ""); err != nil {
+		return nil, err
+	}
+
+	name, err := validateRoom(requestDTO)
+	if err != nil {
+		return nil, err
+	}
+
+	room, err := uc.repository.UpdateRoom(ctx, id, name, requestDTO.Capacity)
+	if err != nil {
+		return nil, roomStorageError("update room", err, name)
+	}
+	return room, nil
+}
+
+func (uc *ManageVenuesUseCase) DeleteRoom(ctx context.Context, id uuid.UUID) error {
+	if err := uc.policy.Authorize(ctx, policy.ManageVenues, ""); err != nil {
+		return err
+	}
+
+	if err := uc.repository.DeleteRoom(ctx, id); err != nil {
+		return venueStorageError("delete room", err)
+	}
+	return nil
+}
+
+func requireName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", common.NewCodedError(helper.InvalidArgument, common.CodeEmptyFields, errors.New("name is empty"))
+	}
+	return name, nil
+}
+
+func validateRoom(requestDTO *dto.RoomRequestDTO) (string, error) {
+	if requestDTO.Capacity < 0 {
+		return "", common.NewCodedError(
+			helper.InvalidArgument, common.CodeInvalidRoomCapacity,
+			fmt.Errorf("invalid room capacity %d", requestDTO.Capacity),
+		)
+	}
+	return requireName(requestDTO.Name)
+}
+
+// roomStorageError is venueStorageError for changes that name a room.
+func roomStorageError(operation string, err error, name string) error {
+	if errors.Is(err, repository.ErrRoomNameTaken) {
+		return common.NewCodedError(
+			helper.AlreadyExists, common.CodeRoomNameTaken, fmt.Errorf("%s: %w", operation, err), name,
+		)
+	}
+	return venueStorageError(operation, err)
+}
+
+func venueStorageError(operation string, err error) error {
+	wrapped := fmt.Errorf("%s: %w", operation, err)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return common.NewCodedError(helper.NotFound, common.CodeNotFound, wrapped)
+	case errors.Is(err, repository.ErrVenueNotEmpty):
+		return common.NewCodedError(helper.AlreadyExists, common.CodeVenueNotEmpty, wrapped)
+	case errors.Is(err, repository.ErrRoomInUse):
+		return common.NewCodedError(helper.AlreadyExists, common.CodeRoomInUse, wrapped)
+	}
+
+	log.Error().Msgf("ManageVenuesUseCase: %s: %v", operation, err)
+	return common.NewCodedError(helper.InternalError, common.CodeInternalError, wrapped)
+}
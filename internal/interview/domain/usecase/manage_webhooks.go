@@ -0,0 +1,223 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"online-registration/internal/common"
+	"online-registration/internal/interview/domain/dto"
+	"online-registration/internal/interview/domain/egress"
+	"online-registration/internal/interview/domain/entity"
+	"online-registration/internal/interview/domain/policy"
+	"online-registration/internal/interview/domain/repository"
+	"online-registration/internal/interview/helper"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	webhookSecretBytes      = 32
+	maxWebhookDeliveryLimit = 100
+)
+
+type ManageWebhooksUseCase struct {
+	repository repository.IWebhookRepository
+	policy     *policy.Policy
+}
+
+func NewManageWebhooksUseCase(
+	repository repository.IWebhookRepository,
+	policy *policy.Policy,
+) *ManageWebhooksUseCase {
+	return &ManageWebhooksUseCase{
+		repository: repository,
+		policy:     policy,
+	}
+}
+
+// CreateSubscription registers a webhook. A signing secret is generated when
+// none is given; it is only returned by this call.
+func (uc *ManageWebhooksUseCase) CreateSubscription(
+	ctx context.Context,
+	requestDTO *dto.WebhookSubscriptionRequestDTO,
+) (*entity.WebhookSubscription, error) {
+	if err := uc.policy.Authorize(ctx, policy.ManageWebhooks, ""); err != nil {
+		return nil, err
+	}
+
+	if err := validateWebhookSubscription(ctx, requestDTO); err != nil {
+		return nil, err
+	}
+
+	secret := requestDTO.Secret
+	if secret == "" {
+		var err error
+		if secret, err = generateWebhookSecret(); err != nil {
+			return nil, webhookStorageError("create webhook subscription", err)
+		}
+	}
+
+	subscription, err := uc.repository.CreateSubscription(ctx, &entity.WebhookSubscription{
+		URL:        requestDTO.URL,
+		Secret:     secret,
+		EventTypes: requestDTO.EventTypes,
+	})
+	if err != nil {
+		return nil, webhookStorageError("create webhook subscription", err)
+	}
+	return subscription, nil
+}
+
+func (uc *ManageWebhooksUseCase) ListSubscriptions(ctx context.Context) ([]*entity.WebhookSubscription, error) {
+	if err := uc.policy.Authorize(ctx, policy.ManageWebhooks, ""); err != nil {
+		return nil, err
+	}
+
+	subscriptions, err := uc.repository.ListSubscriptions(ctx)
+	if err != nil {
+		return nil, webhookStorageError("list webhook subscriptions", err)
+	}
+	return subscriptions, nil
+}
+
+func (uc *ManageWebhooksUseCase) GetSubscription(
+	ctx context.Context, id uuid.UUID,
+) (*entity.WebhookSubscription, error) {
+	if err := uc.policy.Authorize(ctx, policy.ManageWebhooks, ""); err != nil {
+		return nil, err
+	}
+
+	subscription, err := uc.repository.GetSubscription(ctx, id)
+	if err != nil {
+		return nil, webhookStorageError("get webhook subscription", err)
+	}
+	return subscription, nil
+}
+
+func (uc *ManageWebhooksUseCase) UpdateSubscription(
+	ctx context.Context, id uuid.UUID, requestDTO *dto.WebhookSubscriptionRequestDTO,
+) (*entity.WebhookSubscription, error) {
+	if err := uc.policy.Authorize(ctx, policy.ManageWebhooks, ""); err != nil {
+		return nil, err
+	}
+
+	if err := validateWebhookSubscription(ctx, requestDTO); err != nil {
+		return nil, err
+	}
+
+	subscription, err := uc.repository.UpdateSubscription(
+		ctx, id, requestDTO.URL, requestDTO.EventTypes, requestDTO.Active,
+	)
+	if err != nil {
+		return nil, webhookStorageError("update webhook subscription", err)
+	}
+	return subscription, nil
+}
+
+func (uc *ManageWebhooksUseCase) DeleteSubscription(ctx context.Context, id uuid.UUID) error {
+	if err := uc.policy.Authorize(ctx, policy.ManageWebhooks, ""); err != nil {
+		return err
+	}
+
+	if err := uc.repository.DeleteSubscription(ctx, id); err != nil {
+		return webhookStorageError("delete webhook subscription", err)
+	}
+	return nil
+}
+
+// ListDeliveries returns the most recent deliveries of a subscription.
+func (uc *ManageWebhooksUseCase) ListDeliveries(
+	ctx context.Context, subscriptionID uuid.UUID, limit int,
+) ([]*entity.WebhookDelivery, error) {
+	if err := uc.policy.Authorize(ctx, policy.ManageWebhooks, ""); err != nil {
+		return nil, err
+	}
+
+	if limit <= 0 || limit > maxWebhookDeliveryLimit {
+		limit = maxWebhookDeliveryLimit
+	}
+
+	if _, err := uc.repository.GetSubscription(ctx, subscriptionID); err != nil {
+		return nil, webhookStorageError("list webhook deliveries", err)
+	}
+
+	deliveries, err := uc.repository.ListDeliveries(ctx, subscriptionID, limit)
+	if err != nil {
+		return nil, webhookStorageError("list webhook deliveries", err)
+	}
+	return deliveries, nil
+}
+
+func (uc *ManageWebhooksUseCase) Redeliver(
+	ctx context.Context, subscriptionID, deliveryID uuid.UUID,
+) (*entity.WebhookDelivery, error) {
+	if err := uc.policy.Authorize(ctx, policy.ManageWebhooks, ""); err != nil {
+		return nil, err
+	}
+
+	if _, err := uc.repository.GetSubscription(ctx, subscriptionID); err != nil {
+		return nil, webhookStorageError("redeliver webhook", err)
+	}
+
+	delivery, err := uc.repository.Redeliver(ctx, subscriptionID, deliveryID)
+	if err != nil {
+		return nil, webhookStorageError("redeliver webhook", err)
+	}
+	return delivery, nil
+}
+
+func validateWebhookSubscription(ctx context.Context, requestDTO *dto.WebhookSubscriptionRequestDTO) error {
+	target, err := url.Parse(requestDTO.URL)
+	if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Hostname() == "" {
+		return common.NewCodedError(
+			helper.InvalidArgument, common.CodeWebhookInvalidURL, fmt.Errorf("invalid webhook url %q", requestDTO.URL),
+		)
+	}
+
+	// checked again whenever a delivery is sent, the host may resolve
+	// elsewhere by then
+	if err := egress.CheckHost(ctx, target.Hostname()); err != nil {
+		return common.NewCodedError(
+			helper.InvalidArgument, common.CodeWebhookForbiddenHost,
+			fmt.Errorf("webhook url %q: %w", requestDTO.URL, err), target.Hostname(),
+		)
+	}
+
+	validTypes := len(requestDTO.EventTypes) > 0
+	for _, eventType := range requestDTO.EventTypes {
+		validTypes = validTypes && slices.Contains(dto.EventTypes, eventType)
+	}
+	if !validTypes {
+		return common.NewCodedError(
+			helper.InvalidArgument, common.CodeWebhookInvalidEventTypes,
+			fmt.Errorf("invalid webhook event types %v", requestDTO.EventTypes),
+			strings.Join(dto.EventTypes, ", "),
+		)
+	}
+
+	return nil
+}
+
+func generateWebhookSecret() (string, error) {
+	secret := make([]byte, webhookSecretBytes)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(secret), nil
+}
+
+func webhookStorageError(operation string, err error) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		return common.NewCodedError(helper.NotFound, common.CodeNotFound, fmt.Errorf("%s: %w", operation, err))
+	}
+
+	log.Error().Msgf("ManageWebhooksUseCase: %s: %v", operation, err)
+	return common.NewCodedError(helper.InternalError, common.CodeInternalError, fmt.Errorf("%s: %w", operation, err))
+}
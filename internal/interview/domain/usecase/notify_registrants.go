@@ -0,0 +1,174 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"online-registration/internal/interview/domain/dto"
+	"online-registration/internal/interview/domain/entity"
+	"online-registration/internal/interview/domain/repository"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+// NotifyRegistrantsUseCase turns registration messages into pending emails
+// to the registrants, and keeps their reminders in line with the start of
+// the event.
+type NotifyRegistrantsUseCase struct {
+	repository     repository.INotificationRepository
+	events         repository.IEventRepository
+	reminderBefore time.Duration
+	defaultTenant  string
+}
+
+// NewNotifyRegistrantsUseCase creates the dispatcher. Reminders are sent
+// reminderBefore the start of the event, and not at all when zero.
+// Registrations written before events had tenants belong to defaultTenant.
+func NewNotifyRegistrantsUseCase(
+	repository repository.INotificationRepository,
+	events repository.IEventRepository,
+	reminderBefore time.Duration,
+	defaultTenant string,
+) *NotifyRegistrantsUseCase {
+	return &NotifyRegistrantsUseCase{
+		repository:     repository,
+		events:         events,
+		reminderBefore: reminderBefore,
+		defaultTenant:  defaultTenant,
+	}
+}
+
+func (uc *NotifyRegistrantsUseCase) Dispatch(ctx context.Context, subject string, data []byte) error {
+	if subject == dto.EventUpdatedSubject {
+		return uc.reschedule(ctx, subject, data)
+	}
+
+	var message dto.RegistrationMessage
+	if err := json.Unmarshal(data, &message); err != nil {
+		log.Error().Str("subject", subject).Msgf("NotifyRegistrantsUseCase.Dispatch: skipping malformed message: %v", err)
+		return nil
+	}
+
+	registration := message.Registration
+	if registration.Email == "" {
+		log.Debug().Str("subject", subject).Str("registration_id", registration.ID.String()).
+			Msg("Registrant has no email, not notified")
+		return nil
+	}
+
+	switch subject {
+	case dto.RegistrationCreatedSubject:
+		if err := uc.enqueue(ctx, message, entity.NotificationConfirmation); err != nil {
+			return err
+		}
+		return uc.enqueueReminder(ctx, registration)
+	case dto.RegistrationWaitlistedSubject:
+		// the registrant hears from us once promoted
+		return nil
+	case dto.RegistrationPromotedSubject:
+		if err := uc.enqueue(ctx, message, entity.NotificationWaitlistPromotion); err != nil {
+			return err
+		}
+		return uc.enqueueReminder(ctx, registration)
+	case dto.RegistrationCancelledSubject, dto.RegistrationEventCancelledSubject:
+		_, err := uc.repository.CancelNotifications(ctx, registration.ID, entity.NotificationReminder)
+		if err != nil {
+			return fmt.Errorf("cancel reminder: %w", err)
+		}
+		return uc.enqueue(ctx, message, entity.NotificationCancellation)
+	}
+	return nil
+}
+
+// enqueue sends the email of kind about the registration of message right
+// away. Redelivered messages are enqueued once.
+func (uc *NotifyRegistrantsUseCase) enqueue(ctx context.Context, message dto.RegistrationMessage, kind string) error {
+	return uc.enqueueAt(ctx, message.Registration, kind, message.ID.String()+":"+kind, time.Now())
+}
+
+// enqueueReminder schedules the reminder of the registration, unless it is
+// already too late for it. A registration has at most one reminder.
+func (uc *NotifyRegistrantsUseCase) enqueueReminder(ctx context.Context, registration dto.RegistrationPayload) error {
+	if uc.reminderBefore <= 0 {
+		return nil
+	}
+
+	event, err := uc.events.GetEventWithDeleted(ctx, registration.EventID)
+	if err != nil {
+		return fmt.Errorf("get event %s: %w", registration.EventID, err)
+	}
+
+	sendAt := event.StartTime.Add(-uc.reminderBefore)
+	if !sendAt.After(time.Now()) {
+		return nil
+	}
+
+	dedupeKey := entity.NotificationReminder + ":" + registration.ID.String()
+	return uc.enqueueAt(ctx, registration, entity.NotificationReminder, dedupeKey, sendAt)
+}
+
+func (uc *NotifyRegistrantsUseCase) enqueueAt(
+	ctx context.Context, registration dto.RegistrationPayload, kind string, dedupeKey string, sendAt time.Time,
+) error {
+	tenantID := registration.TenantID
+	if tenantID == "" {
+		tenantID = uc.defaultTenant
+	}
+
+	enqueued, err := uc.repository.EnqueueNotification(ctx, &entity.Notification{
+		TenantID:       tenantID,
+		Kind:           kind,
+		RegistrationID: registration.ID,
+		EventID:        registration.EventID,
+		Recipient:      registration.Email,
+		DedupeKey:      dedupeKey,
+		SendAt:         sendAt,
+	})
+	if err != nil {
+		return fmt.Errorf("enqueue %s notification: %w", kind, err)
+	}
+
+	if enqueued {
+		log.Debug().
+			Str("kind", kind).
+			Str("registration_id", registration.ID.String()).
+			Time("send_at", sendAt).
+			Msg("Notification enqueued")
+	}
+	return nil
+}
+
+// reschedule moves the pending reminders of an updated event along with its
+// start.
+func (uc *NotifyRegistrantsUseCase) reschedule(ctx context.Context, subject string, data []byte) error {
+	if uc.reminderBefore <= 0 {
+		return nil
+	}
+
+	var message dto.EventMessage
+	if err := json.Unmarshal(data, &message); err != nil {
+		log.Error().Str("subject", subject).Msgf("NotifyRegistrantsUseCase.Dispatch: skipping malformed message: %v", err)
+		return nil
+	}
+
+	if message.Event.ID == uuid.Nil {
+		return nil
+	}
+
+	sendAt := message.Event.StartTime.Add(-uc.reminderBefore)
+	rescheduled, err := uc.repository.RescheduleReminders(ctx, message.Event.ID, sendAt)
+	if err != nil {
+		return fmt.Errorf("reschedule reminders: %w", err)
+	}
+
+	if rescheduled > 0 {
+		log.Info().
+			Str("event_id", message.Event.ID.String()).
+			Int("reminders", rescheduled).
+			Time("send_at", sendAt).
+			Msg("Reminders rescheduled")
+	}
+	return nil
+}
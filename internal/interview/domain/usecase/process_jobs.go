@@ -0,0 +1,176 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"online-registration/internal/interview/domain/auth"
+	"online-registration/internal/interview/domain/entity"
+	"online-registration/internal/interview/domain/jobs"
+	"online-registration/internal/interview/domain/repository"
+	"online-registration/internal/interview/domain/tenant"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// jobLease is how long a worker holds a job. Handlers are cancelled
+	// once it passes, as the job may be taken over by another worker.
+	jobLease = 5 * time.Minute
+
+	jobMinBackoff = 10 * time.Second
+	jobMaxBackoff = time.Hour
+)
+
+type ProcessJobsConfig struct {
+	// Concurrency is the number of jobs run at the same time.
+	Concurrency int
+	// PollInterval is how often due jobs are looked for while the workers
+	// are idle.
+	PollInterval time.Duration
+}
+
+// ProcessJobsUseCase runs the due jobs of the kinds it has handlers for with
+// a pool of workers.
+type ProcessJobsUseCase struct {
+	repository repository.IJobRepository
+	handlers   map[string]jobs.Handler
+	kinds      []string
+	config     ProcessJobsConfig
+}
+
+func NewProcessJobsUseCase(
+	repository repository.IJobRepository,
+	config ProcessJobsConfig,
+	handlers ...jobs.Handler,
+) *ProcessJobsUseCase {
+	uc := &ProcessJobsUseCase{
+		repository: repository,
+		handlers:   make(map[string]jobs.Handler, len(handlers)),
+		config:     config,
+	}
+	for _, handler := range handlers {
+		uc.handlers[handler.Kind()] = handler
+		uc.kinds = append(uc.kinds, handler.Kind())
+	}
+	if uc.config.Concurrency <= 0 {
+		uc.config.Concurrency = 1
+	}
+	return uc
+}
+
+// Run claims and runs due jobs until ctx is cancelled, then waits for the
+// running jobs to finish. Jobs keep running after ctx is cancelled, until
+// their lease passes.
+func (uc *ProcessJobsUseCase) Run(ctx context.Context) {
+	slots := make(chan struct{}, uc.config.Concurrency)
+	// wakes the poller up when a worker frees its slot
+	freed := make(chan struct{}, 1)
+	var running sync.WaitGroup
+	defer running.Wait()
+
+	jobCtx := context.WithoutCancel(ctx)
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+		case <-freed:
+			timer.Stop()
+		}
+
+		free := cap(slots) - len(slots)
+		claimed, err := uc.repository.ClaimJobs(ctx, uc.kinds, free, jobLease)
+		if err != nil && ctx.Err() == nil {
+			log.Error().Msgf("ProcessJobsUseCase.Run: claim jobs: %v", err)
+		}
+
+		for _, job := range claimed {
+			slots <- struct{}{}
+			running.Add(1)
+			go func() {
+				defer running.Done()
+				uc.process(jobCtx, job)
+				<-slots
+				select {
+				case freed <- struct{}{}:
+				default:
+				}
+			}()
+		}
+
+		if free > 0 && len(claimed) == free {
+			timer.Reset(0)
+			continue
+		}
+		timer.Reset(uc.config.PollInterval)
+	}
+}
+
+func (uc *ProcessJobsUseCase) process(ctx context.Context, job *entity.Job) {
+	logger := log.With().Str("job_id", job.ID.String()).Str("kind", job.Kind).Int("attempt", job.Attempts).Logger()
+
+	start := time.Now()
+	var err error
+	if job.Attempts > job.MaxAttempts {
+		// the job was claimed again after its lease passed, its last attempt
+		// was cut short by a crash or by running too long
+		err = jobs.Permanent(fmt.Errorf("gave up after %d attempts", job.MaxAttempts))
+	} else {
+		err = uc.handle(ctx, job)
+	}
+	uc.finish(ctx, job, err)
+
+	if err != nil {
+		logger.Warn().Dur("duration", time.Since(start)).Msgf("Job failed: %v", err)
+		return
+	}
+	logger.Info().Dur("duration", time.Since(start)).Msg("Job succeeded")
+}
+
+func (uc *ProcessJobsUseCase) handle(ctx context.Context, job *entity.Job) (err error) {
+	handler, ok := uc.handlers[job.Kind]
+	if !ok {
+		return fmt.Errorf("no handler for %s jobs", job.Kind)
+	}
+
+	if job.TenantID != "" {
+		ctx = tenant.ContextWithTenant(ctx, job.TenantID)
+	} else {
+		ctx = tenant.ContextWithAllTenants(ctx)
+	}
+	ctx = auth.ContextWithPrincipal(ctx, auth.SystemPrincipal("jobs"))
+	ctx, cancel := context.WithTimeout(ctx, jobLease)
+	defer cancel()
+
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = fmt.Errorf("panic: %v", recovered)
+		}
+	}()
+
+	return handler.Handle(ctx, job)
+}
+
+// finish records the outcome of an attempt, retrying failed jobs with
+// backoff until they run out of attempts.
+func (uc *ProcessJobsUseCase) finish(ctx context.Context, job *entity.Job, jobErr error) {
+	var err error
+	switch {
+	case jobErr == nil:
+		err = uc.repository.CompleteJob(ctx, job)
+	case jobs.IsPermanent(jobErr) || job.Attempts >= job.MaxAttempts:
+		err = uc.repository.FailJob(ctx, job, jobErr.Error(), nil)
+	default:
+		retryAt := time.Now().Add(retryBackoff(job.Attempts, jobMinBackoff, jobMaxBackoff))
+		err = uc.repository.FailJob(ctx, job, jobErr.Error(), &retryAt)
+	}
+
+	if err != nil {
+		log.Error().Str("job_id", job.ID.String()).Msgf("ProcessJobsUseCase: %v", err)
+	}
+}
@@ -0,0 +1,118 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"online-registration/internal/interview/domain/jobs"
+	"online-registration/internal/interview/domain/repository"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// PurgePayload is the payload of purge jobs, which take no arguments.
+type PurgePayload struct{}
+
+// PurgeJob deletes the rows the service no longer needs.
+var PurgeJob = jobs.NewKind[PurgePayload]("purge")
+
+type PurgeConfig struct {
+	// Retention is how long published outbox messages and finished jobs
+	// are kept.
+	Retention time.Duration
+	// TombstoneRetention is how long deleted events are kept.
+	TombstoneRetention time.Duration
+	// BatchSize is the number of deleted events purged per transaction.
+	BatchSize int
+	// RateLimitRefill is the longest period of the rate limits. A bucket
+	// idle for that long is full again and is deleted, zero keeps them.
+	RateLimitRefill time.Duration
+}
+
+// PurgeUseCase deletes expired idempotency keys, the outbox messages and
+// jobs that finished more than the retention ago, the events deleted more
+// than the tombstone retention ago and the rate limit buckets that refilled.
+type PurgeUseCase struct {
+	idempotencyKeys repository.IIdempotencyKeyRepository
+	outbox          repository.IOutboxRepository
+	jobs            repository.IJobRepository
+	events          repository.IEventRepository
+	rateLimits      repository.IRateLimitBucketRepository
+	config          PurgeConfig
+}
+
+func NewPurgeUseCase(
+	idempotencyKeys repository.IIdempotencyKeyRepository,
+	outbox repository.IOutboxRepository,
+	jobs repository.IJobRepository,
+	events repository.IEventRepository,
+	rateLimits repository.IRateLimitBucketRepository,
+	config PurgeConfig,
+) *PurgeUseCase {
+	if config.BatchSize <= 0 {
+		config.BatchSize = 100
+	}
+	return &PurgeUseCase{
+		idempotencyKeys: idempotencyKeys,
+		outbox:          outbox,
+		jobs:            jobs,
+		events:          events,
+		rateLimits:      rateLimits,
+		config:          config,
+	}
+}
+
+// Handler runs the purge jobs.
+func (uc *PurgeUseCase) Handler() jobs.Handler {
+	return PurgeJob.Handler(func(ctx context.Context, _ PurgePayload) error {
+		return uc.Purge(ctx)
+	})
+}
+
+func (uc *PurgeUseCase) Purge(ctx context.Context) error {
+	now := time.Now()
+
+	keys, err := uc.idempotencyKeys.DeleteExpired(ctx, now)
+	if err != nil {
+		return fmt.Errorf("purge idempotency keys: %w", err)
+	}
+
+	messages, err := uc.outbox.DeletePublished(ctx, now.Add(-uc.config.Retention))
+	if err != nil {
+		return fmt.Errorf("purge outbox: %w", err)
+	}
+
+	finished, err := uc.jobs.DeleteFinishedJobs(ctx, now.Add(-uc.config.Retention))
+	if err != nil {
+		return fmt.Errorf("purge jobs: %w", err)
+	}
+
+	tombstones := 0
+	for {
+		purged, err := uc.events.PurgeDeletedEvents(ctx, now.Add(-uc.config.TombstoneRetention), uc.config.BatchSize)
+		tombstones += purged
+		if err != nil {
+			return fmt.Errorf("purge deleted events: %w", err)
+		}
+		if purged < uc.config.BatchSize {
+			break
+		}
+	}
+
+	buckets := 0
+	if uc.config.RateLimitRefill > 0 {
+		buckets, err = uc.rateLimits.DeleteIdleBuckets(ctx, now.Add(-uc.config.RateLimitRefill))
+		if err != nil {
+			return fmt.Errorf("purge rate limit buckets: %w", err)
+		}
+	}
+
+	log.Info().
+		Int("idempotency_keys", keys).
+		Int("outbox_messages", messages).
+		Int("jobs", finished).
+		Int("deleted_events", tombstones).
+		Int("rate_limit_buckets", buckets).
+		Msg("Purged")
+	return nil
+}
@@ -0,0 +1,61 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"online-registration/internal/interview/domain/repository"
+)
+
+// purgeCutoffs records the time each repository is asked to purge before.
+type purgeCutoffs struct {
+	repository.IIdempotencyKeyRepository
+	repository.IOutboxRepository
+	repository.IJobRepository
+	repository.IEventRepository
+
+	buckets *time.Time
+}
+
+func (p *purgeCutoffs) DeleteExpired(context.Context, time.Time) (int, error) { return 0, nil }
+
+func (p *purgeCutoffs) DeletePublished(context.Context, time.Time) (int, error) { return 0, nil }
+
+func (p *purgeCutoffs) DeleteFinishedJobs(context.Context, time.Time) (int, error) { return 0, nil }
+
+func (p *purgeCutoffs) PurgeDeletedEvents(context.Context, time.Time, int) (int, error) {
+	return 0, nil
+}
+
+func (p *purgeCutoffs) DeleteIdleBuckets(_ context.Context, before time.Time) (int, error) {
+	p.buckets = &before
+	return 3, nil
+}
+
+func TestPurgeDeletesRefilledRateLimitBuckets(t *testing.T) {
+	p := &purgeCutoffs{}
+	purge := NewPurgeUseCase(p, p, p, p, p, PurgeConfig{RateLimitRefill: time.Hour})
+
+	if err := purge.Purge(context.Background()); err != nil {
+		t.Fatalf("Purge: %v", err)
+	}
+	if p.buckets == nil {
+		t.Fatal("Purge kept the rate limit buckets")
+	}
+	if idle := time.Since(*p.buckets); idle < time.Hour || idle > time.Hour+time.Minute {
+		t.Errorf("Purge deleted the buckets idle for %s, want those idle for an hour", idle)
+	}
+}
+
+func TestPurgeKeepsRateLimitBucketsWithoutRefill(t *testing.T) {
+	p := &purgeCutoffs{}
+	purge := NewPurgeUseCase(p, p, p, p, p, PurgeConfig{})
+
+	if err := purge.Purge(context.Background()); err != nil {
+		t.Fatalf("Purge: %v", err)
+	}
+	if p.buckets != nil {
+		t.Errorf("Purge deleted the rate limit buckets idle since %s without a refill period", p.buckets)
+	}
+}
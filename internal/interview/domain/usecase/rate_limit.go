@@ -0,0 +1,57 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"online-registration/internal/interview/domain/entity"
+	"online-registration/internal/interview/domain/repository"
+	"time"
+)
+
+// RateLimitDecision tells whether a request may proceed and describes the
+// quota left to the client.
+type RateLimitDecision struct {
+	Allowed   bool
+	Limit     entity.RateLimit
+	Remaining int
+	// Reset is the time until the bucket is full again.
+	Reset time.Duration
+	// RetryAfter is the time until the next request is allowed, zero when
+	// it is allowed right away.
+	RetryAfter time.Duration
+}
+
+type RateLimitUseCase struct {
+	store repository.IRateLimitStore
+}
+
+func NewRateLimitUseCase(store repository.IRateLimitStore) *RateLimitUseCase {
+	return &RateLimitUseCase{
+		store: store,
+	}
+}
+
+// Take consumes a request from the quota of key.
+func (uc *RateLimitUseCase) Take(ctx context.Context, key string, limit entity.RateLimit) (*RateLimitDecision, error) {
+	bucket, err := uc.store.Take(ctx, key, limit)
+	if err != nil {
+		return nil, fmt.Errorf("take rate limit token: %w", err)
+	}
+
+	rate := limit.TokensPerSecond()
+	decision := &RateLimitDecision{
+		Allowed:   bucket.Allowed,
+		Limit:     limit,
+		Remaining: int(math.Max(0, math.Floor(bucket.Tokens))),
+		Reset:     secondsToDuration((float64(limit.Requests) - bucket.Tokens) / rate),
+	}
+	if bucket.Tokens < 1 {
+		decision.RetryAfter = secondsToDuration((1 - bucket.Tokens) / rate)
+	}
+	return decision, nil
+}
+
+func secondsToDuration(seconds float64) time.Duration {
+	return time.Duration(math.Max(0, seconds) * float64(time.Second))
+}
@@ -0,0 +1,66 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"online-registration/internal/interview/domain/entity"
+)
+
+// bucketStore answers every Take with its bucket.
+type bucketStore struct {
+	bucket *entity.RateLimitBucket
+	err    error
+}
+
+func (s bucketStore) Take(context.Context, string, entity.RateLimit) (*entity.RateLimitBucket, error) {
+	return s.bucket, s.err
+}
+
+func TestRateLimitTake(t *testing.T) {
+	// a token every 2 seconds
+	limit := entity.RateLimit{Requests: 30, Period: time.Minute}
+
+	tests := []struct {
+		name           string
+		bucket         entity.RateLimitBucket
+		wantRemaining  int
+		wantReset      time.Duration
+		wantRetryAfter time.Duration
+	}{
+		{"full", entity.RateLimitBucket{Tokens: 29, Allowed: true}, 29, 2 * time.Second, 0},
+		{"fraction left", entity.RateLimitBucket{Tokens: 1.5, Allowed: true}, 1, 57 * time.Second, 0},
+		{"last token taken", entity.RateLimitBucket{Tokens: 0, Allowed: true}, 0, time.Minute, 2 * time.Second},
+		{"denied", entity.RateLimitBucket{Tokens: 0.25, Allowed: false}, 0, 59500 * time.Millisecond, 1500 * time.Millisecond},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bucket := tt.bucket
+			decision, err := NewRateLimitUseCase(bucketStore{bucket: &bucket}).Take(context.Background(), "ip", limit)
+			if err != nil {
+				t.Fatalf("Take: %v", err)
+			}
+			if decision.Allowed != tt.bucket.Allowed || decision.Limit != limit {
+				t.Errorf("Take = %+v, want allowed %v under %+v", decision, tt.bucket.Allowed, limit)
+			}
+			if decision.Remaining != tt.wantRemaining {
+				t.Errorf("Remaining = %d, want %d", decision.Remaining, tt.wantRemaining)
+			}
+			if decision.Reset != tt.wantReset {
+				t.Errorf("Reset = %s, want %s", decision.Reset, tt.wantReset)
+			}
+			if decision.RetryAfter != tt.wantRetryAfter {
+				t.Errorf("RetryAfter = %s, want %s", decision.RetryAfter, tt.wantRetryAfter)
+			}
+		})
+	}
+}
+
+func TestRateLimitTakeFails(t *testing.T) {
+	store := bucketStore{err: errors.New("connection refused")}
+	if _, err := NewRateLimitUseCase(store).Take(context.Background(), "ip", entity.RateLimit{Requests: 1, Period: time.Second}); err == nil {
+		t.Error("Take succeeded without a store")
+	}
+}
@@ -0,0 +1,85 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"online-registration/internal/common"
+	"online-registration/internal/interview/domain/auth"
+	"online-registration/internal/interview/domain/entity"
+	"online-registration/internal/interview/domain/policy"
+	"online-registration/internal/interview/domain/repository"
+	"online-registration/internal/interview/helper"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+// RegisterEventUseCase registers the current principal for events. Side
+// effects such as the onboarding group sync run asynchronously from the
+// registration messages.
+type RegisterEventUseCase struct {
+	repository repository.IRegistrationRepository
+	policy     *policy.Policy
+}
+
+func NewRegisterEventUseCase(
+	repository repository.IRegistrationRepository,
+	policy *policy.Policy,
+) *RegisterEventUseCase {
+	return &RegisterEventUseCase{
+		repository: repository,
+		policy:     policy,
+	}
+}
+
+func (uc *RegisterEventUseCase) Register(ctx context.Context, eventID uuid.UUID) (*entity.Registration, error) {
+	if err := uc.policy.Authorize(ctx, policy.RegisterEvents, ""); err != nil {
+		return nil, err
+	}
+	principal := auth.PrincipalFromContext(ctx)
+
+	registration, err := uc.repository.Register(ctx, eventID, principal.Subject, principal.Email)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, common.NewCodedError(helper.NotFound, common.CodeNotFound, fmt.Errorf("register: %w", err))
+	}
+	if errors.Is(err, repository.ErrEventNotOpen) {
+		return nil, common.NewCodedError(
+			helper.FailedPrecondition, common.CodeEventNotOpen, fmt.Errorf("register: %w", err),
+		)
+	}
+	if errors.Is(err, repository.ErrAlreadyRegistered) {
+		return nil, common.NewCodedError(
+			helper.AlreadyExists, common.CodeAlreadyRegistered, fmt.Errorf("register: %w", err),
+		)
+	}
+	if err != nil {
+		log.Error().Msgf("RegisterEventUseCase.Register: %v", err)
+		return nil, common.NewCodedError(
+			helper.InternalError, common.CodeRegistrationFailed, fmt.Errorf("register: %w", err),
+		)
+	}
+	return registration, nil
+}
+
+func (uc *RegisterEventUseCase) Cancel(ctx context.Context, eventID uuid.UUID) error {
+	if err := uc.policy.Authorize(ctx, policy.RegisterEvents, ""); err != nil {
+		return err
+	}
+	principal := auth.PrincipalFromContext(ctx)
+
+	_, err := uc.repository.CancelRegistration(ctx, eventID, principal.Subject)
+	if errors.Is(err, sql.ErrNoRows) {
+		return common.NewCodedError(
+			helper.NotFound, common.CodeRegistrationNotFound, fmt.Errorf("cancel registration: %w", err),
+		)
+	}
+	if err != nil {
+		log.Error().Msgf("RegisterEventUseCase.Cancel: %v", err)
+		return common.NewCodedError(
+			helper.InternalError, common.CodeRegistrationFailed, fmt.Errorf("cancel registration: %w", err),
+		)
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"online-registration/internal/interview/domain/repository"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// outboxLease is how long a claimed message stays hidden from other
+	// relays. A relay that dies mid-batch delays its messages by this much.
+	outboxLease = 30 * time.Second
+
+	outboxMinBackoff = time.Second
+	outboxMaxBackoff = 5 * time.Minute
+)
+
+// RelayOutboxUseCase publishes messages written to the outbox. Messages are
+// marked as published only after the broker acknowledged them, so delivery
+// is at-least-once and consumers must dedupe by message ID.
+type RelayOutboxUseCase struct {
+	repository repository.IOutboxRepository
+	publisher  repository.IMessagePublisher
+	batchSize  int
+}
+
+func NewRelayOutboxUseCase(
+	repository repository.IOutboxRepository,
+	publisher repository.IMessagePublisher,
+	batchSize int,
+) *RelayOutboxUseCase {
+	return &RelayOutboxUseCase{
+		repository: repository,
+		publisher:  publisher,
+		batchSize:  batchSize,
+	}
+}
+
+// Run relays messages until ctx is cancelled, polling the outbox every
+// interval while it is drained.
+func (uc *RelayOutboxUseCase) Run(ctx context.Context, interval time.Duration) {
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+		}
+
+		relayed, err := uc.RelayBatch(ctx)
+		if err != nil {
+			log.Error().Msgf("RelayOutboxUseCase.Run: %v", err)
+		}
+
+		if relayed == uc.batchSize {
+			timer.Reset(0)
+			continue
+		}
+		timer.Reset(interval)
+	}
+}
+
+// RelayBatch publishes one batch of pending messages and returns how many
+// messages were claimed.
+func (uc *RelayOutboxUseCase) RelayBatch(ctx context.Context) (int, error) {
+	messages, err := uc.repository.Claim(ctx, uc.batchSize, outboxLease)
+	if err != nil {
+		return 0, fmt.Errorf("claim outbox messages: %w", err)
+	}
+
+	published := make([]int64, 0, len(messages))
+	for _, message := range messages {
+		err := uc.publisher.Publish(ctx, message.Subject, message.MessageID.String(), message.Payload)
+		if err == nil {
+			published = append(published, message.ID)
+			continue
+		}
+
+		log.Error().
+			Int64("outbox_id", message.ID).
+			Str("subject", message.Subject).
+			Int("attempts", message.Attempts+1).
+			Msgf("Failed to publish outbox message: %v", err)
+
+		nextAttemptAt := time.Now().Add(retryBackoff(message.Attempts+1, outboxMinBackoff, outboxMaxBackoff))
+		if err := uc.repository.MarkFailed(ctx, message.ID, err.Error(), nextAttemptAt); err != nil {
+			return len(messages), fmt.Errorf("mark outbox message failed: %w", err)
+		}
+	}
+
+	if err := uc.repository.MarkPublished(ctx, published); err != nil {
+		return len(messages), fmt.Errorf("mark outbox messages published: %w", err)
+	}
+
+	return len(messages), nil
+}
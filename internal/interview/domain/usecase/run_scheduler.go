@@ -0,0 +1,229 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"online-registration/internal/interview/domain/auth"
+	"online-registration/internal/interview/domain/cron"
+	"online-registration/internal/interview/domain/repository"
+	"online-registration/internal/interview/domain/tenant"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// ScheduledTask is a task run by RunSchedulerUseCase on Schedule.
+type ScheduledTask struct {
+	Name     string
+	Schedule *cron.Schedule
+	Run      func(ctx context.Context) error
+}
+
+type SchedulerConfig struct {
+	// Instance names this instance in the runs it records.
+	Instance string
+	// PollInterval is how often an instance that isn't leading tries to take
+	// over, and how often the leader checks that it still holds the lock.
+	PollInterval time.Duration
+	// Location is the time zone the schedules are read in.
+	Location *time.Location
+}
+
+// RunSchedulerUseCase runs scheduled tasks on the instance holding the
+// leader lock. Each run claims its tick in the scheduled tasks table first,
+// so a tick runs once even when a new leader takes over mid-tick.
+type RunSchedulerUseCase struct {
+	repository repository.IScheduledTaskRepository
+	lock       repository.ILeaderLock
+	tasks      []ScheduledTask
+	config     SchedulerConfig
+}
+
+func NewRunSchedulerUseCase(
+	repository repository.IScheduledTaskRepository,
+	lock repository.ILeaderLock,
+	config SchedulerConfig,
+	tasks ...ScheduledTask,
+) *RunSchedulerUseCase {
+	if config.Location == nil {
+		config.Location = time.UTC
+	}
+	return &RunSchedulerUseCase{
+		repository: repository,
+		lock:       lock,
+		tasks:      tasks,
+		config:     config,
+	}
+}
+
+// Run leads the scheduler whenever this instance holds the leader lock,
+// until ctx is cancelled. It then waits for the running tasks and releases
+// the lock.
+func (uc *RunSchedulerUseCase) Run(ctx context.Context) {
+	// tasks run for every tenant
+	taskCtx := tenant.ContextWithAllTenants(auth.ContextWithPrincipal(ctx, auth.SystemPrincipal("scheduler")))
+
+	var running sync.WaitGroup
+	defer func() {
+		running.Wait()
+		if err := uc.lock.Release(context.WithoutCancel(ctx)); err != nil {
+			log.Error().Msgf("RunSchedulerUseCase.Run: %v", err)
+		}
+	}()
+
+	// next holds the next tick of every task while leading, and is nil
+	// otherwise
+	var next map[string]time.Time
+	// busy holds the tasks whose last run hasn't finished
+	busy := make(map[string]bool)
+	var mu sync.Mutex
+
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	var lastCheck time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+		}
+
+		if next == nil || time.Since(lastCheck) >= uc.config.PollInterval {
+			lastCheck = time.Now()
+			leading, err := uc.lock.TryAcquire(ctx)
+			if err != nil && ctx.Err() == nil {
+				log.Error().Msgf("RunSchedulerUseCase.Run: %v", err)
+			}
+
+			switch {
+			case leading && next == nil:
+				next, err = uc.plan(ctx)
+				if err != nil {
+					log.Error().Msgf("RunSchedulerUseCase.Run: %v", err)
+					next = nil
+					timer.Reset(uc.config.PollInterval)
+					continue
+				}
+				log.Info().Str("instance", uc.config.Instance).Msg("Leading the scheduler")
+			case !leading && next != nil:
+				log.Warn().Str("instance", uc.config.Instance).Msg("Lost the scheduler lead")
+				next = nil
+			}
+		}
+
+		if next == nil {
+			timer.Reset(uc.config.PollInterval)
+			continue
+		}
+
+		now := time.Now()
+		wake := now.Add(uc.config.PollInterval)
+		for _, task := range uc.tasks {
+			tick := next[task.Name]
+			if tick.IsZero() {
+				// the schedule doesn't fire anymore
+				continue
+			}
+			if tick.After(now) {
+				if tick.Before(wake) {
+					wake = tick
+				}
+				continue
+			}
+
+			nextRunAt := uc.nextTick(task, now)
+			next[task.Name] = nextRunAt
+			if !nextRunAt.IsZero() && nextRunAt.Before(wake) {
+				wake = nextRunAt
+			}
+
+			mu.Lock()
+			if busy[task.Name] {
+				mu.Unlock()
+				log.Warn().Str("task", task.Name).Time("tick", tick).Msg("Skipped a tick, the last run is still going")
+				continue
+			}
+			busy[task.Name] = true
+			mu.Unlock()
+
+			running.Add(1)
+			go func() {
+				defer running.Done()
+				uc.runTask(taskCtx, task, tick, nextRunAt)
+				mu.Lock()
+				delete(busy, task.Name)
+				mu.Unlock()
+			}()
+		}
+
+		timer.Reset(time.Until(wake))
+	}
+}
+
+// plan stores the schedules of the tasks and finds their next ticks. A tick
+// missed while no instance was leading is run once right away.
+func (uc *RunSchedulerUseCase) plan(ctx context.Context) (map[string]time.Time, error) {
+	now := time.Now()
+	next := make(map[string]time.Time, len(uc.tasks))
+	for _, task := range uc.tasks {
+		next[task.Name] = uc.nextTick(task, now)
+		stored, err := uc.repository.SaveScheduledTask(ctx, task.Name, task.Schedule.String(), next[task.Name])
+		if err != nil {
+			return nil, fmt.Errorf("plan %s: %w", task.Name, err)
+		}
+
+		if stored.LastRun != nil {
+			if missed := uc.nextTick(task, stored.LastRun.ScheduledAt); missed.Before(now) {
+				next[task.Name] = missed
+			}
+		}
+	}
+	return next, nil
+}
+
+func (uc *RunSchedulerUseCase) nextTick(task ScheduledTask, after time.Time) time.Time {
+	return task.Schedule.Next(after.In(uc.config.Location))
+}
+
+func (uc *RunSchedulerUseCase) runTask(ctx context.Context, task ScheduledTask, tick, nextRunAt time.Time) {
+	logger := log.With().Str("task", task.Name).Time("tick", tick).Logger()
+
+	claimed, err := uc.repository.ClaimTaskRun(ctx, task.Name, tick, uc.config.Instance)
+	if err != nil {
+		logger.Error().Msgf("RunSchedulerUseCase.runTask: %v", err)
+		return
+	}
+	if !claimed {
+		logger.Debug().Msg("Tick already run by another instance")
+		return
+	}
+
+	start := time.Now()
+	runErr := uc.run(ctx, task)
+
+	var lastError string
+	if runErr != nil {
+		lastError = runErr.Error()
+		logger.Warn().Dur("duration", time.Since(start)).Msgf("Scheduled task failed: %v", runErr)
+	} else {
+		logger.Info().Dur("duration", time.Since(start)).Msg("Scheduled task succeeded")
+	}
+
+	// recorded even when the task was cut short by a shutdown
+	err = uc.repository.FinishTaskRun(context.WithoutCancel(ctx), task.Name, tick, lastError, nextRunAt)
+	if err != nil {
+		logger.Error().Msgf("RunSchedulerUseCase.runTask: %v", err)
+	}
+}
+
+func (uc *RunSchedulerUseCase) run(ctx context.Context, task ScheduledTask) (err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = fmt.Errorf("panic: %v", recovered)
+		}
+	}()
+
+	return task.Run(ctx)
+}
@@ -0,0 +1,249 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"online-registration/internal/interview/domain/cron"
+	"online-registration/internal/interview/domain/entity"
+)
+
+// memoryScheduledTasks claims the runs of tasks like the database does.
+type memoryScheduledTasks struct {
+	mu    sync.Mutex
+	tasks map[string]*entity.ScheduledTask
+}
+
+func newMemoryScheduledTasks() *memoryScheduledTasks {
+	return &memoryScheduledTasks{tasks: make(map[string]*entity.ScheduledTask)}
+}
+
+func (r *memoryScheduledTasks) SaveScheduledTask(
+	_ context.Context, name, schedule string, nextRunAt time.Time,
+) (*entity.ScheduledTask, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	task, ok := r.tasks[name]
+	if !ok {
+		task = &entity.ScheduledTask{Name: name}
+		r.tasks[name] = task
+	}
+	task.Schedule = schedule
+	task.NextRunAt = &nextRunAt
+	copied := *task
+	return &copied, nil
+}
+
+func (r *memoryScheduledTasks) ListScheduledTasks(context.Context) ([]*entity.ScheduledTask, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (r *memoryScheduledTasks) ClaimTaskRun(_ context.Context, name string, tick time.Time, instance string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	task := r.tasks[name]
+	if task.LastRun != nil && !task.LastRun.ScheduledAt.Before(tick) {
+		return false, nil
+	}
+	task.LastRun = &entity.TaskRun{ScheduledAt: tick, StartedAt: time.Now(), Status: entity.TaskRunning, Instance: instance}
+	return true, nil
+}
+
+func (r *memoryScheduledTasks) FinishTaskRun(
+	_ context.Context, name string, tick time.Time, runErr string, nextRunAt time.Time,
+) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	task := r.tasks[name]
+	if task.LastRun == nil || !task.LastRun.ScheduledAt.Equal(tick) {
+		return nil
+	}
+	now := time.Now()
+	task.LastRun.FinishedAt = &now
+	task.LastRun.Status = entity.TaskSucceeded
+	if runErr != "" {
+		task.LastRun.Status = entity.TaskFailed
+		task.LastRun.Error = runErr
+	}
+	task.NextRunAt = &nextRunAt
+	return nil
+}
+
+func (r *memoryScheduledTasks) lastRun(name string) *entity.TaskRun {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if task, ok := r.tasks[name]; ok && task.LastRun != nil {
+		copied := *task.LastRun
+		return &copied
+	}
+	return nil
+}
+
+// switchLock is held while leading is set.
+type switchLock struct {
+	leading  atomic.Bool
+	released atomic.Bool
+}
+
+func (l *switchLock) TryAcquire(context.Context) (bool, error) {
+	return l.leading.Load(), nil
+}
+
+func (l *switchLock) Release(context.Context) error {
+	l.released.Store(true)
+	return nil
+}
+
+// tickRecorder records the instances that ran its tasks.
+type tickRecorder struct {
+	mu   sync.Mutex
+	runs []string
+}
+
+func (r *tickRecorder) task(t *testing.T, instance, spec string) ScheduledTask {
+	schedule, err := cron.Parse(spec)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	return ScheduledTask{Name: "report", Schedule: schedule, Run: func(context.Context) error {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.runs = append(r.runs, instance)
+		return nil
+	}}
+}
+
+func (r *tickRecorder) count(instance string) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	count := 0
+	for _, run := range r.runs {
+		if run == instance {
+			count++
+		}
+	}
+	return count
+}
+
+// runScheduler runs the scheduler of instance until stop is called.
+func runScheduler(repository *memoryScheduledTasks, lock *switchLock, instance string, task ScheduledTask) (stop func()) {
+	scheduler := NewRunSchedulerUseCase(repository, lock, SchedulerConfig{
+		Instance:     instance,
+		PollInterval: 50 * time.Millisecond,
+	}, task)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		scheduler.Run(ctx)
+	}()
+	return func() {
+		cancel()
+		<-done
+	}
+}
+
+func TestRunSchedulerRunsOnTheLeader(t *testing.T) {
+	repository := newMemoryScheduledTasks()
+	recorder := &tickRecorder{}
+	leader, follower := &switchLock{}, &switchLock{}
+	leader.leading.Store(true)
+
+	stopLeader := runScheduler(repository, leader, "a", recorder.task(t, "a", "@every 1s"))
+	stopFollower := runScheduler(repository, follower, "b", recorder.task(t, "b", "@every 1s"))
+	time.Sleep(2200 * time.Millisecond)
+
+	// the follower takes over
+	leader.leading.Store(false)
+	follower.leading.Store(true)
+	time.Sleep(100 * time.Millisecond)
+	ranByLeader := recorder.count("a")
+	time.Sleep(2 * time.Second)
+	stopLeader()
+	stopFollower()
+
+	if ranByLeader < 2 {
+		t.Errorf("leader ran the task %d times in 2s, want every second", ranByLeader)
+	}
+	if recorder.count("a") != ranByLeader {
+		t.Errorf("leader ran the task %d times after it lost the lead", recorder.count("a")-ranByLeader)
+	}
+	if recorder.count("b") < 1 {
+		t.Error("follower never ran the task after taking over")
+	}
+	if !leader.released.Load() || !follower.released.Load() {
+		t.Error("lock not released on stop")
+	}
+	if run := repository.lastRun("report"); run == nil || run.Status != entity.TaskSucceeded || run.Instance != "b" {
+		t.Errorf("last run = %+v, want succeeded on b", run)
+	}
+}
+
+func TestRunSchedulerRunsEveryTickOnce(t *testing.T) {
+	repository := newMemoryScheduledTasks()
+	recorder := &tickRecorder{}
+	// both believe they lead, as when one lost its connection unnoticed
+	first, second := &switchLock{}, &switchLock{}
+	first.leading.Store(true)
+	second.leading.Store(true)
+
+	stopFirst := runScheduler(repository, first, "a", recorder.task(t, "a", "@every 1s"))
+	stopSecond := runScheduler(repository, second, "b", recorder.task(t, "b", "@every 1s"))
+	time.Sleep(2500 * time.Millisecond)
+	stopFirst()
+	stopSecond()
+
+	// two or three ticks passed
+	if runs := recorder.count("a") + recorder.count("b"); runs < 2 || runs > 3 {
+		t.Errorf("task ran %d times in 2.5s, want once a second", runs)
+	}
+}
+
+func TestRunSchedulerCatchesUpMissedTick(t *testing.T) {
+	repository := newMemoryScheduledTasks()
+	lastTick := time.Now().Truncate(time.Minute).Add(-10 * time.Minute)
+	repository.tasks["report"] = &entity.ScheduledTask{
+		Name:    "report",
+		LastRun: &entity.TaskRun{ScheduledAt: lastTick, Status: entity.TaskSucceeded},
+	}
+	recorder := &tickRecorder{}
+	lock := &switchLock{}
+	lock.leading.Store(true)
+
+	stop := runScheduler(repository, lock, "a", recorder.task(t, "a", "* * * * *"))
+	time.Sleep(300 * time.Millisecond)
+	stop()
+
+	if recorder.count("a") != 1 {
+		t.Fatalf("task ran %d times on takeover, want the missed tick once", recorder.count("a"))
+	}
+	if run := repository.lastRun("report"); !run.ScheduledAt.Equal(lastTick.Add(time.Minute)) {
+		t.Errorf("ran the tick of %s, want the one missed after %s", run.ScheduledAt, lastTick)
+	}
+}
+
+func TestRunSchedulerRecordsPanics(t *testing.T) {
+	repository := newMemoryScheduledTasks()
+	lock := &switchLock{}
+	lock.leading.Store(true)
+	task := (&tickRecorder{}).task(t, "a", "@every 1s")
+	task.Run = func(context.Context) error { panic("nil map") }
+
+	stop := runScheduler(repository, lock, "a", task)
+	time.Sleep(1500 * time.Millisecond)
+	stop()
+
+	if run := repository.lastRun("report"); run == nil || run.Status != entity.TaskFailed || run.Error != "panic: nil map" {
+		t.Errorf("last run = %+v, want failed with the panic", run)
+	}
+}
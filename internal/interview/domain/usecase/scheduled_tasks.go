@@ -0,0 +1,45 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"online-registration/internal/common"
+	"online-registration/internal/interview/domain/entity"
+	"online-registration/internal/interview/domain/policy"
+	"online-registration/internal/interview/domain/repository"
+	"online-registration/internal/interview/helper"
+
+	"github.com/rs/zerolog/log"
+)
+
+// ScheduledTasksUseCase reports the last and next runs of the scheduled
+// tasks, as recorded by the scheduler.
+type ScheduledTasksUseCase struct {
+	repository repository.IScheduledTaskRepository
+	policy     *policy.Policy
+}
+
+func NewScheduledTasksUseCase(
+	repository repository.IScheduledTaskRepository,
+	policy *policy.Policy,
+) *ScheduledTasksUseCase {
+	return &ScheduledTasksUseCase{
+		repository: repository,
+		policy:     policy,
+	}
+}
+
+func (uc *ScheduledTasksUseCase) List(ctx context.Context) ([]*entity.ScheduledTask, error) {
+	if err := uc.policy.Authorize(ctx, policy.ReadSchedule, ""); err != nil {
+		return nil, err
+	}
+
+	tasks, err := uc.repository.ListScheduledTasks(ctx)
+	if err != nil {
+		log.Error().Msgf("ScheduledTasksUseCase.List: %v", err)
+		return nil, common.NewCodedError(
+			helper.InternalError, common.CodeInternalError, fmt.Errorf("list scheduled tasks: %w", err),
+		)
+	}
+	return tasks, nil
+}
@@ -0,0 +1,373 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"online-registration/internal/common"
+	"online-registration/internal/interview/domain/auth"
+	"online-registration/internal/interview/domain/dto"
+	"online-registration/internal/interview/domain/entity"
+	"online-registration/internal/interview/domain/policy"
+	"online-registration/internal/interview/domain/repository"
+	"online-registration/internal/interview/domain/tenant"
+	"online-registration/internal/interview/helper"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	eventStreamMinBackoff = time.Second
+	eventStreamMaxBackoff = 30 * time.Second
+	// eventStreamBatchSize bounds the notified changes read at once.
+	eventStreamBatchSize = 100
+)
+
+type EventStreamConfig struct {
+	// Buffer is how many changes a client may fall behind before it is
+	// dropped. A dropped client reconnects and resumes the stream.
+	Buffer int
+	// ReplayLimit is the most changes replayed to a resuming client. A
+	// client further behind is told to reload the events instead.
+	ReplayLimit int
+}
+
+// StreamEventChangesUseCase fans the event changes out to the clients
+// streaming them from this instance. The changes are notified by Postgres
+// when their transactions commit, so clients see the changes made through
+// every instance.
+//
+// Changes are ordered by their outbox id, which is taken when the change is
+// written rather than when it commits. A change that commits after one with
+// a greater id is still streamed live, but a client resuming between the two
+// misses it.
+type StreamEventChangesUseCase struct {
+	repository repository.IEventChangeRepository
+	policy     *policy.Policy
+	config     EventStreamConfig
+
+	mu          sync.Mutex
+	subscribers map[*EventSubscription]struct{}
+	stopped     bool
+}
+
+func NewStreamEventChangesUseCase(
+	repository repository.IEventChangeRepository,
+	policy *policy.Policy,
+	config EventStreamConfig,
+) *StreamEventChangesUseCase {
+	return &StreamEventChangesUseCase{
+		repository:  repository,
+		policy:      policy,
+		config:      config,
+		subscribers: make(map[*EventSubscription]struct{}),
+	}
+}
+
+// EventSubscription is the stream of the changes selected by one client.
+type EventSubscription struct {
+	// Replay holds the changes made since the change the client resumed
+	// after.
+	Replay []*entity.EventChange
+	// Reset is set when the client resumed too far behind to replay the
+	// changes it missed. It must reload the events it shows.
+	Reset bool
+
+	uc      *StreamEventChangesUseCase
+	match   func(change *entity.EventChange) bool
+	changes chan *entity.EventChange
+	// pending holds the live changes while Replay is read, they are sent
+	// once it is
+	pending   []*entity.EventChange
+	replaying bool
+	closed    bool
+}
+
+// Changes returns the live changes. The channel is closed when the client
+// falls behind by more than the buffer, or when the stream stops.
+func (s *EventSubscription) Changes() <-chan *entity.EventChange {
+	return s.changes
+}
+
+// Close stops the subscription.
+func (s *EventSubscription) Close() {
+	s.uc.mu.Lock()
+	defer s.uc.mu.Unlock()
+	s.uc.drop(s)
+}
+
+// Subscribe starts streaming the changes selected by requestDTO to the
+// principal of ctx, with the drafts it may see.
+func (uc *StreamEventChangesUseCase) Subscribe(
+	ctx context.Context, requestDTO *dto.EventStreamRequestDTO,
+) (*EventSubscription, error) {
+	if err := uc.policy.Authorize(ctx, policy.ReadEvents, ""); err != nil {
+		return nil, err
+	}
+
+	for _, eventType := range requestDTO.Types {
+		if !slices.Contains(dto.EventTypes, eventType) {
+			return nil, common.NewCodedError(
+				helper.InvalidArgument, common.CodeInvalidEventTypes,
+				fmt.Errorf("invalid event type %q", eventType),
+				strings.Join(dto.EventTypes, ", "),
+			)
+		}
+	}
+
+	s := &EventSubscription{
+		uc:        uc,
+		match:     uc.matcher(ctx, requestDTO),
+		changes:   make(chan *entity.EventChange, uc.config.Buffer),
+		replaying: requestDTO.LastEventID > 0,
+	}
+
+	uc.mu.Lock()
+	if uc.stopped {
+		s.closed = true
+		close(s.changes)
+	} else {
+		uc.subscribers[s] = struct{}{}
+	}
+	uc.mu.Unlock()
+
+	if !s.replaying {
+		return s, nil
+	}
+
+	// the subscriber is added first so that no change falls between the
+	// replay and the live changes
+	changes, err := uc.repository.ListEventChangesAfter(ctx, requestDTO.LastEventID, uc.config.ReplayLimit+1)
+	if err != nil {
+		s.Close()
+		log.Error().Msgf("StreamEventChangesUseCase.Subscribe: %v", err)
+		return nil, common.NewCodedError(
+			helper.InternalError, common.CodeInternalError, fmt.Errorf("replay event changes: %w", err),
+		)
+	}
+
+	replayed := make(map[int64]bool, len(changes))
+	if len(changes) > uc.config.ReplayLimit {
+		s.Reset = true
+	} else {
+		for _, change := range changes {
+			replayed[change.ID] = true
+			if s.match(change) {
+				s.Replay = append(s.Replay, change)
+			}
+		}
+	}
+
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+	s.replaying = false
+	if !s.closed {
+		// pending holds at most Buffer changes, see fanOut
+		for _, change := range s.pending {
+			if !replayed[change.ID] {
+				s.changes <- change
+			}
+		}
+	}
+	s.pending = nil
+	return s, nil
+}
+
+// matcher returns whether a change is selected by requestDTO and visible to
+// the principal of ctx.
+func (uc *StreamEventChangesUseCase) matcher(
+	ctx context.Context, requestDTO *dto.EventStreamRequestDTO,
+) func(change *entity.EventChange) bool {
+	tenantID, scoped := tenant.FromContext(ctx)
+	scoped = scoped && !tenant.AllTenants(ctx)
+
+	anyDraft, ownDrafts := uc.policy.Reach(ctx, policy.UpdateEvents)
+	var subject string
+	if principal := auth.PrincipalFromContext(ctx); principal != nil {
+		subject = principal.Subject
+	}
+
+	types := requestDTO.Types
+	eventIDs := requestDTO.EventIDs
+	categoryIDs := requestDTO.CategoryIDs
+	tags := entity.NormalizeTags(requestDTO.Tags)
+
+	return func(change *entity.EventChange) bool {
+		if scoped && change.TenantID != tenantID {
+			return false
+		}
+		if change.Status == entity.EventDraft && !anyDraft &&
+			(!ownDrafts || subject == "" || change.CreatedBy != subject) {
+			return false
+		}
+		if len(types) > 0 && !slices.Contains(types, change.Type) {
+			return false
+		}
+		if len(eventIDs) > 0 && !slices.Contains(eventIDs, change.EventID) {
+			return false
+		}
+		if len(categoryIDs) > 0 &&
+			(change.CategoryID == nil || !slices.Contains(categoryIDs, *change.CategoryID)) {
+			return false
+		}
+		if len(tags) > 0 && !slices.ContainsFunc(change.Tags, func(tag string) bool {
+			return slices.Contains(tags, tag)
+		}) {
+			return false
+		}
+		return true
+	}
+}
+
+// Run listens for changes and fans them out until ctx is cancelled, then
+// closes the subscriptions. It listens again after the connection fails,
+// catching up with the changes committed meanwhile.
+func (uc *StreamEventChangesUseCase) Run(ctx context.Context) {
+	defer uc.stop()
+
+	// changes are read for every tenant, the subscriptions filter them
+	ctx = tenant.ContextWithAllTenants(ctx)
+
+	var lastID int64
+	backoff := eventStreamMinBackoff
+	for {
+		// 0 tells that the listener listens, outbox ids start at 1
+		notified := make(chan int64, eventStreamBatchSize)
+		listenErr := make(chan error, 1)
+		go func() {
+			defer close(notified)
+			listenErr <- uc.repository.ListenEventChanges(ctx,
+				func() { notified <- 0 },
+				func(id int64) { notified <- id },
+			)
+		}()
+
+		var caughtUp map[int64]bool
+		for id := range notified {
+			if id == 0 {
+				backoff = eventStreamMinBackoff
+				caughtUp = uc.catchUp(ctx, lastID)
+				for caughtUpID := range caughtUp {
+					lastID = max(lastID, caughtUpID)
+				}
+				continue
+			}
+			if caughtUp[id] {
+				continue
+			}
+
+			ids := []int64{id}
+			for len(ids) < eventStreamBatchSize && len(notified) > 0 {
+				if id := <-notified; id != 0 && !caughtUp[id] {
+					ids = append(ids, id)
+				}
+			}
+
+			changes, err := uc.repository.ListEventChanges(ctx, ids)
+			if err != nil {
+				log.Error().Msgf("StreamEventChangesUseCase.Run: %v", err)
+				continue
+			}
+			for _, change := range changes {
+				lastID = max(lastID, change.ID)
+				uc.fanOut(change)
+			}
+		}
+
+		if err := <-listenErr; err != nil && ctx.Err() == nil {
+			log.Error().Msgf("StreamEventChangesUseCase.Run: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, eventStreamMaxBackoff)
+	}
+}
+
+// catchUp fans out the changes made after lastID while the listener wasn't
+// listening, and returns their ids. The clients are dropped, to resume the
+// stream themselves, when the changes can't be read.
+func (uc *StreamEventChangesUseCase) catchUp(ctx context.Context, lastID int64) map[int64]bool {
+	if lastID == 0 {
+		// nothing was streamed yet
+		return nil
+	}
+
+	changes, err := uc.repository.ListEventChangesAfter(ctx, lastID, uc.config.ReplayLimit+1)
+	if err != nil || len(changes) > uc.config.ReplayLimit {
+		if err != nil {
+			log.Error().Msgf("StreamEventChangesUseCase.catchUp: %v", err)
+		}
+		uc.dropAll()
+		return nil
+	}
+
+	caughtUp := make(map[int64]bool, len(changes))
+	for _, change := range changes {
+		caughtUp[change.ID] = true
+		uc.fanOut(change)
+	}
+	return caughtUp
+}
+
+func (uc *StreamEventChangesUseCase) fanOut(change *entity.EventChange) {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	for s := range uc.subscribers {
+		if !s.match(change) {
+			continue
+		}
+
+		if s.replaying {
+			if len(s.pending) < uc.config.Buffer {
+				s.pending = append(s.pending, change)
+			} else {
+				uc.drop(s)
+			}
+			continue
+		}
+
+		select {
+		case s.changes <- change:
+		default:
+			// the client is too slow, it resumes after reconnecting
+			uc.drop(s)
+		}
+	}
+}
+
+func (uc *StreamEventChangesUseCase) dropAll() {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	for s := range uc.subscribers {
+		uc.drop(s)
+	}
+}
+
+func (uc *StreamEventChangesUseCase) stop() {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	uc.stopped = true
+	for s := range uc.subscribers {
+		uc.drop(s)
+	}
+}
+
+// drop closes a subscription, uc.mu must be held.
+func (uc *StreamEventChangesUseCase) drop(s *EventSubscription) {
+	if s.closed {
+		return
+	}
+	s.closed = true
+	close(s.changes)
+	delete(uc.subscribers, s)
+}
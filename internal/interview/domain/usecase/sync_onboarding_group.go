@@ -0,0 +1,69 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"online-registration/internal/interview/domain/dto"
+	"online-registration/internal/interview/domain/repository"
+	"online-registration/internal/interview/domain/tenant"
+
+	"github.com/rs/zerolog/log"
+)
+
+// SyncOnboardingGroupUseCase keeps the onboarding group of the identity
+// provider in line with the registrations for onboarding events. The desired
+// membership is read back from the database instead of being derived from the
+// message alone, so redelivered or reordered messages and users registered
+// for several onboarding events are handled correctly.
+type SyncOnboardingGroupUseCase struct {
+	repository repository.IRegistrationRepository
+	client     repository.IOnboardingGroupClient
+	groupID    string
+}
+
+func NewSyncOnboardingGroupUseCase(
+	repository repository.IRegistrationRepository,
+	client repository.IOnboardingGroupClient,
+	groupID string,
+) *SyncOnboardingGroupUseCase {
+	return &SyncOnboardingGroupUseCase{
+		repository: repository,
+		client:     client,
+		groupID:    groupID,
+	}
+}
+
+func (uc *SyncOnboardingGroupUseCase) Sync(ctx context.Context, subject string, data []byte) error {
+	var message dto.RegistrationMessage
+	if err := json.Unmarshal(data, &message); err != nil {
+		log.Error().Str("subject", subject).Msgf("SyncOnboardingGroupUseCase.Sync: skipping malformed message: %v", err)
+		return nil
+	}
+
+	if !message.Registration.Onboarding {
+		return nil
+	}
+
+	// the onboarding group is shared by all tenants, so are the
+	// registrations that keep a user in it
+	userID := message.Registration.UserID
+	member, err := uc.repository.HasOnboardingRegistration(tenant.ContextWithAllTenants(ctx), userID)
+	if err != nil {
+		return fmt.Errorf("check onboarding registrations: %w", err)
+	}
+
+	if member {
+		if err := uc.client.AddGroupMember(ctx, uc.groupID, userID); err != nil {
+			return fmt.Errorf("add %s to onboarding group: %w", userID, err)
+		}
+		log.Info().Str("user_id", userID).Msg("User added to the onboarding group")
+		return nil
+	}
+
+	if err := uc.client.RemoveGroupMember(ctx, uc.groupID, userID); err != nil {
+		return fmt.Errorf("remove %s from onboarding group: %w", userID, err)
+	}
+	log.Info().Str("user_id", userID).Msg("User removed from the onboarding group")
+	return nil
+}
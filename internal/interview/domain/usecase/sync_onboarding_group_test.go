@@ -0,0 +1,245 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"online-registration/internal/interview/domain/dto"
+	"online-registration/internal/interview/domain/entity"
+	"online-registration/internal/interview/domain/tenant"
+	"online-registration/internal/interview/infrastructure/avanpost"
+	"online-registration/internal/interview/infrastructure/avanpost/fake"
+
+	"github.com/google/uuid"
+)
+
+const onboardingGroup = "onboarding"
+
+// onboardingRegistrations holds which users have an onboarding registration.
+type onboardingRegistrations struct {
+	mu      sync.Mutex
+	members map[string]bool
+}
+
+func (r *onboardingRegistrations) set(userID string, registered bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.members[userID] = registered
+}
+
+func (r *onboardingRegistrations) Register(context.Context, uuid.UUID, string, string) (*entity.Registration, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (r *onboardingRegistrations) CancelRegistration(context.Context, uuid.UUID, string) (*entity.Registration, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (r *onboardingRegistrations) HasOnboardingRegistration(ctx context.Context, userID string) (bool, error) {
+	if !tenant.AllTenants(ctx) {
+		return false, errors.New("onboarding registrations must be read across tenants")
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.members[userID], nil
+}
+
+type onboardingSyncTest struct {
+	server        *fake.Server
+	registrations *onboardingRegistrations
+	sync          *SyncOnboardingGroupUseCase
+}
+
+func newOnboardingSyncTest(t *testing.T, config avanpost.ClientConfig) *onboardingSyncTest {
+	t.Helper()
+
+	server := fake.NewServer("registration", "secret")
+	httpServer := httptest.NewServer(server)
+	t.Cleanup(httpServer.Close)
+
+	config.BaseURL = httpServer.URL
+	config.ClientID = "registration"
+	if config.ClientSecret == "" {
+		config.ClientSecret = "secret"
+	}
+	config.TokenURL = httpServer.URL + "/oauth2/token"
+	config.Timeout = time.Second
+
+	registrations := &onboardingRegistrations{members: make(map[string]bool)}
+	return &onboardingSyncTest{
+		server:        server,
+		registrations: registrations,
+		sync:          NewSyncOnboardingGroupUseCase(registrations, avanpost.NewClient(config), onboardingGroup),
+	}
+}
+
+func registrationMessage(t *testing.T, subject, userID string, onboarding bool) []byte {
+	t.Helper()
+
+	data, err := json.Marshal(dto.RegistrationMessage{
+		ID:   uuid.New(),
+		Type: subject,
+		Registration: dto.RegistrationPayload{
+			ID:         uuid.New(),
+			TenantID:   "acme",
+			EventID:    uuid.New(),
+			UserID:     userID,
+			Onboarding: onboarding,
+		},
+	})
+	if err != nil {
+		t.Fatalf("marshal message: %v", err)
+	}
+	return data
+}
+
+func (s *onboardingSyncTest) run(t *testing.T, subject, userID string, onboarding bool) error {
+	t.Helper()
+	return s.sync.Sync(context.Background(), subject, registrationMessage(t, subject, userID, onboarding))
+}
+
+func (s *onboardingSyncTest) wantMembers(t *testing.T, want ...string) {
+	t.Helper()
+	if got := s.server.Members(onboardingGroup); !slices.Equal(got, want) {
+		t.Errorf("onboarding group holds %v, want %v", got, want)
+	}
+}
+
+func TestSyncOnboardingGroup(t *testing.T) {
+	s := newOnboardingSyncTest(t, avanpost.ClientConfig{})
+
+	s.registrations.set("alice", true)
+	s.registrations.set("bob", true)
+	for _, user := range []string{"alice", "bob"} {
+		if err := s.run(t, dto.RegistrationCreatedSubject, user, true); err != nil {
+			t.Fatalf("Sync %s: %v", user, err)
+		}
+	}
+	s.wantMembers(t, "alice", "bob")
+
+	// a redelivered message changes nothing
+	if err := s.run(t, dto.RegistrationCreatedSubject, "alice", true); err != nil {
+		t.Fatalf("Sync redelivered: %v", err)
+	}
+	s.wantMembers(t, "alice", "bob")
+
+	// alice cancelled one registration but is still registered for another
+	// onboarding event
+	if err := s.run(t, dto.RegistrationCancelledSubject, "alice", true); err != nil {
+		t.Fatalf("Sync cancelled: %v", err)
+	}
+	s.wantMembers(t, "alice", "bob")
+
+	s.registrations.set("bob", false)
+	if err := s.run(t, dto.RegistrationCancelledSubject, "bob", true); err != nil {
+		t.Fatalf("Sync cancelled: %v", err)
+	}
+	s.wantMembers(t, "alice")
+
+	// removing a user that already left succeeds
+	if err := s.run(t, dto.RegistrationCancelledSubject, "bob", true); err != nil {
+		t.Fatalf("Sync removal of a missing member: %v", err)
+	}
+
+	s.registrations.set("carol", true)
+	if err := s.run(t, dto.RegistrationCreatedSubject, "carol", false); err != nil {
+		t.Fatalf("Sync of a regular event: %v", err)
+	}
+	if err := s.sync.Sync(context.Background(), dto.RegistrationCreatedSubject, []byte("{")); err != nil {
+		t.Fatalf("Sync of a malformed message: %v", err)
+	}
+	s.wantMembers(t, "alice")
+}
+
+func TestSyncOnboardingGroupRetries(t *testing.T) {
+	s := newOnboardingSyncTest(t, avanpost.ClientConfig{MaxRetries: 3, RetryBackoff: time.Millisecond})
+	s.registrations.set("alice", true)
+	s.registrations.set("bob", true)
+
+	s.server.FailNext(2, http.StatusServiceUnavailable)
+	s.server.FailNext(1, http.StatusTooManyRequests)
+	if err := s.run(t, dto.RegistrationCreatedSubject, "alice", true); err != nil {
+		t.Fatalf("Sync with transient failures: %v", err)
+	}
+	s.wantMembers(t, "alice")
+
+	s.server.FailNext(4, http.StatusBadGateway)
+	if err := s.run(t, dto.RegistrationCreatedSubject, "bob", true); err == nil {
+		t.Fatal("Sync succeeded after the retries ran out")
+	}
+	s.wantMembers(t, "alice")
+
+	// the message is redelivered by the broker
+	if err := s.run(t, dto.RegistrationCreatedSubject, "bob", true); err != nil {
+		t.Fatalf("Sync redelivered: %v", err)
+	}
+	s.wantMembers(t, "alice", "bob")
+}
+
+func TestSyncOnboardingGroupDoesNotRetryClientErrors(t *testing.T) {
+	s := newOnboardingSyncTest(t, avanpost.ClientConfig{MaxRetries: 3, RetryBackoff: time.Millisecond})
+	s.registrations.set("alice", true)
+
+	s.server.FailNext(1, http.StatusForbidden)
+	if err := s.run(t, dto.RegistrationCreatedSubject, "alice", true); err == nil {
+		t.Fatal("Sync succeeded on a forbidden response")
+	}
+	s.wantMembers(t)
+}
+
+func TestSyncOnboardingGroupCircuitBreaker(t *testing.T) {
+	const cooldown = 100 * time.Millisecond
+	s := newOnboardingSyncTest(t, avanpost.ClientConfig{BreakerThreshold: 2, BreakerCooldown: cooldown})
+	s.registrations.set("alice", true)
+
+	s.server.FailNext(2, http.StatusInternalServerError)
+	for range 2 {
+		err := s.run(t, dto.RegistrationCreatedSubject, "alice", true)
+		if err == nil || errors.Is(err, avanpost.ErrCircuitOpen) {
+			t.Fatalf("Sync while Avanpost fails = %v, want the failure", err)
+		}
+	}
+
+	// open: Avanpost is not called, the next failure would be served
+	s.server.FailNext(1, http.StatusInternalServerError)
+	if err := s.run(t, dto.RegistrationCreatedSubject, "alice", true); !errors.Is(err, avanpost.ErrCircuitOpen) {
+		t.Fatalf("Sync while the circuit is open = %v, want ErrCircuitOpen", err)
+	}
+
+	// half open: the failing trial opens the circuit again
+	time.Sleep(cooldown)
+	if err := s.run(t, dto.RegistrationCreatedSubject, "alice", true); err == nil || errors.Is(err, avanpost.ErrCircuitOpen) {
+		t.Fatalf("Sync of the trial call = %v, want the failure", err)
+	}
+	if err := s.run(t, dto.RegistrationCreatedSubject, "alice", true); !errors.Is(err, avanpost.ErrCircuitOpen) {
+		t.Fatalf("Sync after a failed trial = %v, want ErrCircuitOpen", err)
+	}
+
+	// a successful trial closes it
+	time.Sleep(cooldown)
+	if err := s.run(t, dto.RegistrationCreatedSubject, "alice", true); err != nil {
+		t.Fatalf("Sync of the trial call: %v", err)
+	}
+	s.wantMembers(t, "alice")
+	if err := s.run(t, dto.RegistrationCancelledSubject, "bob", true); err != nil {
+		t.Fatalf("Sync once the circuit is closed: %v", err)
+	}
+}
+
+func TestSyncOnboardingGroupAuthenticates(t *testing.T) {
+	s := newOnboardingSyncTest(t, avanpost.ClientConfig{ClientSecret: "wrong"})
+	s.registrations.set("alice", true)
+
+	if err := s.run(t, dto.RegistrationCreatedSubject, "alice", true); err == nil {
+		t.Fatal("Sync with wrong client credentials succeeded")
+	}
+	s.wantMembers(t)
+}
@@ -0,0 +1,73 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"online-registration/internal/common"
+	"online-registration/internal/interview/domain/dto"
+	"online-registration/internal/interview/domain/entity"
+	"online-registration/internal/interview/domain/policy"
+	"online-registration/internal/interview/domain/repository"
+	"online-registration/internal/interview/helper"
+
+	"github.com/rs/zerolog/log"
+)
+
+type UpdateEventUseCase struct {
+	repository repository.IEventRepository
+	policy     *policy.Policy
+}
+
+func NewUpdateEventUseCase(
+	repository repository.IEventRepository,
+	policy *policy.Policy,
+) *UpdateEventUseCase {
+	return &UpdateEventUseCase{
+		repository: repository,
+		policy:     policy,
+	}
+}
+
+func (uc *UpdateEventUseCase) UpdateEvent(
+	ctx context.Context,
+	requestDTO *dto.UpdateEventRequestDTO,
+) (*entity.Event, error) {
+	current, err := authorizeEvent(ctx, uc.repository, uc.policy, policy.UpdateEvents, requestDTO.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	event, err := uc.repository.UpdateEvent(ctx, &entity.Event{
+		ID:          requestDTO.ID,
+		Title:       requestDTO.Title,
+		Description: requestDTO.Description,
+		StartTime:   requestDTO.StartTime,
+		EndTime:     requestDTO.EndTime,
+		TimeZone:    requestDTO.TimeZone,
+		AllDay:      requestDTO.AllDay,
+		Onboarding:  requestDTO.Onboarding,
+		RoomID:      requestDTO.RoomID,
+		CategoryID:  requestDTO.CategoryID,
+		Tags:        requestDTO.Tags,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, common.NewCodedError(helper.NotFound, common.CodeNotFound, fmt.Errorf("update event: %w", err))
+	}
+	if errors.Is(err, repository.ErrEventNotEditable) {
+		return nil, common.NewCodedError(
+			helper.FailedPrecondition, common.CodeEventNotEditable, fmt.Errorf("update event: %w", err), current.Status,
+		)
+	}
+	if err := bookingError("update event", err); err != nil {
+		return nil, err
+	}
+	if err != nil {
+		log.Error().Msgf("UpdateEventUseCase.UpdateEvent: %v", err)
+		return nil, common.NewCodedError(
+			helper.InternalError, common.CodeEventUpdateFailed, fmt.Errorf("update event: %w", err),
+		)
+	}
+	return event, nil
+}
@@ -0,0 +1,388 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"online-registration/internal/common"
+	"online-registration/internal/interview/domain/auth"
+	"online-registration/internal/interview/domain/entity"
+	"online-registration/internal/interview/domain/policy"
+	"online-registration/internal/interview/domain/repository"
+	"online-registration/internal/interview/domain/tenant"
+	"online-registration/internal/interview/helper"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// MaxWatchedEvents bounds the events a connection watches at once.
+	MaxWatchedEvents = 50
+
+	seatAvailabilityRetry = time.Second
+)
+
+type SeatAvailabilityConfig struct {
+	// MaxConnections bounds the connections watching seats on this
+	// instance.
+	MaxConnections int
+}
+
+// WatchSeatAvailabilityUseCase pushes the seats left at events to the
+// connections watching them on this instance. The events whose seats change
+// are notified by Postgres, so a connection sees the registrations made
+// through every instance.
+//
+// A watcher only keeps the latest availability of each event until it is
+// taken, so a slow connection skips the intermediate counts instead of
+// falling further behind.
+type WatchSeatAvailabilityUseCase struct {
+	repository repository.ISeatAvailabilityRepository
+	policy     *policy.Policy
+	config     SeatAvailabilityConfig
+
+	mu       sync.Mutex
+	watchers map[uuid.UUID]map[*SeatWatcher]struct{}
+	// changed holds the watched events to read again, signal wakes Run up
+	// when it gets some
+	changed   map[uuid.UUID]bool
+	signal    chan struct{}
+	connected map[*SeatWatcher]struct{}
+	stopped   bool
+}
+
+func NewWatchSeatAvailabilityUseCase(
+	repository repository.ISeatAvailabilityRepository,
+	policy *policy.Policy,
+	config SeatAvailabilityConfig,
+) *WatchSeatAvailabilityUseCase {
+	return &WatchSeatAvailabilityUseCase{
+		repository: repository,
+		policy:     policy,
+		config:     config,
+		watchers:   make(map[uuid.UUID]map[*SeatWatcher]struct{}),
+		changed:    make(map[uuid.UUID]bool),
+		signal:     make(chan struct{}, 1),
+		connected:  make(map[*SeatWatcher]struct{}),
+	}
+}
+
+// SeatWatcher is the connection of one client, watching the seats of some
+// events.
+type SeatWatcher struct {
+	uc *WatchSeatAvailabilityUseCase
+
+	anyDraft, ownDrafts bool
+	subject             string
+
+	// guarded by uc.mu
+	events  map[uuid.UUID]bool
+	pending map[uuid.UUID]*entity.SeatAvailability
+	closed  bool
+
+	ready chan struct{}
+	done  chan struct{}
+}
+
+// Connect opens a watcher for the principal of ctx, unless the instance has
+// too many connections already.
+func (uc *WatchSeatAvailabilityUseCase) Connect(ctx context.Context) (*SeatWatcher, error) {
+	if err := uc.policy.Authorize(ctx, policy.ReadEvents, ""); err != nil {
+		return nil, err
+	}
+
+	w := &SeatWatcher{
+		uc:      uc,
+		events:  make(map[uuid.UUID]bool),
+		pending: make(map[uuid.UUID]*entity.SeatAvailability),
+		ready:   make(chan struct{}, 1),
+		done:    make(chan struct{}),
+	}
+	w.anyDraft, w.ownDrafts = uc.policy.Reach(ctx, policy.UpdateEvents)
+	if principal := auth.PrincipalFromContext(ctx); principal != nil {
+		w.subject = principal.Subject
+	}
+
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	if uc.stopped || len(uc.connected) >= uc.config.MaxConnections {
+		return nil, common.NewCodedError(
+			helper.Unavailable, common.CodeTooManyConnections,
+			fmt.Errorf("connect: %d connections are open", len(uc.connected)),
+		)
+	}
+	uc.connected[w] = struct{}{}
+	return w, nil
+}
+
+// Watch starts pushing the seats of the events to the watcher and returns
+// their current availability. Events the principal can't see are rejected
+// as not found.
+func (w *SeatWatcher) Watch(ctx context.Context, eventIDs []uuid.UUID) ([]*entity.SeatAvailability, error) {
+	uc := w.uc
+
+	unique := make(map[uuid.UUID]bool, len(eventIDs))
+	eventIDs = slices.DeleteFunc(slices.Clone(eventIDs), func(id uuid.UUID) bool {
+		duplicate := unique[id]
+		unique[id] = true
+		return duplicate
+	})
+
+	uc.mu.Lock()
+	watched := len(w.events)
+	for _, id := range eventIDs {
+		if !w.events[id] {
+			watched++
+		}
+	}
+	uc.mu.Unlock()
+	if watched > MaxWatchedEvents {
+		return nil, common.NewCodedError(
+			helper.InvalidArgument, common.CodeTooManyWatchedEvents,
+			fmt.Errorf("watch %d events", watched), MaxWatchedEvents,
+		)
+	}
+
+	availability, err := uc.repository.GetSeatAvailability(ctx, eventIDs)
+	if err != nil {
+		log.Error().Msgf("SeatWatcher.Watch: %v", err)
+		return nil, common.NewCodedError(
+			helper.InternalError, common.CodeInternalError, fmt.Errorf("watch seats: %w", err),
+		)
+	}
+
+	for _, id := range eventIDs {
+		i := slices.IndexFunc(availability, func(seats *entity.SeatAvailability) bool {
+			return seats.EventID == id
+		})
+		if i < 0 || !w.visible(availability[i]) {
+			return nil, common.NewCodedError(
+				helper.NotFound, common.CodeNotFound, fmt.Errorf("watch seats: event %s not found", id),
+			)
+		}
+	}
+
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+	if w.closed {
+		return availability, nil
+	}
+	for _, seats := range availability {
+		w.events[seats.EventID] = true
+		if uc.watchers[seats.EventID] == nil {
+			uc.watchers[seats.EventID] = make(map[*SeatWatcher]struct{})
+		}
+		uc.watchers[seats.EventID][w] = struct{}{}
+		// read again by Run, a change committed before the watcher was
+		// added may have been pushed to the others only
+		uc.changed[seats.EventID] = true
+	}
+	uc.wake()
+	return availability, nil
+}
+
+// Unwatch stops pushing the seats of the events to the watcher.
+func (w *SeatWatcher) Unwatch(eventIDs []uuid.UUID) {
+	w.uc.mu.Lock()
+	defer w.uc.mu.Unlock()
+
+	for _, id := range eventIDs {
+		w.uc.unwatch(w, id)
+	}
+}
+
+// Ready is signalled when the watcher has availability to take.
+func (w *SeatWatcher) Ready() <-chan struct{} {
+	return w.ready
+}
+
+// Take returns the latest availability of the events that changed since
+// the last call.
+func (w *SeatWatcher) Take() []*entity.SeatAvailability {
+	w.uc.mu.Lock()
+	defer w.uc.mu.Unlock()
+
+	availability := make([]*entity.SeatAvailability, 0, len(w.pending))
+	for id, seats := range w.pending {
+		availability = append(availability, seats)
+		delete(w.pending, id)
+	}
+	return availability
+}
+
+// Done is closed when the use case stops.
+func (w *SeatWatcher) Done() <-chan struct{} {
+	return w.done
+}
+
+// Close stops the watcher and frees its connection.
+func (w *SeatWatcher) Close() {
+	w.uc.mu.Lock()
+	defer w.uc.mu.Unlock()
+
+	if w.closed {
+		return
+	}
+	w.closed = true
+	for id := range w.events {
+		w.uc.unwatch(w, id)
+	}
+	delete(w.uc.connected, w)
+}
+
+func (w *SeatWatcher) visible(seats *entity.SeatAvailability) bool {
+	if seats.Status != entity.EventDraft || w.anyDraft {
+		return true
+	}
+	return w.ownDrafts && w.subject != "" && seats.CreatedBy == w.subject
+}
+
+// Run listens for seat changes and pushes them to the watchers until ctx is
+// cancelled. It listens again after the connection fails, reading every
+// watched event again since changes may have been missed meanwhile.
+func (uc *WatchSeatAvailabilityUseCase) Run(ctx context.Context) {
+	defer uc.stop()
+
+	// events are read for every tenant, the watchers were checked when they
+	// started watching them
+	ctx = tenant.ContextWithAllTenants(ctx)
+
+	listened := make(chan struct{})
+	go func() {
+		defer close(listened)
+		uc.listen(ctx)
+	}()
+	defer func() { <-listened }()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-uc.signal:
+		}
+
+		eventIDs := uc.takeChanged()
+		availability, err := uc.repository.GetSeatAvailability(ctx, eventIDs)
+		if err != nil {
+			log.Error().Msgf("WatchSeatAvailabilityUseCase.Run: %v", err)
+			uc.markChanged(eventIDs...)
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(seatAvailabilityRetry):
+			}
+			continue
+		}
+
+		uc.push(availability)
+	}
+}
+
+func (uc *WatchSeatAvailabilityUseCase) listen(ctx context.Context) {
+	backoff := eventStreamMinBackoff
+	for {
+		err := uc.repository.ListenSeatChanges(ctx,
+			func() {
+				backoff = eventStreamMinBackoff
+				uc.markWatchedChanged()
+			},
+			func(eventID uuid.UUID) { uc.markChanged(eventID) },
+		)
+		if ctx.Err() != nil {
+			return
+		}
+		log.Error().Msgf("WatchSeatAvailabilityUseCase.listen: %v", err)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, eventStreamMaxBackoff)
+	}
+}
+
+// markChanged queues the watched ones of the events to be read again.
+func (uc *WatchSeatAvailabilityUseCase) markChanged(eventIDs ...uuid.UUID) {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	for _, id := range eventIDs {
+		if len(uc.watchers[id]) > 0 {
+			uc.changed[id] = true
+		}
+	}
+	uc.wake()
+}
+
+func (uc *WatchSeatAvailabilityUseCase) markWatchedChanged() {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	for id := range uc.watchers {
+		uc.changed[id] = true
+	}
+	uc.wake()
+}
+
+func (uc *WatchSeatAvailabilityUseCase) takeChanged() []uuid.UUID {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	eventIDs := make([]uuid.UUID, 0, len(uc.changed))
+	for id := range uc.changed {
+		eventIDs = append(eventIDs, id)
+		delete(uc.changed, id)
+	}
+	return eventIDs
+}
+
+// wake signals Run that events changed, uc.mu must be held.
+func (uc *WatchSeatAvailabilityUseCase) wake() {
+	if len(uc.changed) == 0 {
+		return
+	}
+	select {
+	case uc.signal <- struct{}{}:
+	default:
+	}
+}
+
+func (uc *WatchSeatAvailabilityUseCase) push(availability []*entity.SeatAvailability) {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	for _, seats := range availability {
+		for w := range uc.watchers[seats.EventID] {
+			w.pending[seats.EventID] = seats
+			select {
+			case w.ready <- struct{}{}:
+			default:
+			}
+		}
+	}
+}
+
+// unwatch removes the watcher of an event, uc.mu must be held.
+func (uc *WatchSeatAvailabilityUseCase) unwatch(w *SeatWatcher, eventID uuid.UUID) {
+	delete(w.events, eventID)
+	delete(w.pending, eventID)
+	delete(uc.watchers[eventID], w)
+	if len(uc.watchers[eventID]) == 0 {
+		delete(uc.watchers, eventID)
+	}
+}
+
+func (uc *WatchSeatAvailabilityUseCase) stop() {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	uc.stopped = true
+	for w := range uc.connected {
+		close(w.done)
+	}
+}
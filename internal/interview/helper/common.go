@@ -2,6 +2,7 @@ package helper
 
 const (
 	Unknown            = 2
+	InvalidArgument    = 3
 	DeadlineExceeded   = 4
 	NotFound           = 5
 	AlreadyExists      = 6
@@ -10,4 +11,5 @@ const (
 	FailedPrecondition = 9
 	InternalError      = 10
 	Unavailable        = 14
+	Unauthenticated    = 16
 )
@@ -0,0 +1,173 @@
+package avanpost
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+)
+
+type ClientConfig struct {
+	// BaseURL is the address of the Avanpost API, e.g. https://avanpost:443.
+	BaseURL string
+	// ClientID, ClientSecret and TokenURL are the OAuth2 client credentials
+	// the client authenticates with. Requests are sent unauthenticated when
+	// ClientID is empty.
+	ClientID     string
+	ClientSecret string
+	TokenURL     string
+	// Timeout limits every single attempt, including the token request.
+	Timeout time.Duration
+	// MaxRetries is the number of times a request is retried after a network
+	// error, a 429 or a 5xx response. RetryBackoff is the delay before the
+	// first retry, doubled for every next one.
+	MaxRetries   int
+	RetryBackoff time.Duration
+	// BreakerThreshold is the number of consecutive failed requests that
+	// open the circuit breaker for BreakerCooldown. Zero disables it.
+	BreakerThreshold int
+	BreakerCooldown  time.Duration
+}
+
+// Client calls the Avanpost administration API.
+type Client struct {
+	config  ClientConfig
+	client  *http.Client
+	breaker *circuitBreaker
+}
+
+func NewClient(config ClientConfig) *Client {
+	client := &http.Client{Timeout: config.Timeout}
+	if config.ClientID != "" {
+		credentials := &clientcredentials.Config{
+			ClientID:     config.ClientID,
+			ClientSecret: config.ClientSecret,
+			TokenURL:     config.TokenURL,
+		}
+		client = credentials.Client(context.WithValue(context.Background(), oauth2.HTTPClient, client))
+		client.Timeout = config.Timeout
+	}
+
+	return &Client{
+		config:  config,
+		client:  client,
+		breaker: newCircuitBreaker(config.BreakerThreshold, config.BreakerCooldown),
+	}
+}
+
+// AddGroupMember adds the user to the group. Adding a user that is already a
+// member succeeds.
+func (c *Client) AddGroupMember(ctx context.Context, groupID, userID string) error {
+	status, err := c.do(ctx, http.MethodPut, groupMemberPath(groupID, userID))
+	if err != nil {
+		return fmt.Errorf("AddGroupMember %w", err)
+	}
+
+	switch {
+	case status >= 200 && status < 300, status == http.StatusConflict:
+		return nil
+	default:
+		return fmt.Errorf("AddGroupMember: unexpected status %d", status)
+	}
+}
+
+// RemoveGroupMember removes the user from the group. Removing a user that is
+// not a member succeeds.
+func (c *Client) RemoveGroupMember(ctx context.Context, groupID, userID string) error {
+	status, err := c.do(ctx, http.MethodDelete, groupMemberPath(groupID, userID))
+	if err != nil {
+		return fmt.Errorf("RemoveGroupMember %w", err)
+	}
+
+	switch {
+	case status >= 200 && status < 300, status == http.StatusNotFound:
+		return nil
+	default:
+		return fmt.Errorf("RemoveGroupMember: unexpected status %d", status)
+	}
+}
+
+func groupMemberPath(groupID, userID string) string {
+	return fmt.Sprintf("/api/v1/groups/%s/members/%s", url.PathEscape(groupID), url.PathEscape(userID))
+}
+
+// do sends the request through the circuit breaker, retrying transient
+// failures, and returns the status of the last response.
+func (c *Client) do(ctx context.Context, method, path string) (int, error) {
+	if err := c.breaker.allow(); err != nil {
+		return 0, err
+	}
+
+	var (
+		status int
+		err    error
+	)
+	backoff := c.config.RetryBackoff
+	for attempt := 0; ; attempt++ {
+		status, err = c.send(ctx, method, path)
+		if !isTransient(status, err) || attempt >= c.config.MaxRetries || ctx.Err() != nil {
+			break
+		}
+
+		log.Warn().
+			Str("method", method).
+			Str("path", path).
+			Int("attempt", attempt+1).
+			Msgf("Avanpost request failed, retrying in %s: status %d, %v", backoff, status, err)
+
+		select {
+		case <-ctx.Done():
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+
+	// the caller gave up, which says nothing about the health of Avanpost
+	if ctx.Err() != nil {
+		c.breaker.abort()
+		return 0, ctx.Err()
+	}
+
+	if isTransient(status, err) {
+		c.breaker.failure()
+		if err == nil {
+			err = fmt.Errorf("avanpost responded with status %d", status)
+		}
+		return status, err
+	}
+
+	c.breaker.success()
+	return status, err
+}
+
+func (c *Client) send(ctx context.Context, method, path string) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, method, strings.TrimRight(c.config.BaseURL, "/")+path, nil)
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	return resp.StatusCode, nil
+}
+
+// isTransient reports whether a request failed in a way that may succeed when
+// retried later.
+func isTransient(status int, err error) bool {
+	if err != nil {
+		return !errors.Is(err, context.Canceled)
+	}
+	return status == http.StatusTooManyRequests || status >= http.StatusInternalServerError
+}
@@ -0,0 +1,172 @@
+// Package fake implements an in-memory stand-in for the parts of the Avanpost
+// API the service uses, so the onboarding group sync can be exercised
+// without a real identity provider.
+package fake
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+const accessToken = "fake-avanpost-token"
+
+// Server serves the OAuth2 token endpoint and the group membership API.
+// Failures and latency can be injected to exercise retries and the circuit
+// breaker of the client.
+type Server struct {
+	clientID     string
+	clientSecret string
+	mux          *http.ServeMux
+
+	mu       sync.Mutex
+	groups   map[string]map[string]struct{}
+	failures []int
+	latency  time.Duration
+}
+
+// NewServer creates a fake Avanpost. When clientID is empty, requests are
+// accepted without a token.
+func NewServer(clientID, clientSecret string) *Server {
+	s := &Server{
+		clientID:     clientID,
+		clientSecret: clientSecret,
+		mux:          http.NewServeMux(),
+		groups:       make(map[string]map[string]struct{}),
+	}
+
+	s.mux.HandleFunc("POST /oauth2/token", s.token)
+	s.mux.HandleFunc("GET /api/v1/groups/{group}/members", s.authorized(s.listMembers))
+	s.mux.HandleFunc("PUT /api/v1/groups/{group}/members/{user}", s.authorized(s.addMember))
+	s.mux.HandleFunc("DELETE /api/v1/groups/{group}/members/{user}", s.authorized(s.removeMember))
+
+	return s
+}
+
+// FailNext makes the next count group requests respond with status.
+func (s *Server) FailNext(count int, status int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for range count {
+		s.failures = append(s.failures, status)
+	}
+}
+
+// SetLatency delays every response by latency.
+func (s *Server) SetLatency(latency time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.latency = latency
+}
+
+// Members returns the sorted members of the group.
+func (s *Server) Members(groupID string) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	members := make([]string, 0, len(s.groups[groupID]))
+	for member := range s.groups[groupID] {
+		members = append(members, member)
+	}
+	sort.Strings(members)
+	return members
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	latency := s.latency
+	s.mu.Unlock()
+
+	if latency > 0 {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-time.After(latency):
+		}
+	}
+
+	s.mux.ServeHTTP(w, r)
+}
+
+func (s *Server) token(w http.ResponseWriter, r *http.Request) {
+	clientID, clientSecret, ok := r.BasicAuth()
+	if !ok {
+		clientID, clientSecret = r.PostFormValue("client_id"), r.PostFormValue("client_secret")
+	}
+	if clientID != s.clientID || clientSecret != s.clientSecret {
+		writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "invalid_client"})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"access_token": accessToken,
+		"token_type":   "Bearer",
+		"expires_in":   3600,
+	})
+}
+
+// authorized checks the bearer token and serves the injected failures before
+// calling next.
+func (s *Server) authorized(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if s.clientID != "" && r.Header.Get("Authorization") != "Bearer "+accessToken {
+			writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "invalid_token"})
+			return
+		}
+
+		s.mu.Lock()
+		var status int
+		if len(s.failures) > 0 {
+			status, s.failures = s.failures[0], s.failures[1:]
+		}
+		s.mu.Unlock()
+
+		if status != 0 {
+			writeJSON(w, status, map[string]string{"error": http.StatusText(status)})
+			return
+		}
+
+		next(w, r)
+	}
+}
+
+func (s *Server) listMembers(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, s.Members(r.PathValue("group")))
+}
+
+func (s *Server) addMember(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	group, user := r.PathValue("group"), r.PathValue("user")
+	if s.groups[group] == nil {
+		s.groups[group] = make(map[string]struct{})
+	}
+	s.groups[group][user] = struct{}{}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) removeMember(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	group, user := r.PathValue("group"), r.PathValue("user")
+	if _, ok := s.groups[group][user]; !ok {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "member not found"})
+		return
+	}
+	delete(s.groups[group], user)
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,145 @@
+package avanpost
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"online-registration/internal/common"
+	"online-registration/internal/interview/domain/auth"
+	"online-registration/internal/interview/helper"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+)
+
+const discoveryTimeout = 10 * time.Second
+
+type TokenVerifierConfig struct {
+	Issuer   string
+	Audience string
+	// RolesClaim is the dot separated path of the claim holding the roles of
+	// the principal, e.g. "roles" or "realm_access.roles".
+	RolesClaim string
+	// TenantClaim is the dot separated path of the claim binding the
+	// principal to a tenant, e.g. "tenant_id".
+	TenantClaim string
+}
+
+// TokenVerifier validates JWTs issued by Avanpost. The issuer is discovered
+// through OIDC discovery on first use, and its signing keys are cached and
+// refetched when a token is signed with an unknown key.
+type TokenVerifier struct {
+	config TokenVerifierConfig
+	client *http.Client
+
+	mu       sync.Mutex
+	verifier *oidc.IDTokenVerifier
+}
+
+// NewTokenVerifier creates a verifier of the tokens of config.Issuer issued
+// for config.Audience. The audience is required, the tokens issued for other
+// clients of the issuer must not be accepted.
+func NewTokenVerifier(config TokenVerifierConfig) (*TokenVerifier, error) {
+	if config.Issuer == "" {
+		return nil, errors.New("NewTokenVerifier: issuer is required")
+	}
+	if config.Audience == "" {
+		return nil, errors.New("NewTokenVerifier: audience is required")
+	}
+
+	return &TokenVerifier{
+		config: config,
+		client: &http.Client{Timeout: discoveryTimeout},
+	}, nil
+}
+
+func (v *TokenVerifier) Verify(ctx context.Context, rawToken string) (*auth.Principal, error) {
+	verifier, err := v.idTokenVerifier()
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := verifier.Verify(ctx, rawToken)
+	if err != nil {
+		return nil, fmt.Errorf("Verify %w", err)
+	}
+
+	var claims map[string]any
+	if err := token.Claims(&claims); err != nil {
+		return nil, fmt.Errorf("Verify %w", err)
+	}
+
+	principal := &auth.Principal{
+		Subject: token.Subject,
+		Roles:   stringsClaim(claims, v.config.RolesClaim),
+	}
+	switch tenants := stringsClaim(claims, v.config.TenantClaim); len(tenants) {
+	case 0:
+	case 1:
+		principal.Tenant = tenants[0]
+	default:
+		return nil, fmt.Errorf("Verify token has %d tenants in claim %s", len(tenants), v.config.TenantClaim)
+	}
+	principal.Email, _ = claims["email"].(string)
+	principal.Name, _ = claims["name"].(string)
+
+	return principal, nil
+}
+
+// idTokenVerifier runs the discovery until it succeeds, so that the service
+// can start while Avanpost is unavailable.
+func (v *TokenVerifier) idTokenVerifier() (*oidc.IDTokenVerifier, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if v.verifier != nil {
+		return v.verifier, nil
+	}
+
+	// the provider keeps the context for refreshing keys in the background,
+	// so it must not be bound to a request
+	ctx := oidc.ClientContext(context.Background(), v.client)
+	provider, err := oidc.NewProvider(ctx, v.config.Issuer)
+	if err != nil {
+		return nil, common.NewCodedError(
+			helper.Unavailable, common.CodeAuthUnavailable, fmt.Errorf("discover issuer %s: %w", v.config.Issuer, err),
+		)
+	}
+
+	v.verifier = provider.Verifier(&oidc.Config{ClientID: v.config.Audience})
+	return v.verifier, nil
+}
+
+// stringsClaim reads a list of strings at a dot separated claim path.
+func stringsClaim(claims map[string]any, path string) []string {
+	if path == "" {
+		return nil
+	}
+
+	var value any = claims
+	for _, key := range strings.Split(path, ".") {
+		object, ok := value.(map[string]any)
+		if !ok {
+			return nil
+		}
+		value = object[key]
+	}
+
+	switch value := value.(type) {
+	case string:
+		return strings.Fields(value)
+	case []any:
+		values := make([]string, 0, len(value))
+		for _, item := range value {
+			if s, ok := item.(string); ok {
+				values = append(values, s)
+			}
+		}
+		return values
+	}
+	return nil
+}
@@ -0,0 +1,273 @@
+package avanpost
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"online-registration/internal/common"
+	"online-registration/internal/interview/helper"
+)
+
+const testAudience = "online-registration"
+
+// fakeIssuer is an OIDC issuer serving discovery and the keys it signs
+// tokens with.
+type fakeIssuer struct {
+	server *httptest.Server
+
+	mu          sync.Mutex
+	unavailable bool
+	keys        map[string]*rsa.PrivateKey
+	signingKey  string
+}
+
+func newFakeIssuer(t *testing.T) *fakeIssuer {
+	t.Helper()
+
+	issuer := &fakeIssuer{keys: make(map[string]*rsa.PrivateKey)}
+	issuer.rotate(t, "key-1")
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /.well-known/openid-configuration", issuer.discovery)
+	mux.HandleFunc("GET /jwks", issuer.jwks)
+	issuer.server = httptest.NewServer(mux)
+	t.Cleanup(issuer.server.Close)
+	return issuer
+}
+
+func (i *fakeIssuer) url() string {
+	return i.server.URL
+}
+
+// rotate signs the next tokens with a new key kid and stops publishing the
+// previous ones.
+func (i *fakeIssuer) rotate(t *testing.T, kid string) {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.keys = map[string]*rsa.PrivateKey{kid: key}
+	i.signingKey = kid
+}
+
+func (i *fakeIssuer) setUnavailable(unavailable bool) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.unavailable = unavailable
+}
+
+func (i *fakeIssuer) discovery(w http.ResponseWriter, _ *http.Request) {
+	i.mu.Lock()
+	unavailable := i.unavailable
+	i.mu.Unlock()
+	if unavailable {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]any{
+		"issuer":                                i.url(),
+		"jwks_uri":                              i.url() + "/jwks",
+		"authorization_endpoint":                i.url() + "/authorize",
+		"token_endpoint":                        i.url() + "/token",
+		"id_token_signing_alg_values_supported": []string{"RS256"},
+	})
+}
+
+func (i *fakeIssuer) jwks(w http.ResponseWriter, _ *http.Request) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	keys := make([]map[string]string, 0, len(i.keys))
+	for kid, key := range i.keys {
+		keys = append(keys, map[string]string{
+			"kty": "RSA",
+			"use": "sig",
+			"alg": "RS256",
+			"kid": kid,
+			"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		})
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]any{"keys": keys})
+}
+
+// token returns a token signed with the current key, with the claims of a
+// valid token overridden by claims.
+func (i *fakeIssuer) token(t *testing.T, claims map[string]any) string {
+	t.Helper()
+
+	i.mu.Lock()
+	kid := i.signingKey
+	key := i.keys[kid]
+	i.mu.Unlock()
+
+	return signToken(t, kid, key, i.tokenClaims(claims))
+}
+
+func (i *fakeIssuer) tokenClaims(claims map[string]any) map[string]any {
+	now := time.Now()
+	payload := map[string]any{
+		"iss":       i.url(),
+		"sub":       "user-1",
+		"aud":       testAudience,
+		"iat":       now.Unix(),
+		"exp":       now.Add(time.Hour).Unix(),
+		"email":     "user@example.com",
+		"name":      "User",
+		"roles":     []string{"organizer"},
+		"tenant_id": "acme",
+	}
+	for name, value := range claims {
+		payload[name] = value
+	}
+	return payload
+}
+
+func signToken(t *testing.T, kid string, key *rsa.PrivateKey, claims map[string]any) string {
+	t.Helper()
+
+	header, _ := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT", "kid": kid})
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		t.Fatalf("marshal claims: %v", err)
+	}
+
+	signed := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	digest := sha256.Sum256([]byte(signed))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	if err != nil {
+		t.Fatalf("sign token: %v", err)
+	}
+	return signed + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func newTestVerifier(t *testing.T, issuer *fakeIssuer) *TokenVerifier {
+	t.Helper()
+
+	verifier, err := NewTokenVerifier(TokenVerifierConfig{
+		Issuer:      issuer.url(),
+		Audience:    testAudience,
+		RolesClaim:  "roles",
+		TenantClaim: "tenant_id",
+	})
+	if err != nil {
+		t.Fatalf("NewTokenVerifier: %v", err)
+	}
+	return verifier
+}
+
+func TestNewTokenVerifierRequiresAudience(t *testing.T) {
+	if _, err := NewTokenVerifier(TokenVerifierConfig{Issuer: "https://issuer.example.com"}); err == nil {
+		t.Error("NewTokenVerifier without an audience succeeded")
+	}
+	if _, err := NewTokenVerifier(TokenVerifierConfig{Audience: testAudience}); err == nil {
+		t.Error("NewTokenVerifier without an issuer succeeded")
+	}
+}
+
+func TestTokenVerifierVerify(t *testing.T) {
+	issuer := newFakeIssuer(t)
+	verifier := newTestVerifier(t, issuer)
+
+	principal, err := verifier.Verify(context.Background(), issuer.token(t, nil))
+	if err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+
+	if principal.Subject != "user-1" || principal.Tenant != "acme" ||
+		principal.Email != "user@example.com" || principal.Name != "User" ||
+		!slices.Equal(principal.Roles, []string{"organizer"}) {
+		t.Errorf("Verify returned principal %+v", principal)
+	}
+}
+
+func TestTokenVerifierRetriesDiscovery(t *testing.T) {
+	issuer := newFakeIssuer(t)
+	issuer.setUnavailable(true)
+	verifier := newTestVerifier(t, issuer)
+
+	_, err := verifier.Verify(context.Background(), issuer.token(t, nil))
+	var processingErr *common.ProcessingError
+	if !errors.As(err, &processingErr) || processingErr.Status != helper.Unavailable ||
+		processingErr.Code != common.CodeAuthUnavailable {
+		t.Fatalf("Verify while the issuer is unavailable = %v, want an Unavailable error", err)
+	}
+
+	issuer.setUnavailable(false)
+	if _, err := verifier.Verify(context.Background(), issuer.token(t, nil)); err != nil {
+		t.Errorf("Verify once the issuer is back: %v", err)
+	}
+}
+
+func TestTokenVerifierFollowsKeyRotation(t *testing.T) {
+	issuer := newFakeIssuer(t)
+	verifier := newTestVerifier(t, issuer)
+	ctx := context.Background()
+
+	oldToken := issuer.token(t, nil)
+	if _, err := verifier.Verify(ctx, oldToken); err != nil {
+		t.Fatalf("Verify before the rotation: %v", err)
+	}
+
+	issuer.rotate(t, "key-2")
+	if _, err := verifier.Verify(ctx, issuer.token(t, nil)); err != nil {
+		t.Errorf("Verify of a token signed with the new key: %v", err)
+	}
+	if _, err := verifier.Verify(ctx, oldToken); err == nil {
+		t.Error("Verify accepted a token signed with a retired key")
+	}
+}
+
+func TestTokenVerifierRejectsInvalidTokens(t *testing.T) {
+	issuer := newFakeIssuer(t)
+	verifier := newTestVerifier(t, issuer)
+
+	forged, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{"expired", issuer.token(t, map[string]any{
+			"iat": time.Now().Add(-2 * time.Hour).Unix(),
+			"exp": time.Now().Add(-time.Hour).Unix(),
+		})},
+		{"other audience", issuer.token(t, map[string]any{"aud": "other-client"})},
+		{"no audience", issuer.token(t, map[string]any{"aud": nil})},
+		{"other issuer", issuer.token(t, map[string]any{"iss": "https://issuer.example.com"})},
+		{"unknown key", signToken(t, "key-1", forged, issuer.tokenClaims(nil))},
+		{"several tenants", issuer.token(t, map[string]any{"tenant_id": []string{"acme", "globex"}})},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if principal, err := verifier.Verify(context.Background(), tt.token); err == nil {
+				t.Errorf("Verify accepted the token of %+v", principal)
+			}
+		})
+	}
+}
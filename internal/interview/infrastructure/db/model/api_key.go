@@ -0,0 +1,54 @@
+package model
+
+import (
+	"online-registration/internal/interview/domain/entity"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+type APIKey struct {
+	bun.BaseModel `bun:"table:api_keys,alias:ak"`
+	ID            uuid.UUID `bun:"id,pk,notnull"`
+	Name          string    `bun:"name,notnull"`
+	Prefix        string    `bun:"prefix,notnull"`
+	KeyHash       string    `bun:"key_hash,notnull"`
+	Scopes        []string  `bun:"scopes,array,notnull"`
+	TenantID      string    `bun:"tenant_id,nullzero"`
+	CreatedAt     time.Time `bun:"created_at,notnull,default:current_timestamp"`
+	LastUsedAt    time.Time `bun:"last_used_at,nullzero"`
+	ExpiresAt     time.Time `bun:"expires_at,nullzero"`
+	RevokedAt     time.Time `bun:"revoked_at,nullzero"`
+}
+
+func (m *APIKey) ToEntity() *entity.APIKey {
+	return &entity.APIKey{
+		ID:         m.ID,
+		Name:       m.Name,
+		Prefix:     m.Prefix,
+		KeyHash:    m.KeyHash,
+		Scopes:     m.Scopes,
+		Tenant:     m.TenantID,
+		CreatedAt:  m.CreatedAt,
+		LastUsedAt: timePtr(m.LastUsedAt),
+		ExpiresAt:  timePtr(m.ExpiresAt),
+		RevokedAt:  timePtr(m.RevokedAt),
+	}
+}
+
+func (m *APIKey) ToModel(entity entity.APIKey) *APIKey {
+	model := &APIKey{
+		ID:        entity.ID,
+		Name:      entity.Name,
+		Prefix:    entity.Prefix,
+		KeyHash:   entity.KeyHash,
+		Scopes:    entity.Scopes,
+		TenantID:  entity.Tenant,
+		CreatedAt: entity.CreatedAt,
+	}
+	if entity.ExpiresAt != nil {
+		model.ExpiresAt = *entity.ExpiresAt
+	}
+	return model
+}
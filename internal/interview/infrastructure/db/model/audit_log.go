@@ -0,0 +1,58 @@
+package model
+
+import (
+	"context"
+	"online-registration/internal/interview/domain/entity"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+type EventAuditEntry struct {
+	bun.BaseModel `bun:"table:event_audit_log,alias:al"`
+	ID            int64                         `bun:"id,pk,autoincrement"`
+	TenantID      string                        `bun:"tenant_id,notnull,nullzero"`
+	EventID       uuid.UUID                     `bun:"event_id,notnull"`
+	Action        string                        `bun:"action,notnull"`
+	Actor         string                        `bun:"actor,notnull"`
+	RequestID     string                        `bun:"request_id,nullzero"`
+	Changes       map[string]entity.AuditChange `bun:"changes,type:jsonb,notnull"`
+	CreatedAt     time.Time                     `bun:"created_at,notnull,default:current_timestamp"`
+}
+
+func (m *EventAuditEntry) ToEntity() *entity.EventAuditEntry {
+	return &entity.EventAuditEntry{
+		ID:        m.ID,
+		TenantID:  m.TenantID,
+		EventID:   m.EventID,
+		Action:    m.Action,
+		Actor:     m.Actor,
+		RequestID: m.RequestID,
+		Changes:   m.Changes,
+		CreatedAt: m.CreatedAt,
+	}
+}
+
+var (
+	_ bun.BeforeSelectHook = (*EventAuditEntry)(nil)
+	_ bun.BeforeInsertHook = (*EventAuditEntry)(nil)
+	_ bun.BeforeUpdateHook = (*EventAuditEntry)(nil)
+	_ bun.BeforeDeleteHook = (*EventAuditEntry)(nil)
+)
+
+func (*EventAuditEntry) BeforeSelect(ctx context.Context, query *bun.SelectQuery) error {
+	return scopeSelect(ctx, query)
+}
+
+func (*EventAuditEntry) BeforeInsert(ctx context.Context, query *bun.InsertQuery) error {
+	return scopeInsert(ctx, query)
+}
+
+func (*EventAuditEntry) BeforeUpdate(ctx context.Context, query *bun.UpdateQuery) error {
+	return scopeUpdate(ctx, query)
+}
+
+func (*EventAuditEntry) BeforeDelete(ctx context.Context, query *bun.DeleteQuery) error {
+	return scopeDelete(ctx, query)
+}
@@ -1,6 +1,7 @@
 package model
 
 import (
+	"context"
 	"online-registration/internal/interview/domain/entity"
 	"time"
 
@@ -9,33 +10,105 @@ import (
 )
 
 type Event struct {
-	bun.BaseModel `bun:"table:events,alias:s"`
-	ID            uuid.UUID `bun:"id,pk,notnull"`
-	Title         string    `bun:"title,notnull"`
-	Description   string    `bun:"description,notnull"`
-	StartTime     time.Time `bun:"start_time,notnull"`
-	EndTime       time.Time `bun:"end_time,notnull"`
-	CreatedAt     time.Time `bun:"created_at,notnull,default:current_timestamp"`
+	bun.BaseModel      `bun:"table:events,alias:s"`
+	ID                 uuid.UUID     `bun:"id,pk,notnull"`
+	TenantID           string        `bun:"tenant_id,notnull,nullzero"`
+	Title              string        `bun:"title,notnull"`
+	Description        string        `bun:"description,notnull"`
+	StartTime          time.Time     `bun:"start_time,notnull"`
+	EndTime            time.Time     `bun:"end_time,notnull"`
+	TimeZone           string        `bun:"time_zone,notnull"`
+	AllDay             bool          `bun:"all_day,notnull"`
+	Onboarding         bool          `bun:"onboarding,notnull"`
+	RoomID             uuid.NullUUID `bun:"room_id"`
+	CategoryID         uuid.NullUUID `bun:"category_id"`
+	Status             string        `bun:"status,notnull"`
+	CancellationReason string        `bun:"cancellation_reason,nullzero"`
+	CreatedBy          string        `bun:"created_by,nullzero"`
+	CreatedAt          time.Time     `bun:"created_at,notnull,default:current_timestamp"`
+	UpdatedAt          time.Time     `bun:"updated_at,notnull,default:current_timestamp"`
+	DeletedAt          time.Time     `bun:"deleted_at,soft_delete,nullzero"`
+	// SearchLanguage is the text search configuration SearchVector is built
+	// with, by a trigger.
+	SearchLanguage string `bun:"search_language,nullzero"`
+	SearchVector   string `bun:"search_vector,scanonly"`
 }
 
 func (m *Event) ToEntity() *entity.Event {
 	return &entity.Event{
-		ID:          m.ID,
-		Title:       m.Title,
-		Description: m.Description,
-		StartTime:   m.StartTime,
-		EndTime:     m.EndTime,
-		CreatedAt:   m.CreatedAt,
+		ID:                 m.ID,
+		TenantID:           m.TenantID,
+		Title:              m.Title,
+		Description:        m.Description,
+		StartTime:          m.StartTime,
+		EndTime:            m.EndTime,
+		TimeZone:           m.TimeZone,
+		AllDay:             m.AllDay,
+		Onboarding:         m.Onboarding,
+		RoomID:             uuidPtr(m.RoomID),
+		CategoryID:         uuidPtr(m.CategoryID),
+		Status:             m.Status,
+		CancellationReason: m.CancellationReason,
+		CreatedBy:          m.CreatedBy,
+		CreatedAt:          m.CreatedAt,
+		UpdatedAt:          m.UpdatedAt,
 	}
 }
 
 func (m *Event) ToModel(entity entity.Event) *Event {
 	return &Event{
-		ID:          entity.ID,
-		Title:       entity.Title,
-		Description: entity.Description,
-		StartTime:   entity.StartTime,
-		EndTime:     entity.EndTime,
-		CreatedAt:   entity.CreatedAt,
+		ID:                 entity.ID,
+		TenantID:           entity.TenantID,
+		Title:              entity.Title,
+		Description:        entity.Description,
+		StartTime:          entity.StartTime,
+		EndTime:            entity.EndTime,
+		TimeZone:           entity.TimeZone,
+		AllDay:             entity.AllDay,
+		Onboarding:         entity.Onboarding,
+		RoomID:             nullUUID(entity.RoomID),
+		CategoryID:         nullUUID(entity.CategoryID),
+		Status:             entity.Status,
+		CancellationReason: entity.CancellationReason,
+		CreatedBy:          entity.CreatedBy,
+		CreatedAt:          entity.CreatedAt,
+		UpdatedAt:          entity.UpdatedAt,
 	}
 }
+
+var (
+	_ bun.BeforeSelectHook = (*Event)(nil)
+	_ bun.BeforeInsertHook = (*Event)(nil)
+	_ bun.BeforeUpdateHook = (*Event)(nil)
+	_ bun.BeforeDeleteHook = (*Event)(nil)
+)
+
+func (*Event) BeforeSelect(ctx context.Context, query *bun.SelectQuery) error {
+	return scopeSelect(ctx, query)
+}
+
+func (*Event) BeforeInsert(ctx context.Context, query *bun.InsertQuery) error {
+	return scopeInsert(ctx, query)
+}
+
+func (*Event) BeforeUpdate(ctx context.Context, query *bun.UpdateQuery) error {
+	return scopeUpdate(ctx, query)
+}
+
+func (*Event) BeforeDelete(ctx context.Context, query *bun.DeleteQuery) error {
+	return scopeDelete(ctx, query)
+}
+
+func uuidPtr(id uuid.NullUUID) *uuid.UUID {
+	if !id.Valid {
+		return nil
+	}
+	return &id.UUID
+}
+
+func nullUUID(id *uuid.UUID) uuid.NullUUID {
+	if id == nil {
+		return uuid.NullUUID{}
+	}
+	return uuid.NullUUID{UUID: *id, Valid: true}
+}
@@ -0,0 +1,32 @@
+package model
+
+import (
+	"online-registration/internal/interview/domain/entity"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+type IdempotencyKey struct {
+	bun.BaseModel `bun:"table:idempotency_keys,alias:ik"`
+	Key           string    `bun:"key,pk,notnull"`
+	Fingerprint   string    `bun:"fingerprint,notnull"`
+	Owner         uuid.UUID `bun:"owner,type:uuid,nullzero"`
+	StatusCode    int       `bun:"status_code,nullzero"`
+	ResponseBody  []byte    `bun:"response_body,nullzero"`
+	CreatedAt     time.Time `bun:"created_at,notnull,default:current_timestamp"`
+	ExpiresAt     time.Time `bun:"expires_at,notnull"`
+}
+
+func (m *IdempotencyKey) ToEntity() *entity.IdempotencyKey {
+	return &entity.IdempotencyKey{
+		Key:          m.Key,
+		Fingerprint:  m.Fingerprint,
+		Owner:        m.Owner,
+		StatusCode:   m.StatusCode,
+		ResponseBody: m.ResponseBody,
+		CreatedAt:    m.CreatedAt,
+		ExpiresAt:    m.ExpiresAt,
+	}
+}
@@ -0,0 +1,49 @@
+package model
+
+import (
+	"encoding/json"
+	"online-registration/internal/interview/domain/entity"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+// Job is not tenant scoped: workers run the jobs of every tenant, and jobs
+// for a tenant carry it themselves.
+type Job struct {
+	bun.BaseModel `bun:"table:jobs,alias:j"`
+	ID            uuid.UUID       `bun:"id,pk,notnull"`
+	Kind          string          `bun:"kind,notnull"`
+	TenantID      string          `bun:"tenant_id,nullzero"`
+	Payload       json.RawMessage `bun:"payload,type:jsonb,notnull"`
+	UniqueKey     string          `bun:"unique_key,nullzero"`
+	Status        string          `bun:"status,notnull"`
+	Attempts      int             `bun:"attempts,notnull"`
+	MaxAttempts   int             `bun:"max_attempts,notnull"`
+	LastError     string          `bun:"last_error,nullzero"`
+	RunAt         time.Time       `bun:"run_at,notnull,default:current_timestamp"`
+	LockedUntil   time.Time       `bun:"locked_until,nullzero"`
+	CreatedAt     time.Time       `bun:"created_at,notnull,default:current_timestamp"`
+	UpdatedAt     time.Time       `bun:"updated_at,notnull,default:current_timestamp"`
+	FinishedAt    time.Time       `bun:"finished_at,nullzero"`
+}
+
+func (m *Job) ToEntity() *entity.Job {
+	return &entity.Job{
+		ID:          m.ID,
+		Kind:        m.Kind,
+		TenantID:    m.TenantID,
+		Payload:     m.Payload,
+		UniqueKey:   m.UniqueKey,
+		Status:      m.Status,
+		Attempts:    m.Attempts,
+		MaxAttempts: m.MaxAttempts,
+		LastError:   m.LastError,
+		RunAt:       m.RunAt,
+		LockedUntil: timePtr(m.LockedUntil),
+		CreatedAt:   m.CreatedAt,
+		UpdatedAt:   m.UpdatedAt,
+		FinishedAt:  timePtr(m.FinishedAt),
+	}
+}
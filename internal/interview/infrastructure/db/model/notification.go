@@ -0,0 +1,63 @@
+package model
+
+import (
+	"context"
+	"online-registration/internal/interview/domain/entity"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+type Notification struct {
+	bun.BaseModel  `bun:"table:notifications,alias:n"`
+	ID             uuid.UUID `bun:"id,pk,notnull"`
+	TenantID       string    `bun:"tenant_id,notnull,nullzero"`
+	Kind           string    `bun:"kind,notnull"`
+	RegistrationID uuid.UUID `bun:"registration_id,notnull"`
+	EventID        uuid.UUID `bun:"event_id,notnull"`
+	Recipient      string    `bun:"recipient,notnull"`
+	DedupeKey      string    `bun:"dedupe_key,notnull"`
+	Status         string    `bun:"status,notnull"`
+	Attempts       int       `bun:"attempts,notnull"`
+	LastError      string    `bun:"last_error,nullzero"`
+	SendAt         time.Time `bun:"send_at,notnull,default:current_timestamp"`
+	CreatedAt      time.Time `bun:"created_at,notnull,default:current_timestamp"`
+	SentAt         time.Time `bun:"sent_at,nullzero"`
+}
+
+func (m *Notification) ToEntity() *entity.Notification {
+	return &entity.Notification{
+		ID:             m.ID,
+		TenantID:       m.TenantID,
+		Kind:           m.Kind,
+		RegistrationID: m.RegistrationID,
+		EventID:        m.EventID,
+		Recipient:      m.Recipient,
+		DedupeKey:      m.DedupeKey,
+		Status:         m.Status,
+		Attempts:       m.Attempts,
+		LastError:      m.LastError,
+		SendAt:         m.SendAt,
+		CreatedAt:      m.CreatedAt,
+		SentAt:         timePtr(m.SentAt),
+	}
+}
+
+var (
+	_ bun.BeforeSelectHook = (*Notification)(nil)
+	_ bun.BeforeInsertHook = (*Notification)(nil)
+	_ bun.BeforeUpdateHook = (*Notification)(nil)
+)
+
+func (*Notification) BeforeSelect(ctx context.Context, query *bun.SelectQuery) error {
+	return scopeSelect(ctx, query)
+}
+
+func (*Notification) BeforeInsert(ctx context.Context, query *bun.InsertQuery) error {
+	return scopeInsert(ctx, query)
+}
+
+func (*Notification) BeforeUpdate(ctx context.Context, query *bun.UpdateQuery) error {
+	return scopeUpdate(ctx, query)
+}
@@ -0,0 +1,37 @@
+package model
+
+import (
+	"encoding/json"
+	"online-registration/internal/interview/domain/entity"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+type OutboxMessage struct {
+	bun.BaseModel `bun:"table:outbox,alias:o"`
+	ID            int64           `bun:"id,pk,autoincrement"`
+	MessageID     uuid.UUID       `bun:"message_id,notnull"`
+	Subject       string          `bun:"subject,notnull"`
+	Payload       json.RawMessage `bun:"payload,type:jsonb,notnull"`
+	Attempts      int             `bun:"attempts,notnull"`
+	LastError     string          `bun:"last_error,nullzero"`
+	CreatedAt     time.Time       `bun:"created_at,notnull,default:current_timestamp"`
+	NextAttemptAt time.Time       `bun:"next_attempt_at,notnull,default:current_timestamp"`
+	PublishedAt   time.Time       `bun:"published_at,nullzero"`
+}
+
+func (m *OutboxMessage) ToEntity() *entity.OutboxMessage {
+	return &entity.OutboxMessage{
+		ID:            m.ID,
+		MessageID:     m.MessageID,
+		Subject:       m.Subject,
+		Payload:       m.Payload,
+		Attempts:      m.Attempts,
+		LastError:     m.LastError,
+		CreatedAt:     m.CreatedAt,
+		NextAttemptAt: m.NextAttemptAt,
+		PublishedAt:   m.PublishedAt,
+	}
+}
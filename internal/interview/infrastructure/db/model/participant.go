@@ -0,0 +1,52 @@
+package model
+
+import (
+	"context"
+	"online-registration/internal/interview/domain/entity"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+type Participant struct {
+	bun.BaseModel `bun:"table:event_participants,alias:ep"`
+	EventID       uuid.UUID `bun:"event_id,pk,notnull"`
+	TenantID      string    `bun:"tenant_id,notnull,nullzero"`
+	UserID        string    `bun:"user_id,pk,notnull"`
+	Role          string    `bun:"role,notnull"`
+	CreatedAt     time.Time `bun:"created_at,notnull,default:current_timestamp"`
+}
+
+func (m *Participant) ToEntity() *entity.Participant {
+	return &entity.Participant{
+		EventID:   m.EventID,
+		TenantID:  m.TenantID,
+		UserID:    m.UserID,
+		Role:      m.Role,
+		CreatedAt: m.CreatedAt,
+	}
+}
+
+var (
+	_ bun.BeforeSelectHook = (*Participant)(nil)
+	_ bun.BeforeInsertHook = (*Participant)(nil)
+	_ bun.BeforeUpdateHook = (*Participant)(nil)
+	_ bun.BeforeDeleteHook = (*Participant)(nil)
+)
+
+func (*Participant) BeforeSelect(ctx context.Context, query *bun.SelectQuery) error {
+	return scopeSelect(ctx, query)
+}
+
+func (*Participant) BeforeInsert(ctx context.Context, query *bun.InsertQuery) error {
+	return scopeInsert(ctx, query)
+}
+
+func (*Participant) BeforeUpdate(ctx context.Context, query *bun.UpdateQuery) error {
+	return scopeUpdate(ctx, query)
+}
+
+func (*Participant) BeforeDelete(ctx context.Context, query *bun.DeleteQuery) error {
+	return scopeDelete(ctx, query)
+}
@@ -0,0 +1,58 @@
+package model
+
+import (
+	"context"
+	"online-registration/internal/interview/domain/entity"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+type Registration struct {
+	bun.BaseModel `bun:"table:registrations,alias:rg"`
+	ID            uuid.UUID `bun:"id,pk,notnull"`
+	TenantID      string    `bun:"tenant_id,notnull,nullzero"`
+	EventID       uuid.UUID `bun:"event_id,notnull"`
+	UserID        string    `bun:"user_id,notnull"`
+	Email         string    `bun:"email,nullzero"`
+	Status        string    `bun:"status,notnull"`
+	CreatedAt     time.Time `bun:"created_at,notnull,default:current_timestamp"`
+	CancelledAt   time.Time `bun:"cancelled_at,nullzero"`
+}
+
+func (m *Registration) ToEntity() *entity.Registration {
+	return &entity.Registration{
+		ID:          m.ID,
+		TenantID:    m.TenantID,
+		EventID:     m.EventID,
+		UserID:      m.UserID,
+		Email:       m.Email,
+		Status:      m.Status,
+		CreatedAt:   m.CreatedAt,
+		CancelledAt: timePtr(m.CancelledAt),
+	}
+}
+
+var (
+	_ bun.BeforeSelectHook = (*Registration)(nil)
+	_ bun.BeforeInsertHook = (*Registration)(nil)
+	_ bun.BeforeUpdateHook = (*Registration)(nil)
+	_ bun.BeforeDeleteHook = (*Registration)(nil)
+)
+
+func (*Registration) BeforeSelect(ctx context.Context, query *bun.SelectQuery) error {
+	return scopeSelect(ctx, query)
+}
+
+func (*Registration) BeforeInsert(ctx context.Context, query *bun.InsertQuery) error {
+	return scopeInsert(ctx, query)
+}
+
+func (*Registration) BeforeUpdate(ctx context.Context, query *bun.UpdateQuery) error {
+	return scopeUpdate(ctx, query)
+}
+
+func (*Registration) BeforeDelete(ctx context.Context, query *bun.DeleteQuery) error {
+	return scopeDelete(ctx, query)
+}
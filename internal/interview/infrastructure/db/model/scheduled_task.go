@@ -0,0 +1,44 @@
+package model
+
+import (
+	"online-registration/internal/interview/domain/entity"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// ScheduledTask is not tenant scoped, the scheduler runs its tasks for every
+// tenant.
+type ScheduledTask struct {
+	bun.BaseModel  `bun:"table:scheduled_tasks,alias:st"`
+	Name           string    `bun:"name,pk,notnull"`
+	Schedule       string    `bun:"schedule,notnull"`
+	NextRunAt      time.Time `bun:"next_run_at,nullzero"`
+	LastRunAt      time.Time `bun:"last_run_at,nullzero"`
+	LastStartedAt  time.Time `bun:"last_started_at,nullzero"`
+	LastFinishedAt time.Time `bun:"last_finished_at,nullzero"`
+	LastStatus     string    `bun:"last_status,nullzero"`
+	LastError      string    `bun:"last_error,nullzero"`
+	LastInstance   string    `bun:"last_instance,nullzero"`
+	UpdatedAt      time.Time `bun:"updated_at,notnull,default:current_timestamp"`
+}
+
+func (m *ScheduledTask) ToEntity() *entity.ScheduledTask {
+	task := &entity.ScheduledTask{
+		Name:      m.Name,
+		Schedule:  m.Schedule,
+		NextRunAt: timePtr(m.NextRunAt),
+		UpdatedAt: m.UpdatedAt,
+	}
+	if !m.LastRunAt.IsZero() {
+		task.LastRun = &entity.TaskRun{
+			ScheduledAt: m.LastRunAt,
+			StartedAt:   m.LastStartedAt,
+			FinishedAt:  timePtr(m.LastFinishedAt),
+			Status:      m.LastStatus,
+			Error:       m.LastError,
+			Instance:    m.LastInstance,
+		}
+	}
+	return task
+}
@@ -0,0 +1,120 @@
+package model
+
+import (
+	"context"
+	"online-registration/internal/interview/domain/entity"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+type Category struct {
+	bun.BaseModel `bun:"table:categories,alias:cat"`
+	ID            uuid.UUID `bun:"id,pk,notnull"`
+	TenantID      string    `bun:"tenant_id,notnull,nullzero"`
+	Name          string    `bun:"name,notnull"`
+	CreatedAt     time.Time `bun:"created_at,notnull,default:current_timestamp"`
+	UpdatedAt     time.Time `bun:"updated_at,notnull,default:current_timestamp"`
+}
+
+func (m *Category) ToEntity() *entity.Category {
+	return &entity.Category{
+		ID:        m.ID,
+		TenantID:  m.TenantID,
+		Name:      m.Name,
+		CreatedAt: m.CreatedAt,
+		UpdatedAt: m.UpdatedAt,
+	}
+}
+
+var (
+	_ bun.BeforeSelectHook = (*Category)(nil)
+	_ bun.BeforeInsertHook = (*Category)(nil)
+	_ bun.BeforeUpdateHook = (*Category)(nil)
+	_ bun.BeforeDeleteHook = (*Category)(nil)
+)
+
+func (*Category) BeforeSelect(ctx context.Context, query *bun.SelectQuery) error {
+	return scopeSelect(ctx, query)
+}
+
+func (*Category) BeforeInsert(ctx context.Context, query *bun.InsertQuery) error {
+	return scopeInsert(ctx, query)
+}
+
+func (*Category) BeforeUpdate(ctx context.Context, query *bun.UpdateQuery) error {
+	return scopeUpdate(ctx, query)
+}
+
+func (*Category) BeforeDelete(ctx context.Context, query *bun.DeleteQuery) error {
+	return scopeDelete(ctx, query)
+}
+
+type Tag struct {
+	bun.BaseModel `bun:"table:tags,alias:t"`
+	ID            uuid.UUID `bun:"id,pk,notnull"`
+	TenantID      string    `bun:"tenant_id,notnull,nullzero"`
+	Name          string    `bun:"name,notnull"`
+	CreatedAt     time.Time `bun:"created_at,notnull,default:current_timestamp"`
+	UpdatedAt     time.Time `bun:"updated_at,notnull,default:current_timestamp"`
+}
+
+func (m *Tag) ToEntity() *entity.Tag {
+	return &entity.Tag{
+		ID:        m.ID,
+		TenantID:  m.TenantID,
+		Name:      m.Name,
+		CreatedAt: m.CreatedAt,
+		UpdatedAt: m.UpdatedAt,
+	}
+}
+
+var (
+	_ bun.BeforeSelectHook = (*Tag)(nil)
+	_ bun.BeforeInsertHook = (*Tag)(nil)
+	_ bun.BeforeUpdateHook = (*Tag)(nil)
+	_ bun.BeforeDeleteHook = (*Tag)(nil)
+)
+
+func (*Tag) BeforeSelect(ctx context.Context, query *bun.SelectQuery) error {
+	return scopeSelect(ctx, query)
+}
+
+func (*Tag) BeforeInsert(ctx context.Context, query *bun.InsertQuery) error {
+	return scopeInsert(ctx, query)
+}
+
+func (*Tag) BeforeUpdate(ctx context.Context, query *bun.UpdateQuery) error {
+	return scopeUpdate(ctx, query)
+}
+
+func (*Tag) BeforeDelete(ctx context.Context, query *bun.DeleteQuery) error {
+	return scopeDelete(ctx, query)
+}
+
+// EventTag links an event to one of its tags.
+type EventTag struct {
+	bun.BaseModel `bun:"table:event_tags,alias:et"`
+	EventID       uuid.UUID `bun:"event_id,pk,notnull"`
+	TagID         uuid.UUID `bun:"tag_id,pk,notnull"`
+	TenantID      string    `bun:"tenant_id,notnull,nullzero"`
+}
+
+var (
+	_ bun.BeforeSelectHook = (*EventTag)(nil)
+	_ bun.BeforeInsertHook = (*EventTag)(nil)
+	_ bun.BeforeDeleteHook = (*EventTag)(nil)
+)
+
+func (*EventTag) BeforeSelect(ctx context.Context, query *bun.SelectQuery) error {
+	return scopeSelect(ctx, query)
+}
+
+func (*EventTag) BeforeInsert(ctx context.Context, query *bun.InsertQuery) error {
+	return scopeInsert(ctx, query)
+}
+
+func (*EventTag) BeforeDelete(ctx context.Context, query *bun.DeleteQuery) error {
+	return scopeDelete(ctx, query)
+}
@@ -0,0 +1,72 @@
+package model
+
+import (
+	"context"
+
+	"online-registration/internal/interview/domain/tenant"
+
+	"github.com/uptrace/bun"
+)
+
+// The models of tenant scoped tables embed tenantScoped. Their hooks add the
+// tenant of the context to every select, update and delete, and set it on
+// inserts, so a repository can't forget it. Queries fail with tenant.ErrMissing
+// when the context has no tenant, unless it was lifted with
+// tenant.ContextWithAllTenants.
+//
+// bun only runs the select hook for Scan, so Count, Exists and subqueries of
+// tenant scoped tables must call ScopeToTenant themselves.
+
+func tenantFromContext(ctx context.Context) (id string, scoped bool, err error) {
+	if tenant.AllTenants(ctx) {
+		return "", false, nil
+	}
+	id, ok := tenant.FromContext(ctx)
+	if !ok {
+		return "", false, tenant.ErrMissing
+	}
+	return id, true, nil
+}
+
+// ScopeToTenant adds the tenant of ctx to a select that bun runs without
+// hooks.
+func ScopeToTenant(ctx context.Context, query *bun.SelectQuery) error {
+	return scopeSelect(ctx, query)
+}
+
+func scopeSelect(ctx context.Context, query *bun.SelectQuery) error {
+	id, scoped, err := tenantFromContext(ctx)
+	if scoped {
+		query.Where("?TableAlias.tenant_id = ?", id)
+	}
+	return err
+}
+
+func scopeUpdate(ctx context.Context, query *bun.UpdateQuery) error {
+	id, scoped, err := tenantFromContext(ctx)
+	if scoped {
+		query.Where("?TableAlias.tenant_id = ?", id)
+	}
+	return err
+}
+
+func scopeDelete(ctx context.Context, query *bun.DeleteQuery) error {
+	id, scoped, err := tenantFromContext(ctx)
+	if scoped {
+		query.Where("?TableAlias.tenant_id = ?", id)
+	}
+	return err
+}
+
+func scopeInsert(ctx context.Context, query *bun.InsertQuery) error {
+	id, scoped, err := tenantFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	if !scoped {
+		// rows inserted across tenants carry their tenant themselves
+		return nil
+	}
+	query.Value("tenant_id", "?", id)
+	return nil
+}
@@ -0,0 +1,105 @@
+package model
+
+import (
+	"context"
+	"online-registration/internal/interview/domain/entity"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+type Venue struct {
+	bun.BaseModel `bun:"table:venues,alias:v"`
+	ID            uuid.UUID `bun:"id,pk,notnull"`
+	TenantID      string    `bun:"tenant_id,notnull,nullzero"`
+	Name          string    `bun:"name,notnull"`
+	Address       string    `bun:"address,nullzero"`
+	CreatedAt     time.Time `bun:"created_at,notnull,default:current_timestamp"`
+	UpdatedAt     time.Time `bun:"updated_at,notnull,default:current_timestamp"`
+	Rooms         []*Room   `bun:"rel:has-many,join:id=venue_id"`
+}
+
+func (m *Venue) ToEntity() *entity.Venue {
+	venue := &entity.Venue{
+		ID:        m.ID,
+		TenantID:  m.TenantID,
+		Name:      m.Name,
+		Address:   m.Address,
+		CreatedAt: m.CreatedAt,
+		UpdatedAt: m.UpdatedAt,
+	}
+	for _, room := range m.Rooms {
+		venue.Rooms = append(venue.Rooms, room.ToEntity())
+	}
+	return venue
+}
+
+var (
+	_ bun.BeforeSelectHook = (*Venue)(nil)
+	_ bun.BeforeInsertHook = (*Venue)(nil)
+	_ bun.BeforeUpdateHook = (*Venue)(nil)
+	_ bun.BeforeDeleteHook = (*Venue)(nil)
+)
+
+func (*Venue) BeforeSelect(ctx context.Context, query *bun.SelectQuery) error {
+	return scopeSelect(ctx, query)
+}
+
+func (*Venue) BeforeInsert(ctx context.Context, query *bun.InsertQuery) error {
+	return scopeInsert(ctx, query)
+}
+
+func (*Venue) BeforeUpdate(ctx context.Context, query *bun.UpdateQuery) error {
+	return scopeUpdate(ctx, query)
+}
+
+func (*Venue) BeforeDelete(ctx context.Context, query *bun.DeleteQuery) error {
+	return scopeDelete(ctx, query)
+}
+
+type Room struct {
+	bun.BaseModel `bun:"table:rooms,alias:rm"`
+	ID            uuid.UUID `bun:"id,pk,notnull"`
+	TenantID      string    `bun:"tenant_id,notnull,nullzero"`
+	VenueID       uuid.UUID `bun:"venue_id,notnull"`
+	Name          string    `bun:"name,notnull"`
+	Capacity      int       `bun:"capacity,nullzero"`
+	CreatedAt     time.Time `bun:"created_at,notnull,default:current_timestamp"`
+	UpdatedAt     time.Time `bun:"updated_at,notnull,default:current_timestamp"`
+}
+
+func (m *Room) ToEntity() *entity.Room {
+	return &entity.Room{
+		ID:        m.ID,
+		TenantID:  m.TenantID,
+		VenueID:   m.VenueID,
+		Name:      m.Name,
+		Capacity:  m.Capacity,
+		CreatedAt: m.CreatedAt,
+		UpdatedAt: m.UpdatedAt,
+	}
+}
+
+var (
+	_ bun.BeforeSelectHook = (*Room)(nil)
+	_ bun.BeforeInsertHook = (*Room)(nil)
+	_ bun.BeforeUpdateHook = (*Room)(nil)
+	_ bun.BeforeDeleteHook = (*Room)(nil)
+)
+
+func (*Room) BeforeSelect(ctx context.Context, query *bun.SelectQuery) error {
+	return scopeSelect(ctx, query)
+}
+
+func (*Room) BeforeInsert(ctx context.Context, query *bun.InsertQuery) error {
+	return scopeInsert(ctx, query)
+}
+
+func (*Room) BeforeUpdate(ctx context.Context, query *bun.UpdateQuery) error {
+	return scopeUpdate(ctx, query)
+}
+
+func (*Room) BeforeDelete(ctx context.Context, query *bun.DeleteQuery) error {
+	return scopeDelete(ctx, query)
+}
@@ -0,0 +1,112 @@
+package model
+
+import (
+	"context"
+	"encoding/json"
+	"online-registration/internal/interview/domain/entity"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+type WebhookSubscription struct {
+	bun.BaseModel       `bun:"table:webhook_subscriptions,alias:ws"`
+	ID                  uuid.UUID `bun:"id,pk,notnull"`
+	TenantID            string    `bun:"tenant_id,notnull,nullzero"`
+	URL                 string    `bun:"url,notnull"`
+	Secret              string    `bun:"secret,notnull"`
+	EventTypes          []string  `bun:"event_types,array,notnull"`
+	Active              bool      `bun:"active,notnull"`
+	ConsecutiveFailures int       `bun:"consecutive_failures,notnull"`
+	DisabledAt          time.Time `bun:"disabled_at,nullzero"`
+	CreatedAt           time.Time `bun:"created_at,notnull,default:current_timestamp"`
+	UpdatedAt           time.Time `bun:"updated_at,notnull,default:current_timestamp"`
+}
+
+func (m *WebhookSubscription) ToEntity() *entity.WebhookSubscription {
+	return &entity.WebhookSubscription{
+		ID:                  m.ID,
+		TenantID:            m.TenantID,
+		URL:                 m.URL,
+		Secret:              m.Secret,
+		EventTypes:          m.EventTypes,
+		Active:              m.Active,
+		ConsecutiveFailures: m.ConsecutiveFailures,
+		DisabledAt:          timePtr(m.DisabledAt),
+		CreatedAt:           m.CreatedAt,
+		UpdatedAt:           m.UpdatedAt,
+	}
+}
+
+var (
+	_ bun.BeforeSelectHook = (*WebhookSubscription)(nil)
+	_ bun.BeforeInsertHook = (*WebhookSubscription)(nil)
+	_ bun.BeforeUpdateHook = (*WebhookSubscription)(nil)
+	_ bun.BeforeDeleteHook = (*WebhookSubscription)(nil)
+)
+
+func (*WebhookSubscription) BeforeSelect(ctx context.Context, query *bun.SelectQuery) error {
+	return scopeSelect(ctx, query)
+}
+
+func (*WebhookSubscription) BeforeInsert(ctx context.Context, query *bun.InsertQuery) error {
+	return scopeInsert(ctx, query)
+}
+
+func (*WebhookSubscription) BeforeUpdate(ctx context.Context, query *bun.UpdateQuery) error {
+	return scopeUpdate(ctx, query)
+}
+
+func (*WebhookSubscription) BeforeDelete(ctx context.Context, query *bun.DeleteQuery) error {
+	return scopeDelete(ctx, query)
+}
+
+type WebhookDelivery struct {
+	bun.BaseModel  `bun:"table:webhook_deliveries,alias:wd"`
+	ID             uuid.UUID            `bun:"id,pk,notnull"`
+	SubscriptionID uuid.UUID            `bun:"subscription_id,notnull"`
+	MessageID      uuid.UUID            `bun:"message_id,notnull"`
+	RedeliveryOf   uuid.NullUUID        `bun:"redelivery_of"`
+	EventType      string               `bun:"event_type,notnull"`
+	Payload        json.RawMessage      `bun:"payload,type:jsonb,notnull"`
+	Status         string               `bun:"status,notnull"`
+	Attempts       int                  `bun:"attempts,notnull"`
+	LastStatusCode int                  `bun:"last_status_code,nullzero"`
+	LastError      string               `bun:"last_error,nullzero"`
+	NextAttemptAt  time.Time            `bun:"next_attempt_at,notnull,default:current_timestamp"`
+	CreatedAt      time.Time            `bun:"created_at,notnull,default:current_timestamp"`
+	DeliveredAt    time.Time            `bun:"delivered_at,nullzero"`
+	Subscription   *WebhookSubscription `bun:"rel:belongs-to,join:subscription_id=id"`
+}
+
+func (m *WebhookDelivery) ToEntity() *entity.WebhookDelivery {
+	delivery := &entity.WebhookDelivery{
+		ID:             m.ID,
+		SubscriptionID: m.SubscriptionID,
+		MessageID:      m.MessageID,
+		EventType:      m.EventType,
+		Payload:        m.Payload,
+		Status:         m.Status,
+		Attempts:       m.Attempts,
+		LastStatusCode: m.LastStatusCode,
+		LastError:      m.LastError,
+		NextAttemptAt:  m.NextAttemptAt,
+		CreatedAt:      m.CreatedAt,
+		DeliveredAt:    timePtr(m.DeliveredAt),
+	}
+	if m.RedeliveryOf.Valid {
+		delivery.RedeliveryOf = &m.RedeliveryOf.UUID
+	}
+	if m.Subscription != nil {
+		delivery.Subscription = m.Subscription.ToEntity()
+	}
+	return delivery
+}
+
+func timePtr(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
@@ -0,0 +1,167 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"online-registration/internal/interview/domain/entity"
+	"online-registration/internal/interview/infrastructure/db/model"
+
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+// apiKeyTouchInterval limits how often the last use of a key is written.
+const apiKeyTouchInterval = time.Minute
+
+type APIKeyRepository struct {
+	db *bun.DB
+}
+
+func NewDBAPIKeyRepository(db *bun.DB) *APIKeyRepository {
+	return &APIKeyRepository{
+		db: db,
+	}
+}
+
+func (r *APIKeyRepository) CreateAPIKey(ctx context.Context, key *entity.APIKey) (*entity.APIKey, error) {
+	model := &model.APIKey{}
+	model = model.ToModel(*key)
+
+	_, err := r.
+		db.
+		NewInsert().
+		Model(model).
+		Returning("*").
+		Exec(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("CreateAPIKey %w", err)
+	}
+
+	return model.ToEntity(), nil
+}
+
+func (r *APIKeyRepository) ListAPIKeys(ctx context.Context) ([]*entity.APIKey, error) {
+	var models []*model.APIKey
+	err := r.
+		db.
+		NewSelect().
+		Model(&models).
+		Order("created_at").
+		Scan(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("ListAPIKeys %w", err)
+	}
+
+	keys := make([]*entity.APIKey, 0, len(models))
+	for _, m := range models {
+		keys = append(keys, m.ToEntity())
+	}
+
+	return keys, nil
+}
+
+func (r *APIKeyRepository) GetAPIKey(ctx context.Context, id uuid.UUID) (*entity.APIKey, error) {
+	model := new(model.APIKey)
+	err := r.
+		db.
+		NewSelect().
+		Model(model).
+		Where("id = ?", id).
+		Scan(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("GetAPIKey %w", err)
+	}
+
+	return model.ToEntity(), nil
+}
+
+func (r *APIKeyRepository) GetAPIKeyByPrefix(ctx context.Context, prefix string) (*entity.APIKey, error) {
+	model := new(model.APIKey)
+	err := r.
+		db.
+		NewSelect().
+		Model(model).
+		Where("prefix = ?", prefix).
+		Scan(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("GetAPIKeyByPrefix %w", err)
+	}
+
+	return model.ToEntity(), nil
+}
+
+func (r *APIKeyRepository) RevokeAPIKey(ctx context.Context, id uuid.UUID) error {
+	result, err := r.
+		db.
+		NewUpdate().
+		Model((*model.APIKey)(nil)).
+		Set("revoked_at = ?", time.Now()).
+		Where("id = ?", id).
+		Where("revoked_at IS NULL").
+		Exec(ctx)
+
+	if err != nil {
+		return fmt.Errorf("RevokeAPIKey %w", err)
+	}
+
+	return ensureAffected(result, "RevokeAPIKey")
+}
+
+func (r *APIKeyRepository) RotateAPIKey(
+	ctx context.Context, id uuid.UUID, replacement *entity.APIKey, expiresAt time.Time,
+) (*entity.APIKey, error) {
+	replacementModel := &model.APIKey{}
+	replacementModel = replacementModel.ToModel(*replacement)
+
+	err := r.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		result, err := tx.
+			NewUpdate().
+			Model((*model.APIKey)(nil)).
+			Set("expires_at = LEAST(COALESCE(expires_at, ?0), ?0)", expiresAt).
+			Where("id = ?", id).
+			Where("revoked_at IS NULL").
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+		if err := ensureAffected(result, "expire rotated key"); err != nil {
+			return err
+		}
+
+		_, err = tx.
+			NewInsert().
+			Model(replacementModel).
+			Returning("*").
+			Exec(ctx)
+		return err
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("RotateAPIKey %w", err)
+	}
+
+	return replacementModel.ToEntity(), nil
+}
+
+func (r *APIKeyRepository) TouchAPIKey(ctx context.Context, id uuid.UUID, usedAt time.Time) error {
+	_, err := r.
+		db.
+		NewUpdate().
+		Model((*model.APIKey)(nil)).
+		Set("last_used_at = ?", usedAt).
+		Where("id = ?", id).
+		Where("last_used_at IS NULL OR last_used_at < ?", usedAt.Add(-apiKeyTouchInterval)).
+		Exec(ctx)
+
+	if err != nil {
+		return fmt.Errorf("TouchAPIKey %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"online-registration/internal/interview/domain/audit"
+	"online-registration/internal/interview/domain/entity"
+	"online-registration/internal/interview/infrastructure/db/model"
+
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+type AuditLogRepository struct {
+	db *bun.DB
+}
+
+func NewDBAuditLogRepository(db *bun.DB) *AuditLogRepository {
+	return &AuditLogRepository{
+		db: db,
+	}
+}
+
+// ListEventHistory returns the latest entries of an event, newest first.
+func (r *AuditLogRepository) ListEventHistory(
+	ctx context.Context, eventID uuid.UUID, limit int,
+) ([]*entity.EventAuditEntry, error) {
+	var models []*model.EventAuditEntry
+	err := r.
+		db.
+		NewSelect().
+		Model(&models).
+		Where("event_id = ?", eventID).
+		Order("id DESC").
+		Limit(limit).
+		Scan(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("ListEventHistory %w", err)
+	}
+
+	return auditEntries(models), nil
+}
+
+func (r *AuditLogRepository) ListAuditEntries(
+	ctx context.Context, filter entity.AuditLogFilter,
+) ([]*entity.EventAuditEntry, error) {
+	var models []*model.EventAuditEntry
+	query := r.
+		db.
+		NewSelect().
+		Model(&models).
+		Where("id > ?", filter.AfterID).
+		Order("id").
+		Limit(filter.Limit)
+
+	if !filter.Since.IsZero() {
+		query = query.Where("created_at >= ?", filter.Since)
+	}
+	if !filter.Until.IsZero() {
+		query = query.Where("created_at < ?", filter.Until)
+	}
+
+	if err := query.Scan(ctx); err != nil {
+		return nil, fmt.Errorf("ListAuditEntries %w", err)
+	}
+
+	return auditEntries(models), nil
+}
+
+func auditEntries(models []*model.EventAuditEntry) []*entity.EventAuditEntry {
+	entries := make([]*entity.EventAuditEntry, 0, len(models))
+	for _, m := range models {
+		entries = append(entries, m.ToEntity())
+	}
+	return entries
+}
+
+// insertEventAudit records the change of an event from before to after, by
+// the actor of ctx. It must be called with the transaction that changes the
+// events row.
+func insertEventAudit(ctx context.Context, db bun.IDB, action string, before, after *entity.Event) error {
+	event := after
+	if event == nil {
+		event = before
+	}
+
+	_, err := db.
+		NewInsert().
+		Model(&model.EventAuditEntry{
+			TenantID:  event.TenantID,
+			EventID:   event.ID,
+			Action:    action,
+			Actor:     audit.Actor(ctx),
+			RequestID: audit.RequestIDFromContext(ctx),
+			Changes:   entity.EventAuditDiff(before, after),
+		}).
+		Exec(ctx)
+
+	if err != nil {
+		return fmt.Errorf("insert audit entry: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,141 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"online-registration/internal/interview/domain/audit"
+	"online-registration/internal/interview/domain/entity"
+	"online-registration/internal/interview/domain/tenant"
+)
+
+func TestAuditLogRecordsEveryChange(t *testing.T) {
+	db := openMigratedTestDB(t)
+	ctx := audit.ContextWithRequestID(tenantContext("acme"), "r1")
+	events := NewDBEventRepository(db, "english")
+	auditLog := NewDBAuditLogRepository(db)
+
+	event := createTestEvent(t, ctx, events, testEvent("Onboarding day", 0))
+	event.Title = "Onboarding week"
+	if _, err := events.UpdateEvent(ctx, event); err != nil {
+		t.Fatalf("UpdateEvent: %v", err)
+	}
+	if _, err := events.TransitionEvent(ctx, event.ID, entity.EventPublished, ""); err != nil {
+		t.Fatalf("TransitionEvent: %v", err)
+	}
+	if err := events.DeleteEvent(ctx, event.ID); err != nil {
+		t.Fatalf("DeleteEvent: %v", err)
+	}
+
+	history, err := auditLog.ListEventHistory(ctx, event.ID, 10)
+	if err != nil {
+		t.Fatalf("ListEventHistory: %v", err)
+	}
+	var actions []string
+	for _, entry := range history {
+		actions = append(actions, entry.Action)
+		if entry.Actor != "bob" || entry.RequestID != "r1" || entry.TenantID != "acme" {
+			t.Errorf("%s entry by %q in request %q of tenant %q, want bob in r1 of acme",
+				entry.Action, entry.Actor, entry.RequestID, entry.TenantID)
+		}
+	}
+	want := []string{
+		entity.AuditActionDeleted, entity.AuditActionPublished, entity.AuditActionUpdated, entity.AuditActionCreated,
+	}
+	if fmt.Sprint(actions) != fmt.Sprint(want) {
+		t.Fatalf("history = %v, want %v", actions, want)
+	}
+
+	tests := []struct {
+		action   string
+		field    string
+		from, to any
+		changes  int
+	}{
+		{entity.AuditActionUpdated, "title", "Onboarding day", "Onboarding week", 1},
+		{entity.AuditActionPublished, "status", entity.EventDraft, entity.EventPublished, 1},
+		{entity.AuditActionCreated, "title", nil, "Onboarding day", len(entity.EventAuditDiff(nil, event))},
+		{entity.AuditActionDeleted, "title", "Onboarding week", nil, len(entity.EventAuditDiff(event, nil))},
+	}
+	for _, tt := range tests {
+		for _, entry := range history {
+			if entry.Action != tt.action {
+				continue
+			}
+			change := entry.Changes[tt.field]
+			if len(entry.Changes) != tt.changes || change.From != tt.from || change.To != tt.to {
+				t.Errorf("%s entry changed %d fields, %s from %v to %v, want %d, from %v to %v",
+					tt.action, len(entry.Changes), tt.field, change.From, change.To, tt.changes, tt.from, tt.to)
+			}
+		}
+	}
+
+	// the history of an event is kept within its tenant
+	other, err := auditLog.ListEventHistory(tenantContext("globex"), event.ID, 10)
+	if err != nil {
+		t.Fatalf("ListEventHistory of another tenant: %v", err)
+	}
+	if len(other) != 0 {
+		t.Errorf("another tenant read %d entries, want none", len(other))
+	}
+}
+
+func TestAuditLogListEntries(t *testing.T) {
+	db := openMigratedTestDB(t)
+	events := NewDBEventRepository(db, "english")
+	auditLog := NewDBAuditLogRepository(db)
+	for i, tenantID := range []string{"acme", "globex", "acme"} {
+		createTestEvent(t, tenantContext(tenantID), events, testEvent(fmt.Sprint("Event ", i), i))
+	}
+
+	ctx := tenant.ContextWithAllTenants(context.Background())
+	list := func(filter entity.AuditLogFilter) []int64 {
+		t.Helper()
+
+		entries, err := auditLog.ListAuditEntries(ctx, filter)
+		if err != nil {
+			t.Fatalf("ListAuditEntries: %v", err)
+		}
+		ids := []int64{}
+		for _, entry := range entries {
+			ids = append(ids, entry.ID)
+		}
+		return ids
+	}
+
+	all := list(entity.AuditLogFilter{Limit: 10})
+	if len(all) != 3 {
+		t.Fatalf("listed %d entries of all tenants, want 3", len(all))
+	}
+
+	now := time.Now()
+	tests := []struct {
+		name   string
+		filter entity.AuditLogFilter
+		want   []int64
+	}{
+		{"first page", entity.AuditLogFilter{Limit: 2}, all[:2]},
+		{"next page", entity.AuditLogFilter{AfterID: all[1], Limit: 2}, all[2:]},
+		{"last page", entity.AuditLogFilter{AfterID: all[2], Limit: 2}, []int64{}},
+		{"since", entity.AuditLogFilter{Since: now.Add(-time.Hour), Limit: 10}, all},
+		{"since later", entity.AuditLogFilter{Since: now.Add(time.Hour), Limit: 10}, []int64{}},
+		{"until", entity.AuditLogFilter{Until: now.Add(time.Hour), Limit: 10}, all},
+		{"until earlier", entity.AuditLogFilter{Until: now.Add(-time.Hour), Limit: 10}, []int64{}},
+	}
+	for _, tt := range tests {
+		if got := list(tt.filter); fmt.Sprint(got) != fmt.Sprint(tt.want) {
+			t.Errorf("%s = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+
+	// a tenant only exports its own entries
+	entries, err := auditLog.ListAuditEntries(tenantContext("acme"), entity.AuditLogFilter{Limit: 10})
+	if err != nil {
+		t.Fatalf("ListAuditEntries of a tenant: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Errorf("acme listed %d entries, want 2", len(entries))
+	}
+}
@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"online-registration/internal/interview/domain/dto"
+	"online-registration/internal/interview/domain/entity"
+	"online-registration/internal/interview/domain/tenant"
+	"online-registration/internal/interview/infrastructure/db/model"
+
+	"github.com/uptrace/bun"
+)
+
+// eventChangesChannel is the channel the outbox trigger notifies the ids of
+// lifecycle messages on, see migration 000021.
+const eventChangesChannel = "event_changes"
+
+// EventChangeRepository reads event changes from the lifecycle messages of
+// the outbox, so they are streamed from the same transactions that publish
+// them to NATS.
+type EventChangeRepository struct {
+	db *bun.DB
+}
+
+func NewDBEventChangeRepository(db *bun.DB) *EventChangeRepository {
+	return &EventChangeRepository{
+		db: db,
+	}
+}
+
+func (r *EventChangeRepository) ListEventChanges(ctx context.Context, ids []int64) ([]*entity.EventChange, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	var models []*model.OutboxMessage
+	err := r.
+		db.
+		NewSelect().
+		Model(&models).
+		Column("id", "subject", "payload").
+		Where("id IN (?)", bun.In(ids)).
+		Where("subject IN (?)", bun.In(dto.EventTypes)).
+		Order("id").
+		Scan(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("ListEventChanges %w", err)
+	}
+
+	return toEventChanges(models)
+}
+
+func (r *EventChangeRepository) ListEventChangesAfter(
+	ctx context.Context, afterID int64, limit int,
+) ([]*entity.EventChange, error) {
+	query := r.
+		db.
+		NewSelect().
+		Model((*model.OutboxMessage)(nil)).
+		Column("id", "subject", "payload").
+		Where("id > ?", afterID).
+		Where("subject IN (?)", bun.In(dto.EventTypes)).
+		Order("id").
+		Limit(limit)
+
+	// the outbox isn't tenant scoped, the tenant is read from the message
+	if !tenant.AllTenants(ctx) {
+		id, ok := tenant.FromContext(ctx)
+		if !ok {
+			return nil, fmt.Errorf("ListEventChangesAfter %w", tenant.ErrMissing)
+		}
+		query.Where("payload->'event'->>'tenant_id' = ?", id)
+	}
+
+	var models []*model.OutboxMessage
+	if err := query.Scan(ctx, &models); err != nil {
+		return nil, fmt.Errorf("ListEventChangesAfter %w", err)
+	}
+
+	return toEventChanges(models)
+}
+
+func (r *EventChangeRepository) ListenEventChanges(
+	ctx context.Context, listening func(), notify func(id int64),
+) error {
+	err := listen(ctx, r.db, eventChangesChannel, listening, func(payload string) {
+		if id, err := strconv.ParseInt(payload, 10, 64); err == nil {
+			notify(id)
+		}
+	})
+	if err != nil {
+		return fmt.Errorf("ListenEventChanges %w", err)
+	}
+	return nil
+}
+
+func toEventChanges(models []*model.OutboxMessage) ([]*entity.EventChange, error) {
+	changes := make([]*entity.EventChange, 0, len(models))
+	for _, m := range models {
+		var message dto.EventMessage
+		if err := json.Unmarshal(m.Payload, &message); err != nil {
+			return nil, fmt.Errorf("unmarshal event message %d: %w", m.ID, err)
+		}
+
+		changes = append(changes, &entity.EventChange{
+			ID:         m.ID,
+			Type:       m.Subject,
+			EventID:    message.Event.ID,
+			TenantID:   message.Event.TenantID,
+			Status:     message.Event.Status,
+			CategoryID: message.Event.CategoryID,
+			Tags:       message.Event.Tags,
+			CreatedBy:  message.Event.CreatedBy,
+			Message:    m.Payload,
+		})
+	}
+	return changes, nil
+}
@@ -2,54 +2,677 @@ package repository
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
 	"time"
 
+	"online-registration/internal/interview/domain/dto"
 	"online-registration/internal/interview/domain/entity"
+	"online-registration/internal/interview/domain/repository"
 	"online-registration/internal/interview/infrastructure/db/model"
 
 	"github.com/google/uuid"
 	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/schema"
 )
 
 type EventRepository struct {
 	db *bun.DB
+	// searchLanguage is the text search configuration events are indexed
+	// and searched with.
+	searchLanguage string
 }
 
-func NewDBEventRepository(db *bun.DB) *EventRepository {
+func NewDBEventRepository(db *bun.DB, searchLanguage string) *EventRepository {
 	return &EventRepository{
-		db: db,
+		db:             db,
+		searchLanguage: searchLanguage,
 	}
 }
 
-func (r *EventRepository) CreateEvent(
-	ctx context.Context,
-	title string, description string, startTime time.Time, endTime time.Time,
-) (*entity.Event, error) {
+func (r *EventRepository) CreateEvent(ctx context.Context, event *entity.Event) (*entity.Event, error) {
+	model := &model.Event{}
+	model = model.ToModel(*event)
+	model.ID = uuid.New()
+	model.SearchLanguage = r.searchLanguage
+	var created *entity.Event
+
+	err := r.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		if err := lockRoom(ctx, tx, event.RoomID); err != nil {
+			return err
+		}
+		if err := lockCategory(ctx, tx, event.CategoryID); err != nil {
+			return err
+		}
+
+		_, err := tx.
+			NewInsert().
+			Model(model).
+			Returning("*").
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+
+		created = model.ToEntity()
+		if created.Tags, err = setEventTags(ctx, tx, model.ID, event.Tags); err != nil {
+			return err
+		}
+
+		if err := insertEventAudit(ctx, tx, entity.AuditActionCreated, nil, created); err != nil {
+			return err
+		}
 
-	registrationLog := &entity.Event{
-		ID:          uuid.New(),
-		Title:       title,
-		Description: description,
-		StartTime:   startTime,
-		EndTime:     endTime,
+		return insertEventMessage(ctx, tx, dto.EventCreatedSubject, created)
+	})
+
+	if isRoomOverlap(err) {
+		err = r.roomConflict(ctx, model.ID, *event.RoomID, event.StartTime, event.EndTime)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("CreateEvent %w", err)
 	}
 
-	model := &model.Event{}
-	model = model.ToModel(*registrationLog)
+	return created, nil
+}
+
+func (r *EventRepository) GetEvent(ctx context.Context, id uuid.UUID) (*entity.Event, error) {
+	model := new(model.Event)
+	err := r.
+		db.
+		NewSelect().
+		Model(model).
+		Where("id = ?", id).
+		Scan(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("GetEvent %w", err)
+	}
+
+	event := model.ToEntity()
+	if err := loadEventTags(ctx, r.db, event); err != nil {
+		return nil, fmt.Errorf("GetEvent %w", err)
+	}
+
+	return event, nil
+}
 
-	_, err := r.
+// GetEventWithDeleted also returns soft deleted events.
+func (r *EventRepository) GetEventWithDeleted(ctx context.Context, id uuid.UUID) (*entity.Event, error) {
+	model := new(model.Event)
+	err := r.
 		db.
-		NewInsert().
+		NewSelect().
 		Model(model).
-		Returning("*").
+		WhereAllWithDeleted().
+		Where("id = ?", id).
+		Scan(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("GetEventWithDeleted %w", err)
+	}
+
+	event := model.ToEntity()
+	if err := loadEventTags(ctx, r.db, event); err != nil {
+		return nil, fmt.Errorf("GetEventWithDeleted %w", err)
+	}
+
+	return event, nil
+}
+
+func (r *EventRepository) ListEvents(ctx context.Context, filter entity.EventFilter) ([]*entity.Event, error) {
+	var models []*model.Event
+	query := filterEvents(r.db.NewSelect().Model(&models), filter).
+		Order("start_time", "id")
+
+	if err := query.Scan(ctx); err != nil {
+		return nil, fmt.Errorf("ListEvents %w", err)
+	}
+
+	events := make([]*entity.Event, 0, len(models))
+	for _, m := range models {
+		events = append(events, m.ToEntity())
+	}
+	if err := loadEventTags(ctx, r.db, events...); err != nil {
+		return nil, fmt.Errorf("ListEvents %w", err)
+	}
+
+	return events, nil
+}
+
+// eventMatch is an event with the search columns of SearchEvents.
+type eventMatch struct {
+	model.Event          `bun:",extend"`
+	Rank                 float64 `bun:"rank,scanonly"`
+	TitleHighlight       string  `bun:"title_highlight,scanonly"`
+	DescriptionHighlight string  `bun:"description_highlight,scanonly"`
+}
+
+// SearchEvents returns the events matching filter.Query, the most relevant
+// first.
+func (r *EventRepository) SearchEvents(ctx context.Context, filter entity.EventFilter) ([]*entity.EventMatch, error) {
+	terms := entity.SearchTerms(filter.Query)
+	if len(terms) == 0 {
+		return []*entity.EventMatch{}, nil
+	}
+
+	tsQuery := r.tsQuery(terms)
+	highlight := func(column string, options string) schema.QueryWithArgs {
+		return bun.SafeQuery("ts_headline(?::regconfig, ?TableAlias.?, ?, ?)",
+			r.searchLanguage, bun.Ident(column), tsQuery,
+			fmt.Sprintf("StartSel=%s, StopSel=%s, %s", entity.HighlightStart, entity.HighlightStop, options),
+		)
+	}
+
+	var models []*eventMatch
+	query := filterEvents(r.db.NewSelect().Model(&models), filter).
+		ColumnExpr("?TableColumns").
+		ColumnExpr("ts_rank_cd(?TableAlias.search_vector, ?) AS rank", tsQuery).
+		ColumnExpr("? AS title_highlight", highlight("title", "HighlightAll=true")).
+		ColumnExpr("? AS description_highlight", highlight("description", "MaxFragments=2, MaxWords=30, MinWords=10")).
+		Where("?TableAlias.search_vector @@ ?", tsQuery).
+		OrderExpr("rank DESC").
+		Order("start_time", "id")
+
+	if err := query.Scan(ctx); err != nil {
+		return nil, fmt.Errorf("SearchEvents %w", err)
+	}
+
+	matches := make([]*entity.EventMatch, 0, len(models))
+	events := make([]*entity.Event, 0, len(models))
+	for _, m := range models {
+		match := &entity.EventMatch{
+			Event:                m.ToEntity(),
+			Rank:                 m.Rank,
+			TitleHighlight:       m.TitleHighlight,
+			DescriptionHighlight: m.DescriptionHighlight,
+		}
+		matches = append(matches, match)
+		events = append(events, match.Event)
+	}
+	if err := loadEventTags(ctx, r.db, events...); err != nil {
+		return nil, fmt.Errorf("SearchEvents %w", err)
+	}
+
+	return matches, nil
+}
+
+// CountFacets counts the events of filter by tag and by category.
+func (r *EventRepository) CountFacets(ctx context.Context, filter entity.EventFilter) (*entity.EventFacets, error) {
+	facets := &entity.EventFacets{
+		Tags:       []*entity.Facet{},
+		Categories: []*entity.Facet{},
+	}
+
+	// like SearchEvents, a query without terms matches nothing
+	terms := entity.SearchTerms(filter.Query)
+	if filter.Query != "" && len(terms) == 0 {
+		return facets, nil
+	}
+
+	filter.Limit, filter.Offset = 0, 0
+	matching := func() *bun.SelectQuery {
+		query := filterEvents(r.db.NewSelect().Model((*model.Event)(nil)), filter)
+		if len(terms) > 0 {
+			query = query.Where("?TableAlias.search_vector @@ ?", r.tsQuery(terms))
+		}
+		return query
+	}
+
+	err := matching().
+		ColumnExpr("t.id, t.name, count(*) AS count").
+		Join("JOIN event_tags AS et ON et.event_id = ?TableAlias.id").
+		Join("JOIN tags AS t ON t.id = et.tag_id").
+		GroupExpr("t.id, t.name").
+		OrderExpr("count DESC, t.name").
+		Scan(ctx, &facets.Tags)
+	if err != nil {
+		return nil, fmt.Errorf("CountFacets %w", err)
+	}
+
+	err = matching().
+		ColumnExpr("cat.id, cat.name, count(*) AS count").
+		Join("JOIN categories AS cat ON cat.id = ?TableAlias.category_id").
+		GroupExpr("cat.id, cat.name").
+		OrderExpr("count DESC, cat.name").
+		Scan(ctx, &facets.Categories)
+	if err != nil {
+		return nil, fmt.Errorf("CountFacets %w", err)
+	}
+
+	return facets, nil
+}
+
+// tsQuery matches the search terms as prefixes. It uses the language of the
+// deployment, so that the index is used, rows indexed in another one need
+// ReindexSearch to be found reliably.
+func (r *EventRepository) tsQuery(terms []string) schema.QueryWithArgs {
+	return bun.SafeQuery("to_tsquery(?::regconfig, ?)", r.searchLanguage, entity.PrefixQuery(terms))
+}
+
+// ReindexSearch rebuilds the search index of the events indexed in another
+// language than the current one, and returns how many were reindexed.
+func (r *EventRepository) ReindexSearch(ctx context.Context) (int, error) {
+	result, err := r.
+		db.
+		NewUpdate().
+		Model((*model.Event)(nil)).
+		WhereAllWithDeleted().
+		Set("search_language = ?", r.searchLanguage).
+		Where("search_language <> ?::regconfig", r.searchLanguage).
 		Exec(ctx)
 
 	if err != nil {
-		return nil, fmt.Errorf("CreateEvent %w", err)
+		return 0, fmt.Errorf("ReindexSearch %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("ReindexSearch %w", err)
+	}
+
+	return int(affected), nil
+}
+
+// filterEvents applies the status, tags, category, visibility and paging of
+// filter to a listing of events.
+func filterEvents(query *bun.SelectQuery, filter entity.EventFilter) *bun.SelectQuery {
+	query = query.
+		Limit(filter.Limit).
+		Offset(filter.Offset)
+
+	if len(filter.Statuses) > 0 {
+		query = query.Where("status IN (?)", bun.In(filter.Statuses))
+	}
+	if len(filter.Tags) > 0 {
+		query = query.Where("?TableAlias.id IN (?)", taggedEvents(filter.Tags, filter.AllTags))
+	}
+	if len(filter.CategoryIDs) > 0 {
+		query = query.Where("?TableAlias.category_id IN (?)", bun.In(filter.CategoryIDs))
+	}
+	if !filter.AllDrafts {
+		query = query.WhereGroup(" AND ", func(q *bun.SelectQuery) *bun.SelectQuery {
+			q = q.Where("status <> ?", entity.EventDraft)
+			if filter.DraftsOf != "" {
+				q = q.WhereOr("created_by = ?", filter.DraftsOf)
+			}
+			return q
+		})
+	}
+	return query
+}
+
+func (r *EventRepository) UpdateEvent(ctx context.Context, event *entity.Event) (*entity.Event, error) {
+	model := &model.Event{}
+	var updated *entity.Event
+
+	err := r.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		before, err := lockEvent(ctx, tx, event.ID)
+		if err != nil {
+			return err
+		}
+		if !before.Editable() {
+			return repository.ErrEventNotEditable
+		}
+		if err := lockRoom(ctx, tx, event.RoomID); err != nil {
+			return err
+		}
+		if err := lockCategory(ctx, tx, event.CategoryID); err != nil {
+			return err
+		}
+
+		err = tx.
+			NewUpdate().
+			Model(model).
+			Set("title = ?", event.Title).
+			Set("description = ?", event.Description).
+			Set("start_time = ?", event.StartTime).
+			Set("end_time = ?", event.EndTime).
+			Set("time_zone = ?", event.TimeZone).
+			Set("all_day = ?", event.AllDay).
+			Set("onboarding = ?", event.Onboarding).
+			Set("room_id = ?", event.RoomID).
+			Set("category_id = ?", event.CategoryID).
+			Set("search_language = ?", r.searchLanguage).
+			Set("updated_at = ?", time.Now()).
+			Where("id = ?", event.ID).
+			Returning("*").
+			Scan(ctx)
+		if err != nil {
+			return err
+		}
+
+		updated = model.ToEntity()
+		if updated.Tags, err = setEventTags(ctx, tx, event.ID, event.Tags); err != nil {
+			return err
+		}
+
+		if err := insertEventAudit(ctx, tx, entity.AuditActionUpdated, before, updated); err != nil {
+			return err
+		}
+
+		return insertEventMessage(ctx, tx, dto.EventUpdatedSubject, updated)
+	})
+
+	if isRoomOverlap(err) {
+		err = r.roomConflict(ctx, event.ID, *event.RoomID, event.StartTime, event.EndTime)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("UpdateEvent %w", err)
+	}
+
+	return updated, nil
+}
+
+// DeleteEvent soft deletes the event, leaving a tombstone row behind.
+func (r *EventRepository) DeleteEvent(ctx context.Context, id uuid.UUID) error {
+	model := &model.Event{}
+
+	err := r.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		err := tx.
+			NewDelete().
+			Model(model).
+			Where("id = ?", id).
+			Returning("*").
+			Scan(ctx)
+		if err != nil {
+			return err
+		}
+
+		// the tags stay linked to the tombstone
+		deleted := model.ToEntity()
+		if err := loadEventTags(ctx, tx, deleted); err != nil {
+			return err
+		}
+
+		if err := insertEventAudit(ctx, tx, entity.AuditActionDeleted, deleted, nil); err != nil {
+			return err
+		}
+
+		return insertEventMessage(ctx, tx, dto.EventDeletedSubject, deleted)
+	})
+
+	if err != nil {
+		return fmt.Errorf("DeleteEvent %w", err)
+	}
+
+	return nil
+}
+
+// transitionAuditActions names the audit entries of status changes.
+var transitionAuditActions = map[string]string{
+	entity.EventPublished: entity.AuditActionPublished,
+	entity.EventCancelled: entity.AuditActionCancelled,
+	entity.EventCompleted: entity.AuditActionCompleted,
+}
+
+// transitionSubjects are the subjects status changes are published to.
+var transitionSubjects = map[string]string{
+	entity.EventPublished: dto.EventPublishedSubject,
+	entity.EventCancelled: dto.EventCancelledSubject,
+	entity.EventCompleted: dto.EventCompletedSubject,
+}
+
+func (r *EventRepository) TransitionEvent(
+	ctx context.Context, id uuid.UUID, to string, reason string,
+) (*entity.Event, error) {
+	model := &model.Event{}
+	var after *entity.Event
+
+	err := r.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		before, err := lockEvent(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		if !entity.CanTransitionEvent(before.Status, to) {
+			return &repository.EventTransitionError{From: before.Status, To: to}
+		}
+
+		query := tx.
+			NewUpdate().
+			Model(model).
+			Set("status = ?", to).
+			Set("updated_at = ?", time.Now()).
+			Where("id = ?", id).
+			Returning("*")
+		if to == entity.EventCancelled {
+			query = query.Set("cancellation_reason = ?", reason)
+		}
+
+		if err := query.Scan(ctx); err != nil {
+			return err
+		}
+
+		after = model.ToEntity()
+		after.Tags = before.Tags
+		return recordTransition(ctx, tx, before, after)
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("TransitionEvent %w", err)
+	}
+
+	return after, nil
+}
+
+func (r *EventRepository) CompleteEvents(
+	ctx context.Context, endedBefore time.Time, limit int,
+) ([]*entity.Event, error) {
+	var models []*model.Event
+	var events []*entity.Event
+
+	err := r.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		ended := tx.
+			NewSelect().
+			Model((*model.Event)(nil)).
+			Column("id").
+			Where("status = ?", entity.EventPublished).
+			Where("end_time < ?", endedBefore).
+			Order("end_time").
+			Limit(limit).
+			For("UPDATE SKIP LOCKED")
+
+		err := tx.
+			NewUpdate().
+			Model(&models).
+			Set("status = ?", entity.EventCompleted).
+			Set("updated_at = ?", time.Now()).
+			Where("id IN (?)", ended).
+			Returning("*").
+			Scan(ctx)
+		if err != nil {
+			return err
+		}
+
+		for _, m := range models {
+			events = append(events, m.ToEntity())
+		}
+		if err := loadEventTags(ctx, tx, events...); err != nil {
+			return err
+		}
+
+		for _, after := range events {
+			before := *after
+			before.Status = entity.EventPublished
+			if err := recordTransition(ctx, tx, &before, after); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("CompleteEvents %w", err)
+	}
+
+	return events, nil
+}
+
+func (r *EventRepository) PurgeDeletedEvents(ctx context.Context, deletedBefore time.Time, limit int) (int, error) {
+	var ids []uuid.UUID
+
+	err := r.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		err := tx.
+			NewSelect().
+			Model((*model.Event)(nil)).
+			Column("id").
+			WhereDeleted().
+			Where("deleted_at < ?", deletedBefore).
+			Order("deleted_at").
+			Limit(limit).
+			For("UPDATE SKIP LOCKED").
+			Scan(ctx, &ids)
+		if err != nil || len(ids) == 0 {
+			return err
+		}
+
+		// the rows referencing the events go first, the audit log is kept
+		dependents := []any{
+			(*model.Notification)(nil),
+			(*model.Registration)(nil),
+			(*model.Participant)(nil),
+			(*model.EventTag)(nil),
+		}
+		for _, dependent := range dependents {
+			_, err := tx.
+				NewDelete().
+				Model(dependent).
+				Where("event_id IN (?)", bun.In(ids)).
+				Exec(ctx)
+			if err != nil {
+				return err
+			}
+		}
+
+		_, err = tx.
+			NewDelete().
+			Model((*model.Event)(nil)).
+			Where("id IN (?)", bun.In(ids)).
+			ForceDelete().
+			Exec(ctx)
+		return err
+	})
+
+	if err != nil {
+		return 0, fmt.Errorf("PurgeDeletedEvents %w", err)
+	}
+
+	return len(ids), nil
+}
+
+// recordTransition writes the audit entry and the messages of a status
+// change. Registrants are notified of cancellations.
+func recordTransition(ctx context.Context, tx bun.Tx, before, after *entity.Event) error {
+	if err := insertEventAudit(ctx, tx, transitionAuditActions[after.Status], before, after); err != nil {
+		return err
+	}
+
+	if err := insertEventMessage(ctx, tx, transitionSubjects[after.Status], after); err != nil {
+		return err
+	}
+
+	if after.Status != entity.EventCancelled {
+		return nil
+	}
+
+	var registrations []*model.Registration
+	err := tx.
+		NewSelect().
+		Model(&registrations).
+		Where("event_id = ?", after.ID).
+		Where("status IN (?)", bun.In(entity.ActiveRegistrationStatuses)).
+		Scan(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, registration := range registrations {
+		err := insertRegistrationMessage(
+			ctx, tx, dto.RegistrationEventCancelledSubject, registration.ToEntity(), after,
+		)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// roomConflict finds the event that keeps eventID out of the room between
+// startTime and endTime, after the booking was rejected by the database.
+func (r *EventRepository) roomConflict(
+	ctx context.Context, eventID, roomID uuid.UUID, startTime, endTime time.Time,
+) error {
+	conflict := &repository.RoomConflictError{RoomID: roomID}
+
+	booked := new(model.Event)
+	err := r.
+		db.
+		NewSelect().
+		Model(booked).
+		Column("id", "title").
+		Where("room_id = ?", roomID).
+		Where("id <> ?", eventID).
+		Where("status <> ?", entity.EventCancelled).
+		Where("tstzrange(start_time, end_time, '[)') && tstzrange(?, ?, '[)')", startTime, endTime).
+		Order("start_time").
+		Limit(1).
+		Scan(ctx)
+	if errors.Is(err, sql.ErrNoRows) {
+		// the other booking went away since, the conflict is still reported
+		// so the caller may retry
+		return conflict
+	}
+	if err != nil {
+		return err
+	}
+
+	conflict.EventID = booked.ID
+	conflict.Title = booked.Title
+	return conflict
+}
+
+// lockRoom checks that the room exists in the tenant and keeps it from
+// being deleted until tx ends. A nil roomID books no room.
+func lockRoom(ctx context.Context, tx bun.Tx, roomID *uuid.UUID) error {
+	if roomID == nil {
+		return nil
+	}
+
+	err := tx.
+		NewSelect().
+		Model((*model.Room)(nil)).
+		Column("id").
+		Where("id = ?", *roomID).
+		For("SHARE").
+		Scan(ctx, new(uuid.UUID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return repository.ErrRoomNotFound
+	}
+	return err
+}
+
+// lockEvent reads the event as it is before a change in tx, and keeps
+// concurrent changes out until tx ends.
+func lockEvent(ctx context.Context, tx bun.Tx, id uuid.UUID) (*entity.Event, error) {
+	model := new(model.Event)
+	err := tx.
+		NewSelect().
+		Model(model).
+		Where("id = ?", id).
+		For("UPDATE").
+		Scan(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	event := model.ToEntity()
+	if err := loadEventTags(ctx, tx, event); err != nil {
+		return nil, err
 	}
 
-	return model.ToEntity(), nil
+	return event, nil
 }
 
 //
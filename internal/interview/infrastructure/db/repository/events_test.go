@@ -0,0 +1,465 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"online-registration/internal/interview/domain/auth"
+	"online-registration/internal/interview/domain/dto"
+	"online-registration/internal/interview/domain/entity"
+	"online-registration/internal/interview/domain/repository"
+	"online-registration/internal/interview/domain/tenant"
+
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+// testEventStart is the start of the events of the tests, a day ahead.
+var testEventStart = time.Now().Add(24 * time.Hour).Truncate(time.Hour)
+
+// testEvent is a draft event of an hour, starting hours after
+// testEventStart.
+func testEvent(title string, hours int) *entity.Event {
+	start := testEventStart.Add(time.Duration(hours) * time.Hour)
+	return &entity.Event{
+		Title:     title,
+		StartTime: start,
+		EndTime:   start.Add(time.Hour),
+		TimeZone:  "UTC",
+		Status:    entity.EventDraft,
+		CreatedBy: "bob",
+	}
+}
+
+// tenantContext is the context of requests of bob in tenantID.
+func tenantContext(tenantID string) context.Context {
+	ctx := auth.ContextWithPrincipal(context.Background(), &auth.Principal{Subject: "bob"})
+	return tenant.ContextWithTenant(ctx, tenantID)
+}
+
+func createTestEvent(t *testing.T, ctx context.Context, events *EventRepository, event *entity.Event) *entity.Event {
+	t.Helper()
+
+	created, err := events.CreateEvent(ctx, event)
+	if err != nil {
+		t.Fatalf("CreateEvent %s: %v", event.Title, err)
+	}
+	return created
+}
+
+func transitionTestEvent(t *testing.T, ctx context.Context, events *EventRepository, id uuid.UUID, to string) {
+	t.Helper()
+
+	if _, err := events.TransitionEvent(ctx, id, to, "snow"); err != nil {
+		t.Fatalf("TransitionEvent to %s: %v", to, err)
+	}
+}
+
+func TestEventTransitions(t *testing.T) {
+	db := openMigratedTestDB(t)
+	ctx := tenantContext("acme")
+	events := NewDBEventRepository(db, "english")
+	event := createTestEvent(t, ctx, events, testEvent("Onboarding day", 0))
+
+	published, err := events.TransitionEvent(ctx, event.ID, entity.EventPublished, "")
+	if err != nil {
+		t.Fatalf("publish: %v", err)
+	}
+	if published.Status != entity.EventPublished || published.CancellationReason != "" {
+		t.Errorf("published event is %s for %q", published.Status, published.CancellationReason)
+	}
+
+	var transitionErr *repository.EventTransitionError
+	_, err = events.TransitionEvent(ctx, event.ID, entity.EventPublished, "")
+	if !errors.As(err, &transitionErr) || transitionErr.From != entity.EventPublished {
+		t.Errorf("publishing again = %v, want a transition error from published", err)
+	}
+
+	cancelled, err := events.TransitionEvent(ctx, event.ID, entity.EventCancelled, "snow")
+	if err != nil {
+		t.Fatalf("cancel: %v", err)
+	}
+	if cancelled.Status != entity.EventCancelled || cancelled.CancellationReason != "snow" {
+		t.Errorf("cancelled event is %s for %q, want cancelled for snow", cancelled.Status, cancelled.CancellationReason)
+	}
+
+	_, err = events.TransitionEvent(ctx, event.ID, entity.EventCompleted, "")
+	if !errors.As(err, &transitionErr) || transitionErr.From != entity.EventCancelled {
+		t.Errorf("completing a cancelled event = %v, want a transition error from cancelled", err)
+	}
+	cancelled.Title = "Onboarding week"
+	if _, err := events.UpdateEvent(ctx, cancelled); !errors.Is(err, repository.ErrEventNotEditable) {
+		t.Errorf("updating a cancelled event = %v, want %v", err, repository.ErrEventNotEditable)
+	}
+
+	for _, tt := range []struct {
+		name string
+		ctx  context.Context
+		id   uuid.UUID
+	}{
+		{"missing event", ctx, uuid.New()},
+		{"event of another tenant", tenantContext("globex"), event.ID},
+	} {
+		if _, err := events.TransitionEvent(tt.ctx, tt.id, entity.EventCancelled, "snow"); !errors.Is(err, sql.ErrNoRows) {
+			t.Errorf("transition of %s = %v, want %v", tt.name, err, sql.ErrNoRows)
+		}
+	}
+
+	want := []string{dto.EventCreatedSubject, dto.EventPublishedSubject, dto.EventCancelledSubject}
+	if subjects := outboxSubjects(t, db); fmt.Sprint(subjects) != fmt.Sprint(want) {
+		t.Errorf("published %v, want %v", subjects, want)
+	}
+}
+
+func TestCompleteEvents(t *testing.T) {
+	db := openMigratedTestDB(t)
+	events := NewDBEventRepository(db, "english")
+
+	// the events of yesterday, ending in the order they are listed
+	var ended []uuid.UUID
+	for i, tenantID := range []string{"acme", "globex", "acme"} {
+		ctx := tenantContext(tenantID)
+		event := createTestEvent(t, ctx, events, testEvent(fmt.Sprint("Ended ", i), i-48))
+		transitionTestEvent(t, ctx, events, event.ID, entity.EventPublished)
+		ended = append(ended, event.ID)
+	}
+	acme := tenantContext("acme")
+	draft := createTestEvent(t, acme, events, testEvent("Ended draft", -48))
+	upcoming := createTestEvent(t, acme, events, testEvent("Upcoming", 0))
+	transitionTestEvent(t, acme, events, upcoming.ID, entity.EventPublished)
+	cancelled := createTestEvent(t, acme, events, testEvent("Ended cancelled", -47))
+	transitionTestEvent(t, acme, events, cancelled.ID, entity.EventCancelled)
+
+	ctx := tenant.ContextWithAllTenants(context.Background())
+	var completed []uuid.UUID
+	for _, want := range []int{2, 1, 0} {
+		batch, err := events.CompleteEvents(ctx, time.Now(), 2)
+		if err != nil {
+			t.Fatalf("CompleteEvents: %v", err)
+		}
+		if len(batch) != want {
+			t.Fatalf("CompleteEvents completed %d events, want %d", len(batch), want)
+		}
+		for _, event := range batch {
+			if event.Status != entity.EventCompleted {
+				t.Errorf("%s is %s, want completed", event.Title, event.Status)
+			}
+			completed = append(completed, event.ID)
+		}
+	}
+	if fmt.Sprint(completed) != fmt.Sprint(ended) {
+		t.Errorf("completed %v, want the ended events %v in the order they ended", completed, ended)
+	}
+
+	for _, tt := range []struct {
+		event *entity.Event
+		want  string
+	}{
+		{draft, entity.EventDraft},
+		{upcoming, entity.EventPublished},
+		{cancelled, entity.EventCancelled},
+	} {
+		event, err := events.GetEvent(acme, tt.event.ID)
+		if err != nil {
+			t.Fatalf("GetEvent: %v", err)
+		}
+		if event.Status != tt.want {
+			t.Errorf("%s is %s, want %s", event.Title, event.Status, tt.want)
+		}
+	}
+
+	history, err := NewDBAuditLogRepository(db).ListEventHistory(acme, ended[0], 1)
+	if err != nil {
+		t.Fatalf("ListEventHistory: %v", err)
+	}
+	if len(history) != 1 || history[0].Action != entity.AuditActionCompleted {
+		t.Errorf("completing an event wasn't audited, its history is %v", history)
+	}
+}
+
+func createTestRoom(t *testing.T, ctx context.Context, venues *VenueRepository) uuid.UUID {
+	t.Helper()
+
+	venue, err := venues.CreateVenue(ctx, "Head office", "")
+	if err != nil {
+		t.Fatalf("CreateVenue: %v", err)
+	}
+	room, err := venues.CreateRoom(ctx, venue.ID, fmt.Sprint("Room ", uuid.New()), 10)
+	if err != nil {
+		t.Fatalf("CreateRoom: %v", err)
+	}
+	return room.ID
+}
+
+// bookedEvent is testEvent in room, lasting from the hour from to the hour
+// to after testEventStart.
+func bookedEvent(title string, room uuid.UUID, from, to float64) *entity.Event {
+	event := testEvent(title, 0)
+	event.StartTime = testEventStart.Add(time.Duration(from * float64(time.Hour)))
+	event.EndTime = testEventStart.Add(time.Duration(to * float64(time.Hour)))
+	event.RoomID = &room
+	return event
+}
+
+func TestRoomOverlap(t *testing.T) {
+	db := openMigratedTestDB(t)
+	ctx := tenantContext("acme")
+	events := NewDBEventRepository(db, "english")
+	venues := NewDBVenueRepository(db)
+	room := createTestRoom(t, ctx, venues)
+	otherRoom := createTestRoom(t, ctx, venues)
+
+	booked := createTestEvent(t, ctx, events, bookedEvent("Interview", room, 0, 1))
+
+	conflict := func(t *testing.T, err error) {
+		t.Helper()
+
+		var conflictErr *repository.RoomConflictError
+		if !errors.As(err, &conflictErr) {
+			t.Fatalf("booking = %v, want a room conflict", err)
+		}
+		if conflictErr.RoomID != room || conflictErr.EventID != booked.ID || conflictErr.Title != "Interview" {
+			t.Errorf("conflict with %s %q in %s, want %s Interview in %s",
+				conflictErr.EventID, conflictErr.Title, conflictErr.RoomID, booked.ID, room)
+		}
+	}
+
+	for _, tt := range []struct {
+		name     string
+		from, to float64
+	}{
+		{"same time", 0, 1},
+		{"overlapping the start", -0.5, 0.5},
+		{"overlapping the end", 0.5, 1.5},
+		{"inside", 0.25, 0.75},
+		{"around", -1, 2},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := events.CreateEvent(ctx, bookedEvent(tt.name, room, tt.from, tt.to))
+			conflict(t, err)
+		})
+	}
+
+	// events touching each other and in other rooms don't overlap
+	createTestEvent(t, ctx, events, bookedEvent("Before", room, -1, 0))
+	after := createTestEvent(t, ctx, events, bookedEvent("After", room, 1, 2))
+	createTestEvent(t, ctx, events, bookedEvent("Elsewhere", otherRoom, 0, 1))
+
+	after.StartTime = testEventStart.Add(30 * time.Minute)
+	_, err := events.UpdateEvent(ctx, after)
+	conflict(t, err)
+
+	// a cancelled event frees its room
+	transitionTestEvent(t, ctx, events, booked.ID, entity.EventCancelled)
+	if _, err := events.UpdateEvent(ctx, after); err != nil {
+		t.Errorf("booking the room of a cancelled event: %v", err)
+	}
+
+	missing := uuid.New()
+	if _, err := events.CreateEvent(ctx, bookedEvent("Nowhere", missing, 3, 4)); !errors.Is(err, repository.ErrRoomNotFound) {
+		t.Errorf("booking a missing room = %v, want %v", err, repository.ErrRoomNotFound)
+	}
+	if _, err := events.CreateEvent(tenantContext("globex"), bookedEvent("Elsewhere", room, 3, 4)); !errors.Is(err, repository.ErrRoomNotFound) {
+		t.Errorf("booking the room of another tenant = %v, want %v", err, repository.ErrRoomNotFound)
+	}
+}
+
+func TestSearchEvents(t *testing.T) {
+	db := openMigratedTestDB(t)
+	ctx := tenantContext("acme")
+	events := NewDBEventRepository(db, "english")
+
+	for i, event := range []struct{ title, description string }{
+		{"Backend interview", "Meet the team behind the payments service"},
+		{"Team lunch", "Pizza in the kitchen"},
+		{"Onboarding day", "Welcome to the office"},
+	} {
+		created := testEvent(event.title, i)
+		created.Description = event.description
+		createTestEvent(t, ctx, events, created)
+	}
+	createTestEvent(t, tenantContext("globex"), events, testEvent("Backend interview", 0))
+
+	tests := []struct {
+		name  string
+		query string
+		want  []string
+	}{
+		{"word", "lunch", []string{"Team lunch"}},
+		{"prefix", "interv", []string{"Backend interview"}},
+		{"stem", "interviews", []string{"Backend interview"}},
+		{"all terms", "backend payments", []string{"Backend interview"}},
+		{"missing term", "backend pizza", nil},
+		{"titles rank above descriptions", "team", []string{"Team lunch", "Backend interview"}},
+		{"stop-words", "the onboarding", []string{"Onboarding day"}},
+		{"stop-words only", "the", nil},
+		{"punctuation", "onboarding & | ! :*", []string{"Onboarding day"}},
+		{"punctuation only", "&|!", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			matches, err := events.SearchEvents(ctx, entity.EventFilter{Query: tt.query, AllDrafts: true})
+			if err != nil {
+				t.Fatalf("SearchEvents: %v", err)
+			}
+			var titles []string
+			for _, match := range matches {
+				titles = append(titles, match.Title)
+			}
+			if fmt.Sprint(titles) != fmt.Sprint(tt.want) {
+				t.Errorf("SearchEvents(%q) = %q, want %q", tt.query, titles, tt.want)
+			}
+		})
+	}
+
+	matches, err := events.SearchEvents(ctx, entity.EventFilter{Query: "welc", AllDrafts: true})
+	if err != nil {
+		t.Fatalf("SearchEvents: %v", err)
+	}
+	if len(matches) != 1 {
+		t.Fatalf("SearchEvents found %d events, want Onboarding day", len(matches))
+	}
+	if highlight := matches[0].DescriptionHighlight; !strings.Contains(highlight, entity.HighlightStart+"Welcome"+entity.HighlightStop) {
+		t.Errorf("description highlighted as %q, want Welcome marked", highlight)
+	}
+	if highlight := matches[0].TitleHighlight; highlight != "Onboarding day" {
+		t.Errorf("title highlighted as %q, want it unmarked", highlight)
+	}
+}
+
+// createTaggedEvents creates the events of acme named by their tags, in the
+// categories named, and an event of globex tagged go.
+func createTaggedEvents(t *testing.T, db *bun.DB) map[string]uuid.UUID {
+	t.Helper()
+
+	ctx := tenantContext("acme")
+	events := NewDBEventRepository(db, "english")
+	tags := NewDBTagRepository(db)
+	categories := make(map[string]*uuid.UUID)
+	for _, name := range []string{"interview", "onboarding"} {
+		category, err := tags.CreateCategory(ctx, name)
+		if err != nil {
+			t.Fatalf("CreateCategory: %v", err)
+		}
+		categories[name] = &category.ID
+	}
+
+	ids := make(map[string]uuid.UUID)
+	for i, tagged := range []struct {
+		title    string
+		tags     []string
+		category string
+	}{
+		{"Backend interview", []string{"go", "remote"}, "interview"},
+		{"Go interview", []string{"go"}, "interview"},
+		{"Senior interview", []string{"remote", "senior"}, "interview"},
+		{"Go onboarding", []string{"go"}, "onboarding"},
+		{"Untagged", nil, ""},
+	} {
+		event := testEvent(tagged.title, i)
+		event.Tags = tagged.tags
+		event.CategoryID = categories[tagged.category]
+		ids[tagged.title] = createTestEvent(t, ctx, events, event).ID
+	}
+
+	other := testEvent("Go interview", 0)
+	other.Tags = []string{"go"}
+	createTestEvent(t, tenantContext("globex"), events, other)
+	return ids
+}
+
+func TestTagFilters(t *testing.T) {
+	db := openMigratedTestDB(t)
+	createTaggedEvents(t, db)
+	events := NewDBEventRepository(db, "english")
+
+	tests := []struct {
+		name string
+		tags []string
+		all  bool
+		want []string
+	}{
+		{"any of one", []string{"go"}, false, []string{"Backend interview", "Go interview", "Go onboarding"}},
+		{"any of two", []string{"go", "senior"}, false,
+			[]string{"Backend interview", "Go interview", "Senior interview", "Go onboarding"}},
+		{"all of one", []string{"remote"}, true, []string{"Backend interview", "Senior interview"}},
+		{"all of two", []string{"go", "remote"}, true, []string{"Backend interview"}},
+		{"all of a missing one", []string{"go", "rust"}, true, nil},
+		{"any of a missing one", []string{"rust"}, false, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			listed, err := events.ListEvents(tenantContext("acme"), entity.EventFilter{
+				Tags: tt.tags, AllTags: tt.all, AllDrafts: true, Limit: 10,
+			})
+			if err != nil {
+				t.Fatalf("ListEvents: %v", err)
+			}
+			var titles []string
+			for _, event := range listed {
+				titles = append(titles, event.Title)
+			}
+			if fmt.Sprint(titles) != fmt.Sprint(tt.want) {
+				t.Errorf("ListEvents tagged %v = %q, want %q", tt.tags, titles, tt.want)
+			}
+		})
+	}
+}
+
+func TestCountFacets(t *testing.T) {
+	db := openMigratedTestDB(t)
+	ids := createTaggedEvents(t, db)
+	events := NewDBEventRepository(db, "english")
+
+	formatFacets := func(facets []*entity.Facet) string {
+		var counts []string
+		for _, facet := range facets {
+			counts = append(counts, fmt.Sprintf("%s:%d", facet.Name, facet.Count))
+		}
+		return fmt.Sprint(counts)
+	}
+
+	tests := []struct {
+		name           string
+		filter         entity.EventFilter
+		wantTags       string
+		wantCategories string
+	}{
+		{"all events", entity.EventFilter{}, "[go:3 remote:2 senior:1]", "[interview:3 onboarding:1]"},
+		{"tagged", entity.EventFilter{Tags: []string{"remote"}}, "[remote:2 go:1 senior:1]", "[interview:2]"},
+		{"searched", entity.EventFilter{Query: "onboarding"}, "[go:1]", "[onboarding:1]"},
+		{"paged", entity.EventFilter{Limit: 1, Offset: 1}, "[go:3 remote:2 senior:1]", "[interview:3 onboarding:1]"},
+		{"punctuation only", entity.EventFilter{Query: "&|!"}, "[]", "[]"},
+		{"nothing", entity.EventFilter{Tags: []string{"rust"}}, "[]", "[]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.filter.AllDrafts = true
+			facets, err := events.CountFacets(tenantContext("acme"), tt.filter)
+			if err != nil {
+				t.Fatalf("CountFacets: %v", err)
+			}
+			if got := formatFacets(facets.Tags); got != tt.wantTags {
+				t.Errorf("tags = %s, want %s", got, tt.wantTags)
+			}
+			if got := formatFacets(facets.Categories); got != tt.wantCategories {
+				t.Errorf("categories = %s, want %s", got, tt.wantCategories)
+			}
+		})
+	}
+
+	// drafts are only counted for whoever may see them
+	transitionTestEvent(t, tenantContext("acme"), events, ids["Go onboarding"], entity.EventPublished)
+	facets, err := events.CountFacets(tenantContext("acme"), entity.EventFilter{})
+	if err != nil {
+		t.Fatalf("CountFacets: %v", err)
+	}
+	if got := formatFacets(facets.Tags); got != "[go:1]" {
+		t.Errorf("tags of published events = %s, want [go:1]", got)
+	}
+}
@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// SQLSTATE codes of the constraint violations handled by the repositories.
+const (
+	foreignKeyViolation = "23503"
+	uniqueViolation     = "23505"
+	exclusionViolation  = "23P01"
+)
+
+// roomOverlapConstraint keeps two events out of the same room at once.
+const roomOverlapConstraint = "events_room_overlap_excl"
+
+// isConstraintViolation reports whether err was raised by constraint with
+// the SQLSTATE code.
+func isConstraintViolation(err error, code, constraint string) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == code && pgErr.ConstraintName == constraint
+}
+
+func isRoomOverlap(err error) bool {
+	return isConstraintViolation(err, exclusionViolation, roomOverlapConstraint)
+}
+
+// ensureAffected turns an update or delete that matched no rows into
+// sql.ErrNoRows.
+func ensureAffected(result sql.Result, operation string) error {
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s %w", operation, err)
+	}
+	if affected == 0 {
+		return fmt.Errorf("%s %w", operation, sql.ErrNoRows)
+	}
+	return nil
+}
+
+func nullInt(value int) sql.NullInt64 {
+	return sql.NullInt64{Int64: int64(value), Valid: value != 0}
+}
+
+func nullString(value string) sql.NullString {
+	return sql.NullString{String: value, Valid: value != ""}
+}
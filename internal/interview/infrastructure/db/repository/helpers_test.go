@@ -0,0 +1,30 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+func TestIsRoomOverlap(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"overlap", &pgconn.PgError{Code: exclusionViolation, ConstraintName: roomOverlapConstraint}, true},
+		{"wrapped overlap", fmt.Errorf("CreateEvent %w",
+			&pgconn.PgError{Code: exclusionViolation, ConstraintName: roomOverlapConstraint}), true},
+		{"other exclusion", &pgconn.PgError{Code: exclusionViolation, ConstraintName: "other_excl"}, false},
+		{"other violation", &pgconn.PgError{Code: uniqueViolation, ConstraintName: roomOverlapConstraint}, false},
+		{"other error", errors.New("connection reset"), false},
+		{"no error", nil, false},
+	}
+	for _, tt := range tests {
+		if got := isRoomOverlap(tt.err); got != tt.want {
+			t.Errorf("isRoomOverlap of %s = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"online-registration/internal/interview/domain/entity"
+	"online-registration/internal/interview/domain/repository"
+	"online-registration/internal/interview/infrastructure/db/model"
+
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+type IdempotencyKeyRepository struct {
+	db *bun.DB
+}
+
+func NewDBIdempotencyKeyRepository(db *bun.DB) *IdempotencyKeyRepository {
+	return &IdempotencyKeyRepository{
+		db: db,
+	}
+}
+
+// Acquire inserts the key or takes over an existing one that has expired or
+// whose request was abandoned mid-flight, under a new owner. The primary key
+// makes the claim atomic across instances.
+func (r *IdempotencyKeyRepository) Acquire(
+	ctx context.Context, key, fingerprint string, ttl, lockTimeout time.Duration,
+) (*entity.IdempotencyKey, bool, error) {
+	now := time.Now()
+	record := &model.IdempotencyKey{
+		Key:         key,
+		Fingerprint: fingerprint,
+		Owner:       uuid.New(),
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(ttl),
+	}
+
+	err := r.
+		db.
+		NewInsert().
+		Model(record).
+		On("CONFLICT (key) DO UPDATE").
+		Set("fingerprint = EXCLUDED.fingerprint").
+		Set("owner = EXCLUDED.owner").
+		Set("status_code = NULL").
+		Set("response_body = NULL").
+		Set("created_at = EXCLUDED.created_at").
+		Set("expires_at = EXCLUDED.expires_at").
+		Where("ik.expires_at < ?", now).
+		WhereOr("ik.status_code IS NULL AND ik.created_at < ?", now.Add(-lockTimeout)).
+		Returning("*").
+		Scan(ctx)
+
+	if err == nil {
+		return record.ToEntity(), true, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return nil, false, fmt.Errorf("AcquireIdempotencyKey %w", err)
+	}
+
+	existing := new(model.IdempotencyKey)
+	err = r.
+		db.
+		NewSelect().
+		Model(existing).
+		Where("key = ?", key).
+		Scan(ctx)
+
+	if err != nil {
+		return nil, false, fmt.Errorf("AcquireIdempotencyKey %w", err)
+	}
+
+	return existing.ToEntity(), false, nil
+}
+
+func (r *IdempotencyKeyRepository) Complete(
+	ctx context.Context, key string, owner uuid.UUID, statusCode int, responseBody []byte,
+) error {
+	result, err := r.
+		db.
+		NewUpdate().
+		Model((*model.IdempotencyKey)(nil)).
+		Set("status_code = ?", statusCode).
+		Set("response_body = ?", responseBody).
+		Where("key = ?", key).
+		Where("owner = ?", owner).
+		Where("status_code IS NULL").
+		Exec(ctx)
+
+	if err != nil {
+		return fmt.Errorf("CompleteIdempotencyKey %w", err)
+	}
+
+	if updated, _ := result.RowsAffected(); updated == 0 {
+		return fmt.Errorf("CompleteIdempotencyKey %w", repository.ErrIdempotencyKeyLost)
+	}
+
+	return nil
+}
+
+func (r *IdempotencyKeyRepository) Release(ctx context.Context, key string, owner uuid.UUID) error {
+	result, err := r.
+		db.
+		NewDelete().
+		Model((*model.IdempotencyKey)(nil)).
+		Where("key = ?", key).
+		Where("owner = ?", owner).
+		Where("status_code IS NULL").
+		Exec(ctx)
+
+	if err != nil {
+		return fmt.Errorf("ReleaseIdempotencyKey %w", err)
+	}
+
+	if deleted, _ := result.RowsAffected(); deleted == 0 {
+		return fmt.Errorf("ReleaseIdempotencyKey %w", repository.ErrIdempotencyKeyLost)
+	}
+
+	return nil
+}
+
+func (r *IdempotencyKeyRepository) DeleteExpired(ctx context.Context, before time.Time) (int, error) {
+	result, err := r.
+		db.
+		NewDelete().
+		Model((*model.IdempotencyKey)(nil)).
+		Where("expires_at < ?", before).
+		Exec(ctx)
+
+	if err != nil {
+		return 0, fmt.Errorf("DeleteExpiredIdempotencyKeys %w", err)
+	}
+
+	deleted, _ := result.RowsAffected()
+	return int(deleted), nil
+}
@@ -0,0 +1,323 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"online-registration/internal/interview/domain/entity"
+	"online-registration/internal/interview/domain/repository"
+	"online-registration/internal/interview/infrastructure/db/model"
+
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+const jobUniqueKeyConstraint = "jobs_unique_key_idx"
+
+// activeJob matches the jobs holding their unique key.
+const activeJob = "status IN ('pending', 'running')"
+
+type JobRepository struct {
+	db *bun.DB
+}
+
+func NewDBJobRepository(db *bun.DB) *JobRepository {
+	return &JobRepository{
+		db: db,
+	}
+}
+
+func (r *JobRepository) EnqueueJob(ctx context.Context, job *entity.Job) (*entity.Job, bool, error) {
+	model := &model.Job{
+		ID:          uuid.New(),
+		Kind:        job.Kind,
+		TenantID:    job.TenantID,
+		Payload:     job.Payload,
+		UniqueKey:   job.UniqueKey,
+		Status:      entity.JobPending,
+		MaxAttempts: job.MaxAttempts,
+		RunAt:       job.RunAt,
+	}
+
+	// the job holding the key may finish between the insert and the select,
+	// the insert then goes through the second time
+	for range 2 {
+		err := r.
+			db.
+			NewInsert().
+			Model(model).
+			On("CONFLICT (unique_key) WHERE " + activeJob + " DO NOTHING").
+			Returning("*").
+			Scan(ctx)
+		if err == nil {
+			return model.ToEntity(), true, nil
+		}
+		if !errors.Is(err, sql.ErrNoRows) {
+			return nil, false, fmt.Errorf("EnqueueJob %w", err)
+		}
+
+		existing, err := r.activeJob(ctx, job.UniqueKey)
+		if err == nil {
+			return existing, false, nil
+		}
+		if !errors.Is(err, sql.ErrNoRows) {
+			return nil, false, fmt.Errorf("EnqueueJob %w", err)
+		}
+	}
+
+	return nil, false, fmt.Errorf("EnqueueJob: unique key %q is contended", job.UniqueKey)
+}
+
+func (r *JobRepository) activeJob(ctx context.Context, uniqueKey string) (*entity.Job, error) {
+	model := new(model.Job)
+	err := r.
+		db.
+		NewSelect().
+		Model(model).
+		Where("unique_key = ?", uniqueKey).
+		Where(activeJob).
+		Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return model.ToEntity(), nil
+}
+
+func (r *JobRepository) GetJob(ctx context.Context, id uuid.UUID) (*entity.Job, error) {
+	model := new(model.Job)
+	err := r.
+		db.
+		NewSelect().
+		Model(model).
+		Where("id = ?", id).
+		Scan(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("GetJob %w", err)
+	}
+
+	return model.ToEntity(), nil
+}
+
+func (r *JobRepository) ListJobs(ctx context.Context, filter entity.JobFilter) ([]*entity.Job, error) {
+	var models []*model.Job
+	query := r.
+		db.
+		NewSelect().
+		Model(&models).
+		Order("created_at DESC").
+		Limit(filter.Limit)
+	if filter.Kind != "" {
+		query = query.Where("kind = ?", filter.Kind)
+	}
+	if len(filter.Statuses) > 0 {
+		query = query.Where("status IN (?)", bun.In(filter.Statuses))
+	}
+
+	if err := query.Scan(ctx); err != nil {
+		return nil, fmt.Errorf("ListJobs %w", err)
+	}
+
+	jobs := make([]*entity.Job, 0, len(models))
+	for _, m := range models {
+		jobs = append(jobs, m.ToEntity())
+	}
+
+	return jobs, nil
+}
+
+func (r *JobRepository) RetryJob(ctx context.Context, id uuid.UUID) (*entity.Job, error) {
+	model := new(model.Job)
+	err := r.
+		db.
+		NewUpdate().
+		Model(model).
+		Set("status = ?", entity.JobPending).
+		Set("attempts = 0").
+		Set("run_at = ?", time.Now()).
+		Set("locked_until = NULL").
+		Set("finished_at = NULL").
+		Set("updated_at = ?", time.Now()).
+		Where("id = ?", id).
+		Where("status IN (?)", bun.In([]string{entity.JobFailed, entity.JobCancelled})).
+		Returning("*").
+		Scan(ctx)
+
+	if isConstraintViolation(err, uniqueViolation, jobUniqueKeyConstraint) {
+		err = repository.ErrJobDuplicate
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		err = r.transitionError(ctx, id, repository.ErrJobNotRetryable)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("RetryJob %w", err)
+	}
+
+	return model.ToEntity(), nil
+}
+
+func (r *JobRepository) CancelJob(ctx context.Context, id uuid.UUID) (*entity.Job, error) {
+	now := time.Now()
+	model := new(model.Job)
+	err := r.
+		db.
+		NewUpdate().
+		Model(model).
+		Set("status = ?", entity.JobCancelled).
+		Set("finished_at = ?", now).
+		Set("updated_at = ?", now).
+		Where("id = ?", id).
+		Where("status = ?", entity.JobPending).
+		Returning("*").
+		Scan(ctx)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		err = r.transitionError(ctx, id, repository.ErrJobNotCancellable)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("CancelJob %w", err)
+	}
+
+	return model.ToEntity(), nil
+}
+
+// transitionError tells a job that doesn't exist, sql.ErrNoRows, from one
+// in the wrong status, notAllowed.
+func (r *JobRepository) transitionError(ctx context.Context, id uuid.UUID, notAllowed error) error {
+	exists, err := r.
+		db.
+		NewSelect().
+		Model((*model.Job)(nil)).
+		Where("id = ?", id).
+		Exists(ctx)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return sql.ErrNoRows
+	}
+	return notAllowed
+}
+
+func (r *JobRepository) ClaimJobs(
+	ctx context.Context, kinds []string, limit int, lease time.Duration,
+) ([]*entity.Job, error) {
+	if len(kinds) == 0 || limit <= 0 {
+		return nil, nil
+	}
+	now := time.Now()
+
+	due := r.
+		db.
+		NewSelect().
+		Model((*model.Job)(nil)).
+		Column("id").
+		Where("kind IN (?)", bun.In(kinds)).
+		WhereGroup(" AND ", func(q *bun.SelectQuery) *bun.SelectQuery {
+			return q.
+				Where("status = ? AND run_at <= ?", entity.JobPending, now).
+				WhereOr("status = ? AND locked_until <= ?", entity.JobRunning, now)
+		}).
+		Order("run_at").
+		Limit(limit).
+		For("UPDATE SKIP LOCKED")
+
+	var models []*model.Job
+	err := r.
+		db.
+		NewUpdate().
+		Model(&models).
+		Set("status = ?", entity.JobRunning).
+		Set("attempts = attempts + 1").
+		Set("locked_until = ?", now.Add(lease)).
+		Set("updated_at = ?", now).
+		Where("id IN (?)", due).
+		Returning("*").
+		Scan(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("ClaimJobs %w", err)
+	}
+
+	sort.Slice(models, func(i, j int) bool { return models[i].RunAt.Before(models[j].RunAt) })
+
+	jobs := make([]*entity.Job, 0, len(models))
+	for _, m := range models {
+		jobs = append(jobs, m.ToEntity())
+	}
+
+	return jobs, nil
+}
+
+func (r *JobRepository) CompleteJob(ctx context.Context, job *entity.Job) error {
+	now := time.Now()
+	_, err := r.
+		db.
+		NewUpdate().
+		Model((*model.Job)(nil)).
+		Set("status = ?", entity.JobSucceeded).
+		Set("last_error = NULL").
+		Set("locked_until = NULL").
+		Set("finished_at = ?", now).
+		Set("updated_at = ?", now).
+		Where("id = ?", job.ID).
+		Where("status = ?", entity.JobRunning).
+		Where("attempts = ?", job.Attempts).
+		Exec(ctx)
+
+	if err != nil {
+		return fmt.Errorf("CompleteJob %w", err)
+	}
+
+	return nil
+}
+
+func (r *JobRepository) FailJob(ctx context.Context, job *entity.Job, lastError string, retryAt *time.Time) error {
+	now := time.Now()
+	query := r.
+		db.
+		NewUpdate().
+		Model((*model.Job)(nil)).
+		Set("last_error = ?", lastError).
+		Set("locked_until = NULL").
+		Set("updated_at = ?", now).
+		Where("id = ?", job.ID).
+		Where("status = ?", entity.JobRunning).
+		Where("attempts = ?", job.Attempts)
+
+	if retryAt != nil {
+		query = query.
+			Set("status = ?", entity.JobPending).
+			Set("run_at = ?", *retryAt)
+	} else {
+		query = query.
+			Set("status = ?", entity.JobFailed).
+			Set("finished_at = ?", now)
+	}
+
+	if _, err := query.Exec(ctx); err != nil {
+		return fmt.Errorf("FailJob %w", err)
+	}
+
+	return nil
+}
+
+func (r *JobRepository) DeleteFinishedJobs(ctx context.Context, before time.Time) (int, error) {
+	result, err := r.
+		db.
+		NewDelete().
+		Model((*model.Job)(nil)).
+		Where("finished_at < ?", before).
+		Exec(ctx)
+
+	if err != nil {
+		return 0, fmt.Errorf("DeleteFinishedJobs %w", err)
+	}
+
+	deleted, _ := result.RowsAffected()
+	return int(deleted), nil
+}
@@ -0,0 +1,154 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"online-registration/internal/interview/domain/entity"
+	"online-registration/internal/interview/infrastructure/db/model"
+
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+func enqueueTestJob(t *testing.T, jobs *JobRepository, kind string, runAt time.Time) *entity.Job {
+	t.Helper()
+
+	job, _, err := jobs.EnqueueJob(context.Background(), &entity.Job{
+		Kind: kind, Payload: []byte(`{}`), MaxAttempts: 3, RunAt: runAt,
+	})
+	if err != nil {
+		t.Fatalf("EnqueueJob: %v", err)
+	}
+	return job
+}
+
+func claimTestJobs(t *testing.T, jobs *JobRepository, limit int) []*entity.Job {
+	t.Helper()
+
+	claimed, err := jobs.ClaimJobs(context.Background(), []string{"sync"}, limit, time.Minute)
+	if err != nil {
+		t.Fatalf("ClaimJobs: %v", err)
+	}
+	return claimed
+}
+
+func expireJobLease(t *testing.T, db *bun.DB, id uuid.UUID) {
+	t.Helper()
+
+	_, err := db.
+		NewUpdate().
+		Model((*model.Job)(nil)).
+		Set("locked_until = ?", time.Now().Add(-time.Second)).
+		Where("id = ?", id).
+		Exec(context.Background())
+	if err != nil {
+		t.Fatalf("expire lease: %v", err)
+	}
+}
+
+func getTestJob(t *testing.T, jobs *JobRepository, id uuid.UUID) *entity.Job {
+	t.Helper()
+
+	job, err := jobs.GetJob(context.Background(), id)
+	if err != nil {
+		t.Fatalf("GetJob: %v", err)
+	}
+	return job
+}
+
+func TestClaimJobsLeasesJobs(t *testing.T) {
+	db := openMigratedTestDB(t)
+	jobs := NewDBJobRepository(db)
+	now := time.Now()
+
+	var due []uuid.UUID
+	for i := range 3 {
+		due = append(due, enqueueTestJob(t, jobs, "sync", now.Add(time.Duration(i-3)*time.Second)).ID)
+	}
+	enqueueTestJob(t, jobs, "sync", now.Add(time.Hour))
+	enqueueTestJob(t, jobs, "purge", now.Add(-time.Hour))
+
+	var claimed []uuid.UUID
+	for _, want := range []int{2, 1, 0} {
+		batch := claimTestJobs(t, jobs, 2)
+		if len(batch) != want {
+			t.Fatalf("ClaimJobs claimed %d jobs, want %d", len(batch), want)
+		}
+		for _, job := range batch {
+			if job.Status != entity.JobRunning || job.Attempts != 1 || job.LockedUntil == nil {
+				t.Errorf("claimed job is %s after %d attempts, locked until %v", job.Status, job.Attempts, job.LockedUntil)
+			}
+			claimed = append(claimed, job.ID)
+		}
+	}
+	if fmt.Sprint(claimed) != fmt.Sprint(due) {
+		t.Errorf("claimed %v, want the due jobs %v in the order they were due", claimed, due)
+	}
+
+	// a worker that let its lease pass loses the job to the next one
+	expireJobLease(t, db, due[1])
+	batch := claimTestJobs(t, jobs, 2)
+	if len(batch) != 1 || batch[0].ID != due[1] || batch[0].Attempts != 2 {
+		t.Fatalf("ClaimJobs after the lease passed claimed %d jobs, want job %s on its second attempt", len(batch), due[1])
+	}
+}
+
+func TestStaleWorkerCantSettleJob(t *testing.T) {
+	db := openMigratedTestDB(t)
+	jobs := NewDBJobRepository(db)
+	ctx := context.Background()
+	retryAt := time.Now().Add(time.Hour)
+
+	tests := []struct {
+		name       string
+		settle     func(job *entity.Job) error
+		wantStatus string
+	}{
+		{"complete", func(job *entity.Job) error { return jobs.CompleteJob(ctx, job) }, entity.JobSucceeded},
+		{"retry", func(job *entity.Job) error { return jobs.FailJob(ctx, job, "timeout", &retryAt) }, entity.JobPending},
+		{"fail", func(job *entity.Job) error { return jobs.FailJob(ctx, job, "timeout", nil) }, entity.JobFailed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id := enqueueTestJob(t, jobs, "sync", time.Now().Add(-time.Second)).ID
+			stale := claimTestJobs(t, jobs, 1)[0]
+			expireJobLease(t, db, id)
+			current := claimTestJobs(t, jobs, 1)[0]
+
+			// the first worker settling the job late changes nothing, the
+			// job is the second one's
+			if err := tt.settle(stale); err != nil {
+				t.Fatalf("settle stale job: %v", err)
+			}
+			if job := getTestJob(t, jobs, id); job.Status != entity.JobRunning || job.Attempts != 2 || job.LastError != "" {
+				t.Fatalf("stale worker left the job %s after %d attempts with %q, want running",
+					job.Status, job.Attempts, job.LastError)
+			}
+
+			if err := tt.settle(current); err != nil {
+				t.Fatalf("settle job: %v", err)
+			}
+			job := getTestJob(t, jobs, id)
+			if job.Status != tt.wantStatus || job.LockedUntil != nil {
+				t.Errorf("job is %s, locked until %v, want %s and unlocked", job.Status, job.LockedUntil, tt.wantStatus)
+			}
+			if tt.wantStatus == entity.JobPending && job.RunAt.Sub(retryAt).Abs() > time.Millisecond {
+				t.Errorf("job runs at %s, want %s", job.RunAt, retryAt)
+			}
+			if finished := job.FinishedAt != nil; finished != (tt.wantStatus != entity.JobPending) {
+				t.Errorf("job finished at %v", job.FinishedAt)
+			}
+
+			// nor does settling it twice
+			if err := jobs.CompleteJob(ctx, stale); err != nil {
+				t.Fatalf("CompleteJob: %v", err)
+			}
+			if again := getTestJob(t, jobs, id); again.Status != tt.wantStatus {
+				t.Errorf("settled job changed to %s", again.Status)
+			}
+		})
+	}
+}
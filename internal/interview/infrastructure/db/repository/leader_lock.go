@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"hash/fnv"
+	"sync"
+
+	"github.com/uptrace/bun"
+)
+
+// AdvisoryLock is a leader lock held as a session level Postgres advisory
+// lock. The session is kept on a connection of its own for as long as the
+// lock is held, so the lock goes away with the instance or its connection.
+type AdvisoryLock struct {
+	db  *bun.DB
+	key int64
+
+	mu   sync.Mutex
+	conn *bun.Conn
+}
+
+// NewDBAdvisoryLock returns the advisory lock named name, shared by every
+// instance using the same name.
+func NewDBAdvisoryLock(db *bun.DB, name string) *AdvisoryLock {
+	hash := fnv.New64a()
+	hash.Write([]byte(name))
+
+	return &AdvisoryLock{
+		db:  db,
+		key: int64(hash.Sum64()),
+	}
+}
+
+func (l *AdvisoryLock) TryAcquire(ctx context.Context) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.conn != nil {
+		// the lock lives as long as the session holding it
+		if err := l.conn.PingContext(ctx); err != nil {
+			l.discard()
+			return false, fmt.Errorf("TryAcquire: lock connection lost: %w", err)
+		}
+		return true, nil
+	}
+
+	conn, err := l.db.Conn(ctx)
+	if err != nil {
+		return false, fmt.Errorf("TryAcquire %w", err)
+	}
+
+	var acquired bool
+	if err := conn.NewRaw("SELECT pg_try_advisory_lock(?)", l.key).Scan(ctx, &acquired); err != nil {
+		_ = conn.Close()
+		return false, fmt.Errorf("TryAcquire %w", err)
+	}
+	if !acquired {
+		_ = conn.Close()
+		return false, nil
+	}
+
+	l.conn = &conn
+	return true, nil
+}
+
+func (l *AdvisoryLock) Release(ctx context.Context) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.conn == nil {
+		return nil
+	}
+
+	var released bool
+	if err := l.conn.NewRaw("SELECT pg_advisory_unlock(?)", l.key).Scan(ctx, &released); err != nil {
+		l.discard()
+		return fmt.Errorf("Release %w", err)
+	}
+
+	err := l.conn.Close()
+	l.conn = nil
+	if err != nil {
+		return fmt.Errorf("Release %w", err)
+	}
+	return nil
+}
+
+// discard closes the connection of the lock instead of returning it to the
+// pool, where its session could go on holding the lock.
+func (l *AdvisoryLock) discard() {
+	_ = l.conn.Raw(func(any) error {
+		return driver.ErrBadConn
+	})
+	_ = l.conn.Close()
+	l.conn = nil
+}
@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"context"
+	"testing"
+)
+
+func TestAdvisoryLockIsHeldByOneInstance(t *testing.T) {
+	db := openTestDB(t)
+	ctx := context.Background()
+	first := NewDBAdvisoryLock(db, "scheduler")
+	second := NewDBAdvisoryLock(db, "scheduler")
+
+	if held, err := first.TryAcquire(ctx); err != nil || !held {
+		t.Fatalf("first TryAcquire = %v, %v, want held", held, err)
+	}
+	if held, err := second.TryAcquire(ctx); err != nil || held {
+		t.Fatalf("second TryAcquire = %v, %v, want not held", held, err)
+	}
+	// the holder checks the lock again
+	if held, err := first.TryAcquire(ctx); err != nil || !held {
+		t.Fatalf("first TryAcquire again = %v, %v, want held", held, err)
+	}
+	// locks of other names are apart
+	if held, err := NewDBAdvisoryLock(db, "reports").TryAcquire(ctx); err != nil || !held {
+		t.Errorf("TryAcquire of another name = %v, %v, want held", held, err)
+	}
+
+	if err := first.Release(ctx); err != nil {
+		t.Fatalf("Release: %v", err)
+	}
+	if held, err := second.TryAcquire(ctx); err != nil || !held {
+		t.Errorf("second TryAcquire after the release = %v, %v, want held", held, err)
+	}
+	if err := second.Release(ctx); err != nil {
+		t.Fatalf("Release: %v", err)
+	}
+	// releasing a lock not held does nothing
+	if err := second.Release(ctx); err != nil {
+		t.Errorf("Release of a released lock: %v", err)
+	}
+}
+
+func TestAdvisoryLockGoesWithItsConnection(t *testing.T) {
+	db := openTestDB(t)
+	ctx := context.Background()
+	first := NewDBAdvisoryLock(db, "scheduler")
+	second := NewDBAdvisoryLock(db, "scheduler")
+
+	if held, err := first.TryAcquire(ctx); err != nil || !held {
+		t.Fatalf("first TryAcquire = %v, %v, want held", held, err)
+	}
+
+	var pid int
+	if err := first.conn.NewRaw("SELECT pg_backend_pid()").Scan(ctx, &pid); err != nil {
+		t.Fatalf("backend pid: %v", err)
+	}
+	if _, err := db.ExecContext(ctx, "SELECT pg_terminate_backend(?)", pid); err != nil {
+		t.Fatalf("terminate backend: %v", err)
+	}
+
+	if held, err := first.TryAcquire(ctx); err == nil || held {
+		t.Errorf("TryAcquire after the connection was lost = %v, %v, want an error", held, err)
+	}
+	if held, err := second.TryAcquire(ctx); err != nil || !held {
+		t.Errorf("second TryAcquire after the holder was lost = %v, %v, want held", held, err)
+	}
+	second.Release(ctx)
+}
@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"context"
+	"database/sql/driver"
+
+	"github.com/jackc/pgx/v5/stdlib"
+	"github.com/uptrace/bun"
+)
+
+// listen listens on a Postgres notification channel on a connection of its
+// own. It calls listening once it listens, then notify with the payload of
+// every notification, until ctx is cancelled or the connection fails.
+func listen(
+	ctx context.Context, db *bun.DB, channel string, listening func(), notify func(payload string),
+) error {
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	var listenErr error
+	// the connection is closed instead of going back to the pool, where its
+	// session would go on listening
+	_ = conn.Raw(func(driverConn any) error {
+		pgxConn := driverConn.(*stdlib.Conn).Conn()
+
+		if _, err := pgxConn.Exec(ctx, "LISTEN "+channel); err != nil {
+			listenErr = err
+			return driver.ErrBadConn
+		}
+		listening()
+
+		for {
+			notification, err := pgxConn.WaitForNotification(ctx)
+			if err != nil {
+				listenErr = err
+				return driver.ErrBadConn
+			}
+			notify(notification.Payload)
+		}
+	})
+
+	return listenErr
+}
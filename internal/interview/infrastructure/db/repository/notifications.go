@@ -0,0 +1,201 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"online-registration/internal/interview/domain/entity"
+	"online-registration/internal/interview/infrastructure/db/model"
+
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+type NotificationRepository struct {
+	db *bun.DB
+}
+
+func NewDBNotificationRepository(db *bun.DB) *NotificationRepository {
+	return &NotificationRepository{
+		db: db,
+	}
+}
+
+func (r *NotificationRepository) EnqueueNotification(
+	ctx context.Context, notification *entity.Notification,
+) (bool, error) {
+	model := &model.Notification{
+		ID:             uuid.New(),
+		TenantID:       notification.TenantID,
+		Kind:           notification.Kind,
+		RegistrationID: notification.RegistrationID,
+		EventID:        notification.EventID,
+		Recipient:      notification.Recipient,
+		DedupeKey:      notification.DedupeKey,
+		Status:         entity.NotificationPending,
+		SendAt:         notification.SendAt,
+	}
+
+	result, err := r.
+		db.
+		NewInsert().
+		Model(model).
+		On("CONFLICT (dedupe_key) DO NOTHING").
+		Exec(ctx)
+
+	if err != nil {
+		return false, fmt.Errorf("EnqueueNotification %w", err)
+	}
+
+	enqueued, _ := result.RowsAffected()
+	return enqueued > 0, nil
+}
+
+func (r *NotificationRepository) CancelNotifications(
+	ctx context.Context, registrationID uuid.UUID, kind string,
+) (int, error) {
+	result, err := r.
+		db.
+		NewUpdate().
+		Model((*model.Notification)(nil)).
+		Set("status = ?", entity.NotificationCancelled).
+		Where("registration_id = ?", registrationID).
+		Where("kind = ?", kind).
+		Where("status = ?", entity.NotificationPending).
+		Exec(ctx)
+
+	if err != nil {
+		return 0, fmt.Errorf("CancelNotifications %w", err)
+	}
+
+	cancelled, _ := result.RowsAffected()
+	return int(cancelled), nil
+}
+
+func (r *NotificationRepository) RescheduleReminders(
+	ctx context.Context, eventID uuid.UUID, sendAt time.Time,
+) (int, error) {
+	result, err := r.
+		db.
+		NewUpdate().
+		Model((*model.Notification)(nil)).
+		Set("send_at = ?", sendAt).
+		Where("event_id = ?", eventID).
+		Where("kind = ?", entity.NotificationReminder).
+		Where("status = ?", entity.NotificationPending).
+		Where("send_at <> ?", sendAt).
+		Exec(ctx)
+
+	if err != nil {
+		return 0, fmt.Errorf("RescheduleReminders %w", err)
+	}
+
+	rescheduled, _ := result.RowsAffected()
+	return int(rescheduled), nil
+}
+
+func (r *NotificationRepository) ClaimNotifications(
+	ctx context.Context, limit int, lease time.Duration,
+) ([]*entity.Notification, error) {
+	now := time.Now()
+
+	due := r.
+		db.
+		NewSelect().
+		Model((*model.Notification)(nil)).
+		Column("id").
+		Where("status = ?", entity.NotificationPending).
+		Where("send_at <= ?", now).
+		Order("send_at").
+		Limit(limit).
+		For("UPDATE SKIP LOCKED")
+	if err := model.ScopeToTenant(ctx, due); err != nil {
+		return nil, fmt.Errorf("ClaimNotifications %w", err)
+	}
+
+	var models []*model.Notification
+	err := r.
+		db.
+		NewUpdate().
+		Model(&models).
+		Set("send_at = ?", now.Add(lease)).
+		Where("id IN (?)", due).
+		Returning("*").
+		Scan(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("ClaimNotifications %w", err)
+	}
+
+	sort.Slice(models, func(i, j int) bool { return models[i].CreatedAt.Before(models[j].CreatedAt) })
+
+	notifications := make([]*entity.Notification, 0, len(models))
+	for _, m := range models {
+		notifications = append(notifications, m.ToEntity())
+	}
+
+	return notifications, nil
+}
+
+func (r *NotificationRepository) MarkNotificationSent(ctx context.Context, id uuid.UUID) error {
+	_, err := r.
+		db.
+		NewUpdate().
+		Model((*model.Notification)(nil)).
+		Set("status = ?", entity.NotificationSent).
+		Set("attempts = attempts + 1").
+		Set("last_error = NULL").
+		Set("sent_at = ?", time.Now()).
+		Where("id = ?", id).
+		Exec(ctx)
+
+	if err != nil {
+		return fmt.Errorf("MarkNotificationSent %w", err)
+	}
+
+	return nil
+}
+
+func (r *NotificationRepository) MarkNotificationFailed(
+	ctx context.Context, id uuid.UUID, lastError string, nextAttemptAt *time.Time,
+) error {
+	query := r.
+		db.
+		NewUpdate().
+		Model((*model.Notification)(nil)).
+		Set("attempts = attempts + 1").
+		Set("last_error = ?", lastError).
+		Where("id = ?", id)
+
+	if nextAttemptAt != nil {
+		query = query.Set("send_at = ?", *nextAttemptAt)
+	} else {
+		query = query.Set("status = ?", entity.NotificationFailed)
+	}
+
+	if _, err := query.Exec(ctx); err != nil {
+		return fmt.Errorf("MarkNotificationFailed %w", err)
+	}
+
+	return nil
+}
+
+func (r *NotificationRepository) MarkNotificationCancelled(ctx context.Context, id uuid.UUID, reason string) error {
+	_, err := r.
+		db.
+		NewUpdate().
+		Model((*model.Notification)(nil)).
+		Set("status = ?", entity.NotificationCancelled).
+		Set("last_error = ?", nullString(reason)).
+		Where("id = ?", id).
+		Where("status = ?", entity.NotificationPending).
+		Exec(ctx)
+
+	if err != nil {
+		return fmt.Errorf("MarkNotificationCancelled %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"online-registration/internal/interview/domain/entity"
+	"online-registration/internal/interview/domain/tenant"
+	"online-registration/internal/interview/infrastructure/db/model"
+
+	"github.com/google/uuid"
+)
+
+func TestNotificationClaimLeasesNotifications(t *testing.T) {
+	db := openTestDB(t)
+	ctx := tenant.ContextWithAllTenants(context.Background())
+	if _, err := db.NewCreateTable().Model((*model.Notification)(nil)).Exec(ctx); err != nil {
+		t.Fatalf("create notifications: %v", err)
+	}
+	if _, err := db.ExecContext(ctx, `CREATE UNIQUE INDEX ON "notifications" ("dedupe_key")`); err != nil {
+		t.Fatalf("create index: %v", err)
+	}
+	repository := NewDBNotificationRepository(db)
+
+	enqueue := func(dedupeKey string, sendAt time.Time) {
+		t.Helper()
+		_, err := repository.EnqueueNotification(ctx, &entity.Notification{
+			TenantID:       "acme",
+			Kind:           entity.NotificationConfirmation,
+			RegistrationID: uuid.New(),
+			EventID:        uuid.New(),
+			Recipient:      "alice@example.com",
+			DedupeKey:      dedupeKey,
+			SendAt:         sendAt,
+		})
+		if err != nil {
+			t.Fatalf("EnqueueNotification: %v", err)
+		}
+	}
+	enqueue("due", time.Now().Add(-time.Minute))
+	enqueue("later", time.Now().Add(time.Hour))
+
+	claimed, err := repository.ClaimNotifications(ctx, 10, time.Minute)
+	if err != nil {
+		t.Fatalf("ClaimNotifications: %v", err)
+	}
+	if len(claimed) != 1 || claimed[0].DedupeKey != "due" {
+		t.Fatalf("ClaimNotifications returned %d notifications, want the due one", len(claimed))
+	}
+
+	// leased until the worker holding it is presumed dead
+	if again, err := repository.ClaimNotifications(ctx, 10, time.Minute); err != nil || len(again) != 0 {
+		t.Fatalf("ClaimNotifications during the lease = %d, %v, want none", len(again), err)
+	}
+
+	retryAt := time.Now().Add(-time.Second)
+	if err := repository.MarkNotificationFailed(ctx, claimed[0].ID, "unavailable", &retryAt); err != nil {
+		t.Fatalf("MarkNotificationFailed: %v", err)
+	}
+	retried, err := repository.ClaimNotifications(ctx, 10, time.Minute)
+	if err != nil {
+		t.Fatalf("ClaimNotifications: %v", err)
+	}
+	if len(retried) != 1 || retried[0].Attempts != 1 || retried[0].LastError != "unavailable" {
+		t.Fatalf("ClaimNotifications of the retry returned %+v", retried)
+	}
+
+	if err := repository.MarkNotificationSent(ctx, retried[0].ID); err != nil {
+		t.Fatalf("MarkNotificationSent: %v", err)
+	}
+	if _, err := db.NewUpdate().
+		Model((*model.Notification)(nil)).
+		Set("send_at = ?", time.Now().Add(-time.Second)).
+		Where("id = ?", retried[0].ID).
+		Exec(ctx); err != nil {
+		t.Fatalf("expire lease: %v", err)
+	}
+	if sent, err := repository.ClaimNotifications(ctx, 10, time.Minute); err != nil || len(sent) != 0 {
+		t.Errorf("ClaimNotifications after the notification was sent = %d, %v, want none", len(sent), err)
+	}
+}
@@ -0,0 +1,195 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"online-registration/internal/interview/domain/dto"
+	"online-registration/internal/interview/domain/entity"
+	"online-registration/internal/interview/infrastructure/db/model"
+
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+type OutboxRepository struct {
+	db *bun.DB
+}
+
+func NewDBOutboxRepository(db *bun.DB) *OutboxRepository {
+	return &OutboxRepository{
+		db: db,
+	}
+}
+
+func (r *OutboxRepository) Claim(
+	ctx context.Context, limit int, lease time.Duration,
+) ([]*entity.OutboxMessage, error) {
+	now := time.Now()
+
+	pending := r.
+		db.
+		NewSelect().
+		Model((*model.OutboxMessage)(nil)).
+		Column("id").
+		Where("published_at IS NULL").
+		Where("next_attempt_at <= ?", now).
+		Order("id").
+		Limit(limit).
+		For("UPDATE SKIP LOCKED")
+
+	var models []*model.OutboxMessage
+	err := r.
+		db.
+		NewUpdate().
+		Model(&models).
+		Set("next_attempt_at = ?", now.Add(lease)).
+		Where("id IN (?)", pending).
+		Returning("*").
+		Scan(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("ClaimOutboxMessages %w", err)
+	}
+
+	sort.Slice(models, func(i, j int) bool { return models[i].ID < models[j].ID })
+
+	messages := make([]*entity.OutboxMessage, 0, len(models))
+	for _, m := range models {
+		messages = append(messages, m.ToEntity())
+	}
+
+	return messages, nil
+}
+
+func (r *OutboxRepository) MarkPublished(ctx context.Context, ids []int64) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	_, err := r.
+		db.
+		NewUpdate().
+		Model((*model.OutboxMessage)(nil)).
+		Set("published_at = ?", time.Now()).
+		Set("attempts = attempts + 1").
+		Set("last_error = NULL").
+		Where("id IN (?)", bun.In(ids)).
+		Exec(ctx)
+
+	if err != nil {
+		return fmt.Errorf("MarkOutboxMessagesPublished %w", err)
+	}
+
+	return nil
+}
+
+func (r *OutboxRepository) MarkFailed(
+	ctx context.Context, id int64, lastError string, nextAttemptAt time.Time,
+) error {
+	_, err := r.
+		db.
+		NewUpdate().
+		Model((*model.OutboxMessage)(nil)).
+		Set("attempts = attempts + 1").
+		Set("last_error = ?", lastError).
+		Set("next_attempt_at = ?", nextAttemptAt).
+		Where("id = ?", id).
+		Exec(ctx)
+
+	if err != nil {
+		return fmt.Errorf("MarkOutboxMessageFailed %w", err)
+	}
+
+	return nil
+}
+
+func (r *OutboxRepository) DeletePublished(ctx context.Context, before time.Time) (int, error) {
+	result, err := r.
+		db.
+		NewDelete().
+		Model((*model.OutboxMessage)(nil)).
+		Where("published_at < ?", before).
+		Exec(ctx)
+
+	if err != nil {
+		return 0, fmt.Errorf("DeletePublishedOutboxMessages %w", err)
+	}
+
+	deleted, _ := result.RowsAffected()
+	return int(deleted), nil
+}
+
+// insertEventMessage writes a lifecycle message for event to the outbox. It
+// must be called with the transaction that changes the events row.
+func insertEventMessage(ctx context.Context, db bun.IDB, subject string, event *entity.Event) error {
+	messageID := uuid.New()
+	now := time.Now()
+
+	payload, err := json.Marshal(dto.EventMessage{
+		ID:         messageID,
+		Type:       subject,
+		OccurredAt: now,
+		Event:      dto.NewEventPayload(event),
+	})
+	if err != nil {
+		return fmt.Errorf("marshal event message: %w", err)
+	}
+
+	return insertOutboxMessage(ctx, db, messageID, subject, payload, now)
+}
+
+// insertRegistrationMessage writes a registration message to the outbox. It
+// must be called with the transaction that changes the registrations or the
+// events row.
+func insertRegistrationMessage(
+	ctx context.Context, db bun.IDB, subject string, registration *entity.Registration, event *entity.Event,
+) error {
+	messageID := uuid.New()
+	now := time.Now()
+
+	payload, err := json.Marshal(dto.RegistrationMessage{
+		ID:         messageID,
+		Type:       subject,
+		OccurredAt: now,
+		Registration: dto.RegistrationPayload{
+			ID:         registration.ID,
+			TenantID:   registration.TenantID,
+			EventID:    registration.EventID,
+			UserID:     registration.UserID,
+			Email:      registration.Email,
+			Status:     registration.Status,
+			Onboarding: event.Onboarding,
+			Reason:     event.CancellationReason,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("marshal registration message: %w", err)
+	}
+
+	return insertOutboxMessage(ctx, db, messageID, subject, payload, now)
+}
+
+func insertOutboxMessage(
+	ctx context.Context, db bun.IDB, messageID uuid.UUID, subject string, payload []byte, now time.Time,
+) error {
+	_, err := db.
+		NewInsert().
+		Model(&model.OutboxMessage{
+			MessageID:     messageID,
+			Subject:       subject,
+			Payload:       payload,
+			CreatedAt:     now,
+			NextAttemptAt: now,
+		}).
+		Exec(ctx)
+
+	if err != nil {
+		return fmt.Errorf("insert outbox message: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,170 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"online-registration/cmd/migrations"
+	"online-registration/internal/interview/domain/tenant"
+	"online-registration/internal/interview/infrastructure/db/model"
+	"online-registration/internal/interview/infrastructure/db/tenantconn"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/stdlib"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/pgdialect"
+	"github.com/uptrace/bun/migrate"
+)
+
+// openTestDB connects to the database of TEST_DATABASE_URL, in a schema of
+// its own that is dropped after the test. The test is skipped without one.
+func openTestDB(t *testing.T) *bun.DB {
+	t.Helper()
+
+	config := createTestSchema(t)
+	db := bun.NewDB(stdlib.OpenDB(*config), pgdialect.New())
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+// openMigratedTestDB is openTestDB with the migrations run, and the tenant of
+// the context of each query set like the service does.
+func openMigratedTestDB(t *testing.T) *bun.DB {
+	t.Helper()
+
+	config := createTestSchema(t)
+	db := bun.NewDB(sql.OpenDB(tenantconn.NewConnector(stdlib.GetConnector(*config))), pgdialect.New())
+	t.Cleanup(func() { db.Close() })
+
+	ctx := tenant.ContextWithAllTenants(context.Background())
+	migrator := migrate.NewMigrator(db, migrations.Migrations)
+	if err := migrator.Init(ctx); err != nil {
+		t.Fatalf("init migrations: %v", err)
+	}
+	if _, err := migrator.Migrate(ctx); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	return db
+}
+
+// createTestSchema creates the schema of a test and returns the config of
+// connections using it.
+func createTestSchema(t *testing.T) *pgx.ConnConfig {
+	t.Helper()
+
+	connString := os.Getenv("TEST_DATABASE_URL")
+	if connString == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+
+	config, err := pgx.ParseConfig(connString)
+	if err != nil {
+		t.Fatalf("parse TEST_DATABASE_URL: %v", err)
+	}
+	admin := stdlib.OpenDB(*config)
+	defer admin.Close()
+
+	// extensions are shared by the database, they would go with the schema
+	// of the test creating them otherwise
+	if _, err := admin.Exec(`CREATE EXTENSION IF NOT EXISTS "btree_gist" SCHEMA public`); err != nil {
+		t.Fatalf("create extension: %v", err)
+	}
+
+	schema := fmt.Sprintf("test_%d", time.Now().UnixNano())
+	if _, err := admin.Exec(`CREATE SCHEMA "` + schema + `"`); err != nil {
+		t.Fatalf("create schema: %v", err)
+	}
+	t.Cleanup(func() {
+		admin := stdlib.OpenDB(*config)
+		defer admin.Close()
+		if _, err := admin.Exec(`DROP SCHEMA "` + schema + `" CASCADE`); err != nil {
+			t.Errorf("drop schema: %v", err)
+		}
+	})
+
+	config.RuntimeParams["search_path"] = schema + ", public"
+	return config
+}
+
+func insertTestOutboxMessages(t *testing.T, db *bun.DB, count int) {
+	t.Helper()
+
+	ctx := context.Background()
+	if _, err := db.NewCreateTable().Model((*model.OutboxMessage)(nil)).Exec(ctx); err != nil {
+		t.Fatalf("create outbox: %v", err)
+	}
+	for range count {
+		if err := insertOutboxMessage(ctx, db, uuid.New(), "events.created", []byte(`{}`), time.Now()); err != nil {
+			t.Fatalf("insertOutboxMessage: %v", err)
+		}
+	}
+}
+
+func TestOutboxClaimLeasesMessages(t *testing.T) {
+	db := openTestDB(t)
+	insertTestOutboxMessages(t, db, 3)
+	repository := NewDBOutboxRepository(db)
+	ctx := context.Background()
+
+	for _, want := range [][]int64{{1, 2}, {3}, nil} {
+		messages, err := repository.Claim(ctx, 2, time.Minute)
+		if err != nil {
+			t.Fatalf("Claim: %v", err)
+		}
+		var ids []int64
+		for _, message := range messages {
+			ids = append(ids, message.ID)
+		}
+		if fmt.Sprint(ids) != fmt.Sprint(want) {
+			t.Errorf("Claim returned messages %v, want %v", ids, want)
+		}
+	}
+
+	// an expired lease hands the message out again
+	if _, err := db.NewUpdate().
+		Model((*model.OutboxMessage)(nil)).
+		Set("next_attempt_at = ?", time.Now().Add(-time.Second)).
+		Where("id = 2").
+		Exec(ctx); err != nil {
+		t.Fatalf("expire lease: %v", err)
+	}
+	messages, err := repository.Claim(ctx, 10, time.Minute)
+	if err != nil {
+		t.Fatalf("Claim: %v", err)
+	}
+	if len(messages) != 1 || messages[0].ID != 2 {
+		t.Errorf("Claim after the lease expired returned %d messages, want message 2", len(messages))
+	}
+}
+
+func TestOutboxClaimSkipsLockedMessages(t *testing.T) {
+	db := openTestDB(t)
+	insertTestOutboxMessages(t, db, 3)
+	repository := NewDBOutboxRepository(db)
+	ctx := context.Background()
+
+	// another relay is in the middle of claiming message 1
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		t.Fatalf("begin: %v", err)
+	}
+	defer tx.Rollback()
+	if _, err := tx.ExecContext(ctx, `SELECT id FROM outbox WHERE id = 1 FOR UPDATE`); err != nil {
+		t.Fatalf("lock message: %v", err)
+	}
+
+	claimCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	messages, err := repository.Claim(claimCtx, 10, time.Minute)
+	if err != nil {
+		t.Fatalf("Claim blocked on the locked message: %v", err)
+	}
+	if len(messages) != 2 || messages[0].ID != 2 || messages[1].ID != 3 {
+		t.Errorf("Claim returned %d messages, want messages 2 and 3", len(messages))
+	}
+}
@@ -0,0 +1,191 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"online-registration/internal/interview/domain/entity"
+	"online-registration/internal/interview/domain/repository"
+	"online-registration/internal/interview/infrastructure/db/model"
+
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+type ParticipantRepository struct {
+	db *bun.DB
+}
+
+func NewDBParticipantRepository(db *bun.DB) *ParticipantRepository {
+	return &ParticipantRepository{
+		db: db,
+	}
+}
+
+func (r *ParticipantRepository) SetParticipant(
+	ctx context.Context, eventID uuid.UUID, userID string, role string,
+) (*entity.Participant, error) {
+	model := &model.Participant{
+		EventID: eventID,
+		UserID:  userID,
+		Role:    role,
+	}
+
+	err := r.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		event, err := lockEvent(ctx, tx, eventID)
+		if err != nil {
+			return err
+		}
+		if !event.Editable() {
+			return repository.ErrEventNotEditable
+		}
+
+		_, err = tx.
+			NewInsert().
+			Model(model).
+			On("CONFLICT (event_id, user_id) DO UPDATE").
+			Set("role = EXCLUDED.role").
+			Returning("*").
+			Exec(ctx)
+		return err
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("SetParticipant %w", err)
+	}
+
+	return model.ToEntity(), nil
+}
+
+func (r *ParticipantRepository) RemoveParticipant(ctx context.Context, eventID uuid.UUID, userID string) error {
+	err := r.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		event, err := lockEvent(ctx, tx, eventID)
+		if err != nil {
+			return err
+		}
+		if !event.Editable() {
+			return repository.ErrEventNotEditable
+		}
+
+		result, err := tx.
+			NewDelete().
+			Model((*model.Participant)(nil)).
+			Where("event_id = ?", eventID).
+			Where("user_id = ?", userID).
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+
+		return ensureAffected(result, "RemoveParticipant")
+	})
+
+	if err != nil {
+		return fmt.Errorf("RemoveParticipant %w", err)
+	}
+
+	return nil
+}
+
+func (r *ParticipantRepository) ListParticipants(
+	ctx context.Context, eventID uuid.UUID,
+) ([]*entity.Participant, error) {
+	var models []*model.Participant
+	err := r.
+		db.
+		NewSelect().
+		Model(&models).
+		Where("event_id = ?", eventID).
+		Order("created_at", "user_id").
+		Scan(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("ListParticipants %w", err)
+	}
+
+	participants := make([]*entity.Participant, 0, len(models))
+	for _, m := range models {
+		participants = append(participants, m.ToEntity())
+	}
+
+	return participants, nil
+}
+
+type busyRow struct {
+	UserID    string        `bun:"user_id"`
+	RoomID    uuid.NullUUID `bun:"room_id"`
+	StartTime time.Time     `bun:"start_time"`
+	EndTime   time.Time     `bun:"end_time"`
+}
+
+func (r *ParticipantRepository) ListBusy(
+	ctx context.Context, userIDs []string, roomIDs []uuid.UUID, start, end time.Time,
+) ([]*entity.BusyPeriod, error) {
+	var rows []busyRow
+
+	if len(userIDs) > 0 {
+		var participating []busyRow
+		err := r.
+			busyEvents(start, end).
+			Join(`JOIN "event_participants" AS "ep" ON "ep"."event_id" = "s"."id"`).
+			ColumnExpr(`"ep"."user_id"`).
+			Where(`"ep"."user_id" IN (?)`, bun.In(userIDs)).
+			Scan(ctx, &participating)
+		if err != nil {
+			return nil, fmt.Errorf("ListBusy %w", err)
+		}
+
+		var registered []busyRow
+		err = r.
+			busyEvents(start, end).
+			Join(`JOIN "registrations" AS "rg" ON "rg"."event_id" = "s"."id"`).
+			ColumnExpr(`"rg"."user_id"`).
+			Where(`"rg"."user_id" IN (?)`, bun.In(userIDs)).
+			Where(`"rg"."status" = ?`, entity.RegistrationRegistered).
+			Scan(ctx, &registered)
+		if err != nil {
+			return nil, fmt.Errorf("ListBusy %w", err)
+		}
+
+		rows = append(append(rows, participating...), registered...)
+	}
+
+	if len(roomIDs) > 0 {
+		var booked []busyRow
+		err := r.
+			busyEvents(start, end).
+			ColumnExpr(`"s"."room_id"`).
+			Where(`"s"."room_id" IN (?)`, bun.In(roomIDs)).
+			Scan(ctx, &booked)
+		if err != nil {
+			return nil, fmt.Errorf("ListBusy %w", err)
+		}
+
+		rows = append(rows, booked...)
+	}
+
+	periods := make([]*entity.BusyPeriod, 0, len(rows))
+	for _, row := range rows {
+		periods = append(periods, &entity.BusyPeriod{
+			UserID: row.UserID,
+			RoomID: row.RoomID.UUID,
+			Period: entity.Period{Start: row.StartTime, End: row.EndTime},
+		})
+	}
+
+	return periods, nil
+}
+
+// busyEvents selects the times of the events that overlap the window
+// between start and end and keep people and rooms busy.
+func (r *ParticipantRepository) busyEvents(start, end time.Time) *bun.SelectQuery {
+	return r.
+		db.
+		NewSelect().
+		Model((*model.Event)(nil)).
+		Column("start_time", "end_time").
+		Where(`"s"."status" <> ?`, entity.EventCancelled).
+		Where(`"s"."start_time" < ?`, end).
+		Where(`"s"."end_time" > ?`, start)
+}
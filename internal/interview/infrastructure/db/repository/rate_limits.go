@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"online-registration/internal/interview/domain/entity"
+
+	"github.com/uptrace/bun"
+)
+
+// refilledTokens is the content of an existing bucket once it is refilled for
+// the time elapsed since its last update. The database clock is used to avoid
+// skew between instances.
+const refilledTokens = `LEAST(?1, "b"."tokens" + EXTRACT(EPOCH FROM now() - "b"."updated_at")::float8 * ?2)`
+
+// takeTokenQuery refills the bucket and takes a token in a single statement,
+// so concurrent requests of all instances see a consistent bucket.
+const takeTokenQuery = `
+	INSERT INTO "rate_limit_buckets" AS "b" ("key", "tokens", "allowed", "updated_at")
+	VALUES (?0, ?1 - 1, TRUE, now())
+	ON CONFLICT ("key") DO UPDATE SET
+		"tokens" = CASE WHEN ` + refilledTokens + ` >= 1 THEN ` + refilledTokens + ` - 1 ELSE ` + refilledTokens + ` END,
+		"allowed" = ` + refilledTokens + ` >= 1,
+		"updated_at" = now()
+	RETURNING "b"."tokens", "b"."allowed"
+`
+
+type RateLimitRepository struct {
+	db *bun.DB
+}
+
+func NewDBRateLimitRepository(db *bun.DB) *RateLimitRepository {
+	return &RateLimitRepository{
+		db: db,
+	}
+}
+
+func (r *RateLimitRepository) Take(
+	ctx context.Context, key string, limit entity.RateLimit,
+) (*entity.RateLimitBucket, error) {
+	bucket := &entity.RateLimitBucket{}
+	err := r.
+		db.
+		NewRaw(takeTokenQuery, key, float64(limit.Requests), limit.TokensPerSecond()).
+		Scan(ctx, &bucket.Tokens, &bucket.Allowed)
+
+	if err != nil {
+		return nil, fmt.Errorf("TakeRateLimitToken %w", err)
+	}
+
+	return bucket, nil
+}
+
+// DeleteIdleBuckets deletes the buckets last updated before before. A
+// bucket idle for longer than the period of its limit is full, and a
+// missing bucket starts full, so they can go.
+func (r *RateLimitRepository) DeleteIdleBuckets(ctx context.Context, before time.Time) (int, error) {
+	result, err := r.
+		db.
+		NewDelete().
+		TableExpr(`"rate_limit_buckets"`).
+		Where(`"updated_at" < ?`, before).
+		Exec(ctx)
+
+	if err != nil {
+		return 0, fmt.Errorf("DeleteIdleRateLimitBuckets %w", err)
+	}
+
+	deleted, _ := result.RowsAffected()
+	return int(deleted), nil
+}
@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"online-registration/internal/interview/domain/entity"
+)
+
+func createRateLimitBuckets(t *testing.T, ctx context.Context, repository *RateLimitRepository) {
+	t.Helper()
+
+	_, err := repository.db.ExecContext(ctx, `
+		CREATE TABLE "rate_limit_buckets" (
+			"key" TEXT NOT NULL PRIMARY KEY,
+			"tokens" DOUBLE PRECISION NOT NULL,
+			"allowed" BOOLEAN NOT NULL,
+			"updated_at" TIMESTAMPTZ NOT NULL DEFAULT current_timestamp
+		)
+	`)
+	if err != nil {
+		t.Fatalf("create rate_limit_buckets: %v", err)
+	}
+}
+
+func TestRateLimitTake(t *testing.T) {
+	db := openTestDB(t)
+	ctx := context.Background()
+	repository := NewDBRateLimitRepository(db)
+	createRateLimitBuckets(t, ctx, repository)
+	limit := entity.RateLimit{Requests: 2, Period: time.Hour}
+
+	for i, want := range []bool{true, true, false} {
+		bucket, err := repository.Take(ctx, "ip:192.0.2.1", limit)
+		if err != nil {
+			t.Fatalf("Take: %v", err)
+		}
+		if bucket.Allowed != want {
+			t.Errorf("request %d allowed = %v, want %v", i+1, bucket.Allowed, want)
+		}
+	}
+
+	// every key has a bucket of its own
+	if bucket, err := repository.Take(ctx, "ip:192.0.2.2", limit); err != nil || !bucket.Allowed {
+		t.Errorf("Take of another key = %+v, %v, want allowed", bucket, err)
+	}
+}
+
+func TestRateLimitDeleteIdleBuckets(t *testing.T) {
+	db := openTestDB(t)
+	ctx := context.Background()
+	repository := NewDBRateLimitRepository(db)
+	createRateLimitBuckets(t, ctx, repository)
+	limit := entity.RateLimit{Requests: 1, Period: time.Minute}
+
+	for _, key := range []string{"ip:192.0.2.1", "ip:192.0.2.2"} {
+		if _, err := repository.Take(ctx, key, limit); err != nil {
+			t.Fatalf("Take: %v", err)
+		}
+	}
+	if _, err := db.ExecContext(ctx,
+		`UPDATE "rate_limit_buckets" SET "updated_at" = now() - interval '1 hour' WHERE "key" = 'ip:192.0.2.1'`,
+	); err != nil {
+		t.Fatalf("age bucket: %v", err)
+	}
+
+	deleted, err := repository.DeleteIdleBuckets(ctx, time.Now().Add(-limit.Period))
+	if err != nil {
+		t.Fatalf("DeleteIdleBuckets: %v", err)
+	}
+	if deleted != 1 {
+		t.Errorf("DeleteIdleBuckets deleted %d buckets, want the idle one", deleted)
+	}
+
+	// the purged bucket starts full, the other one is still empty
+	if bucket, err := repository.Take(ctx, "ip:192.0.2.1", limit); err != nil || !bucket.Allowed {
+		t.Errorf("Take of the purged key = %+v, %v, want allowed", bucket, err)
+	}
+	if bucket, err := repository.Take(ctx, "ip:192.0.2.2", limit); err != nil || bucket.Allowed {
+		t.Errorf("Take of the busy key = %+v, %v, want denied", bucket, err)
+	}
+}
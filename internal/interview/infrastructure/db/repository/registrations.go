@@ -0,0 +1,270 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"online-registration/internal/interview/domain/dto"
+	"online-registration/internal/interview/domain/entity"
+	"online-registration/internal/interview/domain/repository"
+	"online-registration/internal/interview/infrastructure/db/model"
+
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+type RegistrationRepository struct {
+	db *bun.DB
+}
+
+func NewDBRegistrationRepository(db *bun.DB) *RegistrationRepository {
+	return &RegistrationRepository{
+		db: db,
+	}
+}
+
+func (r *RegistrationRepository) Register(
+	ctx context.Context, eventID uuid.UUID, userID, email string,
+) (*entity.Registration, error) {
+	registration := &model.Registration{
+		ID:        uuid.New(),
+		EventID:   eventID,
+		UserID:    userID,
+		Email:     email,
+		Status:    entity.RegistrationRegistered,
+		CreatedAt: time.Now(),
+	}
+
+	err := r.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		// lock the event so it can't be deleted while the registration is
+		// being added, and so that the registrations and cancellations of
+		// the event count its seats one at a time
+		event := new(model.Event)
+		err := tx.
+			NewSelect().
+			Model(event).
+			Where("id = ?", eventID).
+			For("NO KEY UPDATE").
+			Scan(ctx)
+		if err != nil {
+			return err
+		}
+		if event.Status != entity.EventPublished {
+			return repository.ErrEventNotOpen
+		}
+		registration.TenantID = event.TenantID
+
+		free, limited, err := freeSeats(ctx, tx, event)
+		if err != nil {
+			return err
+		}
+		subject := dto.RegistrationCreatedSubject
+		if limited && free <= 0 {
+			registration.Status = entity.RegistrationWaitlisted
+			subject = dto.RegistrationWaitlistedSubject
+		}
+
+		result, err := tx.
+			NewInsert().
+			Model(registration).
+			On("CONFLICT (event_id, user_id) WHERE status IN (?) DO NOTHING", bun.In(entity.ActiveRegistrationStatuses)).
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+		if affected, _ := result.RowsAffected(); affected == 0 {
+			return repository.ErrAlreadyRegistered
+		}
+
+		return insertRegistrationMessage(ctx, tx, subject, registration.ToEntity(), event.ToEntity())
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("Register %w", err)
+	}
+
+	return registration.ToEntity(), nil
+}
+
+func (r *RegistrationRepository) CancelRegistration(
+	ctx context.Context, eventID uuid.UUID, userID string,
+) (*entity.Registration, error) {
+	registration := new(model.Registration)
+
+	err := r.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		// registrations of deleted events can still be cancelled
+		event := new(model.Event)
+		err := tx.
+			NewSelect().
+			Model(event).
+			WhereAllWithDeleted().
+			Where("id = ?", eventID).
+			For("NO KEY UPDATE").
+			Scan(ctx)
+		if err != nil {
+			return err
+		}
+
+		err = tx.
+			NewSelect().
+			Model(registration).
+			Where("event_id = ?", eventID).
+			Where("user_id = ?", userID).
+			Where("status IN (?)", bun.In(entity.ActiveRegistrationStatuses)).
+			For("UPDATE").
+			Scan(ctx)
+		if err != nil {
+			return err
+		}
+		// only a registered user frees a seat, a waitlisted one leaves the
+		// waitlist
+		freed := registration.Status == entity.RegistrationRegistered
+
+		registration.Status = entity.RegistrationCancelled
+		registration.CancelledAt = time.Now()
+		_, err = tx.
+			NewUpdate().
+			Model(registration).
+			Column("status", "cancelled_at").
+			WherePK().
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+
+		err = insertRegistrationMessage(
+			ctx, tx, dto.RegistrationCancelledSubject, registration.ToEntity(), event.ToEntity(),
+		)
+		if err != nil {
+			return err
+		}
+
+		if !freed {
+			return nil
+		}
+		return promoteWaitlist(ctx, tx, event)
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("CancelRegistration %w", err)
+	}
+
+	return registration.ToEntity(), nil
+}
+
+func (r *RegistrationRepository) HasOnboardingRegistration(ctx context.Context, userID string) (bool, error) {
+	query := r.
+		db.
+		NewSelect().
+		Model((*model.Registration)(nil)).
+		Join(`JOIN "events" AS "s" ON "s"."id" = "rg"."event_id"`).
+		Where("rg.user_id = ?", userID).
+		Where("rg.status = ?", entity.RegistrationRegistered).
+		Where("s.onboarding").
+		Where("s.status <> ?", entity.EventCancelled).
+		Where("s.deleted_at IS NULL")
+	if err := model.ScopeToTenant(ctx, query); err != nil {
+		return false, fmt.Errorf("HasOnboardingRegistration %w", err)
+	}
+
+	exists, err := query.Exists(ctx)
+	if err != nil {
+		return false, fmt.Errorf("HasOnboardingRegistration %w", err)
+	}
+
+	return exists, nil
+}
+
+// freeSeats returns the seats of the event left to register for. They are
+// only limited when the room of the event has a capacity, and may be
+// negative when the capacity was lowered after the event filled up.
+func freeSeats(ctx context.Context, tx bun.Tx, event *model.Event) (free int, limited bool, err error) {
+	if !event.RoomID.Valid {
+		return 0, false, nil
+	}
+
+	var capacity int
+	err = tx.
+		NewSelect().
+		Model((*model.Room)(nil)).
+		ColumnExpr("COALESCE(capacity, 0)").
+		Where("id = ?", event.RoomID.UUID).
+		Scan(ctx, &capacity)
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && capacity <= 0) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+
+	query := tx.
+		NewSelect().
+		Model((*model.Registration)(nil)).
+		Where("event_id = ?", event.ID).
+		Where("status = ?", entity.RegistrationRegistered)
+	if err := model.ScopeToTenant(ctx, query); err != nil {
+		return 0, false, err
+	}
+	registered, err := query.Count(ctx)
+	if err != nil {
+		return 0, false, err
+	}
+
+	return capacity - registered, true, nil
+}
+
+// promoteWaitlist registers the users waiting for the event, in the order
+// they joined the waitlist, while it has free seats. The event must be
+// locked by tx. Registrants are only promoted to published events.
+func promoteWaitlist(ctx context.Context, tx bun.Tx, event *model.Event) error {
+	if event.Status != entity.EventPublished || !event.DeletedAt.IsZero() {
+		return nil
+	}
+
+	free, limited, err := freeSeats(ctx, tx, event)
+	if err != nil {
+		return err
+	}
+	if limited && free <= 0 {
+		return nil
+	}
+
+	var waitlisted []*model.Registration
+	query := tx.
+		NewSelect().
+		Model(&waitlisted).
+		Where("event_id = ?", event.ID).
+		Where("status = ?", entity.RegistrationWaitlisted).
+		Order("created_at", "id").
+		For("UPDATE")
+	if limited {
+		query = query.Limit(free)
+	}
+	if err := query.Scan(ctx); err != nil {
+		return err
+	}
+
+	for _, registration := range waitlisted {
+		registration.Status = entity.RegistrationRegistered
+		_, err := tx.
+			NewUpdate().
+			Model(registration).
+			Column("status").
+			WherePK().
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+
+		err = insertRegistrationMessage(
+			ctx, tx, dto.RegistrationPromotedSubject, registration.ToEntity(), event.ToEntity(),
+		)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
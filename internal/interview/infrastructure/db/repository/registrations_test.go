@@ -0,0 +1,191 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"online-registration/internal/interview/domain/dto"
+	"online-registration/internal/interview/domain/entity"
+	"online-registration/internal/interview/domain/repository"
+	"online-registration/internal/interview/domain/tenant"
+	"online-registration/internal/interview/infrastructure/db/model"
+
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+// createRegistrationTables creates the tables registering for an event
+// touches, with the index the registrations conflict on.
+func createRegistrationTables(t *testing.T, db *bun.DB) {
+	t.Helper()
+
+	ctx := context.Background()
+	for _, m := range []any{
+		(*model.Event)(nil), (*model.Room)(nil), (*model.Registration)(nil), (*model.OutboxMessage)(nil),
+	} {
+		if _, err := db.NewCreateTable().Model(m).Exec(ctx); err != nil {
+			t.Fatalf("create table: %v", err)
+		}
+	}
+
+	_, err := db.ExecContext(ctx, `
+		CREATE UNIQUE INDEX "registrations_active_idx" ON "registrations" ("event_id", "user_id")
+		WHERE "status" IN ('registered', 'waitlisted')
+	`)
+	if err != nil {
+		t.Fatalf("create index: %v", err)
+	}
+}
+
+// insertTestEvent inserts a published event of the tenant of ctx, in a room
+// of capacity seats.
+func insertTestEvent(t *testing.T, ctx context.Context, db *bun.DB, capacity int) (*model.Event, *model.Room) {
+	t.Helper()
+
+	room := &model.Room{ID: uuid.New(), VenueID: uuid.New(), Name: "Hall", Capacity: capacity}
+	if _, err := db.NewInsert().Model(room).Exec(ctx); err != nil {
+		t.Fatalf("insert room: %v", err)
+	}
+
+	start := time.Now().Add(24 * time.Hour)
+	event := &model.Event{
+		ID:        uuid.New(),
+		Title:     "Onboarding day",
+		StartTime: start,
+		EndTime:   start.Add(time.Hour),
+		TimeZone:  "UTC",
+		RoomID:    uuid.NullUUID{UUID: room.ID, Valid: true},
+		Status:    entity.EventPublished,
+	}
+	if _, err := db.NewInsert().Model(event).Exec(ctx); err != nil {
+		t.Fatalf("insert event: %v", err)
+	}
+	return event, room
+}
+
+func registrationStatus(t *testing.T, ctx context.Context, db *bun.DB, eventID uuid.UUID, userID string) string {
+	t.Helper()
+
+	registration := new(model.Registration)
+	err := db.
+		NewSelect().
+		Model(registration).
+		Where("event_id = ?", eventID).
+		Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Limit(1).
+		Scan(ctx)
+	if err != nil {
+		t.Fatalf("get registration of %s: %v", userID, err)
+	}
+	return registration.Status
+}
+
+func outboxSubjects(t *testing.T, db *bun.DB) []string {
+	t.Helper()
+
+	var subjects []string
+	err := db.
+		NewSelect().
+		Model((*model.OutboxMessage)(nil)).
+		Column("subject").
+		Order("id").
+		Scan(context.Background(), &subjects)
+	if err != nil {
+		t.Fatalf("list outbox: %v", err)
+	}
+	return subjects
+}
+
+func TestRegistrationWaitlist(t *testing.T) {
+	db := openTestDB(t)
+	createRegistrationTables(t, db)
+	ctx := tenant.ContextWithTenant(context.Background(), "acme")
+	event, room := insertTestEvent(t, ctx, db, 1)
+	registrations := NewDBRegistrationRepository(db)
+
+	for _, want := range []struct {
+		userID, status string
+	}{
+		{"alice", entity.RegistrationRegistered},
+		{"bob", entity.RegistrationWaitlisted},
+		{"carol", entity.RegistrationWaitlisted},
+	} {
+		registration, err := registrations.Register(ctx, event.ID, want.userID, want.userID+"@example.com")
+		if err != nil {
+			t.Fatalf("Register %s: %v", want.userID, err)
+		}
+		if registration.Status != want.status {
+			t.Errorf("Register %s = %s, want %s", want.userID, registration.Status, want.status)
+		}
+	}
+
+	if _, err := registrations.Register(ctx, event.ID, "bob", ""); !errors.Is(err, repository.ErrAlreadyRegistered) {
+		t.Errorf("Register of a waitlisted user = %v, want ErrAlreadyRegistered", err)
+	}
+
+	// the seat of alice goes to bob, who joined the waitlist first
+	if _, err := registrations.CancelRegistration(ctx, event.ID, "alice"); err != nil {
+		t.Fatalf("CancelRegistration: %v", err)
+	}
+	if status := registrationStatus(t, ctx, db, event.ID, "bob"); status != entity.RegistrationRegistered {
+		t.Errorf("bob is %s after a seat freed up, want registered", status)
+	}
+	if status := registrationStatus(t, ctx, db, event.ID, "carol"); status != entity.RegistrationWaitlisted {
+		t.Errorf("carol is %s, want waitlisted", status)
+	}
+
+	// leaving the waitlist frees no seat
+	if _, err := registrations.CancelRegistration(ctx, event.ID, "carol"); err != nil {
+		t.Fatalf("CancelRegistration: %v", err)
+	}
+	if _, err := registrations.Register(ctx, event.ID, "dave", ""); err != nil {
+		t.Fatalf("Register dave: %v", err)
+	}
+	if status := registrationStatus(t, ctx, db, event.ID, "dave"); status != entity.RegistrationWaitlisted {
+		t.Errorf("dave is %s, want waitlisted", status)
+	}
+
+	// so do the seats added to the room
+	if _, err := NewDBVenueRepository(db).UpdateRoom(ctx, room.ID, room.Name, 2); err != nil {
+		t.Fatalf("UpdateRoom: %v", err)
+	}
+	if status := registrationStatus(t, ctx, db, event.ID, "dave"); status != entity.RegistrationRegistered {
+		t.Errorf("dave is %s after the room grew, want registered", status)
+	}
+
+	want := []string{
+		dto.RegistrationCreatedSubject,
+		dto.RegistrationWaitlistedSubject,
+		dto.RegistrationWaitlistedSubject,
+		dto.RegistrationCancelledSubject,
+		dto.RegistrationPromotedSubject,
+		dto.RegistrationCancelledSubject,
+		dto.RegistrationWaitlistedSubject,
+		dto.RegistrationPromotedSubject,
+	}
+	if got := outboxSubjects(t, db); !slices.Equal(got, want) {
+		t.Errorf("outbox holds %v, want %v", got, want)
+	}
+}
+
+func TestRegistrationWithoutCapacity(t *testing.T) {
+	db := openTestDB(t)
+	createRegistrationTables(t, db)
+	ctx := tenant.ContextWithTenant(context.Background(), "acme")
+	event, _ := insertTestEvent(t, ctx, db, 0)
+	registrations := NewDBRegistrationRepository(db)
+
+	for _, userID := range []string{"alice", "bob", "carol"} {
+		registration, err := registrations.Register(ctx, event.ID, userID, "")
+		if err != nil {
+			t.Fatalf("Register %s: %v", userID, err)
+		}
+		if registration.Status != entity.RegistrationRegistered {
+			t.Errorf("Register %s = %s in a room without capacity, want registered", userID, registration.Status)
+		}
+	}
+}
@@ -0,0 +1,136 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"online-registration/internal/interview/domain/entity"
+	"online-registration/internal/interview/infrastructure/db/model"
+
+	"github.com/uptrace/bun"
+)
+
+type ScheduledTaskRepository struct {
+	db *bun.DB
+}
+
+func NewDBScheduledTaskRepository(db *bun.DB) *ScheduledTaskRepository {
+	return &ScheduledTaskRepository{
+		db: db,
+	}
+}
+
+func (r *ScheduledTaskRepository) SaveScheduledTask(
+	ctx context.Context, name, schedule string, nextRunAt time.Time,
+) (*entity.ScheduledTask, error) {
+	model := &model.ScheduledTask{
+		Name:      name,
+		Schedule:  schedule,
+		NextRunAt: nextRunAt,
+		UpdatedAt: time.Now(),
+	}
+	err := r.
+		db.
+		NewInsert().
+		Model(model).
+		On("CONFLICT (name) DO UPDATE").
+		Set("schedule = EXCLUDED.schedule").
+		Set("next_run_at = EXCLUDED.next_run_at").
+		Set("updated_at = EXCLUDED.updated_at").
+		Returning("*").
+		Scan(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("SaveScheduledTask %w", err)
+	}
+
+	return model.ToEntity(), nil
+}
+
+func (r *ScheduledTaskRepository) ListScheduledTasks(ctx context.Context) ([]*entity.ScheduledTask, error) {
+	var models []*model.ScheduledTask
+	err := r.
+		db.
+		NewSelect().
+		Model(&models).
+		Order("name").
+		Scan(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("ListScheduledTasks %w", err)
+	}
+
+	tasks := make([]*entity.ScheduledTask, 0, len(models))
+	for _, m := range models {
+		tasks = append(tasks, m.ToEntity())
+	}
+
+	return tasks, nil
+}
+
+func (r *ScheduledTaskRepository) ClaimTaskRun(
+	ctx context.Context, name string, tick time.Time, instance string,
+) (bool, error) {
+	now := time.Now()
+	result, err := r.
+		db.
+		NewUpdate().
+		Model((*model.ScheduledTask)(nil)).
+		Set("last_run_at = ?", tick).
+		Set("last_started_at = ?", now).
+		Set("last_finished_at = NULL").
+		Set("last_status = ?", entity.TaskRunning).
+		Set("last_error = NULL").
+		Set("last_instance = ?", instance).
+		Set("updated_at = ?", now).
+		Where("name = ?", name).
+		Where("last_run_at IS NULL OR last_run_at < ?", tick).
+		Exec(ctx)
+
+	if err != nil {
+		return false, fmt.Errorf("ClaimTaskRun %w", err)
+	}
+
+	claimed, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("ClaimTaskRun %w", err)
+	}
+
+	return claimed == 1, nil
+}
+
+func (r *ScheduledTaskRepository) FinishTaskRun(
+	ctx context.Context, name string, tick time.Time, runErr string, nextRunAt time.Time,
+) error {
+	status := entity.TaskSucceeded
+	if runErr != "" {
+		status = entity.TaskFailed
+	}
+
+	now := time.Now()
+	query := r.
+		db.
+		NewUpdate().
+		Model((*model.ScheduledTask)(nil)).
+		Set("last_finished_at = ?", now).
+		Set("last_status = ?", status).
+		Set("last_error = ?", nullString(runErr)).
+		Set("updated_at = ?", now).
+		Where("name = ?", name).
+		Where("last_run_at = ?", tick)
+	// a schedule that doesn't fire anymore has no next tick
+	if nextRunAt.IsZero() {
+		query = query.Set("next_run_at = NULL")
+	} else {
+		query = query.Set("next_run_at = ?", nextRunAt)
+	}
+
+	_, err := query.Exec(ctx)
+
+	if err != nil {
+		return fmt.Errorf("FinishTaskRun %w", err)
+	}
+
+	return nil
+}
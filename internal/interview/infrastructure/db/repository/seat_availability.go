@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"online-registration/internal/interview/domain/entity"
+	"online-registration/internal/interview/infrastructure/db/model"
+
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+// seatChangesChannel is the channel the ids of the events whose seats
+// changed are notified on, see migration 000022.
+const seatChangesChannel = "seat_changes"
+
+type SeatAvailabilityRepository struct {
+	db *bun.DB
+}
+
+func NewDBSeatAvailabilityRepository(db *bun.DB) *SeatAvailabilityRepository {
+	return &SeatAvailabilityRepository{
+		db: db,
+	}
+}
+
+func (r *SeatAvailabilityRepository) GetSeatAvailability(
+	ctx context.Context, eventIDs []uuid.UUID,
+) ([]*entity.SeatAvailability, error) {
+	if len(eventIDs) == 0 {
+		return nil, nil
+	}
+
+	var rows []struct {
+		EventID    uuid.UUID `bun:"event_id"`
+		Status     string    `bun:"status"`
+		CreatedBy  string    `bun:"created_by"`
+		Capacity   int       `bun:"capacity"`
+		Registered int       `bun:"registered"`
+		Waitlisted int       `bun:"waitlisted"`
+	}
+	query := r.
+		db.
+		NewSelect().
+		Model((*model.Event)(nil)).
+		ColumnExpr("s.id AS event_id, s.status, COALESCE(s.created_by, '') AS created_by").
+		ColumnExpr("COALESCE(rm.capacity, 0) AS capacity").
+		ColumnExpr(
+			"(SELECT count(*) FROM registrations AS rg WHERE rg.event_id = s.id AND rg.status = ?) AS registered",
+			entity.RegistrationRegistered,
+		).
+		ColumnExpr(
+			"(SELECT count(*) FROM registrations AS rg WHERE rg.event_id = s.id AND rg.status = ?) AS waitlisted",
+			entity.RegistrationWaitlisted,
+		).
+		Join("LEFT JOIN rooms AS rm ON rm.id = s.room_id").
+		Where("s.id IN (?)", bun.In(eventIDs))
+
+	if err := query.Scan(ctx, &rows); err != nil {
+		return nil, fmt.Errorf("GetSeatAvailability %w", err)
+	}
+
+	availability := make([]*entity.SeatAvailability, 0, len(rows))
+	for _, row := range rows {
+		seats := &entity.SeatAvailability{
+			EventID:    row.EventID,
+			Status:     row.Status,
+			CreatedBy:  row.CreatedBy,
+			Capacity:   row.Capacity,
+			Registered: row.Registered,
+			Waitlisted: row.Waitlisted,
+		}
+		if row.Capacity > 0 {
+			remaining := max(row.Capacity-row.Registered, 0)
+			seats.Remaining = &remaining
+		}
+		availability = append(availability, seats)
+	}
+	return availability, nil
+}
+
+func (r *SeatAvailabilityRepository) ListenSeatChanges(
+	ctx context.Context, listening func(), notify func(eventID uuid.UUID),
+) error {
+	err := listen(ctx, r.db, seatChangesChannel, listening, func(payload string) {
+		if eventID, err := uuid.Parse(payload); err == nil {
+			notify(eventID)
+		}
+	})
+	if err != nil {
+		return fmt.Errorf("ListenSeatChanges %w", err)
+	}
+	return nil
+}
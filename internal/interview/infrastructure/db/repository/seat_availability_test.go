@@ -0,0 +1,41 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"online-registration/internal/interview/domain/tenant"
+
+	"github.com/google/uuid"
+)
+
+func TestSeatAvailabilityCountsWaitlist(t *testing.T) {
+	db := openTestDB(t)
+	createRegistrationTables(t, db)
+	ctx := tenant.ContextWithTenant(context.Background(), "acme")
+	event, _ := insertTestEvent(t, ctx, db, 2)
+
+	registrations := NewDBRegistrationRepository(db)
+	for _, userID := range []string{"alice", "bob", "carol", "dave", "erin"} {
+		if _, err := registrations.Register(ctx, event.ID, userID, ""); err != nil {
+			t.Fatalf("Register %s: %v", userID, err)
+		}
+	}
+	if _, err := registrations.CancelRegistration(ctx, event.ID, "erin"); err != nil {
+		t.Fatalf("CancelRegistration: %v", err)
+	}
+
+	availability, err := NewDBSeatAvailabilityRepository(db).GetSeatAvailability(ctx, []uuid.UUID{event.ID})
+	if err != nil {
+		t.Fatalf("GetSeatAvailability: %v", err)
+	}
+	if len(availability) != 1 {
+		t.Fatalf("GetSeatAvailability returned %d events, want 1", len(availability))
+	}
+
+	seats := availability[0]
+	if seats.Capacity != 2 || seats.Registered != 2 || seats.Waitlisted != 2 ||
+		seats.Remaining == nil || *seats.Remaining != 0 {
+		t.Errorf("GetSeatAvailability = %+v, want 2 registered, 2 waitlisted and no seat left", seats)
+	}
+}
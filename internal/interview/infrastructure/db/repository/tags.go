@@ -0,0 +1,330 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+
+	"online-registration/internal/interview/domain/entity"
+	"online-registration/internal/interview/domain/repository"
+	"online-registration/internal/interview/infrastructure/db/model"
+
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/schema"
+)
+
+const (
+	tagNameConstraint      = "tags_tenant_name_key"
+	categoryNameConstraint = "categories_tenant_name_key"
+)
+
+type TagRepository struct {
+	db *bun.DB
+}
+
+func NewDBTagRepository(db *bun.DB) *TagRepository {
+	return &TagRepository{
+		db: db,
+	}
+}
+
+func (r *TagRepository) ListTags(ctx context.Context) ([]*entity.Tag, error) {
+	var models []*model.Tag
+	err := r.
+		db.
+		NewSelect().
+		Model(&models).
+		Order("name").
+		Scan(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("ListTags %w", err)
+	}
+
+	tags := make([]*entity.Tag, 0, len(models))
+	for _, m := range models {
+		tags = append(tags, m.ToEntity())
+	}
+
+	return tags, nil
+}
+
+func (r *TagRepository) CreateTag(ctx context.Context, name string) (*entity.Tag, error) {
+	model := &model.Tag{
+		ID:   uuid.New(),
+		Name: name,
+	}
+
+	_, err := r.
+		db.
+		NewInsert().
+		Model(model).
+		Returning("*").
+		Exec(ctx)
+
+	if isConstraintViolation(err, uniqueViolation, tagNameConstraint) {
+		err = repository.ErrTagNameTaken
+	}
+	if err != nil {
+		return nil, fmt.Errorf("CreateTag %w", err)
+	}
+
+	return model.ToEntity(), nil
+}
+
+func (r *TagRepository) UpdateTag(ctx context.Context, id uuid.UUID, name string) (*entity.Tag, error) {
+	model := new(model.Tag)
+	err := r.
+		db.
+		NewUpdate().
+		Model(model).
+		Set("name = ?", name).
+		Set("updated_at = ?", time.Now()).
+		Where("id = ?", id).
+		Returning("*").
+		Scan(ctx)
+
+	if isConstraintViolation(err, uniqueViolation, tagNameConstraint) {
+		err = repository.ErrTagNameTaken
+	}
+	if err != nil {
+		return nil, fmt.Errorf("UpdateTag %w", err)
+	}
+
+	return model.ToEntity(), nil
+}
+
+func (r *TagRepository) DeleteTag(ctx context.Context, id uuid.UUID) error {
+	result, err := r.
+		db.
+		NewDelete().
+		Model((*model.Tag)(nil)).
+		Where("id = ?", id).
+		Exec(ctx)
+
+	if err != nil {
+		return fmt.Errorf("DeleteTag %w", err)
+	}
+
+	return ensureAffected(result, "DeleteTag")
+}
+
+func (r *TagRepository) ListCategories(ctx context.Context) ([]*entity.Category, error) {
+	var models []*model.Category
+	err := r.
+		db.
+		NewSelect().
+		Model(&models).
+		Order("name").
+		Scan(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("ListCategories %w", err)
+	}
+
+	categories := make([]*entity.Category, 0, len(models))
+	for _, m := range models {
+		categories = append(categories, m.ToEntity())
+	}
+
+	return categories, nil
+}
+
+func (r *TagRepository) CreateCategory(ctx context.Context, name string) (*entity.Category, error) {
+	model := &model.Category{
+		ID:   uuid.New(),
+		Name: name,
+	}
+
+	_, err := r.
+		db.
+		NewInsert().
+		Model(model).
+		Returning("*").
+		Exec(ctx)
+
+	if isConstraintViolation(err, uniqueViolation, categoryNameConstraint) {
+		err = repository.ErrCategoryNameTaken
+	}
+	if err != nil {
+		return nil, fmt.Errorf("CreateCategory %w", err)
+	}
+
+	return model.ToEntity(), nil
+}
+
+func (r *TagRepository) UpdateCategory(ctx context.Context, id uuid.UUID, name string) (*entity.Category, error) {
+	model := new(model.Category)
+	err := r.
+		db.
+		NewUpdate().
+		Model(model).
+		Set("name = ?", name).
+		Set("updated_at = ?", time.Now()).
+		Where("id = ?", id).
+		Returning("*").
+		Scan(ctx)
+
+	if isConstraintViolation(err, uniqueViolation, categoryNameConstraint) {
+		err = repository.ErrCategoryNameTaken
+	}
+	if err != nil {
+		return nil, fmt.Errorf("UpdateCategory %w", err)
+	}
+
+	return model.ToEntity(), nil
+}
+
+func (r *TagRepository) DeleteCategory(ctx context.Context, id uuid.UUID) error {
+	result, err := r.
+		db.
+		NewDelete().
+		Model((*model.Category)(nil)).
+		Where("id = ?", id).
+		Exec(ctx)
+
+	if err != nil {
+		return fmt.Errorf("DeleteCategory %w", err)
+	}
+
+	return ensureAffected(result, "DeleteCategory")
+}
+
+// lockCategory checks that the category exists in the tenant and keeps it
+// from being deleted until tx ends. A nil categoryID puts the event in no
+// category.
+func lockCategory(ctx context.Context, tx bun.Tx, categoryID *uuid.UUID) error {
+	if categoryID == nil {
+		return nil
+	}
+
+	err := tx.
+		NewSelect().
+		Model((*model.Category)(nil)).
+		Column("id").
+		Where("id = ?", *categoryID).
+		For("SHARE").
+		Scan(ctx, new(uuid.UUID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return repository.ErrCategoryNotFound
+	}
+	return err
+}
+
+// setEventTags replaces the tags of the event by the tags named names,
+// creating the missing ones, and returns their names in order.
+func setEventTags(ctx context.Context, tx bun.Tx, eventID uuid.UUID, names []string) ([]string, error) {
+	_, err := tx.
+		NewDelete().
+		Model((*model.EventTag)(nil)).
+		Where("event_id = ?", eventID).
+		Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(names) == 0 {
+		return []string{}, nil
+	}
+
+	created := make([]*model.Tag, 0, len(names))
+	for _, name := range names {
+		created = append(created, &model.Tag{ID: uuid.New(), Name: name})
+	}
+	_, err = tx.
+		NewInsert().
+		Model(&created).
+		On("CONFLICT (tenant_id, name) DO NOTHING").
+		Returning("NULL").
+		Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// keeps the tags from being deleted before the event links to them
+	var tags []*model.Tag
+	err = tx.
+		NewSelect().
+		Model(&tags).
+		Where("name IN (?)", bun.In(names)).
+		Order("name").
+		For("SHARE").
+		Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	links := make([]*model.EventTag, 0, len(tags))
+	tagNames := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		links = append(links, &model.EventTag{EventID: eventID, TagID: tag.ID})
+		tagNames = append(tagNames, tag.Name)
+	}
+	_, err = tx.
+		NewInsert().
+		Model(&links).
+		Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return tagNames, nil
+}
+
+// loadEventTags sets the tags of the events.
+func loadEventTags(ctx context.Context, db bun.IDB, events ...*entity.Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	byID := make(map[uuid.UUID]*entity.Event, len(events))
+	for _, event := range events {
+		event.Tags = []string{}
+		byID[event.ID] = event
+	}
+
+	var rows []struct {
+		EventID uuid.UUID `bun:"event_id"`
+		Name    string    `bun:"name"`
+	}
+	err := db.
+		NewSelect().
+		Model((*model.EventTag)(nil)).
+		ColumnExpr("et.event_id, t.name").
+		Join("JOIN tags AS t ON t.id = et.tag_id").
+		Where("et.event_id IN (?)", bun.In(slices.Collect(maps.Keys(byID)))).
+		OrderExpr("t.name").
+		Scan(ctx, &rows)
+	if err != nil {
+		return fmt.Errorf("load event tags: %w", err)
+	}
+
+	for _, row := range rows {
+		event := byID[row.EventID]
+		event.Tags = append(event.Tags, row.Name)
+	}
+	return nil
+}
+
+// taggedEvents selects the ids of the events with any of the tags, or with
+// all of them. It is only used to narrow down tenant scoped listings of
+// events, so it doesn't scope the tags itself.
+func taggedEvents(tags []string, all bool) schema.QueryWithArgs {
+	if all {
+		return bun.SafeQuery(`
+			SELECT et.event_id FROM event_tags AS et JOIN tags AS t ON t.id = et.tag_id
+			WHERE t.name IN (?) GROUP BY et.event_id HAVING count(*) = ?`,
+			bun.In(tags), len(tags),
+		)
+	}
+	return bun.SafeQuery(`
+		SELECT et.event_id FROM event_tags AS et JOIN tags AS t ON t.id = et.tag_id
+		WHERE t.name IN (?)`,
+		bun.In(tags),
+	)
+}
@@ -0,0 +1,278 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"online-registration/internal/interview/domain/entity"
+	"online-registration/internal/interview/domain/repository"
+	"online-registration/internal/interview/infrastructure/db/model"
+
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+const (
+	roomVenueConstraint = "rooms_venue_id_fkey"
+	roomNameConstraint  = "rooms_venue_name_key"
+)
+
+type VenueRepository struct {
+	db *bun.DB
+}
+
+func NewDBVenueRepository(db *bun.DB) *VenueRepository {
+	return &VenueRepository{
+		db: db,
+	}
+}
+
+func (r *VenueRepository) CreateVenue(ctx context.Context, name string, address string) (*entity.Venue, error) {
+	model := &model.Venue{
+		ID:      uuid.New(),
+		Name:    name,
+		Address: address,
+	}
+
+	_, err := r.
+		db.
+		NewInsert().
+		Model(model).
+		Returning("*").
+		Exec(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("CreateVenue %w", err)
+	}
+
+	return model.ToEntity(), nil
+}
+
+func (r *VenueRepository) ListVenues(ctx context.Context) ([]*entity.Venue, error) {
+	var models []*model.Venue
+	err := r.
+		db.
+		NewSelect().
+		Model(&models).
+		Relation("Rooms", orderRooms).
+		Order("name", "id").
+		Scan(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("ListVenues %w", err)
+	}
+
+	venues := make([]*entity.Venue, 0, len(models))
+	for _, m := range models {
+		venues = append(venues, m.ToEntity())
+	}
+
+	return venues, nil
+}
+
+func (r *VenueRepository) GetVenue(ctx context.Context, id uuid.UUID) (*entity.Venue, error) {
+	model := new(model.Venue)
+	err := r.
+		db.
+		NewSelect().
+		Model(model).
+		Relation("Rooms", orderRooms).
+		Where("id = ?", id).
+		Scan(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("GetVenue %w", err)
+	}
+
+	return model.ToEntity(), nil
+}
+
+func (r *VenueRepository) UpdateVenue(
+	ctx context.Context, id uuid.UUID, name string, address string,
+) (*entity.Venue, error) {
+	model := new(model.Venue)
+	err := r.
+		db.
+		NewUpdate().
+		Model(model).
+		Set("name = ?", name).
+		Set("address = ?", nullString(address)).
+		Set("updated_at = ?", time.Now()).
+		Where("id = ?", id).
+		Returning("*").
+		Scan(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("UpdateVenue %w", err)
+	}
+
+	return model.ToEntity(), nil
+}
+
+func (r *VenueRepository) DeleteVenue(ctx context.Context, id uuid.UUID) error {
+	result, err := r.
+		db.
+		NewDelete().
+		Model((*model.Venue)(nil)).
+		Where("id = ?", id).
+		Exec(ctx)
+
+	if isConstraintViolation(err, foreignKeyViolation, roomVenueConstraint) {
+		err = repository.ErrVenueNotEmpty
+	}
+	if err != nil {
+		return fmt.Errorf("DeleteVenue %w", err)
+	}
+
+	return ensureAffected(result, "DeleteVenue")
+}
+
+func (r *VenueRepository) CreateRoom(
+	ctx context.Context, venueID uuid.UUID, name string, capacity int,
+) (*entity.Room, error) {
+	model := &model.Room{
+		ID:       uuid.New(),
+		VenueID:  venueID,
+		Name:     name,
+		Capacity: capacity,
+	}
+
+	err := r.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		// the venue must be visible to the tenant, the foreign key alone
+		// would accept the venues of other tenants
+		if err := lockVenue(ctx, tx, venueID); err != nil {
+			return err
+		}
+
+		_, err := tx.
+			NewInsert().
+			Model(model).
+			Returning("*").
+			Exec(ctx)
+		return err
+	})
+
+	if isConstraintViolation(err, uniqueViolation, roomNameConstraint) {
+		err = repository.ErrRoomNameTaken
+	}
+	if err != nil {
+		return nil, fmt.Errorf("CreateRoom %w", err)
+	}
+
+	return model.ToEntity(), nil
+}
+
+func (r *VenueRepository) UpdateRoom(
+	ctx context.Context, id uuid.UUID, name string, capacity int,
+) (*entity.Room, error) {
+	room := new(model.Room)
+	err := r.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		err := tx.
+			NewUpdate().
+			Model(room).
+			Set("name = ?", name).
+			Set("capacity = ?", nullInt(capacity)).
+			Set("updated_at = ?", time.Now()).
+			Where("id = ?", id).
+			Returning("*").
+			Scan(ctx)
+		if err != nil {
+			return err
+		}
+
+		// seats added to the room go to the waitlists of its events
+		var events []*model.Event
+		err = tx.
+			NewSelect().
+			Model(&events).
+			Where("room_id = ?", id).
+			Where("status = ?", entity.EventPublished).
+			Where("end_time > ?", time.Now()).
+			Where("EXISTS (SELECT 1 FROM registrations AS rg WHERE rg.event_id = s.id AND rg.status = ?)",
+				entity.RegistrationWaitlisted).
+			Order("id").
+			For("NO KEY UPDATE").
+			Scan(ctx)
+		if err != nil {
+			return err
+		}
+		for _, event := range events {
+			if err := promoteWaitlist(ctx, tx, event); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	if isConstraintViolation(err, uniqueViolation, roomNameConstraint) {
+		err = repository.ErrRoomNameTaken
+	}
+	if err != nil {
+		return nil, fmt.Errorf("UpdateRoom %w", err)
+	}
+
+	return room.ToEntity(), nil
+}
+
+func (r *VenueRepository) DeleteRoom(ctx context.Context, id uuid.UUID) error {
+	err := r.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		// keeps new bookings out, they share lock the room
+		err := tx.
+			NewSelect().
+			Model((*model.Room)(nil)).
+			Column("id").
+			Where("id = ?", id).
+			For("UPDATE").
+			Scan(ctx, new(uuid.UUID))
+		if err != nil {
+			return err
+		}
+
+		inUse, err := tx.
+			NewSelect().
+			Model((*model.Event)(nil)).
+			Where("room_id = ?", id).
+			Where("status IN (?)", bun.In([]string{entity.EventDraft, entity.EventPublished})).
+			Exists(ctx)
+		if err != nil {
+			return err
+		}
+		if inUse {
+			return repository.ErrRoomInUse
+		}
+
+		result, err := tx.
+			NewDelete().
+			Model((*model.Room)(nil)).
+			Where("id = ?", id).
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+
+		return ensureAffected(result, "DeleteRoom")
+	})
+
+	if err != nil {
+		return fmt.Errorf("DeleteRoom %w", err)
+	}
+
+	return nil
+}
+
+// lockVenue checks that the venue exists in the tenant and keeps it from
+// being deleted until tx ends.
+func lockVenue(ctx context.Context, tx bun.Tx, id uuid.UUID) error {
+	return tx.
+		NewSelect().
+		Model((*model.Venue)(nil)).
+		Column("id").
+		Where("id = ?", id).
+		For("SHARE").
+		Scan(ctx, new(uuid.UUID))
+}
+
+func orderRooms(query *bun.SelectQuery) *bun.SelectQuery {
+	return query.Order("name")
+}
@@ -0,0 +1,388 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"online-registration/internal/interview/domain/entity"
+	"online-registration/internal/interview/infrastructure/db/model"
+
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/pgdialect"
+)
+
+type WebhookRepository struct {
+	db *bun.DB
+}
+
+func NewDBWebhookRepository(db *bun.DB) *WebhookRepository {
+	return &WebhookRepository{
+		db: db,
+	}
+}
+
+func (r *WebhookRepository) CreateSubscription(
+	ctx context.Context, subscription *entity.WebhookSubscription,
+) (*entity.WebhookSubscription, error) {
+	model := &model.WebhookSubscription{
+		ID:         uuid.New(),
+		URL:        subscription.URL,
+		Secret:     subscription.Secret,
+		EventTypes: subscription.EventTypes,
+		Active:     true,
+	}
+
+	_, err := r.
+		db.
+		NewInsert().
+		Model(model).
+		Returning("*").
+		Exec(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("CreateWebhookSubscription %w", err)
+	}
+
+	return model.ToEntity(), nil
+}
+
+func (r *WebhookRepository) ListSubscriptions(ctx context.Context) ([]*entity.WebhookSubscription, error) {
+	var models []*model.WebhookSubscription
+	err := r.
+		db.
+		NewSelect().
+		Model(&models).
+		Order("created_at").
+		Scan(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("ListWebhookSubscriptions %w", err)
+	}
+
+	subscriptions := make([]*entity.WebhookSubscription, 0, len(models))
+	for _, m := range models {
+		subscriptions = append(subscriptions, m.ToEntity())
+	}
+
+	return subscriptions, nil
+}
+
+func (r *WebhookRepository) GetSubscription(ctx context.Context, id uuid.UUID) (*entity.WebhookSubscription, error) {
+	model := new(model.WebhookSubscription)
+	err := r.
+		db.
+		NewSelect().
+		Model(model).
+		Where("id = ?", id).
+		Scan(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("GetWebhookSubscription %w", err)
+	}
+
+	return model.ToEntity(), nil
+}
+
+// UpdateSubscription also clears the failure streak, so that re-activating a
+// disabled subscription gives it a fresh start.
+func (r *WebhookRepository) UpdateSubscription(
+	ctx context.Context, id uuid.UUID, url string, eventTypes []string, active bool,
+) (*entity.WebhookSubscription, error) {
+	model := new(model.WebhookSubscription)
+	query := r.
+		db.
+		NewUpdate().
+		Model(model).
+		Set("url = ?", url).
+		Set("event_types = ?", pgdialect.Array(eventTypes)).
+		Set("active = ?", active).
+		Set("updated_at = ?", time.Now()).
+		Where("id = ?", id).
+		Returning("*")
+
+	if active {
+		query = query.
+			Set("consecutive_failures = 0").
+			Set("disabled_at = NULL")
+	}
+
+	if err := query.Scan(ctx); err != nil {
+		return nil, fmt.Errorf("UpdateWebhookSubscription %w", err)
+	}
+
+	return model.ToEntity(), nil
+}
+
+func (r *WebhookRepository) DeleteSubscription(ctx context.Context, id uuid.UUID) error {
+	result, err := r.
+		db.
+		NewDelete().
+		Model((*model.WebhookSubscription)(nil)).
+		Where("id = ?", id).
+		Exec(ctx)
+
+	if err != nil {
+		return fmt.Errorf("DeleteWebhookSubscription %w", err)
+	}
+
+	return ensureAffected(result, "DeleteWebhookSubscription")
+}
+
+func (r *WebhookRepository) EnqueueDeliveries(
+	ctx context.Context, tenantID string, messageID uuid.UUID, eventType string, payload []byte,
+) (int, error) {
+	result, err := r.db.ExecContext(ctx, `
+		INSERT INTO "webhook_deliveries" ("id", "subscription_id", "message_id", "event_type", "payload")
+		SELECT gen_random_uuid(), "id", ?, ?, ?
+		FROM "webhook_subscriptions"
+		WHERE "tenant_id" = ? AND "active" AND ? = ANY ("event_types")
+		ON CONFLICT ("subscription_id", "message_id") WHERE "redelivery_of" IS NULL DO NOTHING
+	`, messageID, eventType, string(payload), tenantID, eventType)
+
+	if err != nil {
+		return 0, fmt.Errorf("EnqueueWebhookDeliveries %w", err)
+	}
+
+	enqueued, _ := result.RowsAffected()
+	return int(enqueued), nil
+}
+
+func (r *WebhookRepository) ListDeliveries(
+	ctx context.Context, subscriptionID uuid.UUID, limit int,
+) ([]*entity.WebhookDelivery, error) {
+	var models []*model.WebhookDelivery
+	err := r.
+		db.
+		NewSelect().
+		Model(&models).
+		Where("subscription_id = ?", subscriptionID).
+		Order("created_at DESC").
+		Limit(limit).
+		Scan(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("ListWebhookDeliveries %w", err)
+	}
+
+	deliveries := make([]*entity.WebhookDelivery, 0, len(models))
+	for _, m := range models {
+		deliveries = append(deliveries, m.ToEntity())
+	}
+
+	return deliveries, nil
+}
+
+func (r *WebhookRepository) Redeliver(
+	ctx context.Context, subscriptionID, deliveryID uuid.UUID,
+) (*entity.WebhookDelivery, error) {
+	original := new(model.WebhookDelivery)
+	err := r.
+		db.
+		NewSelect().
+		Model(original).
+		Where("id = ?", deliveryID).
+		Where("subscription_id = ?", subscriptionID).
+		Scan(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("RedeliverWebhook %w", err)
+	}
+
+	model := &model.WebhookDelivery{
+		ID:             uuid.New(),
+		SubscriptionID: original.SubscriptionID,
+		MessageID:      original.MessageID,
+		RedeliveryOf:   uuid.NullUUID{UUID: original.ID, Valid: true},
+		EventType:      original.EventType,
+		Payload:        original.Payload,
+		Status:         entity.WebhookDeliveryPending,
+		NextAttemptAt:  time.Now(),
+	}
+
+	_, err = r.
+		db.
+		NewInsert().
+		Model(model).
+		Returning("*").
+		Exec(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("RedeliverWebhook %w", err)
+	}
+
+	return model.ToEntity(), nil
+}
+
+func (r *WebhookRepository) ClaimDeliveries(
+	ctx context.Context, limit int, lease time.Duration,
+) ([]*entity.WebhookDelivery, error) {
+	now := time.Now()
+
+	due := r.
+		db.
+		NewSelect().
+		Model((*model.WebhookDelivery)(nil)).
+		Column("id").
+		Where("status = ?", entity.WebhookDeliveryPending).
+		Where("next_attempt_at <= ?", now).
+		Where(`subscription_id IN (SELECT "id" FROM "webhook_subscriptions" WHERE "active")`).
+		Order("next_attempt_at").
+		Limit(limit).
+		For("UPDATE SKIP LOCKED")
+
+	var claimed []*model.WebhookDelivery
+	err := r.
+		db.
+		NewUpdate().
+		Model(&claimed).
+		Set("next_attempt_at = ?", now.Add(lease)).
+		Where("id IN (?)", due).
+		Returning("id").
+		Scan(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("ClaimWebhookDeliveries %w", err)
+	}
+
+	if len(claimed) == 0 {
+		return nil, nil
+	}
+
+	ids := make([]uuid.UUID, 0, len(claimed))
+	for _, m := range claimed {
+		ids = append(ids, m.ID)
+	}
+
+	var models []*model.WebhookDelivery
+	err = r.
+		db.
+		NewSelect().
+		Model(&models).
+		Relation("Subscription").
+		Where("wd.id IN (?)", bun.In(ids)).
+		Scan(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("ClaimWebhookDeliveries %w", err)
+	}
+
+	sort.Slice(models, func(i, j int) bool { return models[i].CreatedAt.Before(models[j].CreatedAt) })
+
+	deliveries := make([]*entity.WebhookDelivery, 0, len(models))
+	for _, m := range models {
+		deliveries = append(deliveries, m.ToEntity())
+	}
+
+	return deliveries, nil
+}
+
+// RenewDeliveryLease matches the lease the delivery was claimed or last
+// renewed with, which no other worker holds.
+func (r *WebhookRepository) RenewDeliveryLease(
+	ctx context.Context, delivery *entity.WebhookDelivery, lease time.Duration,
+) (bool, error) {
+	var renewed []*model.WebhookDelivery
+	err := r.
+		db.
+		NewUpdate().
+		Model(&renewed).
+		Set("next_attempt_at = ?", time.Now().Add(lease)).
+		Where("id = ?", delivery.ID).
+		Where("status = ?", entity.WebhookDeliveryPending).
+		Where("next_attempt_at = ?", delivery.NextAttemptAt).
+		Returning("next_attempt_at").
+		Scan(ctx)
+
+	if err != nil {
+		return false, fmt.Errorf("RenewWebhookDeliveryLease %w", err)
+	}
+
+	if len(renewed) == 0 {
+		return false, nil
+	}
+
+	delivery.NextAttemptAt = renewed[0].NextAttemptAt
+	return true, nil
+}
+
+func (r *WebhookRepository) MarkDeliverySucceeded(
+	ctx context.Context, delivery *entity.WebhookDelivery, statusCode int,
+) error {
+	err := r.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		now := time.Now()
+		_, err := tx.
+			NewUpdate().
+			Model((*model.WebhookDelivery)(nil)).
+			Set("status = ?", entity.WebhookDeliverySucceeded).
+			Set("attempts = attempts + 1").
+			Set("last_status_code = ?", statusCode).
+			Set("last_error = NULL").
+			Set("delivered_at = ?", now).
+			Where("id = ?", delivery.ID).
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.
+			NewUpdate().
+			Model((*model.WebhookSubscription)(nil)).
+			Set("consecutive_failures = 0").
+			Where("id = ?", delivery.SubscriptionID).
+			Where("consecutive_failures > 0").
+			Exec(ctx)
+		return err
+	})
+
+	if err != nil {
+		return fmt.Errorf("MarkWebhookDeliverySucceeded %w", err)
+	}
+
+	return nil
+}
+
+func (r *WebhookRepository) MarkDeliveryFailed(
+	ctx context.Context, delivery *entity.WebhookDelivery, statusCode int, lastError string,
+	nextAttemptAt *time.Time, disableAfter int,
+) error {
+	err := r.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		now := time.Now()
+		query := tx.
+			NewUpdate().
+			Model((*model.WebhookDelivery)(nil)).
+			Set("attempts = attempts + 1").
+			Set("last_status_code = ?", nullInt(statusCode)).
+			Set("last_error = ?", lastError).
+			Where("id = ?", delivery.ID)
+
+		if nextAttemptAt != nil {
+			query = query.Set("next_attempt_at = ?", *nextAttemptAt)
+		} else {
+			query = query.Set("status = ?", entity.WebhookDeliveryFailed)
+		}
+
+		if _, err := query.Exec(ctx); err != nil {
+			return err
+		}
+
+		_, err := tx.
+			NewUpdate().
+			Model((*model.WebhookSubscription)(nil)).
+			Set("consecutive_failures = consecutive_failures + 1").
+			Set("active = active AND consecutive_failures + 1 < ?", disableAfter).
+			Set("disabled_at = CASE WHEN active AND consecutive_failures + 1 >= ? THEN ? ELSE disabled_at END",
+				disableAfter, now).
+			Where("id = ?", delivery.SubscriptionID).
+			Exec(ctx)
+		return err
+	})
+
+	if err != nil {
+		return fmt.Errorf("MarkWebhookDeliveryFailed %w", err)
+	}
+
+	return nil
+}
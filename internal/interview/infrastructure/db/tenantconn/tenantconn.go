@@ -0,0 +1,117 @@
+// Package tenantconn exposes the tenant of each query to the row level
+// security policies of the tenant scoped tables. Every query sets
+// app.tenant_id of its connection to the tenant of its context first, so a
+// query can't run with the tenant of whatever request used the connection
+// before. The policies deny every row while the setting is empty, that is
+// for contexts without a tenant.
+package tenantconn
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+
+	"online-registration/internal/interview/domain/tenant"
+
+	"github.com/jackc/pgx/v5/stdlib"
+)
+
+// AllTenants is the app.tenant_id of contexts lifted with
+// tenant.ContextWithAllTenants. It is not a valid tenant id.
+const AllTenants = "*"
+
+// Setting returns the app.tenant_id of the queries run with ctx.
+func Setting(ctx context.Context) string {
+	if tenant.AllTenants(ctx) {
+		return AllTenants
+	}
+	id, _ := tenant.FromContext(ctx)
+	return id
+}
+
+type connector struct {
+	driver.Connector
+}
+
+// NewConnector wraps a pgx connector so that the connections it opens set
+// app.tenant_id before every query. Prepared statements are not covered, bun
+// doesn't use them.
+func NewConnector(c driver.Connector) driver.Connector {
+	return connector{Connector: c}
+}
+
+func (c connector) Connect(ctx context.Context) (driver.Conn, error) {
+	dc, err := c.Connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	pgxConn, ok := dc.(*stdlib.Conn)
+	if !ok {
+		dc.Close()
+		return nil, fmt.Errorf("tenantconn: unsupported connection %T", dc)
+	}
+	return &conn{Conn: pgxConn}, nil
+}
+
+// conn embeds the pgx connection to keep the optional interfaces of
+// database/sql it implements.
+type conn struct {
+	*stdlib.Conn
+
+	// setting is the app.tenant_id of the session when known is set
+	setting string
+	known   bool
+}
+
+func (c *conn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	if err := c.setTenant(ctx); err != nil {
+		return nil, err
+	}
+	return c.Conn.ExecContext(ctx, query, args)
+}
+
+func (c *conn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	if err := c.setTenant(ctx); err != nil {
+		return nil, err
+	}
+	return c.Conn.QueryContext(ctx, query, args)
+}
+
+func (c *conn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	dtx, err := c.Conn.BeginTx(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &tx{Tx: dtx, conn: c}, nil
+}
+
+func (c *conn) setTenant(ctx context.Context) error {
+	setting := Setting(ctx)
+	if c.known && c.setting == setting {
+		return nil
+	}
+
+	c.known = false
+	_, err := c.Conn.ExecContext(
+		ctx, "SELECT set_config('app.tenant_id', $1, false)", []driver.NamedValue{{Ordinal: 1, Value: setting}},
+	)
+	if err != nil {
+		return fmt.Errorf("set app.tenant_id: %w", err)
+	}
+
+	c.setting, c.known = setting, true
+	return nil
+}
+
+// tx forgets the setting of the session when rolled back, which reverts the
+// settings changed by the transaction.
+type tx struct {
+	driver.Tx
+	conn *conn
+}
+
+func (t *tx) Rollback() error {
+	t.conn.known = false
+	return t.Tx.Rollback()
+}
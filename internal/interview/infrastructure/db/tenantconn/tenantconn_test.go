@@ -0,0 +1,118 @@
+package tenantconn
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"os"
+	"testing"
+
+	"online-registration/internal/interview/domain/tenant"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/stdlib"
+)
+
+func openTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+
+	connString := os.Getenv("TEST_DATABASE_URL")
+	if connString == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+
+	config, err := pgx.ParseConfig(connString)
+	if err != nil {
+		t.Fatalf("parse TEST_DATABASE_URL: %v", err)
+	}
+
+	db := sql.OpenDB(NewConnector(stdlib.GetConnector(*config)))
+	// a single connection, so every query reuses the session of the
+	// previous one
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func currentTenant(t *testing.T, ctx context.Context, q interface {
+	QueryRowContext(context.Context, string, ...any) *sql.Row
+}) string {
+	t.Helper()
+
+	var setting string
+	if err := q.QueryRowContext(ctx, "SELECT current_setting('app.tenant_id', true)").Scan(&setting); err != nil {
+		t.Fatalf("read app.tenant_id: %v", err)
+	}
+	return setting
+}
+
+func TestSetting(t *testing.T) {
+	ctx := context.Background()
+	acme := tenant.ContextWithTenant(ctx, "acme")
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		want string
+	}{
+		{"no tenant", ctx, ""},
+		{"tenant", acme, "acme"},
+		{"all tenants", tenant.ContextWithAllTenants(ctx), AllTenants},
+		{"all tenants over a tenant", tenant.ContextWithAllTenants(acme), AllTenants},
+	}
+
+	for _, tt := range tests {
+		if got := Setting(tt.ctx); got != tt.want {
+			t.Errorf("Setting of %s = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestConnSetsTenantOfEveryQuery(t *testing.T) {
+	db := openTestDB(t)
+	ctx := context.Background()
+	acme := tenant.ContextWithTenant(ctx, "acme")
+	globex := tenant.ContextWithTenant(ctx, "globex")
+
+	for _, step := range []struct {
+		ctx  context.Context
+		want string
+	}{
+		{acme, "acme"},
+		{globex, "globex"},
+		{ctx, ""},
+		{tenant.ContextWithAllTenants(ctx), AllTenants},
+		{acme, "acme"},
+	} {
+		if got := currentTenant(t, step.ctx, db); got != step.want {
+			t.Errorf("app.tenant_id = %q, want %q", got, step.want)
+		}
+	}
+}
+
+func TestConnResetsTenantAfterRollback(t *testing.T) {
+	db := openTestDB(t)
+	ctx := context.Background()
+	acme := tenant.ContextWithTenant(ctx, "acme")
+	globex := tenant.ContextWithTenant(ctx, "globex")
+
+	if got := currentTenant(t, acme, db); got != "acme" {
+		t.Fatalf("app.tenant_id = %q, want acme", got)
+	}
+
+	tx, err := db.BeginTx(globex, nil)
+	if err != nil {
+		t.Fatalf("BeginTx: %v", err)
+	}
+	if got := currentTenant(t, globex, tx); got != "globex" {
+		t.Errorf("app.tenant_id in the transaction = %q, want globex", got)
+	}
+	if err := tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+		t.Fatalf("Rollback: %v", err)
+	}
+
+	// the rollback restored acme, the next query of globex must set it again
+	if got := currentTenant(t, globex, db); got != "globex" {
+		t.Errorf("app.tenant_id after the rollback = %q, want globex", got)
+	}
+}
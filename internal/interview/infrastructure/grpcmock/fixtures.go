@@ -0,0 +1,255 @@
+package grpcmock
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"gopkg.in/yaml.v3"
+)
+
+// fixtureFile is the format of a fixture file:
+//
+//	descriptor_sets:
+//	  - avanpost.protoset
+//	fixtures:
+//	  - method: /avanpost.v1.Groups/AddMember
+//	    match:
+//	      group_id: onboarding
+//	    latency: 200ms
+//	    response:
+//	      added: true
+//	  - method: /avanpost.v1.Groups/AddMember
+//	    error:
+//	      code: UNAVAILABLE
+//	      message: avanpost is down
+//
+// Descriptor sets are produced with protoc --descriptor_set_out
+// --include_imports, relative paths are resolved against the fixture file.
+type fixtureFile struct {
+	DescriptorSets []string      `yaml:"descriptor_sets"`
+	Fixtures       []fixtureSpec `yaml:"fixtures"`
+}
+
+type fixtureSpec struct {
+	Method   string         `yaml:"method"`
+	Match    map[string]any `yaml:"match"`
+	Latency  string         `yaml:"latency"`
+	Response map[string]any `yaml:"response"`
+	Error    *struct {
+		Code    string `yaml:"code"`
+		Message string `yaml:"message"`
+	} `yaml:"error"`
+}
+
+// Fixture is a canned answer to the requests of a method matching Match.
+type Fixture struct {
+	Method string
+	// Match is compared with the request in its protojson form, using the
+	// field names of the .proto files. Every field
+	// of Match must be present in the request with the same value, nested
+	// messages are matched the same way. An empty Match matches any request.
+	Match   map[string]any
+	Latency time.Duration
+	// Response is the protojson form of the response message, it is ignored
+	// when Code is not OK.
+	Response json.RawMessage
+	Code     codes.Code
+	Message  string
+}
+
+// Fixtures holds the canned answers and the descriptors of the services they
+// belong to.
+type Fixtures struct {
+	files    *protoregistry.Files
+	fixtures []Fixture
+}
+
+// Load reads the fixture file at path, or every .yaml, .yml and .json file
+// when path is a directory.
+func Load(path string) (*Fixtures, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("load fixtures: %w", err)
+	}
+
+	paths := []string{path}
+	if info.IsDir() {
+		entries, err := os.ReadDir(path)
+		if err != nil {
+			return nil, fmt.Errorf("load fixtures: %w", err)
+		}
+
+		paths = paths[:0]
+		for _, entry := range entries {
+			ext := filepath.Ext(entry.Name())
+			if !entry.IsDir() && slices.Contains([]string{".yaml", ".yml", ".json"}, ext) {
+				paths = append(paths, filepath.Join(path, entry.Name()))
+			}
+		}
+	}
+
+	descriptors := &descriptorpb.FileDescriptorSet{}
+	var fixtures []Fixture
+	for _, path := range paths {
+		file, err := readFixtureFile(path)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, set := range file.DescriptorSets {
+			if !filepath.IsAbs(set) {
+				set = filepath.Join(filepath.Dir(path), set)
+			}
+			if err := readDescriptorSet(set, descriptors); err != nil {
+				return nil, err
+			}
+		}
+
+		for i, spec := range file.Fixtures {
+			fixture, err := spec.toFixture()
+			if err != nil {
+				return nil, fmt.Errorf("%s: fixture %d: %w", path, i, err)
+			}
+			fixtures = append(fixtures, fixture)
+		}
+	}
+
+	files, err := protodesc.NewFiles(dedupeDescriptors(descriptors))
+	if err != nil {
+		return nil, fmt.Errorf("load descriptor sets: %w", err)
+	}
+
+	return &Fixtures{files: files, fixtures: fixtures}, nil
+}
+
+func readFixtureFile(path string) (*fixtureFile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read fixtures: %w", err)
+	}
+
+	// JSON is a subset of YAML, so both formats go through the same decoder
+	file := &fixtureFile{}
+	if err := yaml.Unmarshal(data, file); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	return file, nil
+}
+
+func readDescriptorSet(path string, into *descriptorpb.FileDescriptorSet) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("read descriptor set: %w", err)
+	}
+
+	set := &descriptorpb.FileDescriptorSet{}
+	if err := proto.Unmarshal(data, set); err != nil {
+		return fmt.Errorf("parse descriptor set %s: %w", path, err)
+	}
+
+	into.File = append(into.File, set.File...)
+	return nil
+}
+
+// dedupeDescriptors drops the files included by several descriptor sets, like
+// the well-known types.
+func dedupeDescriptors(set *descriptorpb.FileDescriptorSet) *descriptorpb.FileDescriptorSet {
+	seen := make(map[string]bool, len(set.File))
+	deduped := &descriptorpb.FileDescriptorSet{}
+	for _, file := range set.File {
+		if seen[file.GetName()] {
+			continue
+		}
+		seen[file.GetName()] = true
+		deduped.File = append(deduped.File, file)
+	}
+	return deduped
+}
+
+func (s fixtureSpec) toFixture() (Fixture, error) {
+	fixture := Fixture{
+		Method: s.Method,
+		Match:  s.Match,
+	}
+
+	if !strings.HasPrefix(s.Method, "/") || strings.Count(s.Method, "/") != 2 {
+		return fixture, fmt.Errorf("method %q must look like /package.Service/Method", s.Method)
+	}
+
+	if s.Latency != "" {
+		latency, err := time.ParseDuration(s.Latency)
+		if err != nil {
+			return fixture, fmt.Errorf("latency: %w", err)
+		}
+		fixture.Latency = latency
+	}
+
+	if s.Error != nil {
+		// codes.Code accepts the upper case names, e.g. "NOT_FOUND"
+		if err := fixture.Code.UnmarshalJSON([]byte(fmt.Sprintf("%q", strings.ToUpper(s.Error.Code)))); err != nil {
+			return fixture, fmt.Errorf("error code: %w", err)
+		}
+		fixture.Message = s.Error.Message
+		return fixture, nil
+	}
+
+	response, err := json.Marshal(s.Response)
+	if err != nil {
+		return fixture, fmt.Errorf("response: %w", err)
+	}
+	fixture.Response = response
+
+	return fixture, nil
+}
+
+// find returns the first fixture of method matching the request.
+func (f *Fixtures) find(method string, request map[string]any) (*Fixture, bool) {
+	for i := range f.fixtures {
+		fixture := &f.fixtures[i]
+		if fixture.Method == method && matches(fixture.Match, request) {
+			return fixture, true
+		}
+	}
+	return nil, false
+}
+
+func matches(want, got any) bool {
+	switch want := want.(type) {
+	case map[string]any:
+		got, ok := got.(map[string]any)
+		if !ok {
+			return false
+		}
+		for key, value := range want {
+			if !matches(value, got[key]) {
+				return false
+			}
+		}
+		return true
+	case []any:
+		got, ok := got.([]any)
+		if !ok || len(got) != len(want) {
+			return false
+		}
+		for i := range want {
+			if !matches(want[i], got[i]) {
+				return false
+			}
+		}
+		return true
+	default:
+		// protojson renders 64-bit integers as strings and enums as names, so
+		// scalars are compared by their text
+		return got != nil && fmt.Sprint(want) == fmt.Sprint(got)
+	}
+}
@@ -0,0 +1,145 @@
+// Package grpcmock serves canned responses for gRPC services from fixture
+// files, so integrations with external gRPC dependencies can be exercised
+// offline. Requests and responses are handled through the service
+// descriptors, no generated code is needed.
+package grpcmock
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// requestMarshalOptions renders requests with the field names of the .proto
+// files and with unset fields, so fixtures can match on zero values too.
+var requestMarshalOptions = protojson.MarshalOptions{
+	UseProtoNames:   true,
+	EmitUnpopulated: true,
+}
+
+// NewServer creates a gRPC server answering every unary method found in the
+// descriptors of fixtures.
+func NewServer(fixtures *Fixtures) *grpc.Server {
+	return grpc.NewServer(grpc.UnknownServiceHandler(func(_ any, stream grpc.ServerStream) error {
+		return fixtures.serve(stream)
+	}))
+}
+
+func (f *Fixtures) serve(stream grpc.ServerStream) error {
+	method, ok := grpc.MethodFromServerStream(stream)
+	if !ok {
+		return status.Error(codes.Internal, "method is unknown")
+	}
+
+	descriptor, err := f.methodDescriptor(method)
+	if err != nil {
+		return err
+	}
+	if descriptor.IsStreamingClient() || descriptor.IsStreamingServer() {
+		return status.Errorf(codes.Unimplemented, "%s: streaming methods are not supported", method)
+	}
+
+	request := dynamicpb.NewMessage(descriptor.Input())
+	if err := stream.RecvMsg(request); err != nil {
+		return err
+	}
+
+	requestJSON, err := requestMarshalOptions.Marshal(request)
+	if err != nil {
+		return status.Errorf(codes.Internal, "encode request: %v", err)
+	}
+	var fields map[string]any
+	if err := json.Unmarshal(requestJSON, &fields); err != nil {
+		return status.Errorf(codes.Internal, "decode request: %v", err)
+	}
+
+	fixture, ok := f.find(method, fields)
+	if !ok {
+		log.Warn().Str("method", method).RawJSON("request", requestJSON).Msg("No gRPC fixture matches the request")
+		return status.Errorf(codes.Unimplemented, "%s: no fixture matches the request", method)
+	}
+
+	log.Debug().Str("method", method).RawJSON("request", requestJSON).Msg("Serving gRPC fixture")
+
+	if fixture.Latency > 0 {
+		select {
+		case <-stream.Context().Done():
+			return status.FromContextError(stream.Context().Err()).Err()
+		case <-time.After(fixture.Latency):
+		}
+	}
+
+	if fixture.Code != codes.OK {
+		return status.Error(fixture.Code, fixture.Message)
+	}
+
+	response := dynamicpb.NewMessage(descriptor.Output())
+	if err := protojson.Unmarshal(fixture.Response, response); err != nil {
+		return status.Errorf(codes.Internal, "%s: fixture response: %v", method, err)
+	}
+	return stream.SendMsg(response)
+}
+
+// methodDescriptor resolves a full method name, e.g. /pkg.Service/Method.
+func (f *Fixtures) methodDescriptor(method string) (protoreflect.MethodDescriptor, error) {
+	service, name, ok := strings.Cut(strings.TrimPrefix(method, "/"), "/")
+	if !ok {
+		return nil, status.Errorf(codes.Unimplemented, "%s: malformed method name", method)
+	}
+
+	descriptor, err := f.files.FindDescriptorByName(protoreflect.FullName(service))
+	if err != nil {
+		return nil, status.Errorf(codes.Unimplemented, "%s: unknown service", method)
+	}
+
+	serviceDescriptor, ok := descriptor.(protoreflect.ServiceDescriptor)
+	if !ok {
+		return nil, status.Errorf(codes.Unimplemented, "%s: %s is not a service", method, service)
+	}
+
+	methodDescriptor := serviceDescriptor.Methods().ByName(protoreflect.Name(name))
+	if methodDescriptor == nil {
+		return nil, status.Errorf(codes.Unimplemented, "%s: unknown method", method)
+	}
+	return methodDescriptor, nil
+}
+
+// Methods lists the methods having fixtures.
+func (f *Fixtures) Methods() []string {
+	seen := make(map[string]bool)
+	var methods []string
+	for _, fixture := range f.fixtures {
+		if !seen[fixture.Method] {
+			seen[fixture.Method] = true
+			methods = append(methods, fixture.Method)
+		}
+	}
+	return methods
+}
+
+// Validate checks that every fixture targets a unary method of the loaded
+// descriptors and that its response fits the output message.
+func (f *Fixtures) Validate() error {
+	for _, fixture := range f.fixtures {
+		descriptor, err := f.methodDescriptor(fixture.Method)
+		if err != nil {
+			return fmt.Errorf("fixture %s: %w", fixture.Method, err)
+		}
+		if fixture.Code != codes.OK {
+			continue
+		}
+		if err := protojson.Unmarshal(fixture.Response, dynamicpb.NewMessage(descriptor.Output())); err != nil {
+			return fmt.Errorf("fixture %s: response: %w", fixture.Method, err)
+		}
+	}
+	return nil
+}
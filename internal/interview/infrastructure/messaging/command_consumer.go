@@ -0,0 +1,277 @@
+package messaging
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"online-registration/internal/common"
+	"online-registration/internal/interview/domain/dto"
+	"online-registration/internal/interview/helper"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// ReplyToHeader names the subject a command sender wants its result on,
+	// in addition to the shared result subject.
+	ReplyToHeader = "Reply-To"
+
+	deadLetterSubjectHeader  = "Dead-Letter-Subject"
+	deadLetterErrorHeader    = "Dead-Letter-Error"
+	deadLetterAttemptsHeader = "Dead-Letter-Attempts"
+
+	commandAckWait     = 30 * time.Second
+	commandRetryDelay  = 5 * time.Second
+	commandConsumerTag = "event-commands-worker"
+)
+
+type CommandFunc func(ctx context.Context, data []byte) (*dto.CommandResultDTO, error)
+
+type CommandConsumerConfig struct {
+	Stream            string
+	ResultSubject     string
+	DeadLetterSubject string
+	// MaxDeliver is the number of attempts after which a failing command is
+	// moved to the dead-letter subject.
+	MaxDeliver int
+}
+
+// CommandConsumer consumes commands from a JetStream stream and dispatches
+// them by subject.
+type CommandConsumer struct {
+	nc         *nats.Conn
+	js         jetstream.JetStream
+	config     CommandConsumerConfig
+	handlers   map[string]CommandFunc
+	ackWait    time.Duration
+	retryDelay time.Duration
+
+	mu sync.Mutex
+	// waits counts the deliveries of a command, by stream sequence, that
+	// found it run by another worker. They are not failed attempts.
+	waits map[uint64]uint64
+}
+
+func NewCommandConsumer(nc *nats.Conn, config CommandConsumerConfig) (*CommandConsumer, error) {
+	js, err := jetstream.New(nc)
+	if err != nil {
+		return nil, fmt.Errorf("NewCommandConsumer %w", err)
+	}
+
+	return &CommandConsumer{
+		nc:         nc,
+		js:         js,
+		config:     config,
+		handlers:   make(map[string]CommandFunc),
+		ackWait:    commandAckWait,
+		retryDelay: commandRetryDelay,
+		waits:      make(map[uint64]uint64),
+	}, nil
+}
+
+// Handle registers fn for commands published to subject. It must be called
+// before Start.
+func (c *CommandConsumer) Handle(subject string, fn CommandFunc) {
+	c.handlers[subject] = fn
+}
+
+// Start creates the stream and the durable consumer if needed and consumes
+// commands until ctx is cancelled.
+func (c *CommandConsumer) Start(ctx context.Context) error {
+	subjects := make([]string, 0, len(c.handlers)+1)
+	for subject := range c.handlers {
+		subjects = append(subjects, subject)
+	}
+
+	_, err := c.js.CreateOrUpdateStream(ctx, jetstream.StreamConfig{
+		Name:     c.config.Stream,
+		Subjects: append(subjects, c.config.DeadLetterSubject),
+		Storage:  jetstream.FileStorage,
+	})
+	if err != nil {
+		return fmt.Errorf("ensure stream %s: %w", c.config.Stream, err)
+	}
+
+	consumer, err := c.js.CreateOrUpdateConsumer(ctx, c.config.Stream, jetstream.ConsumerConfig{
+		Durable:        commandConsumerTag,
+		FilterSubjects: subjects,
+		AckPolicy:      jetstream.AckExplicitPolicy,
+		AckWait:        c.ackWait,
+	})
+	if err != nil {
+		return fmt.Errorf("ensure consumer %s: %w", commandConsumerTag, err)
+	}
+
+	consumeCtx, err := consumer.Consume(func(msg jetstream.Msg) {
+		c.handle(ctx, msg)
+	})
+	if err != nil {
+		return fmt.Errorf("consume %s: %w", c.config.Stream, err)
+	}
+
+	go func() {
+		<-ctx.Done()
+		consumeCtx.Drain()
+	}()
+
+	return nil
+}
+
+func (c *CommandConsumer) handle(ctx context.Context, msg jetstream.Msg) {
+	metadata, err := msg.Metadata()
+	if err != nil {
+		log.Error().Msgf("CommandConsumer: read metadata: %v", err)
+		_ = msg.Term()
+		return
+	}
+
+	handler, ok := c.handlers[msg.Subject()]
+	if !ok {
+		c.deadLetter(msg, metadata.NumDelivered, fmt.Errorf("no handler for subject %s", msg.Subject()))
+		return
+	}
+
+	sequence := metadata.Sequence.Stream
+	stop := c.keepInProgress(msg)
+	result, err := handler(ctx, msg.Data())
+	stop()
+
+	if err == nil {
+		c.forget(sequence)
+		c.publishResult(msg, result)
+		if err := msg.Ack(); err != nil {
+			log.Error().Msgf("CommandConsumer: ack: %v", err)
+		}
+		return
+	}
+
+	var processingErr *common.ProcessingError
+	isProcessingErr := errors.As(err, &processingErr)
+	if isProcessingErr && processingErr.Code == common.CodeIdempotencyKeyInProgress {
+		c.wait(sequence)
+		log.Info().
+			Str("subject", msg.Subject()).
+			Msg("CommandConsumer: command is run by another worker, retrying once it is done")
+		if err := msg.NakWithDelay(c.retryDelay); err != nil {
+			log.Error().Msgf("CommandConsumer: nak: %v", err)
+		}
+		return
+	}
+
+	attempts := metadata.NumDelivered - c.waitsOf(sequence)
+	malformed := isProcessingErr && processingErr.Status == helper.InvalidArgument
+	if malformed || attempts >= uint64(c.config.MaxDeliver) {
+		c.forget(sequence)
+		c.deadLetter(msg, attempts, err)
+		return
+	}
+
+	log.Warn().
+		Str("subject", msg.Subject()).
+		Uint64("attempt", attempts).
+		Msgf("CommandConsumer: command failed, retrying: %v", err)
+
+	if err := msg.NakWithDelay(c.retryDelay * time.Duration(attempts)); err != nil {
+		log.Error().Msgf("CommandConsumer: nak: %v", err)
+	}
+}
+
+// keepInProgress tells the server that msg is being handled every third of
+// the ack wait until stop is called, so that slow commands are not
+// redelivered while they run.
+func (c *CommandConsumer) keepInProgress(msg jetstream.Msg) (stop func()) {
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(c.ackWait / 3)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				if err := msg.InProgress(); err != nil {
+					log.Warn().Msgf("CommandConsumer: in progress: %v", err)
+				}
+			}
+		}
+	}()
+	return func() { close(done) }
+}
+
+// wait records a delivery of the command at sequence that found it run by
+// another worker. Deliveries settled by other instances are not forgotten
+// here, the counts are small and go with the process.
+func (c *CommandConsumer) wait(sequence uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.waits[sequence]++
+}
+
+func (c *CommandConsumer) waitsOf(sequence uint64) uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.waits[sequence]
+}
+
+func (c *CommandConsumer) forget(sequence uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.waits, sequence)
+}
+
+func (c *CommandConsumer) publishResult(msg jetstream.Msg, result *dto.CommandResultDTO) {
+	data, err := json.Marshal(result)
+	if err != nil {
+		log.Error().Msgf("CommandConsumer: marshal result: %v", err)
+		return
+	}
+
+	subjects := []string{c.config.ResultSubject}
+	if replyTo := msg.Headers().Get(ReplyToHeader); replyTo != "" {
+		subjects = append(subjects, replyTo)
+	}
+
+	for _, subject := range subjects {
+		if err := c.nc.Publish(subject, data); err != nil {
+			log.Error().Msgf("CommandConsumer: publish result to %s: %v", subject, err)
+		}
+	}
+}
+
+// deadLetter moves msg to the dead-letter subject and terminates it, so it
+// is not redelivered.
+func (c *CommandConsumer) deadLetter(msg jetstream.Msg, attempts uint64, cause error) {
+	log.Error().
+		Str("subject", msg.Subject()).
+		Uint64("attempts", attempts).
+		Msgf("CommandConsumer: moving command to dead letter: %v", cause)
+
+	deadLetter := nats.NewMsg(c.config.DeadLetterSubject)
+	deadLetter.Data = msg.Data()
+	for key, values := range msg.Headers() {
+		deadLetter.Header[key] = values
+	}
+	deadLetter.Header.Set(deadLetterSubjectHeader, msg.Subject())
+	deadLetter.Header.Set(deadLetterErrorHeader, cause.Error())
+	deadLetter.Header.Set(deadLetterAttemptsHeader, strconv.FormatUint(attempts, 10))
+
+	ctx, cancel := context.WithTimeout(context.Background(), c.ackWait)
+	defer cancel()
+
+	if _, err := c.js.PublishMsg(ctx, deadLetter); err != nil {
+		log.Error().Msgf("CommandConsumer: publish dead letter: %v", err)
+		_ = msg.Nak()
+		return
+	}
+
+	if err := msg.Term(); err != nil {
+		log.Error().Msgf("CommandConsumer: term: %v", err)
+	}
+}
@@ -0,0 +1,212 @@
+package messaging
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"online-registration/internal/common"
+	"online-registration/internal/interview/domain/dto"
+	"online-registration/internal/interview/helper"
+
+	"github.com/nats-io/nats.go"
+)
+
+const (
+	testCommandStream     = "COMMANDS"
+	testCommandSubject    = "commands.create_event"
+	testResultSubject     = "commands.results"
+	testDeadLetterSubject = "commands.dead_letter"
+)
+
+// startTestConsumer consumes the commands of testCommandSubject with fn
+// until the test ends.
+func startTestConsumer(t *testing.T, nc *nats.Conn, maxDeliver int, fn CommandFunc) *CommandConsumer {
+	t.Helper()
+
+	consumer, err := NewCommandConsumer(nc, CommandConsumerConfig{
+		Stream:            testCommandStream,
+		ResultSubject:     testResultSubject,
+		DeadLetterSubject: testDeadLetterSubject,
+		MaxDeliver:        maxDeliver,
+	})
+	if err != nil {
+		t.Fatalf("NewCommandConsumer: %v", err)
+	}
+	consumer.ackWait = time.Second
+	consumer.retryDelay = 10 * time.Millisecond
+	consumer.Handle(testCommandSubject, fn)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	if err := consumer.Start(ctx); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	return consumer
+}
+
+func subscribeSync(t *testing.T, nc *nats.Conn, subject string) *nats.Subscription {
+	t.Helper()
+
+	sub, err := nc.SubscribeSync(subject)
+	if err != nil {
+		t.Fatalf("subscribe %s: %v", subject, err)
+	}
+	t.Cleanup(func() { sub.Unsubscribe() })
+	return sub
+}
+
+func publishCommand(t *testing.T, nc *nats.Conn, replyTo string) {
+	t.Helper()
+
+	msg := nats.NewMsg(testCommandSubject)
+	msg.Data = []byte(`{"command_id":"c1"}`)
+	if replyTo != "" {
+		msg.Header.Set(ReplyToHeader, replyTo)
+	}
+	if err := nc.PublishMsg(msg); err != nil {
+		t.Fatalf("publish command: %v", err)
+	}
+}
+
+func nextResult(t *testing.T, sub *nats.Subscription) dto.CommandResultDTO {
+	t.Helper()
+
+	msg, err := sub.NextMsg(5 * time.Second)
+	if err != nil {
+		t.Fatalf("no result on %s: %v", sub.Subject, err)
+	}
+	var result dto.CommandResultDTO
+	if err := json.Unmarshal(msg.Data, &result); err != nil {
+		t.Fatalf("decode result: %v", err)
+	}
+	return result
+}
+
+func okResult(context.Context, []byte) (*dto.CommandResultDTO, error) {
+	return &dto.CommandResultDTO{CommandID: "c1", Command: "create_event", Status: dto.CommandStatusOK}, nil
+}
+
+func TestCommandConsumerPublishesResults(t *testing.T) {
+	nc := runJetStream(t)
+	results := subscribeSync(t, nc, testResultSubject)
+	replies := subscribeSync(t, nc, "replies.sender")
+	startTestConsumer(t, nc, 3, okResult)
+
+	publishCommand(t, nc, "replies.sender")
+
+	for _, sub := range []*nats.Subscription{results, replies} {
+		if result := nextResult(t, sub); result.CommandID != "c1" || result.Status != dto.CommandStatusOK {
+			t.Errorf("result on %s = %+v", sub.Subject, result)
+		}
+	}
+}
+
+func TestCommandConsumerDeadLettersMalformedCommands(t *testing.T) {
+	nc := runJetStream(t)
+	deadLetters := subscribeSync(t, nc, testDeadLetterSubject)
+	var calls atomic.Int32
+	startTestConsumer(t, nc, 3, func(context.Context, []byte) (*dto.CommandResultDTO, error) {
+		calls.Add(1)
+		return nil, common.NewCodedError(helper.InvalidArgument, common.CodeInvalidRequestBody, errors.New("bad json"))
+	})
+
+	publishCommand(t, nc, "")
+
+	msg, err := deadLetters.NextMsg(5 * time.Second)
+	if err != nil {
+		t.Fatalf("no dead letter: %v", err)
+	}
+	if string(msg.Data) != `{"command_id":"c1"}` {
+		t.Errorf("dead letter holds %s, want the command", msg.Data)
+	}
+	if got := msg.Header.Get(deadLetterSubjectHeader); got != testCommandSubject {
+		t.Errorf("%s = %q, want %q", deadLetterSubjectHeader, got, testCommandSubject)
+	}
+	if got := msg.Header.Get(deadLetterAttemptsHeader); got != "1" {
+		t.Errorf("%s = %q, want a single attempt", deadLetterAttemptsHeader, got)
+	}
+	if msg.Header.Get(deadLetterErrorHeader) == "" {
+		t.Errorf("dead letter has no %s", deadLetterErrorHeader)
+	}
+
+	// terminated, so not redelivered
+	time.Sleep(200 * time.Millisecond)
+	if calls.Load() != 1 {
+		t.Errorf("command handled %d times, want once", calls.Load())
+	}
+}
+
+func TestCommandConsumerRetriesFailuresThenDeadLetters(t *testing.T) {
+	nc := runJetStream(t)
+	deadLetters := subscribeSync(t, nc, testDeadLetterSubject)
+	var calls atomic.Int32
+	startTestConsumer(t, nc, 3, func(context.Context, []byte) (*dto.CommandResultDTO, error) {
+		calls.Add(1)
+		return nil, errors.New("database unavailable")
+	})
+
+	publishCommand(t, nc, "")
+
+	msg, err := deadLetters.NextMsg(5 * time.Second)
+	if err != nil {
+		t.Fatalf("no dead letter: %v", err)
+	}
+	if got := msg.Header.Get(deadLetterAttemptsHeader); got != "3" {
+		t.Errorf("%s = %q, want 3", deadLetterAttemptsHeader, got)
+	}
+	if calls.Load() != 3 {
+		t.Errorf("command handled %d times, want 3", calls.Load())
+	}
+}
+
+func TestCommandConsumerDoesNotCountCommandsInProgress(t *testing.T) {
+	nc := runJetStream(t)
+	results := subscribeSync(t, nc, testResultSubject)
+	deadLetters := subscribeSync(t, nc, testDeadLetterSubject)
+	var calls atomic.Int32
+	startTestConsumer(t, nc, 2, func(ctx context.Context, data []byte) (*dto.CommandResultDTO, error) {
+		// run by another worker for a while, then failing once
+		switch calls.Add(1) {
+		case 1, 2, 3, 4:
+			return nil, common.NewCodedError(
+				helper.AlreadyExists, common.CodeIdempotencyKeyInProgress, errors.New("in progress"),
+			)
+		case 5:
+			return nil, errors.New("database unavailable")
+		}
+		return okResult(ctx, data)
+	})
+
+	publishCommand(t, nc, "")
+
+	if result := nextResult(t, results); result.Status != dto.CommandStatusOK {
+		t.Errorf("result = %+v, want ok", result)
+	}
+	if msg, err := deadLetters.NextMsg(100 * time.Millisecond); err == nil {
+		t.Errorf("command dead-lettered after %s attempts", msg.Header.Get(deadLetterAttemptsHeader))
+	}
+}
+
+func TestCommandConsumerKeepsSlowCommandsInProgress(t *testing.T) {
+	nc := runJetStream(t)
+	results := subscribeSync(t, nc, testResultSubject)
+	var calls atomic.Int32
+	consumer := startTestConsumer(t, nc, 3, func(ctx context.Context, data []byte) (*dto.CommandResultDTO, error) {
+		calls.Add(1)
+		time.Sleep(2500 * time.Millisecond)
+		return okResult(ctx, data)
+	})
+
+	publishCommand(t, nc, "")
+
+	nextResult(t, results)
+	// a redelivery would have come after the ack wait
+	time.Sleep(consumer.ackWait)
+	if calls.Load() != 1 {
+		t.Errorf("command handled %d times while it ran longer than the ack wait, want once", calls.Load())
+	}
+}
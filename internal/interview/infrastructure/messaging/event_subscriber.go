@@ -0,0 +1,75 @@
+package messaging
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/rs/zerolog/log"
+)
+
+const eventRetryDelay = 10 * time.Second
+
+type EventFunc func(ctx context.Context, subject string, data []byte) error
+
+// EventSubscriber delivers event lifecycle messages from the events stream to
+// a durable consumer. Messages are redelivered until fn succeeds.
+type EventSubscriber struct {
+	js       jetstream.JetStream
+	stream   string
+	subjects []string
+}
+
+func NewEventSubscriber(nc *nats.Conn, stream string, subjects ...string) (*EventSubscriber, error) {
+	js, err := jetstream.New(nc)
+	if err != nil {
+		return nil, fmt.Errorf("NewEventSubscriber %w", err)
+	}
+
+	return &EventSubscriber{
+		js:       js,
+		stream:   stream,
+		subjects: subjects,
+	}, nil
+}
+
+// Subscribe consumes the messages matching filterSubjects with the durable
+// consumer name until ctx is cancelled.
+func (s *EventSubscriber) Subscribe(ctx context.Context, name string, fn EventFunc, filterSubjects ...string) error {
+	_, err := s.js.CreateOrUpdateStream(ctx, eventStreamConfig(s.stream, s.subjects))
+	if err != nil {
+		return fmt.Errorf("ensure stream %s: %w", s.stream, err)
+	}
+
+	consumer, err := s.js.CreateOrUpdateConsumer(ctx, s.stream, jetstream.ConsumerConfig{
+		Durable:        name,
+		FilterSubjects: filterSubjects,
+		AckPolicy:      jetstream.AckExplicitPolicy,
+	})
+	if err != nil {
+		return fmt.Errorf("ensure consumer %s: %w", name, err)
+	}
+
+	consumeCtx, err := consumer.Consume(func(msg jetstream.Msg) {
+		if err := fn(ctx, msg.Subject(), msg.Data()); err != nil {
+			log.Error().Str("consumer", name).Str("subject", msg.Subject()).Msgf("EventSubscriber: %v", err)
+			_ = msg.NakWithDelay(eventRetryDelay)
+			return
+		}
+		if err := msg.Ack(); err != nil {
+			log.Error().Str("consumer", name).Msgf("EventSubscriber: ack: %v", err)
+		}
+	})
+	if err != nil {
+		return fmt.Errorf("consume %s: %w", name, err)
+	}
+
+	go func() {
+		<-ctx.Done()
+		consumeCtx.Drain()
+	}()
+
+	return nil
+}
@@ -0,0 +1,78 @@
+package messaging
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+// duplicateWindow is how long JetStream remembers message IDs to drop
+// messages the relay publishes more than once.
+const duplicateWindow = 10 * time.Minute
+
+type JetStreamPublisher struct {
+	js       jetstream.JetStream
+	stream   string
+	subjects []string
+
+	mu          sync.Mutex
+	streamReady bool
+}
+
+func NewJetStreamPublisher(nc *nats.Conn, stream string, subjects ...string) (*JetStreamPublisher, error) {
+	js, err := jetstream.New(nc)
+	if err != nil {
+		return nil, fmt.Errorf("NewJetStreamPublisher %w", err)
+	}
+
+	return &JetStreamPublisher{
+		js:       js,
+		stream:   stream,
+		subjects: subjects,
+	}, nil
+}
+
+func (p *JetStreamPublisher) Publish(ctx context.Context, subject string, messageID string, payload []byte) error {
+	if err := p.ensureStream(ctx); err != nil {
+		return err
+	}
+
+	_, err := p.js.Publish(ctx, subject, payload, jetstream.WithMsgID(messageID))
+	if err != nil {
+		return fmt.Errorf("Publish %w", err)
+	}
+
+	return nil
+}
+
+// ensureStream creates the stream on first use so that the service can start
+// while NATS is unavailable.
+func (p *JetStreamPublisher) ensureStream(ctx context.Context) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.streamReady {
+		return nil
+	}
+
+	_, err := p.js.CreateOrUpdateStream(ctx, eventStreamConfig(p.stream, p.subjects))
+	if err != nil {
+		return fmt.Errorf("ensure stream %s: %w", p.stream, err)
+	}
+
+	p.streamReady = true
+	return nil
+}
+
+func eventStreamConfig(stream string, subjects []string) jetstream.StreamConfig {
+	return jetstream.StreamConfig{
+		Name:       stream,
+		Subjects:   subjects,
+		Storage:    jetstream.FileStorage,
+		Duplicates: duplicateWindow,
+	}
+}
@@ -0,0 +1,113 @@
+package messaging
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats-server/v2/server"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+const (
+	testStream  = "EVENTS"
+	testSubject = "events.created"
+)
+
+// runJetStream starts an embedded NATS server with JetStream enabled and
+// returns a connection to it.
+func runJetStream(t *testing.T) *nats.Conn {
+	t.Helper()
+
+	s, err := server.NewServer(&server.Options{
+		Host:      "127.0.0.1",
+		Port:      -1,
+		JetStream: true,
+		StoreDir:  t.TempDir(),
+		NoLog:     true,
+		NoSigs:    true,
+	})
+	if err != nil {
+		t.Fatalf("start NATS server: %v", err)
+	}
+	go s.Start()
+	t.Cleanup(s.Shutdown)
+	if !s.ReadyForConnections(5 * time.Second) {
+		t.Fatal("NATS server isn't ready")
+	}
+
+	nc, err := nats.Connect(s.ClientURL())
+	if err != nil {
+		t.Fatalf("connect to NATS: %v", err)
+	}
+	t.Cleanup(nc.Close)
+	return nc
+}
+
+func newTestPublisher(t *testing.T, nc *nats.Conn) *JetStreamPublisher {
+	t.Helper()
+
+	publisher, err := NewJetStreamPublisher(nc, testStream, "events.>")
+	if err != nil {
+		t.Fatalf("NewJetStreamPublisher: %v", err)
+	}
+	return publisher
+}
+
+// streamMessages returns the number of messages in the test stream.
+func streamMessages(t *testing.T, nc *nats.Conn) uint64 {
+	t.Helper()
+
+	js, err := jetstream.New(nc)
+	if err != nil {
+		t.Fatalf("jetstream.New: %v", err)
+	}
+	stream, err := js.Stream(context.Background(), testStream)
+	if err != nil {
+		t.Fatalf("get stream: %v", err)
+	}
+	info, err := stream.Info(context.Background())
+	if err != nil {
+		t.Fatalf("stream info: %v", err)
+	}
+	return info.State.Msgs
+}
+
+func TestJetStreamPublisherCreatesStream(t *testing.T) {
+	nc := runJetStream(t)
+	publisher := newTestPublisher(t, nc)
+
+	if err := publisher.Publish(context.Background(), testSubject, "1", []byte(`{}`)); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+
+	if got := streamMessages(t, nc); got != 1 {
+		t.Errorf("stream holds %d messages, want 1", got)
+	}
+}
+
+func TestJetStreamPublisherDropsDuplicates(t *testing.T) {
+	nc := runJetStream(t)
+	publisher := newTestPublisher(t, nc)
+	ctx := context.Background()
+
+	for _, messageID := range []string{"1", "1", "2", "1"} {
+		if err := publisher.Publish(ctx, testSubject, messageID, []byte(`{}`)); err != nil {
+			t.Fatalf("Publish %s: %v", messageID, err)
+		}
+	}
+
+	if got := streamMessages(t, nc); got != 2 {
+		t.Errorf("stream holds %d messages, want 2", got)
+	}
+}
+
+func TestJetStreamPublisherFailsOutsideStream(t *testing.T) {
+	nc := runJetStream(t)
+	publisher := newTestPublisher(t, nc)
+
+	if err := publisher.Publish(context.Background(), "other.created", "1", []byte(`{}`)); err == nil {
+		t.Error("Publish to a subject outside the stream succeeded")
+	}
+}
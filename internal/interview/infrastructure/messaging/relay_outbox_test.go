@@ -0,0 +1,193 @@
+package messaging
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"online-registration/internal/interview/domain/entity"
+	"online-registration/internal/interview/domain/usecase"
+
+	"github.com/google/uuid"
+)
+
+// memoryOutbox is an outbox kept in memory, leasing claimed messages like
+// the database one does.
+type memoryOutbox struct {
+	mu       sync.Mutex
+	now      time.Time
+	messages []*entity.OutboxMessage
+	// failMarkPublished fails the next MarkPublished, as if the relay died
+	// between publishing and marking.
+	failMarkPublished bool
+}
+
+func newMemoryOutbox(subjects ...string) *memoryOutbox {
+	outbox := &memoryOutbox{now: time.Now()}
+	for i, subject := range subjects {
+		outbox.messages = append(outbox.messages, &entity.OutboxMessage{
+			ID:            int64(i + 1),
+			MessageID:     uuid.New(),
+			Subject:       subject,
+			Payload:       []byte(`{}`),
+			CreatedAt:     outbox.now,
+			NextAttemptAt: outbox.now,
+		})
+	}
+	return outbox
+}
+
+func (o *memoryOutbox) advance(d time.Duration) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.now = o.now.Add(d)
+}
+
+func (o *memoryOutbox) message(id int64) entity.OutboxMessage {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return *o.messages[id-1]
+}
+
+func (o *memoryOutbox) Claim(_ context.Context, limit int, lease time.Duration) ([]*entity.OutboxMessage, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	var claimed []*entity.OutboxMessage
+	for _, message := range o.messages {
+		if len(claimed) == limit {
+			break
+		}
+		if !message.PublishedAt.IsZero() || message.NextAttemptAt.After(o.now) {
+			continue
+		}
+		message.NextAttemptAt = o.now.Add(lease)
+		copied := *message
+		claimed = append(claimed, &copied)
+	}
+	return claimed, nil
+}
+
+func (o *memoryOutbox) MarkPublished(_ context.Context, ids []int64) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if o.failMarkPublished {
+		o.failMarkPublished = false
+		return errors.New("connection reset")
+	}
+	for _, id := range ids {
+		message := o.messages[id-1]
+		message.PublishedAt = o.now
+		message.Attempts++
+		message.LastError = ""
+	}
+	return nil
+}
+
+func (o *memoryOutbox) MarkFailed(_ context.Context, id int64, lastError string, nextAttemptAt time.Time) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	message := o.messages[id-1]
+	message.Attempts++
+	message.LastError = lastError
+	message.NextAttemptAt = nextAttemptAt
+	return nil
+}
+
+func (o *memoryOutbox) DeletePublished(context.Context, time.Time) (int, error) {
+	return 0, nil
+}
+
+func TestRelayOutboxPublishesMessages(t *testing.T) {
+	nc := runJetStream(t)
+	outbox := newMemoryOutbox(testSubject, testSubject, testSubject)
+	relay := usecase.NewRelayOutboxUseCase(outbox, newTestPublisher(t, nc), 2)
+	ctx := context.Background()
+
+	for _, want := range []int{2, 1, 0} {
+		relayed, err := relay.RelayBatch(ctx)
+		if err != nil {
+			t.Fatalf("RelayBatch: %v", err)
+		}
+		if relayed != want {
+			t.Errorf("RelayBatch relayed %d messages, want %d", relayed, want)
+		}
+	}
+
+	for id := int64(1); id <= 3; id++ {
+		if message := outbox.message(id); message.PublishedAt.IsZero() {
+			t.Errorf("message %d isn't marked published", id)
+		}
+	}
+	if got := streamMessages(t, nc); got != 3 {
+		t.Errorf("stream holds %d messages, want 3", got)
+	}
+}
+
+func TestRelayOutboxLeasesClaimedMessages(t *testing.T) {
+	nc := runJetStream(t)
+	outbox := newMemoryOutbox(testSubject)
+	outbox.failMarkPublished = true
+	relay := usecase.NewRelayOutboxUseCase(outbox, newTestPublisher(t, nc), 10)
+	ctx := context.Background()
+
+	if _, err := relay.RelayBatch(ctx); err == nil {
+		t.Fatal("RelayBatch succeeded while marking failed")
+	}
+
+	// the message is leased, another relay must not pick it up yet
+	relayed, err := relay.RelayBatch(ctx)
+	if err != nil {
+		t.Fatalf("RelayBatch: %v", err)
+	}
+	if relayed != 0 {
+		t.Fatalf("RelayBatch relayed %d leased messages", relayed)
+	}
+
+	outbox.advance(time.Minute)
+	relayed, err = relay.RelayBatch(ctx)
+	if err != nil {
+		t.Fatalf("RelayBatch: %v", err)
+	}
+	if relayed != 1 {
+		t.Fatalf("RelayBatch relayed %d messages after the lease, want 1", relayed)
+	}
+
+	// published twice under the same message ID, JetStream keeps one
+	if got := streamMessages(t, nc); got != 1 {
+		t.Errorf("stream holds %d messages, want 1", got)
+	}
+	if message := outbox.message(1); message.PublishedAt.IsZero() {
+		t.Error("message isn't marked published")
+	}
+}
+
+func TestRelayOutboxBacksOffFailedMessages(t *testing.T) {
+	nc := runJetStream(t)
+	outbox := newMemoryOutbox(testSubject, "other.created")
+	relay := usecase.NewRelayOutboxUseCase(outbox, newTestPublisher(t, nc), 10)
+	ctx := context.Background()
+
+	if _, err := relay.RelayBatch(ctx); err != nil {
+		t.Fatalf("RelayBatch: %v", err)
+	}
+
+	if message := outbox.message(1); message.PublishedAt.IsZero() {
+		t.Error("the message of the stream isn't marked published")
+	}
+	failed := outbox.message(2)
+	if !failed.PublishedAt.IsZero() {
+		t.Fatal("the message outside the stream is marked published")
+	}
+	if failed.Attempts != 1 || failed.LastError == "" {
+		t.Errorf("failed message has %d attempts and error %q, want 1 attempt and an error",
+			failed.Attempts, failed.LastError)
+	}
+	if !failed.NextAttemptAt.After(time.Now()) {
+		t.Error("failed message is retried without a backoff")
+	}
+}
@@ -0,0 +1,205 @@
+// Package fake implements an in-memory SMTP server that accepts every email,
+// so notifications can be exercised without a real mail server.
+package fake
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/textproto"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxMessageSize caps the size of an accepted message, in bytes.
+const maxMessageSize = 10 << 20
+
+// Message is an email the server received.
+type Message struct {
+	From       string
+	To         []string
+	Data       []byte
+	ReceivedAt time.Time
+}
+
+// Server speaks enough SMTP for net/smtp: EHLO, MAIL, RCPT, DATA, RSET, NOOP
+// and QUIT, without TLS or authentication.
+type Server struct {
+	// OnMessage, when set, is called with every message received.
+	OnMessage func(Message)
+
+	mu       sync.Mutex
+	messages []Message
+	listener net.Listener
+}
+
+func NewServer() *Server {
+	return &Server{}
+}
+
+// Messages returns the messages received so far, oldest first.
+func (s *Server) Messages() []Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]Message(nil), s.messages...)
+}
+
+// Reset forgets the messages received so far.
+func (s *Server) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.messages = nil
+}
+
+// ListenAndServe accepts connections on addr until Close is called.
+func (s *Server) ListenAndServe(addr string) error {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	return s.Serve(listener)
+}
+
+// Serve accepts connections on listener until Close is called.
+func (s *Server) Serve(listener net.Listener) error {
+	s.mu.Lock()
+	s.listener = listener
+	s.mu.Unlock()
+
+	for {
+		conn, err := listener.Accept()
+		if errors.Is(err, net.ErrClosed) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		go s.serve(conn)
+	}
+}
+
+// Close stops accepting connections.
+func (s *Server) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.listener == nil {
+		return nil
+	}
+	return s.listener.Close()
+}
+
+func (s *Server) serve(conn net.Conn) {
+	defer conn.Close()
+
+	text := textproto.NewConn(conn)
+	reply := func(code int, message string) bool {
+		return text.PrintfLine("%d %s", code, message) == nil
+	}
+
+	if !reply(220, "fake SMTP server ready") {
+		return
+	}
+
+	var message Message
+	for {
+		line, err := text.ReadLine()
+		if err != nil {
+			return
+		}
+
+		verb, arg, _ := strings.Cut(line, " ")
+		switch strings.ToUpper(verb) {
+		case "EHLO", "HELO":
+			message = Message{}
+			if !reply(250, "fake") {
+				return
+			}
+		case "MAIL":
+			message = Message{From: address(arg)}
+			if !reply(250, "OK") {
+				return
+			}
+		case "RCPT":
+			if message.From == "" {
+				reply(503, "MAIL first")
+				continue
+			}
+			message.To = append(message.To, address(arg))
+			if !reply(250, "OK") {
+				return
+			}
+		case "DATA":
+			if len(message.To) == 0 {
+				reply(503, "RCPT first")
+				continue
+			}
+			if !reply(354, "end data with <CR><LF>.<CR><LF>") {
+				return
+			}
+			data, err := readData(text)
+			if err != nil {
+				reply(552, err.Error())
+				return
+			}
+			message.Data = data
+			message.ReceivedAt = time.Now()
+			s.receive(message)
+			message = Message{}
+			if !reply(250, "OK: queued") {
+				return
+			}
+		case "RSET":
+			message = Message{}
+			if !reply(250, "OK") {
+				return
+			}
+		case "NOOP":
+			if !reply(250, "OK") {
+				return
+			}
+		case "QUIT":
+			reply(221, "bye")
+			return
+		default:
+			if !reply(502, "command not implemented") {
+				return
+			}
+		}
+	}
+}
+
+func (s *Server) receive(message Message) {
+	s.mu.Lock()
+	s.messages = append(s.messages, message)
+	onMessage := s.OnMessage
+	s.mu.Unlock()
+
+	if onMessage != nil {
+		onMessage(message)
+	}
+}
+
+// readData reads a message up to the line with a single dot, undoing the
+// dot stuffing of the client.
+func readData(text *textproto.Conn) ([]byte, error) {
+	data, err := io.ReadAll(io.LimitReader(text.DotReader(), maxMessageSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxMessageSize {
+		return nil, fmt.Errorf("message exceeds %d bytes", maxMessageSize)
+	}
+	return data, nil
+}
+
+// address extracts the address of a "FROM:<a@b>" or "TO:<a@b>" argument.
+func address(arg string) string {
+	_, value, _ := strings.Cut(arg, ":")
+	value, _, _ = strings.Cut(strings.TrimSpace(value), " ")
+	return strings.Trim(value, "<>")
+}
@@ -0,0 +1,65 @@
+package notification
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"online-registration/internal/interview/domain/entity"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+// FileSender writes every email to an .eml file of dir instead of sending
+// it, so they can be opened with a mail client during development.
+type FileSender struct {
+	dir  string
+	from string
+}
+
+func NewFileSender(dir string, from string) (*FileSender, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("NewFileSender %w", err)
+	}
+
+	return &FileSender{
+		dir:  dir,
+		from: from,
+	}, nil
+}
+
+func (s *FileSender) Send(_ context.Context, email *entity.Email) error {
+	now := time.Now()
+	message, err := Message(s.from, email, now)
+	if err != nil {
+		return fmt.Errorf("Send %w", err)
+	}
+
+	name := filepath.Join(s.dir, now.UTC().Format("20060102T150405")+"-"+uuid.NewString()+".eml")
+	if err := os.WriteFile(name, message, 0o644); err != nil {
+		return fmt.Errorf("Send %w", err)
+	}
+
+	log.Info().Str("to", email.To).Str("subject", email.Subject).Str("file", name).Msg("Email written")
+	return nil
+}
+
+// LogSender only logs the emails, for environments that don't send them.
+type LogSender struct{}
+
+func NewLogSender() *LogSender {
+	return &LogSender{}
+}
+
+func (s *LogSender) Send(_ context.Context, email *entity.Email) error {
+	log.Info().
+		Str("to", email.To).
+		Str("subject", email.Subject).
+		Int("attachments", len(email.Attachments)).
+		Msg("Email not sent, notifications are logged")
+	log.Debug().Str("to", email.To).Msg(email.Text)
+	return nil
+}
@@ -0,0 +1,106 @@
+// Package notification sends the emails of registrants over SMTP, or keeps
+// them in files or the log during development.
+package notification
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/textproto"
+	"strings"
+	"time"
+
+	"online-registration/internal/interview/domain/entity"
+)
+
+// base64 attachments are wrapped at this many characters
+const base64LineLength = 76
+
+// Message writes email as a MIME message from from: the text, followed by
+// the attachments.
+func Message(from string, email *entity.Email, date time.Time) ([]byte, error) {
+	var buf bytes.Buffer
+	body := multipart.NewWriter(&buf)
+
+	// the header goes first, the writer only writes once the parts are
+	// created
+	header := [][2]string{
+		{"From", from},
+		{"To", email.To},
+		{"Subject", mime.QEncoding.Encode("utf-8", email.Subject)},
+		{"Date", date.Format(time.RFC1123Z)},
+		{"Message-ID", messageID(from)},
+		{"MIME-Version", "1.0"},
+		{"Content-Type", `multipart/mixed; boundary="` + body.Boundary() + `"`},
+	}
+	for _, field := range header {
+		buf.WriteString(field[0] + ": " + field[1] + "\r\n")
+	}
+	buf.WriteString("\r\n")
+
+	part, err := body.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {"text/plain; charset=utf-8"},
+		"Content-Transfer-Encoding": {"quoted-printable"},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Message %w", err)
+	}
+	text := quotedprintable.NewWriter(part)
+	if _, err := text.Write([]byte(email.Text)); err != nil {
+		return nil, fmt.Errorf("Message %w", err)
+	}
+	if err := text.Close(); err != nil {
+		return nil, fmt.Errorf("Message %w", err)
+	}
+
+	for _, attachment := range email.Attachments {
+		part, err := body.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {attachment.ContentType},
+			"Content-Transfer-Encoding": {"base64"},
+			"Content-Disposition":       {mime.FormatMediaType("attachment", map[string]string{"filename": attachment.Filename})},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("Message %w", err)
+		}
+		if _, err := part.Write(wrapBase64(attachment.Content)); err != nil {
+			return nil, fmt.Errorf("Message %w", err)
+		}
+	}
+
+	if err := body.Close(); err != nil {
+		return nil, fmt.Errorf("Message %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// messageID returns a unique id in the domain of the sender.
+func messageID(from string) string {
+	domain := "localhost"
+	if address, err := mail.ParseAddress(from); err == nil {
+		if at := strings.LastIndex(address.Address, "@"); at >= 0 {
+			domain = address.Address[at+1:]
+		}
+	}
+
+	random := make([]byte, 16)
+	_, _ = rand.Read(random)
+	return "<" + hex.EncodeToString(random) + "@" + domain + ">"
+}
+
+func wrapBase64(content []byte) []byte {
+	encoded := base64.StdEncoding.EncodeToString(content)
+
+	var buf bytes.Buffer
+	for len(encoded) > base64LineLength {
+		buf.WriteString(encoded[:base64LineLength] + "\r\n")
+		encoded = encoded[base64LineLength:]
+	}
+	buf.WriteString(encoded + "\r\n")
+	return buf.Bytes()
+}
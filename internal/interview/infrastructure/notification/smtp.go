@@ -0,0 +1,108 @@
+package notification
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"time"
+
+	"online-registration/internal/interview/domain/entity"
+)
+
+type SMTPConfig struct {
+	Host string
+	Port string
+	// Username and Password authenticate with PLAIN when set. Go refuses to
+	// send them unencrypted, except to localhost.
+	Username string
+	Password string
+	Timeout  time.Duration
+}
+
+// SMTPSender sends emails through a mail server, upgrading the connection
+// with STARTTLS when the server offers it.
+type SMTPSender struct {
+	config SMTPConfig
+	from   string
+}
+
+func NewSMTPSender(config SMTPConfig, from string) *SMTPSender {
+	return &SMTPSender{
+		config: config,
+		from:   from,
+	}
+}
+
+func (s *SMTPSender) Send(ctx context.Context, email *entity.Email) error {
+	message, err := Message(s.from, email, time.Now())
+	if err != nil {
+		return fmt.Errorf("Send %w", err)
+	}
+
+	sender, err := mail.ParseAddress(s.from)
+	if err != nil {
+		return fmt.Errorf("Send: invalid sender %q: %w", s.from, err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.config.Timeout)
+	defer cancel()
+
+	dialer := &net.Dialer{}
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(s.config.Host, s.config.Port))
+	if err != nil {
+		return fmt.Errorf("Send %w", err)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	client, err := smtp.NewClient(conn, s.config.Host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("Send %w", err)
+	}
+	defer client.Close()
+
+	if err := s.send(client, sender.Address, email.To, message); err != nil {
+		return fmt.Errorf("Send %w", err)
+	}
+	return nil
+}
+
+func (s *SMTPSender) send(client *smtp.Client, from, to string, message []byte) error {
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: s.config.Host}); err != nil {
+			return err
+		}
+	}
+
+	if s.config.Username != "" {
+		auth := smtp.PlainAuth("", s.config.Username, s.config.Password, s.config.Host)
+		if err := client.Auth(auth); err != nil {
+			return err
+		}
+	}
+
+	if err := client.Mail(from); err != nil {
+		return err
+	}
+	if err := client.Rcpt(to); err != nil {
+		return err
+	}
+
+	data, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := data.Write(message); err != nil {
+		return err
+	}
+	if err := data.Close(); err != nil {
+		return err
+	}
+
+	return client.Quit()
+}
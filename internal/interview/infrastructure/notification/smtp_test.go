@@ -0,0 +1,201 @@
+package notification
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"strings"
+	"testing"
+	"time"
+
+	"online-registration/internal/i18n"
+	"online-registration/internal/interview/domain/entity"
+	"online-registration/internal/interview/domain/notification"
+	"online-registration/internal/interview/infrastructure/notification/fake"
+
+	"github.com/google/uuid"
+)
+
+const testSender = "Registration <registration@example.com>"
+
+// startSMTPServer serves the fake SMTP server on a free local port.
+func startSMTPServer(t *testing.T) (*fake.Server, SMTPConfig) {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	server := fake.NewServer()
+	go server.Serve(listener)
+	t.Cleanup(func() { server.Close() })
+
+	host, port, _ := net.SplitHostPort(listener.Addr().String())
+	return server, SMTPConfig{Host: host, Port: port, Timeout: 5 * time.Second}
+}
+
+func testEvent() *entity.Event {
+	start := time.Date(2030, time.March, 14, 9, 0, 0, 0, time.UTC)
+	return &entity.Event{
+		ID:        uuid.New(),
+		TenantID:  "acme",
+		Title:     "Onboarding day",
+		StartTime: start,
+		EndTime:   start.Add(2 * time.Hour),
+		TimeZone:  "Europe/Berlin",
+		Status:    entity.EventPublished,
+	}
+}
+
+// part is a decoded part of a received message.
+type part struct {
+	contentType string
+	filename    string
+	content     string
+}
+
+func readMessage(t *testing.T, data []byte) (*mail.Message, []part) {
+	t.Helper()
+
+	message, err := mail.ReadMessage(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("read message: %v", err)
+	}
+	mediaType, params, err := mime.ParseMediaType(message.Header.Get("Content-Type"))
+	if err != nil || mediaType != "multipart/mixed" {
+		t.Fatalf("message is %q (%v), want multipart/mixed", mediaType, err)
+	}
+
+	var parts []part
+	reader := multipart.NewReader(message.Body, params["boundary"])
+	for {
+		p, err := reader.NextRawPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("read part: %v", err)
+		}
+
+		var body io.Reader = p
+		switch p.Header.Get("Content-Transfer-Encoding") {
+		case "quoted-printable":
+			body = quotedprintable.NewReader(p)
+		case "base64":
+			body = base64.NewDecoder(base64.StdEncoding, p)
+		}
+		content, err := io.ReadAll(body)
+		if err != nil {
+			t.Fatalf("decode part: %v", err)
+		}
+
+		_, disposition, _ := mime.ParseMediaType(p.Header.Get("Content-Disposition"))
+		parts = append(parts, part{
+			contentType: p.Header.Get("Content-Type"),
+			filename:    disposition["filename"],
+			content:     string(content),
+		})
+	}
+	return message, parts
+}
+
+func TestSMTPSenderSend(t *testing.T) {
+	server, config := startSMTPServer(t)
+	sender := NewSMTPSender(config, testSender)
+
+	email, err := notification.Compose(
+		i18n.Russian, entity.NotificationConfirmation, testEvent(), "registration@example.com",
+		"alice@example.com", time.Now(),
+	)
+	if err != nil {
+		t.Fatalf("Compose: %v", err)
+	}
+	if err := sender.Send(context.Background(), email); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	messages := server.Messages()
+	if len(messages) != 1 {
+		t.Fatalf("server received %d messages, want 1", len(messages))
+	}
+	received := messages[0]
+	if received.From != "registration@example.com" || len(received.To) != 1 || received.To[0] != "alice@example.com" {
+		t.Errorf("envelope is from %q to %v", received.From, received.To)
+	}
+
+	message, parts := readMessage(t, received.Data)
+	subject, err := new(mime.WordDecoder).DecodeHeader(message.Header.Get("Subject"))
+	if err != nil || subject != email.Subject {
+		t.Errorf("Subject = %q (%v), want %q", subject, err, email.Subject)
+	}
+	if len(parts) != 2 {
+		t.Fatalf("message has %d parts, want the text and the invitation", len(parts))
+	}
+	if parts[0].content != email.Text {
+		t.Errorf("text = %q, want %q", parts[0].content, email.Text)
+	}
+
+	invite := parts[1]
+	if invite.filename != "invite.ics" || !strings.HasPrefix(invite.contentType, "text/calendar") ||
+		!strings.Contains(invite.contentType, "method=REQUEST") {
+		t.Errorf("attachment %q is %q, want invite.ics of method REQUEST", invite.filename, invite.contentType)
+	}
+	for _, line := range []string{
+		"BEGIN:VCALENDAR", "METHOD:REQUEST", "SUMMARY:Onboarding day",
+		"ORGANIZER:mailto:registration@example.com", "mailto:alice@example.com",
+	} {
+		if !strings.Contains(invite.content, line) {
+			t.Errorf("invitation lacks %q:\n%s", line, invite.content)
+		}
+	}
+}
+
+func TestSMTPSenderAttachesCancellations(t *testing.T) {
+	server, config := startSMTPServer(t)
+	sender := NewSMTPSender(config, testSender)
+
+	event := testEvent()
+	event.Status = entity.EventCancelled
+	email, err := notification.Compose(
+		i18n.English, entity.NotificationCancellation, event, "registration@example.com",
+		"alice@example.com", time.Now(),
+	)
+	if err != nil {
+		t.Fatalf("Compose: %v", err)
+	}
+	if err := sender.Send(context.Background(), email); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	messages := server.Messages()
+	if len(messages) != 1 {
+		t.Fatalf("server received %d messages, want 1", len(messages))
+	}
+	_, parts := readMessage(t, messages[0].Data)
+	if len(parts) != 2 || !strings.Contains(parts[1].content, "METHOD:CANCEL") {
+		t.Errorf("cancellation does not attach a CANCEL invitation: %+v", parts)
+	}
+}
+
+func TestSMTPSenderFailsWithoutServer(t *testing.T) {
+	// nothing listens on the port once the listener is closed
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	host, port, _ := net.SplitHostPort(listener.Addr().String())
+	listener.Close()
+	config := SMTPConfig{Host: host, Port: port, Timeout: 5 * time.Second}
+
+	sender := NewSMTPSender(config, testSender)
+	email := &entity.Email{To: "alice@example.com", Subject: "Hello", Text: "Hello"}
+	if err := sender.Send(context.Background(), email); err == nil {
+		t.Error("Send succeeded without a mail server")
+	}
+}
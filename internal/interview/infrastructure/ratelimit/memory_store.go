@@ -0,0 +1,79 @@
+// Package ratelimit holds the rate limit stores that don't need a database.
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"online-registration/internal/interview/domain/entity"
+)
+
+// sweepInterval is how often the buckets that refilled are dropped.
+const sweepInterval = time.Minute
+
+type bucket struct {
+	tokens    float64
+	updatedAt time.Time
+	// fullAt is when the bucket is full again, it can be dropped after
+	fullAt time.Time
+}
+
+// MemoryStore keeps the token buckets in process. The limits are enforced
+// per instance, so it suits single instance deployments and development.
+type MemoryStore struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		buckets:   make(map[string]*bucket),
+		lastSweep: time.Now(),
+		now:       time.Now,
+	}
+}
+
+func (s *MemoryStore) Take(_ context.Context, key string, limit entity.RateLimit) (*entity.RateLimitBucket, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	s.sweep(now)
+
+	capacity := float64(limit.Requests)
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &bucket{tokens: capacity, updatedAt: now}
+		s.buckets[key] = b
+	}
+
+	b.tokens = math.Min(capacity, b.tokens+now.Sub(b.updatedAt).Seconds()*limit.TokensPerSecond())
+	b.updatedAt = now
+
+	result := &entity.RateLimitBucket{Allowed: b.tokens >= 1}
+	if result.Allowed {
+		b.tokens--
+	}
+	result.Tokens = b.tokens
+	b.fullAt = now.Add(time.Duration((capacity - b.tokens) / limit.TokensPerSecond() * float64(time.Second)))
+
+	return result, nil
+}
+
+// sweep drops the buckets that are full again, a missing bucket starts full.
+func (s *MemoryStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < sweepInterval {
+		return
+	}
+	s.lastSweep = now
+
+	for key, b := range s.buckets {
+		if now.After(b.fullAt) {
+			delete(s.buckets, key)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"online-registration/internal/interview/domain/entity"
+)
+
+// newTestStore returns a store whose clock only moves when the test moves
+// it.
+func newTestStore() (*MemoryStore, *time.Time) {
+	now := time.Date(2026, time.March, 1, 12, 0, 0, 0, time.UTC)
+	store := NewMemoryStore()
+	store.lastSweep = now
+	store.now = func() time.Time { return now }
+	return store, &now
+}
+
+func TestMemoryStoreTake(t *testing.T) {
+	store, now := newTestStore()
+	// a token every 10 seconds
+	limit := entity.RateLimit{Requests: 3, Period: 30 * time.Second}
+
+	steps := []struct {
+		wait        time.Duration
+		wantAllowed bool
+		wantTokens  float64
+	}{
+		{0, true, 2},
+		{0, true, 1},
+		{0, true, 0},
+		{0, false, 0},
+		{5 * time.Second, false, 0.5},
+		{5 * time.Second, true, 0},
+		// refilled up to the capacity only
+		{time.Hour, true, 2},
+	}
+	for i, step := range steps {
+		*now = now.Add(step.wait)
+		bucket, err := store.Take(context.Background(), "ip:192.0.2.1", limit)
+		if err != nil {
+			t.Fatalf("Take: %v", err)
+		}
+		if bucket.Allowed != step.wantAllowed || bucket.Tokens != step.wantTokens {
+			t.Errorf("step %d: Take = %+v, want allowed %v with %v tokens left",
+				i, bucket, step.wantAllowed, step.wantTokens)
+		}
+	}
+}
+
+func TestMemoryStoreKeepsBucketsApart(t *testing.T) {
+	store, _ := newTestStore()
+	limit := entity.RateLimit{Requests: 1, Period: time.Minute}
+
+	if bucket, _ := store.Take(context.Background(), "read:alice", limit); !bucket.Allowed {
+		t.Fatal("first request of alice denied")
+	}
+	if bucket, _ := store.Take(context.Background(), "read:alice", limit); bucket.Allowed {
+		t.Error("second request of alice allowed")
+	}
+	if bucket, _ := store.Take(context.Background(), "read:bob", limit); !bucket.Allowed {
+		t.Error("first request of bob denied")
+	}
+}
+
+func TestMemoryStoreSweepsFullBuckets(t *testing.T) {
+	store, now := newTestStore()
+	// a token every 10 minutes
+	limit := entity.RateLimit{Requests: 1, Period: 10 * time.Minute}
+
+	store.Take(context.Background(), "ip:192.0.2.1", limit)
+	*now = now.Add(9 * time.Minute)
+	store.Take(context.Background(), "ip:192.0.2.2", limit)
+	if len(store.buckets) != 2 {
+		t.Fatalf("store holds %d buckets, want 2 while they refill", len(store.buckets))
+	}
+
+	*now = now.Add(sweepInterval + time.Second)
+	store.Take(context.Background(), "ip:192.0.2.3", limit)
+	if _, ok := store.buckets["ip:192.0.2.1"]; ok {
+		t.Error("the full bucket was kept")
+	}
+	if _, ok := store.buckets["ip:192.0.2.2"]; !ok {
+		t.Error("the refilling bucket was dropped")
+	}
+}
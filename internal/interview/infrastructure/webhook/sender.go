@@ -0,0 +1,92 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"online-registration/internal/interview/domain/egress"
+)
+
+const (
+	IDHeader        = "X-Webhook-Id"
+	EventHeader     = "X-Webhook-Event"
+	TimestampHeader = "X-Webhook-Timestamp"
+	SignatureHeader = "X-Webhook-Signature"
+
+	signaturePrefix = "sha256="
+	// maxResponseBody caps how much of a failed response is kept for the
+	// delivery log.
+	maxResponseBody = 1024
+)
+
+// Sign returns the signature of a payload sent at timestamp. Receivers
+// recompute it as HMAC-SHA256 over "<timestamp>.<body>" keyed with the
+// subscription secret.
+func Sign(secret string, timestamp int64, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte{'.'})
+	mac.Write(payload)
+	return signaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+type HTTPSender struct {
+	client *http.Client
+}
+
+func NewHTTPSender(timeout time.Duration) *HTTPSender {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	// receivers are dialed directly, a proxy would hide the address checked
+	transport.Proxy = nil
+	transport.DialContext = (&net.Dialer{
+		Timeout:   timeout,
+		KeepAlive: 30 * time.Second,
+		Control:   egress.Control,
+	}).DialContext
+
+	return &HTTPSender{
+		client: &http.Client{Timeout: timeout, Transport: transport},
+	}
+}
+
+// Send POSTs a signed payload to url. It returns the response status code,
+// and an error unless the receiver answered with 2xx. Receivers at internal
+// addresses are refused, see egress.Allowed.
+func (s *HTTPSender) Send(
+	ctx context.Context, url string, secret string, deliveryID string, eventType string, payload []byte,
+) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return 0, fmt.Errorf("Send %w", err)
+	}
+
+	timestamp := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "online-registration-webhooks")
+	req.Header.Set(IDHeader, deliveryID)
+	req.Header.Set(EventHeader, eventType)
+	req.Header.Set(TimestampHeader, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(SignatureHeader, Sign(secret, timestamp, payload))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("Send %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxResponseBody))
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return resp.StatusCode, fmt.Errorf("Send: unexpected status %d: %s", resp.StatusCode, body)
+	}
+
+	return resp.StatusCode, nil
+}
@@ -0,0 +1,230 @@
+package openapi
+
+import (
+	"encoding/json"
+	"path"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Schema is a JSON Schema, as used by OpenAPI 3.1.
+type Schema struct {
+	Ref         string `json:"$ref,omitempty"`
+	Description string `json:"description,omitempty"`
+	// Type is a type name, or a list of them for nullable values.
+	Type                 any                `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Enum                 []string           `json:"enum,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	OneOf                []*Schema          `json:"oneOf,omitempty"`
+}
+
+var (
+	timeType       = reflect.TypeFor[time.Time]()
+	uuidType       = reflect.TypeFor[uuid.UUID]()
+	rawMessageType = reflect.TypeFor[json.RawMessage]()
+)
+
+// Schemas generates the schemas of Go values as encoding/json encodes them.
+// Named structs become component schemas, which the schemas of the values
+// using them refer to.
+type Schemas struct {
+	components map[string]*Schema
+	names      map[reflect.Type]string
+}
+
+func NewSchemas() *Schemas {
+	return &Schemas{
+		components: make(map[string]*Schema),
+		names:      make(map[reflect.Type]string),
+	}
+}
+
+// Of returns the schema of the type of v.
+func (s *Schemas) Of(v any) *Schema {
+	return s.schema(reflect.TypeOf(v))
+}
+
+// Define adds the schema of the type of v as the component name, and returns
+// a reference to it. It is used for the structs whose Go name doesn't suit
+// the API.
+func (s *Schemas) Define(name string, v any) *Schema {
+	t := reflect.TypeOf(v)
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	s.names[t] = name
+	s.components[name] = s.object(t)
+	return ref(name)
+}
+
+// Component returns the component schema name, nil when there is none.
+func (s *Schemas) Component(name string) *Schema {
+	return s.components[name]
+}
+
+// Components returns the component schemas by name.
+func (s *Schemas) Components() map[string]*Schema {
+	return s.components
+}
+
+func (s *Schemas) schema(t reflect.Type) *Schema {
+	switch t {
+	case timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case uuidType:
+		return &Schema{Type: "string", Format: "uuid"}
+	case rawMessageType:
+		return &Schema{}
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		return s.schema(t.Elem())
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Uint, reflect.Uint8, reflect.Uint16:
+		return &Schema{Type: "integer"}
+	case reflect.Int32, reflect.Uint32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Int64, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: s.schema(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: s.schema(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return s.object(t)
+		}
+		return ref(s.component(t))
+	}
+	// interfaces hold any value
+	return &Schema{}
+}
+
+// component returns the name of the component schema of the struct t,
+// adding it first when needed. Structs of different packages sharing a name
+// are told apart by the name of their package.
+func (s *Schemas) component(t reflect.Type) string {
+	if name, ok := s.names[t]; ok {
+		return name
+	}
+
+	name := t.Name()
+	if _, taken := s.components[name]; taken {
+		pkg := path.Base(t.PkgPath())
+		name = strings.ToUpper(pkg[:1]) + pkg[1:] + name
+	}
+	// named before the fields are read, they may refer to it
+	s.names[t] = name
+	s.components[name] = s.object(t)
+	return name
+}
+
+type field struct {
+	name      string
+	typ       reflect.Type
+	omitEmpty bool
+	depth     int
+}
+
+func (s *Schemas) object(t reflect.Type) *Schema {
+	schema := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+	for _, f := range fields(t, 0) {
+		property := s.schema(f.typ)
+		switch f.typ.Kind() {
+		case reflect.Pointer, reflect.Slice, reflect.Map:
+			if !f.omitEmpty {
+				// nil values are encoded as null
+				property = nullable(property)
+			}
+		}
+		schema.Properties[f.name] = property
+	}
+	return schema
+}
+
+// fields lists the fields of the struct t encoding/json encodes, with the
+// fields of embedded structs promoted. A field hides the fields of the same
+// name that are embedded deeper.
+func fields(t reflect.Type, depth int) []field {
+	var listed []field
+	seen := make(map[string]int)
+	add := func(f field) {
+		if i, ok := seen[f.name]; ok {
+			if listed[i].depth > f.depth {
+				listed[i] = f
+			}
+			return
+		}
+		seen[f.name] = len(listed)
+		listed = append(listed, f)
+	}
+
+	for i := range t.NumField() {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, options, _ := strings.Cut(tag, ",")
+
+		if f.Anonymous && name == "" {
+			embedded := f.Type
+			if embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				for _, promoted := range fields(embedded, depth+1) {
+					add(promoted)
+				}
+				continue
+			}
+		}
+		if !f.IsExported() {
+			continue
+		}
+
+		if name == "" {
+			name = f.Name
+		}
+		add(field{
+			name:      name,
+			typ:       f.Type,
+			omitEmpty: strings.Contains(","+options+",", ",omitempty,"),
+			depth:     depth,
+		})
+	}
+	return listed
+}
+
+func ref(name string) *Schema {
+	return &Schema{Ref: "#/components/schemas/" + name}
+}
+
+func nullable(schema *Schema) *Schema {
+	if typ, ok := schema.Type.(string); ok {
+		copied := *schema
+		copied.Type = []string{typ, "null"}
+		return &copied
+	}
+	if schema.Ref != "" {
+		return &Schema{OneOf: []*Schema{schema, {Type: "null"}}}
+	}
+	// the schemas without a type allow null already
+	return schema
+}